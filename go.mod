@@ -12,14 +12,9 @@ require (
 	github.com/ztrue/tracerr v0.4.0
 	golang.org/x/net v0.33.0
 	gopkg.in/yaml.v3 v3.0.1
-	mexc-sdk/mexcsdk v0.0.0
 )
 
-replace mexc-sdk/mexcsdk => ./pkg/mexcsdk
-
 require (
-	github.com/Masterminds/semver/v3 v3.1.1 // indirect
-	github.com/aws/jsii-runtime-go v1.44.1 // indirect
 	github.com/cpuguy83/go-md2man/v2 v2.0.7 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/drybin/palisade/internal/domain/enum/order"
 	"github.com/drybin/palisade/internal/domain/model"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
@@ -692,6 +693,110 @@ func (u StateRepository) UpsertMarketSnapshot(ctx context.Context, snapshot repo
 	})
 }
 
+// UpsertMarketSnapshots сохраняет пачку снимков одной транзакцией.
+func (u StateRepository) UpsertMarketSnapshots(ctx context.Context, snapshots []repo.MarketSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	tx, err := u.Postgree.Begin(ctx)
+	if err != nil {
+		return wrap.Errorf("begin market snapshots tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	db := palisade_database.New(u.Postgree).WithTx(tx)
+	for _, snapshot := range snapshots {
+		if err := db.UpsertMarketSnapshot(ctx, palisade_database.UpsertMarketSnapshotParams{
+			Symbol:             snapshot.Symbol,
+			CollectedAt:        snapshot.CollectedAt,
+			LastPrice:          snapshot.LastPrice,
+			BidPrice:           snapshot.BidPrice,
+			BidQty:             snapshot.BidQty,
+			AskPrice:           snapshot.AskPrice,
+			AskQty:             snapshot.AskQty,
+			QuoteVolume24h:     snapshot.QuoteVolume24h,
+			PriceChangePercent: snapshot.PriceChangePercent,
+		}); err != nil {
+			return wrap.Errorf("upsert market snapshot %s: %w", snapshot.Symbol, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// SaveMarketTrades сохраняет пачку сделок ленты одной транзакцией; уже
+// сохранённые сделки пропускаются.
+func (u StateRepository) SaveMarketTrades(ctx context.Context, trades []repo.MarketTrade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+	tx, err := u.Postgree.Begin(ctx)
+	if err != nil {
+		return wrap.Errorf("begin market trades tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	db := palisade_database.New(u.Postgree).WithTx(tx)
+	for _, trade := range trades {
+		if err := db.InsertMarketTrade(ctx, palisade_database.InsertMarketTradeParams{
+			Symbol:     trade.Symbol,
+			TradedAt:   trade.TradedAt,
+			Price:      trade.Price,
+			Qty:        trade.Qty,
+			BuyerMaker: trade.BuyerMaker,
+			Seq:        trade.Seq,
+		}); err != nil {
+			return wrap.Errorf("insert market trade %s: %w", trade.Symbol, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (u StateRepository) GetLastMarketTradeTime(ctx context.Context, symbol string) (*time.Time, error) {
+	db := palisade_database.New(u.Postgree)
+	t, err := db.GetLastMarketTradeTime(ctx, symbol)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, wrap.Errorf("last market trade time %s: %w", symbol, err)
+	}
+	return &t, nil
+}
+
+func (u StateRepository) ListMarketTradesSince(ctx context.Context, symbol string, since time.Time) ([]repo.MarketTrade, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListMarketTradesSince(ctx, palisade_database.ListMarketTradesSinceParams{
+		Symbol:   symbol,
+		TradedAt: since,
+	})
+	if err != nil {
+		return nil, wrap.Errorf("list market trades %s: %w", symbol, err)
+	}
+	out := make([]repo.MarketTrade, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, repo.MarketTrade{
+			Symbol:     row.Symbol,
+			TradedAt:   row.TradedAt,
+			Price:      row.Price,
+			Qty:        row.Qty,
+			BuyerMaker: row.BuyerMaker,
+			Seq:        row.Seq,
+		})
+	}
+	return out, nil
+}
+
+// DeleteMarketTradesBefore удаляет из ленты сделки старше before и
+// возвращает их число.
+func (u StateRepository) DeleteMarketTradesBefore(ctx context.Context, before time.Time) (int64, error) {
+	db := palisade_database.New(u.Postgree)
+	deleted, err := db.DeleteMarketTradesBefore(ctx, before)
+	if err != nil {
+		return 0, wrap.Errorf("delete market trades before %s: %w", before.Format(time.RFC3339), err)
+	}
+	return deleted, nil
+}
+
 func (u StateRepository) ListMarketSnapshots(ctx context.Context) ([]repo.MarketSnapshot, error) {
 	db := palisade_database.New(u.Postgree)
 	rows, err := db.ListMarketSnapshots(ctx)
@@ -751,6 +856,7 @@ func (u StateRepository) SavePalisadeSignalState(ctx context.Context, signal rep
 		InvalidationReason: signal.InvalidationReason,
 		ValidUntil:         signal.ValidUntil,
 		UpdatedAt:          signal.UpdatedAt,
+		ConfigVersion:      signal.ConfigVersion,
 	})
 }
 
@@ -790,6 +896,10 @@ func (u StateRepository) CreateOrderIntent(ctx context.Context, intent repo.Orde
 	if intent.TradeID > 0 {
 		tradeID = pgtype.Int4{Int32: int32(intent.TradeID), Valid: true}
 	}
+	orderType := intent.OrderType
+	if orderType == "" {
+		orderType = order.LIMIT.String()
+	}
 	created, err := db.CreateOrderIntent(ctx, palisade_database.CreateOrderIntentParams{
 		ClientOrderID:      intent.ClientOrderID,
 		Symbol:             intent.Symbol,
@@ -806,6 +916,8 @@ func (u StateRepository) CreateOrderIntent(ctx context.Context, intent repo.Orde
 		LastError:          intent.LastError,
 		CreatedAt:          intent.CreatedAt,
 		UpdatedAt:          intent.UpdatedAt,
+		OrderType:          orderType,
+		MakerFeeSaving:     intent.MakerFeeSaving,
 	})
 	if err != nil {
 		return nil, wrap.Errorf("create order intent %s: %w", intent.ClientOrderID, err)
@@ -869,6 +981,15 @@ func (u StateRepository) ListOrderIntentsByTradeID(ctx context.Context, tradeID
 	return result, nil
 }
 
+func (u StateRepository) GetMakerFeeSavings(ctx context.Context) (repo.MakerFeeSavings, error) {
+	db := palisade_database.New(u.Postgree)
+	row, err := db.GetMakerFeeSavings(ctx)
+	if err != nil {
+		return repo.MakerFeeSavings{}, wrap.Errorf("get maker fee savings: %w", err)
+	}
+	return repo.MakerFeeSavings{Orders: row.Orders, FeeSavedUSDT: row.FeeSaved}, nil
+}
+
 func mapOrderIntentToDomain(row palisade_database.PalisadeOrderIntent) *repo.OrderIntent {
 	tradeID := 0
 	if row.TradeID.Valid {
@@ -891,6 +1012,429 @@ func mapOrderIntentToDomain(row palisade_database.PalisadeOrderIntent) *repo.Ord
 		LastError:          row.LastError,
 		CreatedAt:          row.CreatedAt,
 		UpdatedAt:          row.UpdatedAt,
+		OrderType:          row.OrderType,
+		MakerFeeSaving:     row.MakerFeeSaving,
+	}
+}
+
+// SaveOrderBracket создаёт брекет сделки или перезаписывает его цены и
+// статус: после перестановки тейк-профита брекет снова активен.
+func (u StateRepository) SaveOrderBracket(ctx context.Context, bracket repo.OrderBracket) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.UpsertOrderBracket(ctx, palisade_database.UpsertOrderBracketParams{
+		TradeID:         bracket.TradeID,
+		Symbol:          bracket.Symbol,
+		TakeProfitPrice: bracket.TakeProfitPrice,
+		StopPrice:       bracket.StopPrice,
+		StopLimitPrice:  bracket.StopLimitPrice,
+		ExpiresAt:       bracket.ExpiresAt,
+		Status:          bracket.Status,
+		CreatedAt:       bracket.CreatedAt,
+	}); err != nil {
+		return wrap.Errorf("save order bracket for trade %d: %w", bracket.TradeID, err)
+	}
+	return nil
+}
+
+func (u StateRepository) ListActiveOrderBrackets(ctx context.Context) ([]repo.OrderBracket, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListActiveOrderBrackets(ctx)
+	if err != nil {
+		return nil, wrap.Errorf("list active order brackets: %w", err)
+	}
+	result := make([]repo.OrderBracket, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, repo.OrderBracket{
+			TradeID:         row.TradeID,
+			Symbol:          row.Symbol,
+			TakeProfitPrice: row.TakeProfitPrice,
+			StopPrice:       row.StopPrice,
+			StopLimitPrice:  row.StopLimitPrice,
+			ExpiresAt:       row.ExpiresAt,
+			Status:          row.Status,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (u StateRepository) UpdateOrderBracketStatus(ctx context.Context, tradeID int, status string) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.UpdateOrderBracketStatus(ctx, palisade_database.UpdateOrderBracketStatusParams{
+		TradeID: tradeID,
+		Status:  status,
+	}); err != nil {
+		return wrap.Errorf("update order bracket for trade %d: %w", tradeID, err)
+	}
+	return nil
+}
+
+// GetTradeExitState возвращает nil, если у сделки нет состояния выхода —
+// SELL ещё не выставлялся или сделка открыта до exit-политики.
+func (u StateRepository) GetTradeExitState(ctx context.Context, tradeID int) (*repo.TradeExitState, error) {
+	db := palisade_database.New(u.Postgree)
+	row, err := db.GetTradeExitState(ctx, tradeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, wrap.Errorf("get exit state for trade %d: %w", tradeID, err)
+	}
+	return &repo.TradeExitState{
+		TradeID:            row.TradeID,
+		StrategyVersion:    row.StrategyVersion,
+		StrategyKey:        row.StrategyKey,
+		Fee:                row.Fee,
+		LotStep:            row.LotStep,
+		BreakEvenArmed:     row.BreakEvenArmed,
+		PartialProfitTaken: row.PartialProfitTaken,
+		PartialOrderID:     row.PartialOrderID,
+		MaxBidPrice:        row.MaxBidPrice,
+		MinBidPrice:        row.MinBidPrice,
+		UpdatedAt:          row.UpdatedAt,
+	}, nil
+}
+
+// SaveTradeExitState создаёт состояние выхода или обновляет его изменяемую
+// часть; стратегия, комиссия и шаг лота остаются с первой записи.
+func (u StateRepository) SaveTradeExitState(ctx context.Context, state repo.TradeExitState) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.UpsertTradeExitState(ctx, palisade_database.UpsertTradeExitStateParams{
+		TradeID:            state.TradeID,
+		StrategyVersion:    state.StrategyVersion,
+		StrategyKey:        state.StrategyKey,
+		Fee:                state.Fee,
+		LotStep:            state.LotStep,
+		BreakEvenArmed:     state.BreakEvenArmed,
+		PartialProfitTaken: state.PartialProfitTaken,
+		PartialOrderID:     state.PartialOrderID,
+		MaxBidPrice:        state.MaxBidPrice,
+		MinBidPrice:        state.MinBidPrice,
+		UpdatedAt:          state.UpdatedAt,
+	}); err != nil {
+		return wrap.Errorf("save exit state for trade %d: %w", state.TradeID, err)
+	}
+	return nil
+}
+
+func (u StateRepository) CreateGrid(ctx context.Context, grid repo.Grid) (*repo.Grid, error) {
+	db := palisade_database.New(u.Postgree)
+	row, err := db.CreateGrid(ctx, palisade_database.CreateGridParams{
+		Symbol:          grid.Symbol,
+		SupportPrice:    grid.SupportPrice,
+		ResistancePrice: grid.ResistancePrice,
+		Levels:          grid.Levels,
+		QuotePerLevel:   grid.QuotePerLevel,
+		Status:          grid.Status,
+		CreatedAt:       grid.CreatedAt,
+	})
+	if err != nil {
+		return nil, wrap.Errorf("create grid for %s: %w", grid.Symbol, err)
+	}
+	result := mapGridToDomain(row)
+	return &result, nil
+}
+
+func (u StateRepository) ListActiveGrids(ctx context.Context) ([]repo.Grid, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListActiveGrids(ctx)
+	if err != nil {
+		return nil, wrap.Errorf("list active grids: %w", err)
+	}
+	result := make([]repo.Grid, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, mapGridToDomain(row))
+	}
+	return result, nil
+}
+
+func (u StateRepository) UpdateGridStatus(ctx context.Context, id int, status, stopReason string) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.UpdateGridStatus(ctx, palisade_database.UpdateGridStatusParams{
+		ID:         id,
+		Status:     status,
+		StopReason: stopReason,
+	}); err != nil {
+		return wrap.Errorf("update grid %d: %w", id, err)
+	}
+	return nil
+}
+
+// SaveGridLevel создаёт уровень сетки или обновляет его состояние; цены и
+// объём уровня после создания не меняются.
+func (u StateRepository) SaveGridLevel(ctx context.Context, level repo.GridLevel) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.UpsertGridLevel(ctx, palisade_database.UpsertGridLevelParams{
+		GridID:        level.GridID,
+		Level:         level.Level,
+		BuyPrice:      level.BuyPrice,
+		SellPrice:     level.SellPrice,
+		Quantity:      level.Quantity,
+		Status:        level.Status,
+		OrderID:       level.OrderID,
+		ClientOrderID: level.ClientOrderID,
+		HeldQuantity:  level.HeldQuantity,
+		HeldCost:      level.HeldCost,
+		Cycles:        level.Cycles,
+		RealizedPnl:   level.RealizedPnl,
+		UpdatedAt:     level.UpdatedAt,
+	}); err != nil {
+		return wrap.Errorf("save grid %d level %d: %w", level.GridID, level.Level, err)
+	}
+	return nil
+}
+
+func (u StateRepository) ListGridLevels(ctx context.Context, gridID int) ([]repo.GridLevel, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListGridLevels(ctx, gridID)
+	if err != nil {
+		return nil, wrap.Errorf("list grid %d levels: %w", gridID, err)
+	}
+	result := make([]repo.GridLevel, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, repo.GridLevel{
+			GridID:        row.GridID,
+			Level:         row.Level,
+			BuyPrice:      row.BuyPrice,
+			SellPrice:     row.SellPrice,
+			Quantity:      row.Quantity,
+			Status:        row.Status,
+			OrderID:       row.OrderID,
+			ClientOrderID: row.ClientOrderID,
+			HeldQuantity:  row.HeldQuantity,
+			HeldCost:      row.HeldCost,
+			Cycles:        row.Cycles,
+			RealizedPnl:   row.RealizedPnl,
+			UpdatedAt:     row.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+func mapGridToDomain(row palisade_database.PalisadeGrid) repo.Grid {
+	return repo.Grid{
+		ID:              row.ID,
+		Symbol:          row.Symbol,
+		SupportPrice:    row.SupportPrice,
+		ResistancePrice: row.ResistancePrice,
+		Levels:          row.Levels,
+		QuotePerLevel:   row.QuotePerLevel,
+		Status:          row.Status,
+		StopReason:      row.StopReason,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+}
+
+func (u StateRepository) CreateSwapRun(ctx context.Context, run repo.SwapRun) (*repo.SwapRun, error) {
+	db := palisade_database.New(u.Postgree)
+	row, err := db.CreateSwapRun(ctx, palisade_database.CreateSwapRunParams{
+		Path:                       run.Path,
+		StartAsset:                 run.StartAsset,
+		Amount:                     run.Amount,
+		ExpectedProfitPercent:      run.ExpectedProfitPercent,
+		ExpectedDepthProfitPercent: run.ExpectedDepthProfitPercent,
+		Status:                     run.Status,
+		StartedAt:                  run.StartedAt,
+		Mode:                       run.Mode,
+	})
+	if err != nil {
+		return nil, wrap.Errorf("create swap run %s: %w", run.Path, err)
+	}
+	result := mapSwapRunToDomain(row)
+	return &result, nil
+}
+
+func (u StateRepository) FinishSwapRun(ctx context.Context, run repo.SwapRun) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.FinishSwapRun(ctx, palisade_database.FinishSwapRunParams{
+		ID:                    run.ID,
+		Status:                run.Status,
+		UnwindOutcome:         run.UnwindOutcome,
+		FinalAmount:           run.FinalAmount,
+		RealizedProfitPercent: run.RealizedProfitPercent,
+		LastError:             run.LastError,
+		FinishedAt:            run.FinishedAt,
+		LiquidatedAmount:      run.LiquidatedAmount,
+	}); err != nil {
+		return wrap.Errorf("finish swap run %d: %w", run.ID, err)
+	}
+	return nil
+}
+
+// SaveSwapLeg создаёт ногу при выставлении ордера и обновляет её исполнение;
+// ожидания ноги после создания не меняются.
+func (u StateRepository) SaveSwapLeg(ctx context.Context, leg repo.SwapLeg) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.UpsertSwapLeg(ctx, palisade_database.UpsertSwapLegParams{
+		RunID:              leg.RunID,
+		Leg:                leg.Leg,
+		Symbol:             leg.Symbol,
+		Side:               leg.Side,
+		FromAsset:          leg.FromAsset,
+		ToAsset:            leg.ToAsset,
+		ExpectedPrice:      leg.ExpectedPrice,
+		ExpectedDepthPrice: leg.ExpectedDepthPrice,
+		Quantity:           leg.Quantity,
+		ClientOrderID:      leg.ClientOrderID,
+		OrderID:            leg.OrderID,
+		Status:             leg.Status,
+		ExecutedQuantity:   leg.ExecutedQuantity,
+		CumulativeQuoteQty: leg.CumulativeQuoteQty,
+		RealizedPrice:      leg.RealizedPrice,
+		Fee:                leg.Fee,
+		PlacedAt:           leg.PlacedAt,
+		FinishedAt:         leg.FinishedAt,
+		Liquidation:        leg.Liquidation,
+	}); err != nil {
+		return wrap.Errorf("save swap run %d leg %d: %w", leg.RunID, leg.Leg, err)
+	}
+	return nil
+}
+
+func (u StateRepository) ListSwapRunsSince(ctx context.Context, since time.Time) ([]repo.SwapRun, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListSwapRunsSince(ctx, since)
+	if err != nil {
+		return nil, wrap.Errorf("list swap runs since %s: %w", since.Format(time.RFC3339), err)
+	}
+	result := make([]repo.SwapRun, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, mapSwapRunToDomain(row))
+	}
+	return result, nil
+}
+
+func (u StateRepository) ListSwapLegsSince(ctx context.Context, since time.Time) ([]repo.SwapLeg, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListSwapLegsSince(ctx, since)
+	if err != nil {
+		return nil, wrap.Errorf("list swap legs since %s: %w", since.Format(time.RFC3339), err)
+	}
+	result := make([]repo.SwapLeg, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, repo.SwapLeg{
+			RunID:              row.RunID,
+			Leg:                row.Leg,
+			Symbol:             row.Symbol,
+			Side:               row.Side,
+			FromAsset:          row.FromAsset,
+			ToAsset:            row.ToAsset,
+			ExpectedPrice:      row.ExpectedPrice,
+			ExpectedDepthPrice: row.ExpectedDepthPrice,
+			Quantity:           row.Quantity,
+			ClientOrderID:      row.ClientOrderID,
+			OrderID:            row.OrderID,
+			Status:             row.Status,
+			ExecutedQuantity:   row.ExecutedQuantity,
+			CumulativeQuoteQty: row.CumulativeQuoteQty,
+			RealizedPrice:      row.RealizedPrice,
+			Fee:                row.Fee,
+			PlacedAt:           row.PlacedAt,
+			FinishedAt:         row.FinishedAt,
+			Liquidation:        row.Liquidation,
+		})
+	}
+	return result, nil
+}
+
+func (u StateRepository) SaveSwapOpportunity(ctx context.Context, opp repo.SwapOpportunity) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.CreateSwapOpportunity(ctx, palisade_database.CreateSwapOpportunityParams{
+		Path:               opp.Path,
+		StartAsset:         opp.StartAsset,
+		Legs:               opp.Legs,
+		ThresholdPercent:   opp.ThresholdPercent,
+		FirstSeenAt:        opp.FirstSeenAt,
+		LastSeenAt:         opp.LastSeenAt,
+		ClosedAt:           opp.ClosedAt,
+		Observations:       opp.Observations,
+		FirstProfitPercent: opp.FirstProfitPercent,
+		PeakProfitPercent:  opp.PeakProfitPercent,
+		PeakAt:             opp.PeakAt,
+		PeakDepthAmount:    opp.PeakDepthAmount,
+		MaxDepthAmount:     opp.MaxDepthAmount,
+	}); err != nil {
+		return wrap.Errorf("save swap opportunity %s: %w", opp.Path, err)
+	}
+	return nil
+}
+
+func (u StateRepository) ListSwapOpportunitiesSince(ctx context.Context, since time.Time) ([]repo.SwapOpportunity, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListSwapOpportunitiesSince(ctx, since)
+	if err != nil {
+		return nil, wrap.Errorf("list swap opportunities since %s: %w", since.Format(time.RFC3339), err)
+	}
+	result := make([]repo.SwapOpportunity, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, repo.SwapOpportunity{
+			ID:                 row.ID,
+			Path:               row.Path,
+			StartAsset:         row.StartAsset,
+			Legs:               row.Legs,
+			ThresholdPercent:   row.ThresholdPercent,
+			FirstSeenAt:        row.FirstSeenAt,
+			LastSeenAt:         row.LastSeenAt,
+			ClosedAt:           row.ClosedAt,
+			Observations:       row.Observations,
+			FirstProfitPercent: row.FirstProfitPercent,
+			PeakProfitPercent:  row.PeakProfitPercent,
+			PeakAt:             row.PeakAt,
+			PeakDepthAmount:    row.PeakDepthAmount,
+			MaxDepthAmount:     row.MaxDepthAmount,
+		})
+	}
+	return result, nil
+}
+
+func (u StateRepository) ListSwapInventory(ctx context.Context) ([]repo.SwapInventory, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListSwapInventory(ctx)
+	if err != nil {
+		return nil, wrap.Errorf("list swap inventory: %w", err)
+	}
+	result := make([]repo.SwapInventory, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, repo.SwapInventory{
+			Asset:    row.Asset,
+			Quantity: row.Quantity,
+			Cost:     row.Cost,
+		})
+	}
+	return result, nil
+}
+
+func (u StateRepository) SaveSwapInventory(ctx context.Context, item repo.SwapInventory) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.UpsertSwapInventory(ctx, palisade_database.UpsertSwapInventoryParams{
+		Asset:    item.Asset,
+		Quantity: item.Quantity,
+		Cost:     item.Cost,
+	}); err != nil {
+		return wrap.Errorf("save swap inventory %s: %w", item.Asset, err)
+	}
+	return nil
+}
+
+func mapSwapRunToDomain(row palisade_database.SwapRun) repo.SwapRun {
+	return repo.SwapRun{
+		ID:                         row.ID,
+		Path:                       row.Path,
+		StartAsset:                 row.StartAsset,
+		Mode:                       row.Mode,
+		Amount:                     row.Amount,
+		ExpectedProfitPercent:      row.ExpectedProfitPercent,
+		ExpectedDepthProfitPercent: row.ExpectedDepthProfitPercent,
+		Status:                     row.Status,
+		UnwindOutcome:              row.UnwindOutcome,
+		FinalAmount:                row.FinalAmount,
+		RealizedProfitPercent:      row.RealizedProfitPercent,
+		LiquidatedAmount:           row.LiquidatedAmount,
+		LastError:                  row.LastError,
+		StartedAt:                  row.StartedAt,
+		FinishedAt:                 row.FinishedAt,
 	}
 }
 
@@ -971,6 +1515,8 @@ func (u StateRepository) CreatePaperTrade(ctx context.Context, trade repo.PaperT
 		ExitReason:         trade.ExitReason,
 		LastPrice:          trade.LastPrice,
 		UpdatedAt:          trade.UpdatedAt,
+		ConfigVersion:      trade.ConfigVersion,
+		StrategyKey:        trade.StrategyKey,
 	})
 	if err != nil {
 		return nil, wrap.Errorf("create paper trade %s: %w", trade.Symbol, err)
@@ -1013,18 +1559,160 @@ func (u StateRepository) GetPaperTradeStats(ctx context.Context, strategyVersion
 		return repo.PaperTradeStats{}, wrap.Errorf("get paper trade stats: %w", err)
 	}
 	return repo.PaperTradeStats{
-		Total:      row.Total,
-		Closed:     row.Closed,
-		Canceled:   row.Canceled,
-		Open:       row.Open,
-		TotalPnL:   row.TotalPnl,
-		OpenPnL:    row.OpenPnl,
-		AveragePnL: row.AveragePnl,
-		Wins:       row.Wins,
-		Losses:     row.Losses,
+		Total:       row.Total,
+		Closed:      row.Closed,
+		Canceled:    row.Canceled,
+		Open:        row.Open,
+		TotalPnL:    row.TotalPnl,
+		OpenPnL:     row.OpenPnl,
+		AveragePnL:  row.AveragePnl,
+		Wins:        row.Wins,
+		Losses:      row.Losses,
+		AverageWin:  row.AverageWin,
+		AverageLoss: row.AverageLoss,
+	}, nil
+}
+
+func (u StateRepository) SaveBacktestRun(ctx context.Context, run repo.BacktestRun) (*repo.BacktestRun, error) {
+	db := palisade_database.New(u.Postgree)
+	row, err := db.CreateBacktestRun(ctx, palisade_database.CreateBacktestRunParams{
+		CreatedAt:       run.CreatedAt,
+		StrategyVersion: run.StrategyVersion,
+		Symbols:         run.Symbols,
+		PeriodFrom:      run.PeriodFrom,
+		PeriodTo:        run.PeriodTo,
+		Spread:          run.Spread,
+		Bars:            run.Bars,
+		Signals:         run.Signals,
+		Trades:          run.Trades,
+		Canceled:        run.Canceled,
+		Wins:            run.Wins,
+		Losses:          run.Losses,
+		WinRate:         run.WinRate,
+		TotalPnl:        run.TotalPnL,
+		MaxDrawdown:     run.MaxDrawdown,
+		ExitReasons:     run.ExitReasons,
+	})
+	if err != nil {
+		return nil, wrap.Errorf("create backtest run v%d: %w", run.StrategyVersion, err)
+	}
+	return &repo.BacktestRun{
+		ID:              row.ID,
+		CreatedAt:       row.CreatedAt,
+		StrategyVersion: row.StrategyVersion,
+		Symbols:         row.Symbols,
+		PeriodFrom:      row.PeriodFrom,
+		PeriodTo:        row.PeriodTo,
+		Spread:          row.Spread,
+		Bars:            row.Bars,
+		Signals:         row.Signals,
+		Trades:          row.Trades,
+		Canceled:        row.Canceled,
+		Wins:            row.Wins,
+		Losses:          row.Losses,
+		WinRate:         row.WinRate,
+		TotalPnL:        row.TotalPnl,
+		MaxDrawdown:     row.MaxDrawdown,
+		ExitReasons:     row.ExitReasons,
 	}, nil
 }
 
+func (u StateRepository) SaveDaemonJobRun(ctx context.Context, run repo.DaemonJobRun) error {
+	params := palisade_database.SaveDaemonJobRunParams{
+		Name:           run.Name,
+		LastStartedAt:  run.StartedAt,
+		LastFinishedAt: run.FinishedAt,
+		LastDurationMs: int(run.FinishedAt.Sub(run.StartedAt).Milliseconds()),
+	}
+	if run.Error == "" {
+		params.LastSuccessAt = &run.FinishedAt
+	} else {
+		params.LastError = &run.Error
+		params.Failures = 1
+	}
+	db := palisade_database.New(u.Postgree)
+	if err := db.SaveDaemonJobRun(ctx, params); err != nil {
+		return wrap.Errorf("save daemon job run %s: %w", run.Name, err)
+	}
+	return nil
+}
+
+func (u StateRepository) SaveStrategyConfig(ctx context.Context, version string, config []byte, createdAt time.Time) error {
+	db := palisade_database.New(u.Postgree)
+	err := db.SaveStrategyConfig(ctx, palisade_database.SaveStrategyConfigParams{
+		Version:   version,
+		Config:    config,
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return wrap.Errorf("save strategy config %s: %w", version, err)
+	}
+	return nil
+}
+
+func (u StateRepository) ListClosedTradesSince(ctx context.Context, since time.Time) ([]repo.ClosedTrade, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListClosedTradesSince(ctx, since)
+	if err != nil {
+		return nil, wrap.Errorf("list closed trades since %s: %w", since.Format(time.RFC3339), err)
+	}
+	result := make([]repo.ClosedTrade, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, mapClosedTrade(palisade_database.ListRecentClosedTradesRow(row)))
+	}
+	return result, nil
+}
+
+func (u StateRepository) ListRecentClosedTrades(ctx context.Context, limit int) ([]repo.ClosedTrade, error) {
+	db := palisade_database.New(u.Postgree)
+	rows, err := db.ListRecentClosedTrades(ctx, int32(limit))
+	if err != nil {
+		return nil, wrap.Errorf("list recent closed trades: %w", err)
+	}
+	result := make([]repo.ClosedTrade, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, mapClosedTrade(row))
+	}
+	return result, nil
+}
+
+func mapClosedTrade(row palisade_database.ListRecentClosedTradesRow) repo.ClosedTrade {
+	trade := repo.ClosedTrade{
+		Source:   row.Source,
+		ID:       row.ID,
+		Symbol:   row.Symbol,
+		BuyPrice: row.BuyPrice,
+		Amount:   row.Amount,
+	}
+	if row.SellPrice != nil {
+		trade.SellPrice = *row.SellPrice
+	}
+	if row.CloseDate != nil {
+		trade.CloseDate = *row.CloseDate
+	}
+	return trade
+}
+
+func (u StateRepository) GetRiskAlertSentAt(ctx context.Context, reason string) (*time.Time, error) {
+	db := palisade_database.New(u.Postgree)
+	sentAt, err := db.GetRiskAlertSentAt(ctx, reason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, wrap.Errorf("get risk alert %s: %w", reason, err)
+	}
+	return &sentAt, nil
+}
+
+func (u StateRepository) SaveRiskAlert(ctx context.Context, reason string, sentAt time.Time) error {
+	db := palisade_database.New(u.Postgree)
+	if err := db.SaveRiskAlert(ctx, palisade_database.SaveRiskAlertParams{Reason: reason, SentAt: sentAt}); err != nil {
+		return wrap.Errorf("save risk alert %s: %w", reason, err)
+	}
+	return nil
+}
+
 func mapPaperTradeToDomain(row palisade_database.PaperTrade) *repo.PaperTrade {
 	return &repo.PaperTrade{
 		ID:                 row.ID,
@@ -1055,6 +1743,8 @@ func mapPaperTradeToDomain(row palisade_database.PaperTrade) *repo.PaperTrade {
 		ExitReason:         row.ExitReason,
 		LastPrice:          row.LastPrice,
 		UpdatedAt:          row.UpdatedAt,
+		ConfigVersion:      row.ConfigVersion,
+		StrategyKey:        row.StrategyKey,
 	}
 }
 
@@ -63,6 +63,33 @@ func (r TrendRepository) UpsertMinuteBar(ctx context.Context, bar repo.MarketMin
 	})
 }
 
+// UpsertMinuteBars сохраняет пачку минутных свечей одной транзакцией.
+func (r TrendRepository) UpsertMinuteBars(ctx context.Context, bars []repo.MarketMinuteBar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+	tx, err := r.Postgree.Begin(ctx)
+	if err != nil {
+		return wrap.Errorf("begin minute bars tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	db := palisade_database.New(r.Postgree).WithTx(tx)
+	for _, bar := range bars {
+		if err := db.UpsertMarketMinuteBar(ctx, palisade_database.UpsertMarketMinuteBarParams{
+			Symbol:   bar.Symbol,
+			OpenTime: bar.OpenTime,
+			Open:     bar.Open,
+			High:     bar.High,
+			Low:      bar.Low,
+			Close:    bar.Close,
+		}); err != nil {
+			return wrap.Errorf("upsert minute bar %s: %w", bar.Symbol, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
 func (r TrendRepository) GetLastMinuteOpenTime(ctx context.Context, symbol string) (*time.Time, error) {
 	db := palisade_database.New(r.Postgree)
 	t, err := db.GetLastMinuteBarOpenTime(ctx, symbol)
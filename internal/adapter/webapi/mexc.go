@@ -3,16 +3,15 @@ package webapi
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"mexc-sdk/mexcsdk"
+	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/drybin/palisade/internal/app/cli/config"
 	"github.com/drybin/palisade/internal/domain/enum"
 	"github.com/drybin/palisade/internal/domain/model"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
 	"github.com/drybin/palisade/pkg/wrap"
 	"github.com/go-resty/resty/v2"
 )
@@ -20,16 +19,23 @@ import (
 // const mexc_spot_account_info_url = "/api/v3/account"
 // const mexc_new_order_url = "/api/v3/order"
 
+var _ repo.IMexcRepository = (*MexcWebapi)(nil)
+
+// mexcOrderLookupTimeout — сколько ждать поиска ордера по clientOrderId
+// после потерянного ответа на NewOrder.
+const mexcOrderLookupTimeout = 10 * time.Second
+
 type MexcWebapi struct {
 	client       *resty.Client
 	publicClient *resty.Client
-	spot         mexcsdk.Spot
+	spot         *MexcSpotClient
+	guard        *mexcRequestGuard
 	config       config.MexcConfig
 }
 
 func NewMexcWebapi(
 	client *resty.Client,
-	spot mexcsdk.Spot,
+	spot *MexcSpotClient,
 	config config.MexcConfig,
 ) *MexcWebapi {
 	// Создаем отдельный клиент для публичных запросов без заголовка X-MEXC-APIKEY
@@ -39,20 +45,45 @@ func NewMexcWebapi(
 	publicClient.SetTimeout(20 * time.Second)
 	// Не устанавливаем X-MEXC-APIKEY для публичных запросов
 
+	// Публичные и подписанные запросы идут на один хост и расходуют общий
+	// лимит по IP.
+	guard := newMexcRequestGuard(config)
+	if spot != nil {
+		guard = spot.guard
+	}
+
 	return &MexcWebapi{
 		client:       client,
 		publicClient: publicClient,
 		spot:         spot,
+		guard:        guard,
 		config:       config,
 	}
 }
 
+// getPublic выполняет публичный GET через лимитер и политику повторов;
+// ответ с ошибкой возвращается как *mexc.APIError.
+func (m *MexcWebapi) getPublic(ctx context.Context, path string, params map[string]string) (*resty.Response, error) {
+	res, err := m.guard.do(ctx, http.MethodGet, path, func() (*resty.Response, error) {
+		return m.publicClient.R().SetContext(ctx).SetQueryParams(params).Get(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.IsError() {
+		return nil, parseMexcAPIError(res, http.MethodGet+" "+path)
+	}
+	return res, nil
+}
+
 func (m *MexcWebapi) GetBalance(ctx context.Context) (*mexc.AccountInfo, error) {
-	res := m.spot.AccountInfo()
-	bytes, _ := json.Marshal(res)
+	bytes, err := m.spot.AccountInfo(ctx)
+	if err != nil {
+		return nil, wrap.Errorf("failed to get account info: %w", err)
+	}
 
 	spotAccountInfo := mexc.SpotAccountInfo{}
-	err := json.Unmarshal(bytes, &spotAccountInfo)
+	err = json.Unmarshal(bytes, &spotAccountInfo)
 	if err != nil {
 		return nil, wrap.Errorf("failed to unmarshal accountInfo info: %w", err)
 	}
@@ -62,11 +93,13 @@ func (m *MexcWebapi) GetBalance(ctx context.Context) (*mexc.AccountInfo, error)
 }
 
 func (m *MexcWebapi) GetAllTickerPrices(ctx context.Context) (*mexc.TickersWithPrice, error) {
-	res := m.spot.TickerPrice(nil)
-	bytes, _ := json.Marshal(res)
+	res, err := m.getPublic(ctx, "/api/v3/ticker/price", nil)
+	if err != nil {
+		return nil, wrap.Errorf("failed to get ticker prices: %w", err)
+	}
 
 	result := mexc.TickersWithPrice{}
-	err := json.Unmarshal(bytes, &result)
+	err = json.Unmarshal(res.Body(), &result)
 	if err != nil {
 		return nil, wrap.Errorf("failed to unmarshal ticker with price info: %w", err)
 	}
@@ -76,13 +109,10 @@ func (m *MexcWebapi) GetAllTickerPrices(ctx context.Context) (*mexc.TickersWithP
 
 // GetAllBookTickers возвращает лучший bid/ask по всем символам (GET /api/v3/ticker/bookTicker).
 func (m *MexcWebapi) GetAllBookTickers(ctx context.Context) (*mexc.BookTickers, error) {
-	res, err := m.publicClient.R().SetContext(ctx).Get("/api/v3/ticker/bookTicker")
+	res, err := m.getPublic(ctx, "/api/v3/ticker/bookTicker", nil)
 	if err != nil {
 		return nil, wrap.Errorf("failed to get book tickers: %w", err)
 	}
-	if res.IsError() {
-		return nil, wrap.Errorf("failed to get book tickers, status: %d, body: %s", res.StatusCode(), string(res.Body()))
-	}
 
 	var arr mexc.BookTickers
 	if err := json.Unmarshal(res.Body(), &arr); err == nil && len(arr) > 0 {
@@ -101,13 +131,10 @@ func (m *MexcWebapi) GetAllBookTickers(ctx context.Context) (*mexc.BookTickers,
 }
 
 func (m *MexcWebapi) GetAll24hTickers(ctx context.Context) (*mexc.Tickers24h, error) {
-	res, err := m.publicClient.R().SetContext(ctx).Get("/api/v3/ticker/24hr")
+	res, err := m.getPublic(ctx, "/api/v3/ticker/24hr", nil)
 	if err != nil {
 		return nil, wrap.Errorf("failed to get 24h tickers: %w", err)
 	}
-	if res.IsError() {
-		return nil, wrap.Errorf("failed to get 24h tickers, status: %d, body: %s", res.StatusCode(), string(res.Body()))
-	}
 
 	var result mexc.Tickers24h
 	if err := json.Unmarshal(res.Body(), &result); err != nil {
@@ -117,14 +144,13 @@ func (m *MexcWebapi) GetAll24hTickers(ctx context.Context) (*mexc.Tickers24h, er
 }
 
 func (m *MexcWebapi) GetSymbolInfo(ctx context.Context, symbol string) (*mexc.SymbolInfo, error) {
-	options := map[string]string{
-		"symbol": symbol,
+	res, err := m.getPublic(ctx, "/api/v3/exchangeInfo", map[string]string{"symbol": symbol})
+	if err != nil {
+		return nil, wrap.Errorf("failed to get symbol info: %w", err)
 	}
-	res := m.spot.ExchangeInfo(options)
-	bytes, _ := json.Marshal(res)
 
 	result := mexc.SymbolInfo{}
-	err := json.Unmarshal(bytes, &result)
+	err = json.Unmarshal(res.Body(), &result)
 	if err != nil {
 		return nil, wrap.Errorf("failed to unmarshal symbol info: %w", err)
 	}
@@ -134,13 +160,10 @@ func (m *MexcWebapi) GetSymbolInfo(ctx context.Context, symbol string) (*mexc.Sy
 
 // GetExchangeInfoAll возвращает exchangeInfo по всем символам (без фильтра symbol).
 func (m *MexcWebapi) GetExchangeInfoAll(ctx context.Context) (*mexc.SymbolInfo, error) {
-	res, err := m.publicClient.R().SetContext(ctx).Get("/api/v3/exchangeInfo")
+	res, err := m.getPublic(ctx, "/api/v3/exchangeInfo", nil)
 	if err != nil {
 		return nil, wrap.Errorf("failed to get exchange info: %w", err)
 	}
-	if res.IsError() {
-		return nil, wrap.Errorf("failed to get exchange info, status: %d, body: %s", res.StatusCode(), string(res.Body()))
-	}
 
 	result := mexc.SymbolInfo{}
 	err = json.Unmarshal(res.Body(), &result)
@@ -151,26 +174,34 @@ func (m *MexcWebapi) GetExchangeInfoAll(ctx context.Context) (*mexc.SymbolInfo,
 	return &result, nil
 }
 
+// NewOrder выставляет ордер. POST /api/v3/order не повторяется, поэтому
+// при ответе, по которому непонятно, принят ли ордер (сеть, таймаут, 5xx),
+// ордер ищется по newClientOrderId: найденный возвращается как выставленный,
+// иначе возвращается исходная ошибка и ордер остаётся за reconcile-orders.
 func (m *MexcWebapi) NewOrder(
+	ctx context.Context,
 	orderParams model.OrderParams,
 ) (*mexc.PlaceOrderResult, error) {
 
-	options := map[string]string{
+	params := map[string]string{
+		"symbol":           orderParams.Symbol,
+		"side":             orderParams.Side.String(),
+		"type":             orderParams.OrderType.String(),
 		"price":            orderParams.GetPrice(),
 		"quantity":         orderParams.GetQuantity(),
 		"newClientOrderId": orderParams.NewClientOrderId,
 	}
 
-	resp := m.spot.NewOrder(orderParams.GetSymbol(), orderParams.GetSide(), orderParams.GetOrderType(), options)
-
-	if resp == nil {
-		return nil, wrap.Errorf("failed to place order on mexc: %v", resp)
+	bytes, err := m.spot.NewOrder(ctx, params)
+	if err != nil {
+		if placed := m.findAmbiguousOrder(ctx, orderParams, err); placed != nil {
+			return placed, nil
+		}
+		return nil, wrap.Errorf("failed to place order on mexc: %w", err)
 	}
 
-	bytes, _ := json.Marshal(resp)
-
 	result := mexc.PlaceOrderResult{}
-	err := json.Unmarshal(bytes, &result)
+	err = json.Unmarshal(bytes, &result)
 	if err != nil {
 		return nil, wrap.Errorf("failed to unmarshal order info: %v", err)
 	}
@@ -179,6 +210,7 @@ func (m *MexcWebapi) NewOrder(
 }
 
 func (m *MexcWebapi) CancelOrder(
+	ctx context.Context,
 	symbol string,
 	orderId string,
 ) (*mexc.CancelOrderResponse, error) {
@@ -189,15 +221,9 @@ func (m *MexcWebapi) CancelOrder(
 	}
 
 	// Вызов cancel
-	resp := m.spot.CancelOrder(&symbol, params)
-
-	if resp == nil {
-		return nil, wrap.Errorf("failed to cancel order: response is nil")
-	}
-
-	bytes, err := json.Marshal(resp)
+	bytes, err := m.spot.CancelOrder(ctx, params)
 	if err != nil {
-		return nil, wrap.Errorf("failed to marshal cancel order response: %w", err)
+		return nil, wrap.Errorf("failed to cancel order: %w", err)
 	}
 
 	result, err := mexc.ParseCancelOrderResponseFromJSON(bytes)
@@ -213,15 +239,9 @@ func (m *MexcWebapi) GetOpenOrders(
 	orderParams model.OrderParams,
 ) (*mexc.OpenOrders, error) {
 
-	resp := m.spot.OpenOrders(orderParams.GetSymbol())
-
-	if resp == nil {
-		return nil, wrap.Errorf("failed to get open orders: %v", resp)
-	}
-
-	bytes, err := json.Marshal(resp)
+	bytes, err := m.spot.OpenOrders(ctx, orderParams.Symbol)
 	if err != nil {
-		return nil, wrap.Errorf("failed to marshal open orders response: %v", err)
+		return nil, wrap.Errorf("failed to get open orders: %w", err)
 	}
 
 	result, err := mexc.ParseOpenOrdersFromJSON(bytes)
@@ -233,66 +253,62 @@ func (m *MexcWebapi) GetOpenOrders(
 }
 
 func (m *MexcWebapi) GetOrderQuery(
+	ctx context.Context,
 	symbol string,
 	orderId string,
 ) (*mexc.QueryOrderResult, error) {
-	return m.queryOrder(symbol, map[string]string{"orderId": orderId})
+	return m.queryOrder(ctx, symbol, map[string]string{"orderId": orderId})
 }
 
 // GetOrderQueryByClientID ищет ордер по clientOrderId. Это нужно для
 // восстановления после таймаута, когда биржа могла создать ордер, но ответ
 // с exchange order id не дошёл до приложения.
 func (m *MexcWebapi) GetOrderQueryByClientID(
+	ctx context.Context,
 	symbol string,
 	clientOrderID string,
 ) (*mexc.QueryOrderResult, error) {
-	return m.queryOrder(symbol, map[string]string{"origClientOrderId": clientOrderID})
+	return m.queryOrder(ctx, symbol, map[string]string{"origClientOrderId": clientOrderID})
 }
 
-func (m *MexcWebapi) queryOrder(symbol string, params map[string]string) (*mexc.QueryOrderResult, error) {
-
-	// Используем recover для перехвата паники от SDK
-	var resp interface{}
-	var panicErr interface{}
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				panicErr = r
-			}
-		}()
-		resp = m.spot.QueryOrder(&symbol, params)
-	}()
-
-	// Если была паника, проверяем, является ли она ошибкой "Order does not exist"
-	if panicErr != nil {
-		panicStr := fmt.Sprintf("%v", panicErr)
-		if strings.Contains(panicStr, "Order does not exist") || strings.Contains(panicStr, "code\":-2013") {
-			// Ордер не существует, возвращаем nil без ошибки
-			return nil, nil
-		}
-		// Другая ошибка, пробрасываем панику дальше
-		panic(panicErr)
-	}
-
-	if resp == nil {
-		return nil, wrap.Errorf("failed to query order: response is nil")
+// findAmbiguousOrder ищет по clientOrderId ордер, ответ на который потерян.
+// Ответ API со статусом ниже 500 (отказ, 429, recvWindow) значит, что ордера
+// нет, и поиск не выполняется. Поиск идёт и после отмены ctx: заявка могла
+// уйти, а её id нужен вызывающему.
+func (m *MexcWebapi) findAmbiguousOrder(ctx context.Context, orderParams model.OrderParams, placeErr error) *mexc.PlaceOrderResult {
+	if orderParams.NewClientOrderId == "" {
+		return nil
+	}
+	if apiErr, ok := mexc.AsAPIError(placeErr); ok && apiErr.HTTPStatus < http.StatusInternalServerError {
+		return nil
+	}
+	lookupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mexcOrderLookupTimeout)
+	defer cancel()
+	found, err := m.GetOrderQueryByClientID(lookupCtx, orderParams.Symbol, orderParams.NewClientOrderId)
+	if err != nil || found == nil {
+		return nil
+	}
+	return &mexc.PlaceOrderResult{
+		Symbol:       found.Symbol,
+		OrderID:      found.OrderID,
+		Price:        found.Price,
+		OrigQty:      found.OrigQty,
+		Type:         found.Type,
+		StpMode:      found.StpMode,
+		Side:         found.Side,
+		TransactTime: found.Time,
 	}
+}
 
-	bytes, err := json.Marshal(resp)
+func (m *MexcWebapi) queryOrder(ctx context.Context, symbol string, params map[string]string) (*mexc.QueryOrderResult, error) {
+	params["symbol"] = symbol
+	bytes, err := m.spot.QueryOrder(ctx, params)
 	if err != nil {
-		return nil, wrap.Errorf("failed to marshal query order response: %w", err)
-	}
-
-	// Проверяем, является ли ответ ошибкой "Order does not exist"
-	var errorResp struct {
-		Code int    `json:"code"`
-		Msg  string `json:"msg"`
-	}
-	if err := json.Unmarshal(bytes, &errorResp); err == nil {
-		if errorResp.Code == -2013 || (errorResp.Msg != "" && (errorResp.Msg == "Order does not exist." || errorResp.Msg == "Order does not exist")) {
+		if mexc.IsOrderNotFound(err) {
 			// Ордер не существует, возвращаем nil без ошибки
 			return nil, nil
 		}
+		return nil, wrap.Errorf("failed to query order: %w", err)
 	}
 
 	result, err := mexc.ParseQueryOrderFromJSON(bytes)
@@ -318,7 +334,6 @@ func (m *MexcWebapi) GetKlinesPublic(
 	limit int,
 	startTimeMs *int64,
 ) (*mexc.Klines, error) {
-	_ = ctx
 	if limit <= 0 {
 		limit = 100
 	}
@@ -334,15 +349,10 @@ func (m *MexcWebapi) GetKlinesPublic(
 		params["startTime"] = strconv.FormatInt(*startTimeMs, 10)
 	}
 
-	res, err := m.publicClient.R().
-		SetQueryParams(params).
-		Get("/api/v3/klines")
+	res, err := m.getPublic(ctx, "/api/v3/klines", params)
 	if err != nil {
 		return nil, wrap.Errorf("failed to get klines: %w", err)
 	}
-	if res.IsError() {
-		return nil, wrap.Errorf("failed to get klines, status: %d, body: %s", res.StatusCode(), string(res.Body()))
-	}
 
 	klines, err := mexc.ParseKlinesFromJSON(res.Body())
 	if err != nil {
@@ -351,23 +361,70 @@ func (m *MexcWebapi) GetKlinesPublic(
 	return &klines, nil
 }
 
+// GetDepth — публичный стакан /api/v3/depth.
+func (m *MexcWebapi) GetDepth(ctx context.Context, symbol string, limit int) (*mexc.OrderBook, error) {
+	res, err := m.getPublic(ctx, "/api/v3/depth", map[string]string{
+		"symbol": symbol,
+		"limit":  strconv.Itoa(limit),
+	})
+	if err != nil {
+		return nil, wrap.Errorf("failed to get depth %s: %w", symbol, err)
+	}
+	book, err := mexc.ParseOrderBookFromJSON(res.Body())
+	if err != nil {
+		return nil, wrap.Errorf("failed to parse depth %s: %w", symbol, err)
+	}
+	return book, nil
+}
+
+// GetTrades — последние сделки /api/v3/trades.
+func (m *MexcWebapi) GetTrades(ctx context.Context, symbol string, limit int) ([]mexc.Trade, error) {
+	res, err := m.getPublic(ctx, "/api/v3/trades", map[string]string{
+		"symbol": symbol,
+		"limit":  strconv.Itoa(limit),
+	})
+	if err != nil {
+		return nil, wrap.Errorf("failed to get trades %s: %w", symbol, err)
+	}
+	trades, err := mexc.ParseTradesFromJSON(res.Body())
+	if err != nil {
+		return nil, wrap.Errorf("failed to parse trades %s: %w", symbol, err)
+	}
+	return trades, nil
+}
+
+// GetAggTrades — агрегированные сделки /api/v3/aggTrades.
+func (m *MexcWebapi) GetAggTrades(ctx context.Context, symbol string, startTimeMs, endTimeMs int64, limit int) ([]mexc.Trade, error) {
+	params := map[string]string{
+		"symbol": symbol,
+		"limit":  strconv.Itoa(limit),
+	}
+	if startTimeMs > 0 {
+		params["startTime"] = strconv.FormatInt(startTimeMs, 10)
+	}
+	if endTimeMs > 0 {
+		params["endTime"] = strconv.FormatInt(endTimeMs, 10)
+	}
+	res, err := m.getPublic(ctx, "/api/v3/aggTrades", params)
+	if err != nil {
+		return nil, wrap.Errorf("failed to get agg trades %s: %w", symbol, err)
+	}
+	trades, err := mexc.ParseAggTradesFromJSON(res.Body())
+	if err != nil {
+		return nil, wrap.Errorf("failed to parse agg trades %s: %w", symbol, err)
+	}
+	return trades, nil
+}
+
 func (m *MexcWebapi) GetAvgPrice(ctx context.Context, symbol string) (*mexc.AvgPrice, error) {
 	// Используем прямой HTTP запрос для публичного endpoint /api/v3/avgPrice
 	// Используем publicClient без заголовка X-MEXC-APIKEY
-	res, err := m.publicClient.R().
-		SetQueryParams(map[string]string{
-			"symbol": symbol,
-		}).
-		Get("/api/v3/avgPrice")
+	res, err := m.getPublic(ctx, "/api/v3/avgPrice", map[string]string{"symbol": symbol})
 
 	if err != nil {
 		return nil, wrap.Errorf("failed to get avg price: %w", err)
 	}
 
-	if res.IsError() {
-		return nil, wrap.Errorf("failed to get avg price, status: %d, body: %s", res.StatusCode(), string(res.Body()))
-	}
-
 	var response struct {
 		Mins  int    `json:"mins"`
 		Price string `json:"price"`
@@ -1,8 +1,10 @@
 package webapi
 
 import (
+    "context"
     "encoding/json"
     "fmt"
+    "net/http"
     
     "github.com/drybin/palisade/internal/app/cli/config"
     "github.com/drybin/palisade/internal/domain/model"
@@ -17,6 +19,7 @@ const mexc_price_url = "/api/market/v2/price?coinId=%s&symbolId=%s"
 
 type MexcV2Webapi struct {
     client *resty.Client
+    guard  *mexcRequestGuard
     config config.MexcConfig
 }
 
@@ -26,14 +29,21 @@ func NewMexcV2Webapi(
 ) *MexcV2Webapi {
     return &MexcV2Webapi{
         client: client,
+        guard:  newMexcRequestGuard(config),
         config: config,
     }
 }
 
-func (m *MexcV2Webapi) GetKLines(pair model.PairWithLevels, interval string, symbolId string) (*mexcV2.KlinesResult, error) {
-    res, err := m.client.R().Get(
-        m.config.BaseUrlV2 + fmt.Sprintf(mexc_klines_url, interval, symbolId),
-    )
+// get — GET к API v2 (отдельный хост BaseUrlV2, свой лимит) с повторами;
+// ожидание лимита и повторы прерываются отменой ctx.
+func (m *MexcV2Webapi) get(ctx context.Context, path string) (*resty.Response, error) {
+    return m.guard.do(ctx, http.MethodGet, path, func() (*resty.Response, error) {
+        return m.client.R().SetContext(ctx).Get(m.config.BaseUrlV2 + path)
+    })
+}
+
+func (m *MexcV2Webapi) GetKLines(ctx context.Context, pair model.PairWithLevels, interval string, symbolId string) (*mexcV2.KlinesResult, error) {
+    res, err := m.get(ctx, fmt.Sprintf(mexc_klines_url, interval, symbolId))
     if err != nil {
         return nil, wrap.Errorf("failed to get klines from api v2: %w", err)
     }
@@ -47,10 +57,8 @@ func (m *MexcV2Webapi) GetKLines(pair model.PairWithLevels, interval string, sym
     return &result, nil
 }
 
-func (m *MexcV2Webapi) GetPrice(coinId string, symbolId string) (*mexcV2.Price, error) {
-    res, err := m.client.R().Get(
-        m.config.BaseUrlV2 + fmt.Sprintf(mexc_price_url, coinId, symbolId),
-    )
+func (m *MexcV2Webapi) GetPrice(ctx context.Context, coinId string, symbolId string) (*mexcV2.Price, error) {
+    res, err := m.get(ctx, fmt.Sprintf(mexc_price_url, coinId, symbolId))
     if err != nil {
         return nil, wrap.Errorf("failed to get price from api v2: %w", err)
     }
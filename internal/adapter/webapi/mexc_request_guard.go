@@ -0,0 +1,194 @@
+package webapi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/go-resty/resty/v2"
+)
+
+// mexcEndpointWeights — вес запросов MEXC spot v3 по документации; запросы
+// без фильтра symbol (все пары) тяжелее. Не перечисленные эндпоинты весят 1.
+var mexcEndpointWeights = map[string]int{
+	"GET /api/v3/exchangeInfo": 10,
+	"GET /api/v3/ticker/24hr":  40,
+	"GET /api/v3/ticker/price": 2,
+	"GET /api/v3/trades":       5,
+	"GET /api/v3/account":      10,
+	"GET /api/v3/order":        2,
+	"GET /api/v3/openOrders":   3,
+}
+
+func mexcEndpointWeight(method, path string) int {
+	if weight, ok := mexcEndpointWeights[method+" "+path]; ok {
+		return weight
+	}
+	return 1
+}
+
+// mexcRequestGuard — общий для всех REST-запросов к одному хосту MEXC
+// лимитер по весам и политика повторов. Нулевые настройки отключают лимит и
+// повторы.
+type mexcRequestGuard struct {
+	limiter *mexcRateLimiter
+	retry   mexcRetryPolicy
+	sleep   func(context.Context, time.Duration) error
+}
+
+func newMexcRequestGuard(cfg config.MexcConfig) *mexcRequestGuard {
+	return &mexcRequestGuard{
+		limiter: newMexcRateLimiter(cfg.RateLimitWeight, cfg.RateLimitWindow, time.Now),
+		retry: mexcRetryPolicy{
+			attempts:  max(1, cfg.RetryAttempts),
+			baseDelay: cfg.RetryBaseDelay,
+			maxDelay:  cfg.RetryMaxDelay,
+		},
+		sleep: sleepContext,
+	}
+}
+
+// do выполняет send с ожиданием лимита перед каждой попыткой. send
+// вызывается заново на каждой попытке, поэтому подписанный запрос получает
+// свежий timestamp и подпись. Исчерпав попытки, do возвращает последний
+// ответ как есть — разбор статуса остаётся за вызывающим.
+func (g *mexcRequestGuard) do(ctx context.Context, method, path string, send func() (*resty.Response, error)) (*resty.Response, error) {
+	weight := mexcEndpointWeight(method, path)
+	for attempt := 1; ; attempt++ {
+		if err := g.limiter.Wait(ctx, weight); err != nil {
+			return nil, err
+		}
+		res, err := send()
+		if attempt >= g.retry.attempts || !g.retry.retryable(method, res, err) {
+			return res, err
+		}
+		if err := g.sleep(ctx, g.retry.delay(attempt, res)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// mexcRetryPolicy повторяет запрос, только если повтор не может создать
+// дубликат:
+//   - 429 — биржа отклонила запрос до обработки, повторять можно любой метод;
+//   - 5xx и сетевые ошибки — только GET и PUT. POST /api/v3/order при
+//     потерянном ответе не повторяется: заявка могла быть принята, её
+//     ищет по clientOrderId MexcWebapi.NewOrder, а не найденную —
+//     reconcile-orders.
+type mexcRetryPolicy struct {
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+func (p mexcRetryPolicy) retryable(method string, res *resty.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return idempotentMethod(method)
+	}
+	if res == nil {
+		return false
+	}
+	if res.StatusCode() == http.StatusTooManyRequests {
+		return true
+	}
+	return res.StatusCode() >= http.StatusInternalServerError && idempotentMethod(method)
+}
+
+// delay — Retry-After из ответа или экспоненциальная пауза baseDelay*2^(n-1),
+// ограниченная maxDelay.
+func (p mexcRetryPolicy) delay(attempt int, res *resty.Response) time.Duration {
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header().Get("Retry-After")); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	delay := time.Duration(float64(p.baseDelay) * math.Pow(2, float64(attempt-1)))
+	if p.maxDelay > 0 && delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+	return delay
+}
+
+func idempotentMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut
+}
+
+// mexcRateLimiter — token bucket по весам: ёмкость weight, полное
+// восстановление за window. nil-лимитер пропускает всё.
+type mexcRateLimiter struct {
+	mu       sync.Mutex
+	capacity float64
+	perSec   float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+func newMexcRateLimiter(weight int, window time.Duration, now func() time.Time) *mexcRateLimiter {
+	if weight <= 0 || window <= 0 {
+		return nil
+	}
+	return &mexcRateLimiter{
+		capacity: float64(weight),
+		perSec:   float64(weight) / window.Seconds(),
+		tokens:   float64(weight),
+		last:     now(),
+		now:      now,
+	}
+}
+
+// Wait блокирует, пока в корзине не наберётся weight токенов.
+func (l *mexcRateLimiter) Wait(ctx context.Context, weight int) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		wait := l.reserve(weight)
+		if wait <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve списывает weight токенов и возвращает 0 либо, если токенов не
+// хватает, время до их появления. Вес больше ёмкости ограничивается ёмкостью.
+func (l *mexcRateLimiter) reserve(weight int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = math.Min(l.capacity, l.tokens+elapsed*l.perSec)
+	}
+	l.last = now
+	need := math.Min(float64(weight), l.capacity)
+	if l.tokens >= need {
+		l.tokens -= need
+		return 0
+	}
+	return time.Duration((need - l.tokens) / l.perSec * float64(time.Second))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
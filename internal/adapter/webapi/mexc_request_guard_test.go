@@ -0,0 +1,111 @@
+package webapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/go-resty/resty/v2"
+)
+
+func TestMexcRateLimiter_reserveByWeight(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	l := newMexcRateLimiter(10, 10*time.Second, func() time.Time { return now })
+
+	if wait := l.reserve(8); wait != 0 {
+		t.Fatalf("full bucket must grant 8, wait %s", wait)
+	}
+	if wait := l.reserve(4); wait != 2*time.Second {
+		t.Fatalf("expected 2s until 4 tokens, got %s", wait)
+	}
+	now = now.Add(2 * time.Second)
+	if wait := l.reserve(4); wait != 0 {
+		t.Fatalf("tokens must refill at 1/s, wait %s", wait)
+	}
+	now = now.Add(time.Hour)
+	if wait := l.reserve(40); wait != 0 {
+		t.Fatalf("weight above capacity must be clamped, wait %s", wait)
+	}
+	if l := newMexcRateLimiter(0, time.Second, time.Now); l.Wait(context.Background(), 100) != nil {
+		t.Fatalf("disabled limiter must not block")
+	}
+}
+
+func newTestGuardServer(t *testing.T, handler http.HandlerFunc) (*mexcRequestGuard, *resty.Client) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	guard := newMexcRequestGuard(config.MexcConfig{RetryAttempts: 3, RetryBaseDelay: time.Millisecond})
+	return guard, resty.New().SetBaseURL(server.URL)
+}
+
+func TestMexcRequestGuard_retriesReadsOn5xxAnd429(t *testing.T) {
+	var calls atomic.Int32
+	guard, client := newTestGuardServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	res, err := guard.do(context.Background(), http.MethodGet, "/api/v3/klines", func() (*resty.Response, error) {
+		return client.R().Get("/api/v3/klines")
+	})
+	if err != nil || res.StatusCode() != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("expected success on third attempt, got %v %v after %d calls", res, err, calls.Load())
+	}
+}
+
+func TestMexcRequestGuard_neverRetriesAmbiguousNewOrder(t *testing.T) {
+	var calls atomic.Int32
+	guard, client := newTestGuardServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGatewayTimeout)
+	})
+	send := func() (*resty.Response, error) { return client.R().Post("/api/v3/order") }
+
+	res, _ := guard.do(context.Background(), http.MethodPost, "/api/v3/order", send)
+	if res.StatusCode() != http.StatusGatewayTimeout || calls.Load() != 1 {
+		t.Fatalf("POST order must not be retried after 5xx, got %d calls", calls.Load())
+	}
+}
+
+func TestMexcRequestGuard_retriesRejectedNewOrder(t *testing.T) {
+	var calls atomic.Int32
+	guard, client := newTestGuardServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	res, err := guard.do(context.Background(), http.MethodPost, "/api/v3/order", func() (*resty.Response, error) {
+		return client.R().Post("/api/v3/order")
+	})
+	if err != nil || res.StatusCode() != http.StatusOK || calls.Load() != 2 {
+		t.Fatalf("429 means the order was not accepted and must be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestMexcRetryPolicy_delay(t *testing.T) {
+	p := mexcRetryPolicy{attempts: 5, baseDelay: 100 * time.Millisecond, maxDelay: 300 * time.Millisecond}
+	if got := p.delay(2, nil); got != 200*time.Millisecond {
+		t.Fatalf("expected exponential backoff 200ms, got %s", got)
+	}
+	if got := p.delay(4, nil); got != 300*time.Millisecond {
+		t.Fatalf("expected backoff capped at 300ms, got %s", got)
+	}
+	res := &resty.Response{RawResponse: &http.Response{Header: http.Header{"Retry-After": []string{"2"}}}}
+	if got := p.delay(1, res); got != 2*time.Second {
+		t.Fatalf("Retry-After must win, got %s", got)
+	}
+}
@@ -0,0 +1,278 @@
+package webapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/pkg/wrap"
+	"github.com/go-resty/resty/v2"
+)
+
+const defaultRecvWindow = 5 * time.Second
+
+// MexcSpotClient — подписанный REST-клиент приватных эндпоинтов MEXC spot v3
+// (account, order, openOrders) на чистом Go вместо jsii mexcsdk.Spot.
+//
+// Запрос подписывается HMAC-SHA256 от строки параметров с timestamp и
+// recvWindow. Смещение часов относительно биржи измеряется по /api/v3/time
+// и применяется к timestamp; при ответе 700003 (timestamp вне recvWindow)
+// клиент пересинхронизирует часы и повторяет запрос один раз — такой ответ
+// гарантирует, что биржа запрос не исполнила.
+//
+// Перед первым ордером часы синхронизируются всегда. С MaxClockSkew > 0 они
+// пересинхронизируются, если последней синхронизации больше
+// ClockSyncInterval, и ордер отклоняется с mexc.ErrClockSkew, если
+// расхождение больше MaxClockSkew: на хосте с уехавшими часами неверны и
+// локальные отметки времени, на которые опираются таймауты стратегий.
+//
+// Все запросы к хосту BaseUrl, включая публичные запросы MexcWebapi, идут
+// через общий guard: лимит по весам и повторы (mexc_request_guard.go).
+type MexcSpotClient struct {
+	client       *resty.Client
+	guard        *mexcRequestGuard
+	apiKey       string
+	secret       string
+	recvWindow   time.Duration
+	maxSkew      time.Duration
+	syncInterval time.Duration
+	offsetMs     atomic.Int64
+	roundTripMs  atomic.Int64
+	syncedAtMs   atomic.Int64
+	now          func() time.Time
+}
+
+func NewMexcSpotClient(config config.MexcConfig) *MexcSpotClient {
+	client := resty.New()
+	client.SetBaseURL(config.BaseUrl)
+	client.SetTimeout(20 * time.Second)
+
+	recvWindow := config.RecvWindow
+	if recvWindow <= 0 {
+		recvWindow = defaultRecvWindow
+	}
+
+	return &MexcSpotClient{
+		client:       client,
+		guard:        newMexcRequestGuard(config),
+		apiKey:       config.ApiKey,
+		secret:       config.Secret,
+		recvWindow:   recvWindow,
+		maxSkew:      config.MaxClockSkew,
+		syncInterval: config.ClockSyncInterval,
+		now:          time.Now,
+	}
+}
+
+// ClockOffset — последнее измеренное смещение часов биржи относительно
+// локальных (server - local).
+func (c *MexcSpotClient) ClockOffset() time.Duration {
+	return time.Duration(c.offsetMs.Load()) * time.Millisecond
+}
+
+// SyncTime измеряет смещение часов по /api/v3/time, считая, что биржа
+// ответила в середине round-trip.
+func (c *MexcSpotClient) SyncTime(ctx context.Context) error {
+	var sentAt, receivedAt time.Time
+	res, err := c.guard.do(ctx, http.MethodGet, "/api/v3/time", func() (*resty.Response, error) {
+		sentAt = c.now()
+		res, err := c.client.R().SetContext(ctx).Get("/api/v3/time")
+		receivedAt = c.now()
+		return res, err
+	})
+	if err != nil {
+		return wrap.Errorf("failed to get server time: %w", err)
+	}
+	if res.IsError() {
+		return parseMexcAPIError(res, "GET /api/v3/time")
+	}
+	var body struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := json.Unmarshal(res.Body(), &body); err != nil || body.ServerTime == 0 {
+		return wrap.Errorf("failed to parse server time: %s", string(res.Body()))
+	}
+	roundTrip := receivedAt.Sub(sentAt)
+	localMs := sentAt.UnixMilli() + roundTrip.Milliseconds()/2
+	c.offsetMs.Store(body.ServerTime - localMs)
+	c.roundTripMs.Store(roundTrip.Milliseconds())
+	c.syncedAtMs.Store(receivedAt.UnixMilli())
+	return nil
+}
+
+// ClockStatus — результат последней синхронизации; нулевой SyncedAt —
+// синхронизации ещё не было.
+func (c *MexcSpotClient) ClockStatus() mexc.ClockStatus {
+	status := mexc.ClockStatus{
+		Offset:    c.ClockOffset(),
+		RoundTrip: time.Duration(c.roundTripMs.Load()) * time.Millisecond,
+	}
+	if ms := c.syncedAtMs.Load(); ms != 0 {
+		status.SyncedAt = time.UnixMilli(ms)
+	}
+	return status
+}
+
+// SyncClock синхронизирует часы и проверяет расхождение с MaxClockSkew.
+func (c *MexcSpotClient) SyncClock(ctx context.Context) (mexc.ClockStatus, error) {
+	if err := c.SyncTime(ctx); err != nil {
+		return mexc.ClockStatus{}, err
+	}
+	status := c.ClockStatus()
+	return status, c.checkSkew(status)
+}
+
+func (c *MexcSpotClient) checkSkew(status mexc.ClockStatus) error {
+	if c.maxSkew <= 0 || status.Skew() <= c.maxSkew {
+		return nil
+	}
+	return wrap.Errorf("offset %s, round-trip %s, limit %s: %w",
+		status.Offset, status.RoundTrip, c.maxSkew, mexc.ErrClockSkew)
+}
+
+// ensureClock — проверка часов перед новым ордером. Первый ордер всегда
+// синхронизирует часы, чтобы подпись не зависела только от повтора по 700003;
+// с MaxClockSkew > 0 часы ещё и пересинхронизируются раз в syncInterval, а
+// ордер при большом расхождении отклоняется. С MaxClockSkew = 0 ошибка
+// синхронизации ордер не останавливает.
+func (c *MexcSpotClient) ensureClock(ctx context.Context) error {
+	status := c.ClockStatus()
+	stale := c.maxSkew > 0 && c.now().Sub(status.SyncedAt) >= c.syncInterval
+	if status.SyncedAt.IsZero() || stale {
+		var err error
+		status, err = c.SyncClock(ctx)
+		if err != nil && !errors.Is(err, mexc.ErrClockSkew) {
+			if c.maxSkew <= 0 {
+				return nil
+			}
+			return wrap.Errorf("sync clock before order: %w", err)
+		}
+	}
+	return c.checkSkew(status)
+}
+
+func (c *MexcSpotClient) AccountInfo(ctx context.Context) ([]byte, error) {
+	return c.signed(ctx, http.MethodGet, "/api/v3/account", nil)
+}
+
+func (c *MexcSpotClient) NewOrder(ctx context.Context, params map[string]string) ([]byte, error) {
+	if err := c.ensureClock(ctx); err != nil {
+		return nil, wrap.Errorf("mexc POST /api/v3/order: %w", err)
+	}
+	return c.signed(ctx, http.MethodPost, "/api/v3/order", params)
+}
+
+func (c *MexcSpotClient) CancelOrder(ctx context.Context, params map[string]string) ([]byte, error) {
+	return c.signed(ctx, http.MethodDelete, "/api/v3/order", params)
+}
+
+func (c *MexcSpotClient) QueryOrder(ctx context.Context, params map[string]string) ([]byte, error) {
+	return c.signed(ctx, http.MethodGet, "/api/v3/order", params)
+}
+
+func (c *MexcSpotClient) OpenOrders(ctx context.Context, symbol string) ([]byte, error) {
+	return c.signed(ctx, http.MethodGet, "/api/v3/openOrders", map[string]string{"symbol": symbol})
+}
+
+// CreateListenKey открывает user data stream; ключ живёт 60 минут без
+// продления.
+func (c *MexcSpotClient) CreateListenKey(ctx context.Context) (string, error) {
+	body, err := c.signed(ctx, http.MethodPost, "/api/v3/userDataStream", nil)
+	if err != nil {
+		return "", err
+	}
+	var res struct {
+		ListenKey string `json:"listenKey"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil || res.ListenKey == "" {
+		return "", wrap.Errorf("failed to parse listenKey: %s", string(body))
+	}
+	return res.ListenKey, nil
+}
+
+func (c *MexcSpotClient) KeepAliveListenKey(ctx context.Context, listenKey string) error {
+	_, err := c.signed(ctx, http.MethodPut, "/api/v3/userDataStream", map[string]string{"listenKey": listenKey})
+	return err
+}
+
+func (c *MexcSpotClient) CloseListenKey(ctx context.Context, listenKey string) error {
+	_, err := c.signed(ctx, http.MethodDelete, "/api/v3/userDataStream", map[string]string{"listenKey": listenKey})
+	return err
+}
+
+func (c *MexcSpotClient) signed(ctx context.Context, method, path string, params map[string]string) ([]byte, error) {
+	body, err := c.doSigned(ctx, method, path, params)
+	if apiErr, ok := mexc.AsAPIError(err); !ok || apiErr.Code != mexc.ErrCodeTimestampRecvWindow {
+		return body, err
+	}
+	if syncErr := c.SyncTime(ctx); syncErr != nil {
+		return nil, wrap.Errorf("resync clock after %v: %w", err, syncErr)
+	}
+	return c.doSigned(ctx, method, path, params)
+}
+
+func (c *MexcSpotClient) doSigned(ctx context.Context, method, path string, params map[string]string) ([]byte, error) {
+	res, err := c.guard.do(ctx, method, path, func() (*resty.Response, error) {
+		return c.sendSigned(ctx, method, path, params)
+	})
+	if err != nil {
+		return nil, wrap.Errorf("mexc %s %s: %w", method, path, err)
+	}
+	if res.IsError() {
+		return nil, parseMexcAPIError(res, method+" "+path)
+	}
+	return res.Body(), nil
+}
+
+// sendSigned подписывает запрос текущим временем: при повторе guard
+// вызывает его заново, и timestamp не устаревает.
+func (c *MexcSpotClient) sendSigned(ctx context.Context, method, path string, params map[string]string) (*resty.Response, error) {
+	values := url.Values{}
+	for key, value := range params {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	values.Set("recvWindow", strconv.FormatInt(c.recvWindow.Milliseconds(), 10))
+	values.Set("timestamp", strconv.FormatInt(c.now().UnixMilli()+c.offsetMs.Load(), 10))
+	query := values.Encode()
+	query += "&signature=" + signMexcPayload(c.secret, query)
+
+	// Строка запроса передаётся как есть: подпись считается по ней, и
+	// signature должна остаться последним параметром.
+	return c.client.R().
+		SetContext(ctx).
+		SetHeader("X-MEXC-APIKEY", c.apiKey).
+		Execute(method, path+"?"+query)
+}
+
+func signMexcPayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseMexcAPIError(res *resty.Response, endpoint string) error {
+	apiErr := &mexc.APIError{HTTPStatus: res.StatusCode(), Endpoint: endpoint}
+	var body struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(res.Body(), &body); err == nil && (body.Code != 0 || body.Msg != "") {
+		apiErr.Code = body.Code
+		apiErr.Msg = body.Msg
+	} else {
+		apiErr.Msg = string(res.Body())
+	}
+	return apiErr
+}
@@ -0,0 +1,251 @@
+package webapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/adapter/webapi/mexcsim"
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/enum"
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/model"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/go-resty/resty/v2"
+)
+
+func newSignedSim(t *testing.T, secret string) (*mexcsim.Exchange, *MexcWebapi, *MexcSpotClient) {
+	t.Helper()
+	return newSignedSimWithConfig(t, config.MexcConfig{ApiKey: "key", Secret: secret})
+}
+
+func newSignedSimWithConfig(t *testing.T, cfg config.MexcConfig) (*mexcsim.Exchange, *MexcWebapi, *MexcSpotClient) {
+	t.Helper()
+	ex := mexcsim.NewExchange()
+	ex.AddSymbol(mexc.SymbolDetail{
+		Symbol:               "AAAUSDT",
+		Status:               "1",
+		BaseAsset:            "AAA",
+		QuoteAsset:           "USDT",
+		QuotePrecision:       4,
+		OrderTypes:           []string{"LIMIT"},
+		IsSpotTradingAllowed: true,
+		QuoteAmountPrecision: "1",
+		Filters:              []mexc.SymbolFilter{{FilterType: "LOT_SIZE", StepSize: "0.01"}},
+	})
+	ex.SetBalance("USDT", 100)
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.99, Qty: 100}}, []mexcsim.Level{{Price: 1.01, Qty: 100}})
+	ex.RequireSignature("key", "secret")
+	server := mexcsim.NewServer(ex)
+	t.Cleanup(server.Close)
+
+	cfg.BaseUrl = server.URL
+	spot := NewMexcSpotClient(cfg)
+	client := resty.New()
+	client.SetBaseURL(server.URL)
+	return ex, NewMexcWebapi(client, spot, cfg), spot
+}
+
+func TestSignMexcPayload_knownVector(t *testing.T) {
+	// Пример из документации Binance-совместимого API, который использует MEXC.
+	payload := "symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559"
+	secret := "NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j"
+	want := "c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71"
+	if got := signMexcPayload(secret, payload); got != want {
+		t.Fatalf("unexpected signature %s", got)
+	}
+}
+
+func TestMexcSpotClient_signedOrderLifecycle(t *testing.T) {
+	ex, api, _ := newSignedSim(t, "secret")
+
+	placed, err := api.NewOrder(context.Background(), model.OrderParams{
+		Symbol: "AAAUSDT", Side: order.BUY, OrderType: order.LIMIT,
+		Price: 1, Quantity: 10, NewClientOrderId: "c1",
+	})
+	if err != nil {
+		t.Fatalf("new order: %v", err)
+	}
+	if len(ex.OpenOrders("AAAUSDT")) != 1 {
+		t.Fatalf("expected order to rest on the exchange")
+	}
+	found, err := api.GetOrderQueryByClientID(context.Background(), "AAAUSDT", "c1")
+	if err != nil || found == nil || found.OrderID != placed.OrderID {
+		t.Fatalf("query by client id: %+v %v", found, err)
+	}
+	open, err := api.GetOpenOrders(context.Background(), model.OrderParams{Symbol: "AAAUSDT"})
+	if err != nil || len(*open) != 1 {
+		t.Fatalf("open orders: %+v %v", open, err)
+	}
+	missing, err := api.GetOrderQuery(context.Background(), "AAAUSDT", "999")
+	if err != nil || missing != nil {
+		t.Fatalf("missing order must be nil without error, got %+v %v", missing, err)
+	}
+}
+
+func TestMexcSpotClient_invalidSignatureIsTypedError(t *testing.T) {
+	_, api, _ := newSignedSim(t, "wrong")
+
+	_, err := api.GetBalance(context.Background())
+	var apiErr *mexc.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.Code != mexc.ErrCodeSignatureInvalid || apiErr.HTTPStatus != http.StatusBadRequest || apiErr.Endpoint != "GET /api/v3/account" {
+		t.Fatalf("unexpected error: %+v", apiErr)
+	}
+}
+
+func TestMexcSpotClient_resyncsClockOnRecvWindowError(t *testing.T) {
+	ex, api, spot := newSignedSim(t, "secret")
+	ex.SetClock(func() time.Time { return time.Now().Add(10 * time.Minute) })
+
+	info, err := api.GetBalance(context.Background())
+	if err != nil {
+		t.Fatalf("expected request to succeed after clock resync: %v", err)
+	}
+	if len(info.Balances) == 0 {
+		t.Fatalf("expected balances, got %+v", info)
+	}
+	if offset := spot.ClockOffset(); offset < 9*time.Minute || offset > 11*time.Minute {
+		t.Fatalf("unexpected clock offset %s", offset)
+	}
+}
+
+func TestMexcSpotClient_refusesOrderOnClockSkew(t *testing.T) {
+	ex, api, spot := newSignedSimWithConfig(t, config.MexcConfig{
+		ApiKey: "key", Secret: "secret", MaxClockSkew: 2 * time.Second, ClockSyncInterval: time.Minute,
+	})
+	ex.SetClock(func() time.Time { return time.Now().Add(10 * time.Minute) })
+
+	_, err := api.NewOrder(context.Background(), model.OrderParams{
+		Symbol: "AAAUSDT", Side: order.BUY, OrderType: order.LIMIT,
+		Price: 1, Quantity: 10, NewClientOrderId: "c1",
+	})
+	if !errors.Is(err, mexc.ErrClockSkew) {
+		t.Fatalf("expected ErrClockSkew, got %v", err)
+	}
+	if len(ex.OpenOrders("AAAUSDT")) != 0 {
+		t.Fatalf("order must not reach the exchange")
+	}
+	if status := spot.ClockStatus(); status.SyncedAt.IsZero() || status.Offset < 9*time.Minute {
+		t.Fatalf("expected lazy sync before order, got %+v", status)
+	}
+
+	ex.SetClock(time.Now)
+	status, err := spot.SyncClock(context.Background())
+	if err != nil || status.Skew() > 2*time.Second {
+		t.Fatalf("expected clock in sync, got %+v %v", status, err)
+	}
+	if _, err := api.NewOrder(context.Background(), model.OrderParams{
+		Symbol: "AAAUSDT", Side: order.BUY, OrderType: order.LIMIT,
+		Price: 1, Quantity: 10, NewClientOrderId: "c2",
+	}); err != nil {
+		t.Fatalf("order after resync: %v", err)
+	}
+}
+
+func TestMexcWebapi_errorsAreClassified(t *testing.T) {
+	_, api, _ := newSignedSim(t, "secret")
+
+	_, err := api.NewOrder(context.Background(), model.OrderParams{
+		Symbol: "AAAUSDT", Side: order.BUY, OrderType: order.LIMIT,
+		Price: 1, Quantity: 500, NewClientOrderId: "big",
+	})
+	if !mexc.IsInsufficientBalance(err) || mexc.ClassifyError(err) != mexc.ErrorClassBusiness || !mexc.IsRejected(err) {
+		t.Fatalf("expected insufficient balance business error, got %v", err)
+	}
+
+	_, err = api.GetKlinesPublic(context.Background(), "XXXUSDT", enum.MINUTES_15, 10, nil)
+	if !mexc.IsInvalidSymbol(err) {
+		t.Fatalf("expected invalid symbol from public endpoint, got %v", err)
+	}
+
+	_, err = api.CancelOrder(context.Background(), "AAAUSDT", "999")
+	if !mexc.IsOrderNotFound(err) {
+		t.Fatalf("expected order not found on cancel, got %v", err)
+	}
+
+	_, badKey, _ := newSignedSim(t, "wrong")
+	_, err = badKey.GetBalance(context.Background())
+	if mexc.ClassifyError(err) != mexc.ErrorClassFatal || mexc.IsRateLimited(err) {
+		t.Fatalf("invalid signature must be fatal, got %v", err)
+	}
+
+	rateLimited := &mexc.APIError{HTTPStatus: http.StatusTooManyRequests, Endpoint: "GET /api/v3/klines"}
+	if !mexc.IsRateLimited(rateLimited) || mexc.ClassifyError(rateLimited) != mexc.ErrorClassRetryable || mexc.IsRejected(rateLimited) {
+		t.Fatalf("429 must be retryable and not a rejection")
+	}
+	if mexc.ClassifyError(errors.New("connection reset")) != mexc.ErrorClassUnknown {
+		t.Fatalf("network error must stay unknown")
+	}
+}
+
+func TestMexcWebapi_tradeTape(t *testing.T) {
+	ex, api, _ := newSignedSim(t, "secret")
+	start := time.UnixMilli(1_700_000_000_000).UTC()
+	ex.SetTrades("AAAUSDT", []mexc.Trade{
+		{Price: 1.0, Qty: 2, Time: start},
+		{Price: 1.01, Qty: 3, Time: start.Add(time.Minute), BuyerMaker: true},
+		{Price: 1.02, Qty: 4, Time: start.Add(2 * time.Minute)},
+	})
+
+	trades, err := api.GetTrades(context.Background(), "AAAUSDT", 2)
+	if err != nil {
+		t.Fatalf("trades: %v", err)
+	}
+	if len(trades) != 2 || trades[0].Price != 1.01 || !trades[0].BuyerMaker || trades[1].Qty != 4 {
+		t.Fatalf("unexpected recent trades %+v", trades)
+	}
+
+	agg, err := api.GetAggTrades(context.Background(), "AAAUSDT", start.Add(time.Minute).UnixMilli(), 0, 10)
+	if err != nil {
+		t.Fatalf("agg trades: %v", err)
+	}
+	if len(agg) != 2 || !agg[0].Time.Equal(start.Add(time.Minute)) || agg[1].Quote() != 1.02*4 {
+		t.Fatalf("unexpected agg trades %+v", agg)
+	}
+}
+
+func TestMexcSpotClient_syncsClockOnFirstOrderWithoutSkewLimit(t *testing.T) {
+	ex, api, spot := newSignedSim(t, "secret")
+	ex.SetClock(func() time.Time { return time.Now().Add(10 * time.Minute) })
+
+	if _, err := api.NewOrder(context.Background(), model.OrderParams{
+		Symbol: "AAAUSDT", Side: order.BUY, OrderType: order.LIMIT,
+		Price: 1, Quantity: 10, NewClientOrderId: "c1",
+	}); err != nil {
+		t.Fatalf("order without skew limit must not be refused: %v", err)
+	}
+	if status := spot.ClockStatus(); status.SyncedAt.IsZero() || status.Offset < 9*time.Minute {
+		t.Fatalf("expected sync before first order, got %+v", status)
+	}
+}
+
+func TestMexcWebapi_orderPathsHonourContext(t *testing.T) {
+	ex, api, _ := newSignedSim(t, "secret")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := api.NewOrder(ctx, model.OrderParams{
+		Symbol: "AAAUSDT", Side: order.BUY, OrderType: order.LIMIT,
+		Price: 1, Quantity: 10, NewClientOrderId: "c1",
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled order, got %v", err)
+	}
+	if len(ex.Orders()) != 0 {
+		t.Fatalf("canceled order must not reach the exchange")
+	}
+
+	ex.DropNextOrderResponse()
+	placed, err := api.NewOrder(context.Background(), model.OrderParams{
+		Symbol: "AAAUSDT", Side: order.BUY, OrderType: order.LIMIT,
+		Price: 1, Quantity: 10, NewClientOrderId: "c2",
+	})
+	if err != nil || placed == nil || len(ex.OpenOrders("AAAUSDT")) != 1 || placed.OrderID != ex.OpenOrders("AAAUSDT")[0].OrderID {
+		t.Fatalf("lost response must be recovered by client order id, got %+v %v", placed, err)
+	}
+}
@@ -0,0 +1,156 @@
+package webapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const (
+	mexcUserStreamChannel = "spot@private.orders.v3.api"
+	// listenKey живёт 60 минут; продлеваем с запасом.
+	mexcListenKeyKeepAlive = 30 * time.Minute
+	mexcListenKeyTimeout   = 10 * time.Second
+)
+
+// MexcUserStream — приватный WebSocket MEXC spot v3 с обновлениями ордеров
+// аккаунта (spot@private.orders.v3.api). На каждое соединение создаётся
+// новый listenKey, который продлевается, пока соединение живо, и закрывается
+// после него. Неудачное продление рвёт соединение: вызывающий переподключится
+// уже с новым ключом.
+type MexcUserStream struct {
+	url       string
+	spot      *MexcSpotClient
+	keepAlive time.Duration
+}
+
+func NewMexcUserStream(config config.MexcConfig, spot *MexcSpotClient) *MexcUserStream {
+	return &MexcUserStream{url: config.WsUrl, spot: spot, keepAlive: mexcListenKeyKeepAlive}
+}
+
+func (s *MexcUserStream) Run(ctx context.Context, ready func(), handler func(mexc.ExecutionReport)) error {
+	listenKey, err := s.spot.CreateListenKey(ctx)
+	if err != nil {
+		return wrap.Errorf("user stream listenKey: %w", err)
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mexcListenKeyTimeout)
+		defer cancel()
+		_ = s.spot.CloseListenKey(closeCtx, listenKey)
+	}()
+
+	streamURL, err := url.Parse(s.url)
+	if err != nil {
+		return wrap.Errorf("user stream url: %w", err)
+	}
+	query := streamURL.Query()
+	query.Set("listenKey", listenKey)
+	streamURL.RawQuery = query.Encode()
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	keepAliveErr := make(chan error, 1)
+	go func() {
+		ticker := time.NewTicker(s.keepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-connCtx.Done():
+				return
+			case <-ticker.C:
+				if err := s.spot.KeepAliveListenKey(connCtx, listenKey); err != nil {
+					if connCtx.Err() == nil {
+						keepAliveErr <- wrap.Errorf("user stream keepalive: %w", err)
+						cancel()
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	err = runMexcWS(connCtx, "user stream", streamURL.String(), []string{mexcUserStreamChannel}, ready, func(raw []byte) error {
+		report, ok, err := parseMexcExecutionReport(raw)
+		if err != nil {
+			return err
+		}
+		if ok {
+			handler(report)
+		}
+		return nil
+	})
+	select {
+	case keepErr := <-keepAliveErr:
+		return keepErr
+	default:
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err == nil {
+		return wrap.Errorf("user stream: connection closed")
+	}
+	return err
+}
+
+// parseMexcExecutionReport разбирает push из spot@private.orders.v3.api.
+// Ответы на подписку и PONG пропускаются (ok=false).
+func parseMexcExecutionReport(raw []byte) (mexc.ExecutionReport, bool, error) {
+	var msg struct {
+		Channel string          `json:"c"`
+		Symbol  string          `json:"s"`
+		Time    int64           `json:"t"`
+		Data    json.RawMessage `json:"d"`
+		Code    *int            `json:"code"`
+		Msg     string          `json:"msg"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return mexc.ExecutionReport{}, false, wrap.Errorf("user stream: bad message %s: %w", string(raw), err)
+	}
+	if msg.Channel == "" {
+		if msg.Code != nil && *msg.Code != 0 {
+			return mexc.ExecutionReport{}, false, wrap.Errorf("user stream: code %d: %s", *msg.Code, msg.Msg)
+		}
+		return mexc.ExecutionReport{}, false, nil
+	}
+	if msg.Channel != mexcUserStreamChannel {
+		return mexc.ExecutionReport{}, false, nil
+	}
+
+	var d struct {
+		OrderID            string  `json:"i"`
+		ClientOrderID      string  `json:"c"`
+		TradeType          int     `json:"S"`
+		Price              float64 `json:"p"`
+		Quantity           float64 `json:"v"`
+		Status             int     `json:"s"`
+		CumulativeQty      float64 `json:"cv"`
+		CumulativeQuoteQty float64 `json:"ca"`
+		CreateTime         int64   `json:"O"`
+	}
+	if err := json.Unmarshal(msg.Data, &d); err != nil {
+		return mexc.ExecutionReport{}, false, wrap.Errorf("user stream: bad order %s: %w", string(raw), err)
+	}
+	side := "BUY"
+	if d.TradeType == 2 {
+		side = "SELL"
+	}
+	return mexc.ExecutionReport{
+		Symbol:             msg.Symbol,
+		OrderID:            d.OrderID,
+		ClientOrderID:      d.ClientOrderID,
+		Side:               side,
+		Status:             mexc.StreamOrderStatus(d.Status),
+		Price:              d.Price,
+		Quantity:           d.Quantity,
+		CumulativeQty:      d.CumulativeQty,
+		CumulativeQuoteQty: d.CumulativeQuoteQty,
+		CreateTime:         d.CreateTime,
+		EventTime:          msg.Time,
+	}, true, nil
+}
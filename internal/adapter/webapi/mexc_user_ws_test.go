@@ -0,0 +1,86 @@
+package webapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/adapter/webapi/mexcsim"
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+func TestParseMexcExecutionReport(t *testing.T) {
+	report, ok, err := parseMexcExecutionReport([]byte(`{"c":"spot@private.orders.v3.api","d":{"A":0,"O":1661938138000,"S":2,"V":0,"a":8,"c":"Signal_S_1","i":"e03a5c7441e44ed899466a7140b71391","m":0,"o":1,"p":0.8,"s":2,"v":10,"ap":0.8,"cv":10,"ca":8},"s":"MXUSDT","t":1661938138193}`))
+	if err != nil || !ok {
+		t.Fatalf("order: %v %v", ok, err)
+	}
+	if report.Symbol != "MXUSDT" || report.OrderID != "e03a5c7441e44ed899466a7140b71391" || report.ClientOrderID != "Signal_S_1" ||
+		report.Side != "SELL" || report.Status != "FILLED" || report.CumulativeQty != 10 || report.CumulativeQuoteQty != 8 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if result := report.QueryOrderResult(); result.ExecutedQty != "10" || result.CummulativeQuoteQty != "8" || result.Price != "0.8" {
+		t.Fatalf("unexpected query result: %+v", result)
+	}
+
+	if _, ok, err := parseMexcExecutionReport([]byte(`{"id":0,"code":0,"msg":"spot@private.orders.v3.api"}`)); ok || err != nil {
+		t.Fatalf("ack must be skipped, got %v %v", ok, err)
+	}
+}
+
+func TestMexcUserStream_ordersAndKeepAlive(t *testing.T) {
+	ex, _, spot := newSignedSim(t, "secret")
+	stream := mexcsim.NewStream()
+	streamServer := mexcsim.NewStreamServer(stream)
+	t.Cleanup(streamServer.Close)
+	ex.SetOrderListener(stream.PublishOrder)
+
+	userStream := NewMexcUserStream(config.MexcConfig{WsUrl: mexcsim.StreamURL(streamServer)}, spot)
+	userStream.keepAlive = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{}, 1)
+	reports := make(chan mexc.ExecutionReport, 8)
+	done := make(chan error, 1)
+	go func() {
+		done <- userStream.Run(ctx, func() { ready <- struct{}{} }, func(r mexc.ExecutionReport) { reports <- r })
+	}()
+
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("user stream was not subscribed")
+	}
+	if !stream.WaitSubscriptions(1, 5*time.Second) {
+		t.Fatalf("stream did not accept subscription")
+	}
+	placed, err := ex.PlaceOrder(mexcsim.OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "LIMIT", Price: 0.98, Quantity: 5, ClientOrderID: "Signal_B_1"})
+	if err != nil {
+		t.Fatalf("place: %v", err)
+	}
+	if _, err := ex.FillOrder(placed.OrderID, 5); err != nil {
+		t.Fatalf("fill: %v", err)
+	}
+	for _, want := range []string{"NEW", "FILLED"} {
+		select {
+		case report := <-reports:
+			if report.OrderID != placed.OrderID || report.Status != want || report.Side != "BUY" {
+				t.Fatalf("expected %s report for %s, got %+v", want, placed.OrderID, report)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %s report", want)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for ex.ListenKeyKeepAlives() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("listenKey was not kept alive")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
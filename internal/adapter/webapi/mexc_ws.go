@@ -0,0 +1,220 @@
+package webapi
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/pkg/wrap"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// MEXC допускает не более 30 подписок на соединение; на символ уходит
+	// две (bookTicker и kline Min1).
+	mexcStreamSymbolsPerConn = 15
+	mexcStreamPingInterval   = 20 * time.Second
+	mexcStreamReadTimeout    = 60 * time.Second
+	mexcStreamOrigin         = "https://www.mexc.com"
+)
+
+// MexcMarketStream — подписчик публичного WebSocket MEXC spot v3 (JSON
+// протокол): bookTicker и kline Min1 по списку символов. Символы делятся
+// между несколькими соединениями из-за лимита подписок; обрыв любого из них
+// завершает Run с ошибкой, чтобы вызывающий переподключился целиком.
+type MexcMarketStream struct {
+	url string
+}
+
+func NewMexcMarketStream(config config.MexcConfig) *MexcMarketStream {
+	return &MexcMarketStream{url: config.WsUrl}
+}
+
+func (s *MexcMarketStream) Run(ctx context.Context, symbols []string, handler func(mexc.StreamEvent)) error {
+	if len(symbols) == 0 {
+		return wrap.Errorf("market stream: no symbols")
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, (len(symbols)+mexcStreamSymbolsPerConn-1)/mexcStreamSymbolsPerConn)
+	var wg sync.WaitGroup
+	for start := 0; start < len(symbols); start += mexcStreamSymbolsPerConn {
+		end := min(start+mexcStreamSymbolsPerConn, len(symbols))
+		wg.Add(1)
+		go func(chunk []string) {
+			defer wg.Done()
+			if err := s.runConn(runCtx, chunk, handler); err != nil {
+				errs <- err
+				cancel()
+			}
+		}(symbols[start:end])
+	}
+	wg.Wait()
+	close(errs)
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	for err := range errs {
+		return err
+	}
+	return wrap.Errorf("market stream: connections closed")
+}
+
+func (s *MexcMarketStream) runConn(ctx context.Context, symbols []string, handler func(mexc.StreamEvent)) error {
+	params := make([]string, 0, len(symbols)*2)
+	for _, symbol := range symbols {
+		params = append(params,
+			"spot@public.bookTicker.v3.api@"+symbol,
+			"spot@public.kline.v3.api@"+symbol+"@Min1",
+		)
+	}
+	return runMexcWS(ctx, "market stream", s.url, params, nil, func(raw []byte) error {
+		event, ok, err := parseMexcStreamMessage(raw)
+		if err != nil {
+			return err
+		}
+		if ok {
+			handler(event)
+		}
+		return nil
+	})
+}
+
+// runMexcWS держит одно соединение с WebSocket MEXC: подписывается на params,
+// шлёт PING и передаёт каждое сообщение в handle, пока соединение живо.
+// ready, если задан, вызывается после отправки подписки. Возвращает nil после
+// отмены ctx.
+func runMexcWS(ctx context.Context, name, url string, params []string, ready func(), handle func([]byte) error) error {
+	wsConfig, err := websocket.NewConfig(url, mexcStreamOrigin)
+	if err != nil {
+		return wrap.Errorf("%s config: %w", name, err)
+	}
+	conn, err := wsConfig.DialContext(ctx)
+	if err != nil {
+		return wrap.Errorf("%s dial: %w", name, err)
+	}
+	// Закрытие соединения по ctx прерывает блокирующий Receive.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	defer conn.Close()
+
+	if err := websocket.JSON.Send(conn, map[string]any{"method": "SUBSCRIPTION", "params": params}); err != nil {
+		return wrap.Errorf("%s subscribe: %w", name, err)
+	}
+	if ready != nil {
+		ready()
+	}
+
+	pingDone := make(chan struct{})
+	defer close(pingDone)
+	go func() {
+		ticker := time.NewTicker(mexcStreamPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pingDone:
+				return
+			case <-ticker.C:
+				if err := websocket.JSON.Send(conn, map[string]string{"method": "PING"}); err != nil {
+					_ = conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(mexcStreamReadTimeout)); err != nil {
+			return wrap.Errorf("%s deadline: %w", name, err)
+		}
+		var raw []byte
+		if err := websocket.Message.Receive(conn, &raw); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return wrap.Errorf("%s receive: %w", name, err)
+		}
+		if err := handle(raw); err != nil {
+			return err
+		}
+	}
+}
+
+// parseMexcStreamMessage разбирает push-сообщение. Ответы на подписку и PONG
+// пропускаются (ok=false); отказ в подписке возвращается ошибкой.
+func parseMexcStreamMessage(raw []byte) (mexc.StreamEvent, bool, error) {
+	var msg struct {
+		Channel string          `json:"c"`
+		Symbol  string          `json:"s"`
+		Time    int64           `json:"t"`
+		Data    json.RawMessage `json:"d"`
+		Code    *int            `json:"code"`
+		Msg     string          `json:"msg"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return mexc.StreamEvent{}, false, wrap.Errorf("market stream: bad message %s: %w", string(raw), err)
+	}
+	if msg.Channel == "" {
+		if msg.Code != nil && *msg.Code != 0 {
+			return mexc.StreamEvent{}, false, wrap.Errorf("market stream: code %d: %s", *msg.Code, msg.Msg)
+		}
+		return mexc.StreamEvent{}, false, nil
+	}
+
+	event := mexc.StreamEvent{Symbol: msg.Symbol, EventTime: msg.Time}
+	switch {
+	case strings.HasPrefix(msg.Channel, "spot@public.bookTicker.v3.api@"):
+		var d struct {
+			AskPrice string `json:"a"`
+			AskQty   string `json:"A"`
+			BidPrice string `json:"b"`
+			BidQty   string `json:"B"`
+		}
+		if err := json.Unmarshal(msg.Data, &d); err != nil {
+			return event, false, wrap.Errorf("market stream: bad bookTicker %s: %w", string(raw), err)
+		}
+		book := &mexc.StreamBookTicker{Symbol: msg.Symbol}
+		book.BidPrice, _ = strconv.ParseFloat(d.BidPrice, 64)
+		book.BidQty, _ = strconv.ParseFloat(d.BidQty, 64)
+		book.AskPrice, _ = strconv.ParseFloat(d.AskPrice, 64)
+		book.AskQty, _ = strconv.ParseFloat(d.AskQty, 64)
+		event.BookTicker = book
+	case strings.HasPrefix(msg.Channel, "spot@public.kline.v3.api@"):
+		var d struct {
+			K struct {
+				Start  int64   `json:"t"`
+				End    int64   `json:"T"`
+				Open   float64 `json:"o"`
+				Close  float64 `json:"c"`
+				High   float64 `json:"h"`
+				Low    float64 `json:"l"`
+				Volume float64 `json:"v"`
+				Amount float64 `json:"a"`
+			} `json:"k"`
+		}
+		if err := json.Unmarshal(msg.Data, &d); err != nil {
+			return event, false, wrap.Errorf("market stream: bad kline %s: %w", string(raw), err)
+		}
+		// Время окна в стриме — в секундах, в REST klines — в миллисекундах.
+		event.Kline = &mexc.Kline{
+			OpenTime:         d.K.Start * 1000,
+			Open:             d.K.Open,
+			High:             d.K.High,
+			Low:              d.K.Low,
+			Close:            d.K.Close,
+			Volume:           d.K.Volume,
+			CloseTime:        d.K.End*1000 - 1,
+			QuoteAssetVolume: d.K.Amount,
+		}
+	default:
+		return event, false, nil
+	}
+	return event, true, nil
+}
@@ -0,0 +1,30 @@
+package webapi
+
+import (
+	"testing"
+)
+
+func TestParseMexcStreamMessage(t *testing.T) {
+	book, ok, err := parseMexcStreamMessage([]byte(`{"c":"spot@public.bookTicker.v3.api@BTCUSDT","d":{"A":"3.34","B":"0.37","a":"20.1","b":"20.0"},"s":"BTCUSDT","t":1661932660144}`))
+	if err != nil || !ok || book.BookTicker == nil {
+		t.Fatalf("book ticker: %+v %v %v", book, ok, err)
+	}
+	if b := book.BookTicker; b.BidPrice != 20.0 || b.BidQty != 0.37 || b.AskPrice != 20.1 || b.AskQty != 3.34 || book.Symbol != "BTCUSDT" {
+		t.Fatalf("unexpected book ticker: %+v", b)
+	}
+
+	kline, ok, err := parseMexcStreamMessage([]byte(`{"c":"spot@public.kline.v3.api@BTCUSDT@Min1","d":{"k":{"T":1661931060,"a":290.4,"c":20279.43,"h":20284.93,"i":"Min1","l":20277.52,"o":20284.93,"t":1661931000,"v":1.43}},"s":"BTCUSDT","t":1661931016878}`))
+	if err != nil || !ok || kline.Kline == nil {
+		t.Fatalf("kline: %+v %v %v", kline, ok, err)
+	}
+	if k := kline.Kline; k.OpenTime != 1661931000000 || k.CloseTime != 1661931059999 || k.Close != 20279.43 || k.Low != 20277.52 {
+		t.Fatalf("unexpected kline: %+v", k)
+	}
+
+	if _, ok, err := parseMexcStreamMessage([]byte(`{"id":0,"code":0,"msg":"PONG"}`)); ok || err != nil {
+		t.Fatalf("ack must be skipped, got %v %v", ok, err)
+	}
+	if _, _, err := parseMexcStreamMessage([]byte(`{"id":0,"code":1,"msg":"Not Subscribed successfully!"}`)); err == nil {
+		t.Fatalf("expected subscription error")
+	}
+}
@@ -0,0 +1,879 @@
+// Package mexcsim — детерминированная in-memory модель спотовой биржи MEXC.
+//
+// Симулятор держит стакан по каждому символу, балансы и ордера аккаунта,
+// исполняет LIMIT/MARKET/LIMIT_MAKER/IOC/FOK заявки с частичными
+// исполнениями и комиссиями из SymbolDetail и отдаёт всё это через те же
+// HTTP-эндпоинты, что использует webapi.MexcWebapi (см. server.go). Это
+// позволяет прогонять полные торговые циклы usecase'ов в go test без
+// реального аккаунта.
+package mexcsim
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+// Статусы ордеров, как их возвращает MEXC.
+const (
+	StatusNew               = "NEW"
+	StatusPartiallyFilled   = "PARTIALLY_FILLED"
+	StatusFilled            = "FILLED"
+	StatusCanceled          = "CANCELED"
+	StatusPartiallyCanceled = "PARTIALLY_CANCELED"
+)
+
+// Коды ошибок MEXC, которые воспроизводит симулятор.
+const (
+	CodeInvalidSymbol       = -1121
+	CodeOrderNotExist       = -2013
+	CodeUnknownOrder        = -2011
+	CodeWouldMatch          = -2010
+	CodeInsufficientBalance = 30004
+	CodeMinNotional         = 30002
+	CodeBadParameter        = 400
+	CodeOrderTypeNotAllowed = 10007
+	CodeAPIKeyInvalid       = 10072
+	CodeSignatureInvalid    = 700002
+	CodeTimestampOutside    = 700003
+)
+
+const floatEpsilon = 1e-12
+
+// Level — уровень стакана: цена и доступное количество.
+type Level struct {
+	Price float64
+	Qty   float64
+}
+
+// Order — состояние ордера аккаунта внутри симулятора.
+type Order struct {
+	Symbol              string
+	OrderID             string
+	ClientOrderID       string
+	Side                string
+	Type                string
+	Status              string
+	Price               float64
+	OrigQty             float64
+	ExecutedQty         float64
+	CummulativeQuoteQty float64
+	Fee                 float64
+	FeeAsset            string
+	Time                int64
+	UpdateTime          int64
+
+	// reserved — заблокированный под ордер остаток (quote для BUY, base для SELL).
+	reserved float64
+}
+
+// IsActive сообщает, стоит ли ордер в стакане.
+func (o Order) IsActive() bool {
+	return o.Status == StatusNew || o.Status == StatusPartiallyFilled
+}
+
+// OrderRequest — параметры новой заявки в терминах REST API.
+type OrderRequest struct {
+	Symbol        string
+	Side          string
+	Type          string
+	Price         float64
+	Quantity      float64
+	QuoteOrderQty float64
+	ClientOrderID string
+}
+
+// APIError — ошибка в формате MEXC: {"code": ..., "msg": ...} плюс HTTP статус.
+type APIError struct {
+	HTTPStatus int
+	Code       int
+	Msg        string
+}
+
+func (e *APIError) Error() string {
+	return "mexcsim: code " + strconv.Itoa(e.Code) + ": " + e.Msg
+}
+
+func newAPIError(code int, msg string) *APIError {
+	return &APIError{HTTPStatus: 400, Code: code, Msg: msg}
+}
+
+type balance struct {
+	free   float64
+	locked float64
+}
+
+type market struct {
+	detail         mexc.SymbolDetail
+	bids           []Level // по убыванию цены
+	asks           []Level // по возрастанию цены
+	klines         mexc.Klines
+	trades         []mexc.Trade // по возрастанию времени
+	lastPrice      float64
+	quoteVolume24h float64
+	changePercent  float64
+}
+
+// Exchange — состояние симулируемой биржи. Все методы потокобезопасны.
+type Exchange struct {
+	mu          sync.Mutex
+	now         func() time.Time
+	markets     map[string]*market
+	symbolOrder []string
+	balances    map[string]*balance
+	orders      map[string]*Order
+	orderSeq    []string
+	byClientID  map[string]*Order
+	nextOrderID int64
+
+	dropNextOrderResponse bool
+
+	apiKey string
+	secret string
+
+	orderListener func(Order)
+	orderUpdates  []Order
+	listenKeys    map[string]bool
+	nextListenKey int
+	keepAlives    int
+}
+
+// NewExchange создаёт пустую биржу с системными часами.
+func NewExchange() *Exchange {
+	return &Exchange{
+		now:         time.Now,
+		markets:     make(map[string]*market),
+		balances:    make(map[string]*balance),
+		orders:      make(map[string]*Order),
+		byClientID:  make(map[string]*Order),
+		listenKeys:  make(map[string]bool),
+		nextOrderID: 1000,
+	}
+}
+
+// SetClock подменяет часы биржи (transactTime, time/updateTime ордеров, serverTime).
+func (e *Exchange) SetClock(now func() time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.now = now
+}
+
+// RequireSignature включает проверку X-MEXC-APIKEY, HMAC-подписи и
+// timestamp/recvWindow на приватных эндпоинтах, как это делает MEXC.
+func (e *Exchange) RequireSignature(apiKey, secret string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.apiKey = apiKey
+	e.secret = secret
+}
+
+// AddSymbol регистрирует торговую пару. Фильтры, комиссии и допустимые типы
+// ордеров берутся из detail так же, как их отдаёт exchangeInfo.
+func (e *Exchange) AddSymbol(detail mexc.SymbolDetail) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.markets[detail.Symbol]; !ok {
+		e.symbolOrder = append(e.symbolOrder, detail.Symbol)
+	}
+	e.markets[detail.Symbol] = &market{detail: detail}
+}
+
+// SetBalance задаёт свободный остаток актива. Заблокированная часть не меняется.
+func (e *Exchange) SetBalance(asset string, free float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.balance(asset).free = free
+}
+
+// Balance возвращает свободный и заблокированный остаток актива.
+func (e *Exchange) Balance(asset string) (free, locked float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	b, ok := e.balances[asset]
+	if !ok {
+		return 0, 0
+	}
+	return b.free, b.locked
+}
+
+// SetBook заменяет внешнюю ликвидность символа и сразу исполняет стоящие
+// ордера аккаунта, которые оказались в пересечении (как maker, по своей цене).
+func (e *Exchange) SetBook(symbol string, bids, asks []Level) {
+	e.mu.Lock()
+	defer e.unlockAndNotify()
+	m, ok := e.markets[symbol]
+	if !ok {
+		return
+	}
+	m.bids = cloneLevels(bids)
+	m.asks = cloneLevels(asks)
+	sort.Slice(m.bids, func(i, j int) bool { return m.bids[i].Price > m.bids[j].Price })
+	sort.Slice(m.asks, func(i, j int) bool { return m.asks[i].Price < m.asks[j].Price })
+	if m.lastPrice == 0 && len(m.bids) > 0 && len(m.asks) > 0 {
+		m.lastPrice = (m.bids[0].Price + m.asks[0].Price) / 2
+	}
+	e.matchResting(m)
+}
+
+// SetTicker24h задаёт суточную статистику для /api/v3/ticker/24hr.
+func (e *Exchange) SetTicker24h(symbol string, quoteVolume, changePercent float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if m, ok := e.markets[symbol]; ok {
+		m.quoteVolume24h = quoteVolume
+		m.changePercent = changePercent
+	}
+}
+
+// SetKlines задаёт историю свечей символа для /api/v3/klines.
+func (e *Exchange) SetKlines(symbol string, klines mexc.Klines) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if m, ok := e.markets[symbol]; ok {
+		m.klines = append(mexc.Klines(nil), klines...)
+	}
+}
+
+// SetTrades задаёт ленту сделок символа для /api/v3/trades и /api/v3/aggTrades.
+func (e *Exchange) SetTrades(symbol string, trades []mexc.Trade) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if m, ok := e.markets[symbol]; ok {
+		m.trades = append([]mexc.Trade(nil), trades...)
+		sort.SliceStable(m.trades, func(i, j int) bool { return m.trades[i].Time.Before(m.trades[j].Time) })
+	}
+}
+
+// SetOrderListener подписывает listener на изменения ордеров аккаунта:
+// размещение, исполнения и отмены. listener вызывается после снятия
+// блокировки биржи, в порядке изменений; обычно это Stream.PublishOrder.
+func (e *Exchange) SetOrderListener(listener func(Order)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.orderListener = listener
+}
+
+// ListenKeyKeepAlives — сколько раз клиент продлевал listenKey.
+func (e *Exchange) ListenKeyKeepAlives() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.keepAlives
+}
+
+// orderChanged запоминает снимок зарегистрированного ордера для listener.
+// Ордер, который ещё размещается, публикуется один раз в конце PlaceOrder.
+func (e *Exchange) orderChanged(o *Order) {
+	if e.orderListener == nil || e.orders[o.OrderID] != o {
+		return
+	}
+	e.orderUpdates = append(e.orderUpdates, *o)
+}
+
+func (e *Exchange) unlockAndNotify() {
+	listener, updates := e.orderListener, e.orderUpdates
+	e.orderUpdates = nil
+	e.mu.Unlock()
+	if listener == nil {
+		return
+	}
+	for _, o := range updates {
+		listener(o)
+	}
+}
+
+// DropNextOrderResponse заставляет следующий принятый ордер «потерять» ответ:
+// заявка создаётся, но клиент получает 504. Так моделируется таймаут, после
+// которого нужен reconcile-orders по clientOrderId.
+func (e *Exchange) DropNextOrderResponse() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dropNextOrderResponse = true
+}
+
+func (e *Exchange) takeDropOrderResponse() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	drop := e.dropNextOrderResponse
+	e.dropNextOrderResponse = false
+	return drop
+}
+
+// FillOrder исполняет до qty стоящего ордера по его цене как maker. Удобно
+// для сценариев с частичным исполнением без перестройки стакана.
+func (e *Exchange) FillOrder(orderID string, qty float64) (Order, error) {
+	e.mu.Lock()
+	defer e.unlockAndNotify()
+	o, ok := e.orders[orderID]
+	if !ok {
+		return Order{}, newAPIError(CodeOrderNotExist, "Order does not exist.")
+	}
+	if !o.IsActive() {
+		return *o, newAPIError(CodeUnknownOrder, "Unknown order sent.")
+	}
+	m := e.markets[o.Symbol]
+	e.fill(m, o, math.Min(qty, o.OrigQty-o.ExecutedQty), o.Price, true)
+	return *o, nil
+}
+
+// Order возвращает снимок ордера по exchange id.
+func (e *Exchange) Order(orderID string) (Order, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	o, ok := e.orders[orderID]
+	if !ok {
+		return Order{}, false
+	}
+	return *o, true
+}
+
+// Orders возвращает все ордера аккаунта в порядке создания.
+func (e *Exchange) Orders() []Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]Order, 0, len(e.orderSeq))
+	for _, id := range e.orderSeq {
+		out = append(out, *e.orders[id])
+	}
+	return out
+}
+
+// PlaceOrder принимает заявку, проверяет фильтры exchangeInfo и баланс,
+// исполняет пересекающуюся часть как taker и ставит остаток в стакан.
+func (e *Exchange) PlaceOrder(req OrderRequest) (Order, error) {
+	e.mu.Lock()
+	defer e.unlockAndNotify()
+
+	m, ok := e.markets[req.Symbol]
+	if !ok {
+		return Order{}, newAPIError(CodeInvalidSymbol, "Invalid symbol.")
+	}
+	side := strings.ToUpper(req.Side)
+	orderType := strings.ToUpper(req.Type)
+	if side != "BUY" && side != "SELL" {
+		return Order{}, newAPIError(CodeBadParameter, "invalid side")
+	}
+	if req.ClientOrderID != "" {
+		if _, exists := e.byClientID[req.ClientOrderID]; exists {
+			return Order{}, newAPIError(CodeBadParameter, "Duplicate clientOrderId")
+		}
+	}
+	if err := e.validate(m, side, orderType, req); err != nil {
+		return Order{}, err
+	}
+
+	now := e.now().UnixMilli()
+	o := &Order{
+		Symbol:        req.Symbol,
+		OrderID:       strconv.FormatInt(e.nextOrderID, 10),
+		ClientOrderID: req.ClientOrderID,
+		Side:          side,
+		Type:          orderType,
+		Status:        StatusNew,
+		Price:         req.Price,
+		OrigQty:       req.Quantity,
+		Time:          now,
+		UpdateTime:    now,
+	}
+
+	switch orderType {
+	case "MARKET":
+		if err := e.executeMarket(m, o, req.QuoteOrderQty); err != nil {
+			return Order{}, err
+		}
+	default:
+		if err := e.reserve(m, o); err != nil {
+			return Order{}, err
+		}
+		if orderType == "FILL_OR_KILL" && e.crossingQty(m, o) < o.OrigQty-floatEpsilon {
+			e.release(m, o)
+			o.Status = StatusCanceled
+			break
+		}
+		e.takeLiquidity(m, o)
+		if orderType == "IMMEDIATE_OR_CANCEL" || orderType == "FILL_OR_KILL" {
+			e.cancelRemainder(m, o)
+		}
+	}
+
+	e.nextOrderID++
+	e.orders[o.OrderID] = o
+	e.orderSeq = append(e.orderSeq, o.OrderID)
+	if o.ClientOrderID != "" {
+		e.byClientID[o.ClientOrderID] = o
+	}
+	e.orderChanged(o)
+	return *o, nil
+}
+
+// CancelOrder снимает активный ордер; остаток разблокируется.
+func (e *Exchange) CancelOrder(symbol, orderID, clientOrderID string) (Order, error) {
+	e.mu.Lock()
+	defer e.unlockAndNotify()
+	o, err := e.lookup(symbol, orderID, clientOrderID)
+	if err != nil {
+		return Order{}, err
+	}
+	if !o.IsActive() {
+		return Order{}, newAPIError(CodeUnknownOrder, "Unknown order sent.")
+	}
+	e.cancelRemainder(e.markets[o.Symbol], o)
+	return *o, nil
+}
+
+// QueryOrder ищет ордер по orderId или origClientOrderId.
+func (e *Exchange) QueryOrder(symbol, orderID, clientOrderID string) (Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	o, err := e.lookup(symbol, orderID, clientOrderID)
+	if err != nil {
+		return Order{}, err
+	}
+	return *o, nil
+}
+
+// OpenOrders возвращает активные ордера символа (или все, если symbol пуст).
+func (e *Exchange) OpenOrders(symbol string) []Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]Order, 0)
+	for _, id := range e.orderSeq {
+		o := e.orders[id]
+		if o.IsActive() && (symbol == "" || o.Symbol == symbol) {
+			out = append(out, *o)
+		}
+	}
+	return out
+}
+
+func (e *Exchange) lookup(symbol, orderID, clientOrderID string) (*Order, error) {
+	if _, ok := e.markets[symbol]; !ok {
+		return nil, newAPIError(CodeInvalidSymbol, "Invalid symbol.")
+	}
+	var o *Order
+	if orderID != "" {
+		o = e.orders[orderID]
+	} else if clientOrderID != "" {
+		o = e.byClientID[clientOrderID]
+	}
+	if o == nil || o.Symbol != symbol {
+		return nil, newAPIError(CodeOrderNotExist, "Order does not exist.")
+	}
+	return o, nil
+}
+
+func (e *Exchange) validate(m *market, side, orderType string, req OrderRequest) error {
+	d := m.detail
+	if !d.IsSpotTradingAllowed {
+		return newAPIError(CodeOrderTypeNotAllowed, "symbol not support api")
+	}
+	if d.TradeSideType == 2 && side != "BUY" || d.TradeSideType == 3 && side != "SELL" {
+		return newAPIError(CodeOrderTypeNotAllowed, "symbol side not allowed")
+	}
+	if !orderTypeAllowed(d.OrderTypes, orderType) {
+		return newAPIError(CodeOrderTypeNotAllowed, "order type not allowed: "+orderType)
+	}
+	if orderType == "MARKET" {
+		if req.Quantity <= 0 && req.QuoteOrderQty <= 0 {
+			return newAPIError(CodeBadParameter, "quantity or quoteOrderQty required")
+		}
+	} else {
+		if req.Price <= 0 || req.Quantity <= 0 {
+			return newAPIError(CodeBadParameter, "price and quantity must be positive")
+		}
+		if !aligned(req.Price, priceStep(d)) {
+			return newAPIError(CodeBadParameter, "price scale is invalid")
+		}
+	}
+	if req.Quantity > 0 {
+		if !aligned(req.Quantity, lotStep(d)) {
+			return newAPIError(CodeBadParameter, "quantity scale is invalid")
+		}
+		for _, f := range d.Filters {
+			if f.FilterType != "LOT_SIZE" {
+				continue
+			}
+			if minQty := parseFloat(f.MinQty); minQty > 0 && req.Quantity < minQty {
+				return newAPIError(CodeBadParameter, "quantity below minQty")
+			}
+			if maxQty := parseFloat(f.MaxQty); maxQty > 0 && req.Quantity > maxQty {
+				return newAPIError(CodeBadParameter, "quantity above maxQty")
+			}
+		}
+	}
+	notional := req.Price * req.Quantity
+	if orderType == "MARKET" {
+		notional = req.QuoteOrderQty
+		if notional <= 0 {
+			notional = req.Quantity * referencePrice(m, side)
+		}
+	}
+	if minQuote := parseFloat(d.QuoteAmountPrecision); minQuote > 0 && notional < minQuote {
+		return newAPIError(CodeMinNotional, "minimum transaction volume cannot be less than:"+d.QuoteAmountPrecision+"USDT")
+	}
+	maxQuote := parseFloat(d.MaxQuoteAmount)
+	if orderType == "MARKET" && parseFloat(d.MaxQuoteAmountMarket) > 0 {
+		maxQuote = parseFloat(d.MaxQuoteAmountMarket)
+	}
+	if maxQuote > 0 && notional > maxQuote {
+		return newAPIError(CodeBadParameter, "maximum transaction volume exceeded")
+	}
+	if orderType == "LIMIT_MAKER" && e.wouldCross(m, side, req.Price) {
+		return newAPIError(CodeWouldMatch, "Order would immediately match and take.")
+	}
+	return nil
+}
+
+func (e *Exchange) wouldCross(m *market, side string, price float64) bool {
+	if side == "BUY" {
+		return len(m.asks) > 0 && m.asks[0].Price <= price+floatEpsilon
+	}
+	return len(m.bids) > 0 && m.bids[0].Price >= price-floatEpsilon
+}
+
+// reserve блокирует средства под лимитную заявку.
+func (e *Exchange) reserve(m *market, o *Order) error {
+	if o.Side == "BUY" {
+		need := o.Price * o.OrigQty
+		b := e.balance(m.detail.QuoteAsset)
+		if b.free+floatEpsilon < need {
+			return newAPIError(CodeInsufficientBalance, "Insufficient position")
+		}
+		b.free -= need
+		b.locked += need
+		o.reserved = need
+		return nil
+	}
+	b := e.balance(m.detail.BaseAsset)
+	if b.free+floatEpsilon < o.OrigQty {
+		return newAPIError(CodeInsufficientBalance, "Insufficient position")
+	}
+	b.free -= o.OrigQty
+	b.locked += o.OrigQty
+	o.reserved = o.OrigQty
+	return nil
+}
+
+func (e *Exchange) release(m *market, o *Order) {
+	if o.reserved <= 0 {
+		return
+	}
+	asset := m.detail.BaseAsset
+	if o.Side == "BUY" {
+		asset = m.detail.QuoteAsset
+	}
+	b := e.balance(asset)
+	b.locked -= o.reserved
+	b.free += o.reserved
+	o.reserved = 0
+}
+
+func (e *Exchange) crossingQty(m *market, o *Order) float64 {
+	total := 0.0
+	levels := m.asks
+	if o.Side == "SELL" {
+		levels = m.bids
+	}
+	for _, level := range levels {
+		if o.Side == "BUY" && level.Price > o.Price+floatEpsilon ||
+			o.Side == "SELL" && level.Price < o.Price-floatEpsilon {
+			break
+		}
+		total += level.Qty
+	}
+	return total
+}
+
+// takeLiquidity исполняет лимитную заявку против внешнего стакана как taker.
+func (e *Exchange) takeLiquidity(m *market, o *Order) {
+	for o.OrigQty-o.ExecutedQty > floatEpsilon {
+		levels := &m.asks
+		if o.Side == "SELL" {
+			levels = &m.bids
+		}
+		if len(*levels) == 0 {
+			return
+		}
+		top := &(*levels)[0]
+		if o.Side == "BUY" && top.Price > o.Price+floatEpsilon ||
+			o.Side == "SELL" && top.Price < o.Price-floatEpsilon {
+			return
+		}
+		qty := math.Min(top.Qty, o.OrigQty-o.ExecutedQty)
+		e.fill(m, o, qty, top.Price, false)
+		consumeTop(levels, qty)
+	}
+}
+
+// executeMarket исполняет рыночную заявку по стакану; невыбранный остаток
+// отменяется (PARTIALLY_CANCELED), как это делает MEXC.
+func (e *Exchange) executeMarket(m *market, o *Order, quoteOrderQty float64) error {
+	levels := &m.asks
+	if o.Side == "SELL" {
+		levels = &m.bids
+	}
+	step := lotStep(m.detail)
+
+	if o.Side == "SELL" {
+		if e.balance(m.detail.BaseAsset).free+floatEpsilon < o.OrigQty {
+			return newAPIError(CodeInsufficientBalance, "Insufficient position")
+		}
+	} else {
+		cost := quoteOrderQty
+		if cost <= 0 {
+			cost = marketCost(*levels, o.OrigQty)
+		}
+		if e.balance(m.detail.QuoteAsset).free+floatEpsilon < cost {
+			return newAPIError(CodeInsufficientBalance, "Insufficient position")
+		}
+	}
+
+	if quoteOrderQty > 0 && o.OrigQty <= 0 {
+		o.Price = 0
+		remainingQuote := quoteOrderQty
+		for remainingQuote > floatEpsilon && len(*levels) > 0 {
+			top := (*levels)[0]
+			qty := math.Min(top.Qty, remainingQuote/top.Price)
+			if step > 0 {
+				qty = math.Floor(qty/step+1e-9) * step
+			}
+			if qty <= floatEpsilon {
+				break
+			}
+			o.OrigQty += qty
+			e.fill(m, o, qty, top.Price, false)
+			consumeTop(levels, qty)
+			remainingQuote -= qty * top.Price
+		}
+	} else {
+		for o.OrigQty-o.ExecutedQty > floatEpsilon && len(*levels) > 0 {
+			top := (*levels)[0]
+			qty := math.Min(top.Qty, o.OrigQty-o.ExecutedQty)
+			e.fill(m, o, qty, top.Price, false)
+			consumeTop(levels, qty)
+		}
+	}
+
+	switch {
+	case o.ExecutedQty <= floatEpsilon:
+		o.Status = StatusCanceled
+	case o.OrigQty-o.ExecutedQty > floatEpsilon:
+		o.Status = StatusPartiallyCanceled
+	}
+	return nil
+}
+
+// matchResting исполняет стоящие ордера аккаунта против нового стакана.
+func (e *Exchange) matchResting(m *market) {
+	for _, id := range e.orderSeq {
+		o := e.orders[id]
+		if o.Symbol != m.detail.Symbol || !o.IsActive() {
+			continue
+		}
+		levels := &m.asks
+		if o.Side == "SELL" {
+			levels = &m.bids
+		}
+		for o.OrigQty-o.ExecutedQty > floatEpsilon && len(*levels) > 0 {
+			top := (*levels)[0]
+			if o.Side == "BUY" && top.Price > o.Price+floatEpsilon ||
+				o.Side == "SELL" && top.Price < o.Price-floatEpsilon {
+				break
+			}
+			qty := math.Min(top.Qty, o.OrigQty-o.ExecutedQty)
+			e.fill(m, o, qty, o.Price, true)
+			consumeTop(levels, qty)
+		}
+	}
+}
+
+// fill применяет исполнение qty по price: двигает балансы, комиссию и статус.
+// Комиссия списывается с получаемого актива (base для BUY, quote для SELL).
+func (e *Exchange) fill(m *market, o *Order, qty, price float64, maker bool) {
+	if qty <= floatEpsilon {
+		return
+	}
+	rate := parseFloat(m.detail.TakerCommission)
+	if maker {
+		rate = parseFloat(m.detail.MakerCommission)
+	}
+	quote := qty * price
+	base := e.balance(m.detail.BaseAsset)
+	quoteBalance := e.balance(m.detail.QuoteAsset)
+
+	if o.Side == "BUY" {
+		if o.Type == "MARKET" {
+			quoteBalance.free -= quote
+		} else {
+			// Резерв был по цене лимита; разница при лучшей цене возвращается.
+			reserved := qty * o.Price
+			quoteBalance.locked -= reserved
+			quoteBalance.free += reserved - quote
+			o.reserved -= reserved
+		}
+		fee := qty * rate
+		base.free += qty - fee
+		o.Fee += fee
+		o.FeeAsset = m.detail.BaseAsset
+	} else {
+		if o.Type == "MARKET" {
+			base.free -= qty
+		} else {
+			base.locked -= qty
+			o.reserved -= qty
+		}
+		fee := quote * rate
+		quoteBalance.free += quote - fee
+		o.Fee += fee
+		o.FeeAsset = m.detail.QuoteAsset
+	}
+
+	o.ExecutedQty += qty
+	o.CummulativeQuoteQty += quote
+	o.UpdateTime = e.now().UnixMilli()
+	if o.OrigQty-o.ExecutedQty <= floatEpsilon {
+		o.Status = StatusFilled
+		if o.Type != "MARKET" {
+			e.release(m, o)
+		}
+	} else {
+		o.Status = StatusPartiallyFilled
+	}
+	m.lastPrice = price
+	m.quoteVolume24h += quote
+	e.orderChanged(o)
+}
+
+func (e *Exchange) cancelRemainder(m *market, o *Order) {
+	if !o.IsActive() {
+		return
+	}
+	e.release(m, o)
+	if o.ExecutedQty > floatEpsilon {
+		o.Status = StatusPartiallyCanceled
+	} else {
+		o.Status = StatusCanceled
+	}
+	o.UpdateTime = e.now().UnixMilli()
+	e.orderChanged(o)
+}
+
+func (e *Exchange) balance(asset string) *balance {
+	b, ok := e.balances[asset]
+	if !ok {
+		b = &balance{}
+		e.balances[asset] = b
+	}
+	return b
+}
+
+func consumeTop(levels *[]Level, qty float64) {
+	(*levels)[0].Qty -= qty
+	if (*levels)[0].Qty <= floatEpsilon {
+		*levels = (*levels)[1:]
+	}
+}
+
+func marketCost(levels []Level, qty float64) float64 {
+	cost := 0.0
+	for _, level := range levels {
+		if qty <= floatEpsilon {
+			break
+		}
+		take := math.Min(level.Qty, qty)
+		cost += take * level.Price
+		qty -= take
+	}
+	return cost
+}
+
+func referencePrice(m *market, side string) float64 {
+	if side == "BUY" && len(m.asks) > 0 {
+		return m.asks[0].Price
+	}
+	if side == "SELL" && len(m.bids) > 0 {
+		return m.bids[0].Price
+	}
+	return m.lastPrice
+}
+
+func orderTypeAllowed(allowed []string, orderType string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, t := range allowed {
+		t = strings.ToUpper(t)
+		if t == orderType {
+			return true
+		}
+		// IOC/FOK на MEXC — модификаторы лимитной заявки.
+		if (t == "LIMIT" || t == "LIMIT_ORDER") &&
+			(orderType == "LIMIT" || orderType == "IMMEDIATE_OR_CANCEL" || orderType == "FILL_OR_KILL") {
+			return true
+		}
+	}
+	return false
+}
+
+// lotStep повторяет логику swapLotStep: LOT_SIZE.stepSize, затем baseSizePrecision.
+func lotStep(d mexc.SymbolDetail) float64 {
+	for _, f := range d.Filters {
+		if f.FilterType == "LOT_SIZE" {
+			if step := parseFloat(f.StepSize); step > 0 {
+				return step
+			}
+		}
+	}
+	if step := parseFloat(d.BaseSizePrecision); step > 0 {
+		return step
+	}
+	if d.BaseAssetPrecision > 0 {
+		return math.Pow(10, -d.BaseAssetPrecision)
+	}
+	return 0
+}
+
+// priceStep повторяет логику signalPriceStep: PRICE_FILTER.stepSize, затем quotePrecision.
+func priceStep(d mexc.SymbolDetail) float64 {
+	for _, f := range d.Filters {
+		if f.FilterType == "PRICE_FILTER" {
+			if step := parseFloat(f.StepSize); step > 0 {
+				return step
+			}
+		}
+	}
+	if d.QuotePrecision > 0 {
+		return math.Pow(10, -float64(d.QuotePrecision))
+	}
+	return 0
+}
+
+func aligned(value, step float64) bool {
+	if step <= 0 {
+		return true
+	}
+	ratio := value / step
+	return math.Abs(ratio-math.Round(ratio)) <= 1e-8*math.Max(1, math.Abs(ratio))
+}
+
+func parseFloat(value string) float64 {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || parsed < 0 {
+		return 0
+	}
+	return parsed
+}
+
+func cloneLevels(levels []Level) []Level {
+	out := make([]Level, 0, len(levels))
+	for _, level := range levels {
+		if level.Price > 0 && level.Qty > 0 {
+			out = append(out, level)
+		}
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package mexcsim
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+func testSymbol() mexc.SymbolDetail {
+	return mexc.SymbolDetail{
+		Symbol:               "AAAUSDT",
+		Status:               "1",
+		BaseAsset:            "AAA",
+		QuoteAsset:           "USDT",
+		QuotePrecision:       4,
+		OrderTypes:           []string{"LIMIT", "MARKET", "LIMIT_MAKER"},
+		IsSpotTradingAllowed: true,
+		QuoteAmountPrecision: "1",
+		MaxQuoteAmount:       "100000",
+		MakerCommission:      "0",
+		TakerCommission:      "0.001",
+		Filters:              []mexc.SymbolFilter{{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.01"}},
+	}
+}
+
+func newTestExchange() *Exchange {
+	e := NewExchange()
+	e.AddSymbol(testSymbol())
+	e.SetBalance("USDT", 100)
+	e.SetBook("AAAUSDT", []Level{{Price: 0.99, Qty: 100}}, []Level{{Price: 1.01, Qty: 3}, {Price: 1.02, Qty: 100}})
+	return e
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPlaceOrder_limitBuyRestsAndLocksQuote(t *testing.T) {
+	e := newTestExchange()
+	o, err := e.PlaceOrder(OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "LIMIT", Price: 1.0, Quantity: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.Status != StatusNew {
+		t.Fatalf("expected NEW, got %s", o.Status)
+	}
+	free, locked := e.Balance("USDT")
+	if !almostEqual(free, 90) || !almostEqual(locked, 10) {
+		t.Fatalf("unexpected USDT balance free=%f locked=%f", free, locked)
+	}
+}
+
+func TestPlaceOrder_crossingLimitPartiallyFillsAsTaker(t *testing.T) {
+	e := newTestExchange()
+	o, err := e.PlaceOrder(OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "LIMIT", Price: 1.01, Quantity: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.Status != StatusPartiallyFilled || !almostEqual(o.ExecutedQty, 3) {
+		t.Fatalf("expected partial fill of 3, got %s %f", o.Status, o.ExecutedQty)
+	}
+	base, _ := e.Balance("AAA")
+	if !almostEqual(base, 3*(1-0.001)) {
+		t.Fatalf("expected taker fee deducted from base, got %f", base)
+	}
+
+	canceled, err := e.CancelOrder("AAAUSDT", o.OrderID, "")
+	if err != nil {
+		t.Fatalf("unexpected cancel error: %v", err)
+	}
+	if canceled.Status != StatusPartiallyCanceled {
+		t.Fatalf("expected PARTIALLY_CANCELED, got %s", canceled.Status)
+	}
+	free, locked := e.Balance("USDT")
+	if !almostEqual(free, 100-3*1.01) || !almostEqual(locked, 0) {
+		t.Fatalf("unexpected USDT after cancel free=%f locked=%f", free, locked)
+	}
+}
+
+func TestSetBook_fillsRestingOrderAsMaker(t *testing.T) {
+	e := newTestExchange()
+	o, _ := e.PlaceOrder(OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "LIMIT", Price: 1.0, Quantity: 10, ClientOrderID: "c1"})
+	e.SetBook("AAAUSDT", []Level{{Price: 0.98, Qty: 100}}, []Level{{Price: 0.995, Qty: 4}})
+
+	got, err := e.QueryOrder("AAAUSDT", "", "c1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.OrderID != o.OrderID || got.Status != StatusPartiallyFilled || !almostEqual(got.ExecutedQty, 4) {
+		t.Fatalf("unexpected order state: %+v", got)
+	}
+	if !almostEqual(got.CummulativeQuoteQty, 4) {
+		t.Fatalf("maker fill must use own limit price, got quote %f", got.CummulativeQuoteQty)
+	}
+	base, _ := e.Balance("AAA")
+	if !almostEqual(base, 4) {
+		t.Fatalf("expected zero maker fee, got base %f", base)
+	}
+}
+
+func TestPlaceOrder_marketSellRemainderIsCanceled(t *testing.T) {
+	e := newTestExchange()
+	e.SetBalance("AAA", 200)
+	o, err := e.PlaceOrder(OrderRequest{Symbol: "AAAUSDT", Side: "SELL", Type: "MARKET", Quantity: 150})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o.Status != StatusPartiallyCanceled || !almostEqual(o.ExecutedQty, 100) {
+		t.Fatalf("expected PARTIALLY_CANCELED with 100 filled, got %s %f", o.Status, o.ExecutedQty)
+	}
+	usdt, _ := e.Balance("USDT")
+	if !almostEqual(usdt, 100+99*(1-0.001)) {
+		t.Fatalf("unexpected USDT after market sell: %f", usdt)
+	}
+}
+
+func TestPlaceOrder_filtersAndErrors(t *testing.T) {
+	e := newTestExchange()
+	cases := []struct {
+		name string
+		req  OrderRequest
+		code int
+	}{
+		{"unknown symbol", OrderRequest{Symbol: "XXXUSDT", Side: "BUY", Type: "LIMIT", Price: 1, Quantity: 10}, CodeInvalidSymbol},
+		{"lot step", OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "LIMIT", Price: 1, Quantity: 10.005}, CodeBadParameter},
+		{"price step", OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "LIMIT", Price: 1.00005, Quantity: 10}, CodeBadParameter},
+		{"min notional", OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "LIMIT", Price: 1, Quantity: 0.5}, CodeMinNotional},
+		{"balance", OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "LIMIT", Price: 1, Quantity: 500}, CodeInsufficientBalance},
+		{"maker crosses", OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "LIMIT_MAKER", Price: 1.01, Quantity: 5}, CodeWouldMatch},
+		{"type not allowed", OrderRequest{Symbol: "AAAUSDT", Side: "BUY", Type: "STOP_LIMIT", Price: 1, Quantity: 5}, CodeOrderTypeNotAllowed},
+	}
+	for _, tc := range cases {
+		_, err := e.PlaceOrder(tc.req)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Code != tc.code {
+			t.Fatalf("%s: expected code %d, got %v", tc.name, tc.code, err)
+		}
+	}
+}
+
+func TestQueryOrder_missingOrder(t *testing.T) {
+	e := newTestExchange()
+	_, err := e.QueryOrder("AAAUSDT", "", "unknown")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != CodeOrderNotExist {
+		t.Fatalf("expected order not exist, got %v", err)
+	}
+}
@@ -0,0 +1,506 @@
+package mexcsim
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+// NewServer поднимает httptest-сервер с REST API симулятора. BaseUrl для
+// config.MexcConfig — server.URL.
+func NewServer(e *Exchange) *httptest.Server {
+	return httptest.NewServer(e.Handler())
+}
+
+// Handler возвращает http.Handler с эндпоинтами /api/v3, которые использует
+// webapi.MexcWebapi. Подпись приватных запросов проверяется только после
+// RequireSignature.
+func (e *Exchange) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/time", e.handleTime)
+	mux.HandleFunc("GET /api/v3/ticker/bookTicker", e.handleBookTicker)
+	mux.HandleFunc("GET /api/v3/ticker/24hr", e.handleTicker24h)
+	mux.HandleFunc("GET /api/v3/ticker/price", e.handleTickerPrice)
+	mux.HandleFunc("GET /api/v3/exchangeInfo", e.handleExchangeInfo)
+	mux.HandleFunc("GET /api/v3/klines", e.handleKlines)
+	mux.HandleFunc("GET /api/v3/depth", e.handleDepth)
+	mux.HandleFunc("GET /api/v3/trades", e.handleTrades)
+	mux.HandleFunc("GET /api/v3/aggTrades", e.handleAggTrades)
+	mux.HandleFunc("GET /api/v3/avgPrice", e.handleAvgPrice)
+	mux.HandleFunc("GET /api/v3/account", e.signed(e.handleAccount))
+	mux.HandleFunc("POST /api/v3/order", e.signed(e.handleNewOrder))
+	mux.HandleFunc("DELETE /api/v3/order", e.signed(e.handleCancelOrder))
+	mux.HandleFunc("GET /api/v3/order", e.signed(e.handleQueryOrder))
+	mux.HandleFunc("GET /api/v3/openOrders", e.signed(e.handleOpenOrders))
+	mux.HandleFunc("POST /api/v3/userDataStream", e.signed(e.handleCreateListenKey))
+	mux.HandleFunc("PUT /api/v3/userDataStream", e.signed(e.handleKeepAliveListenKey))
+	mux.HandleFunc("DELETE /api/v3/userDataStream", e.signed(e.handleCloseListenKey))
+	return mux
+}
+
+// signed проверяет подпись приватного запроса, если она включена через
+// RequireSignature. Подписывается строка query без параметра signature,
+// который MEXC ожидает последним.
+func (e *Exchange) signed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		e.mu.Lock()
+		apiKey, secret, now := e.apiKey, e.secret, e.now()
+		e.mu.Unlock()
+		if secret == "" {
+			next(w, r)
+			return
+		}
+		if r.Header.Get("X-MEXC-APIKEY") != apiKey {
+			writeError(w, &APIError{HTTPStatus: http.StatusUnauthorized, Code: CodeAPIKeyInvalid, Msg: "Api key info invalid"})
+			return
+		}
+		payload, signature, ok := strings.Cut(r.URL.RawQuery, "&signature=")
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(payload))
+		if !ok || !hmac.Equal([]byte(signature), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+			writeError(w, newAPIError(CodeSignatureInvalid, "Signature for this request is not valid."))
+			return
+		}
+		q := r.URL.Query()
+		timestamp, _ := strconv.ParseInt(q.Get("timestamp"), 10, 64)
+		recvWindow, err := strconv.ParseInt(q.Get("recvWindow"), 10, 64)
+		if err != nil || recvWindow <= 0 {
+			recvWindow = 5000
+		}
+		serverMs := now.UnixMilli()
+		if timestamp > serverMs+1000 || serverMs-timestamp > recvWindow {
+			writeError(w, newAPIError(CodeTimestampOutside, "Timestamp for this request is outside of the recvWindow."))
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (e *Exchange) handleTime(w http.ResponseWriter, _ *http.Request) {
+	e.mu.Lock()
+	now := e.now().UnixMilli()
+	e.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]int64{"serverTime": now})
+}
+
+func (e *Exchange) handleBookTicker(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	symbol := r.URL.Query().Get("symbol")
+	out := make(mexc.BookTickers, 0, len(e.symbolOrder))
+	for _, name := range e.symbolOrder {
+		if symbol != "" && name != symbol {
+			continue
+		}
+		m := e.markets[name]
+		book := mexc.BookTicker{Symbol: name, BidPrice: "0", BidQty: "0", AskPrice: "0", AskQty: "0"}
+		if len(m.bids) > 0 {
+			book.BidPrice = formatFloat(m.bids[0].Price)
+			book.BidQty = formatFloat(m.bids[0].Qty)
+		}
+		if len(m.asks) > 0 {
+			book.AskPrice = formatFloat(m.asks[0].Price)
+			book.AskQty = formatFloat(m.asks[0].Qty)
+		}
+		out = append(out, book)
+	}
+	if symbol != "" {
+		if len(out) == 0 {
+			writeError(w, newAPIError(CodeInvalidSymbol, "Invalid symbol."))
+			return
+		}
+		writeJSON(w, http.StatusOK, out[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (e *Exchange) handleDepth(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	q := r.URL.Query()
+	m, ok := e.markets[q.Get("symbol")]
+	if !ok {
+		writeError(w, newAPIError(CodeInvalidSymbol, "Invalid symbol."))
+		return
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"lastUpdateId": e.now().UnixMilli(),
+		"bids":         depthLevels(m.bids, limit),
+		"asks":         depthLevels(m.asks, limit),
+	})
+}
+
+func depthLevels(levels []Level, limit int) [][]string {
+	out := make([][]string, 0, min(limit, len(levels)))
+	for _, level := range levels {
+		if len(out) >= limit {
+			break
+		}
+		out = append(out, []string{formatFloat(level.Price), formatFloat(level.Qty)})
+	}
+	return out
+}
+
+func (e *Exchange) handleTrades(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	q := r.URL.Query()
+	m, ok := e.markets[q.Get("symbol")]
+	if !ok {
+		writeError(w, newAPIError(CodeInvalidSymbol, "Invalid symbol."))
+		return
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 500
+	}
+	trades := m.trades
+	if len(trades) > limit {
+		trades = trades[len(trades)-limit:]
+	}
+	out := make([]map[string]interface{}, 0, len(trades))
+	for _, trade := range trades {
+		out = append(out, map[string]interface{}{
+			"id":           nil,
+			"price":        formatFloat(trade.Price),
+			"qty":          formatFloat(trade.Qty),
+			"quoteQty":     formatFloat(trade.Quote()),
+			"time":         trade.Time.UnixMilli(),
+			"isBuyerMaker": trade.BuyerMaker,
+			"isBestMatch":  true,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (e *Exchange) handleAggTrades(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	q := r.URL.Query()
+	m, ok := e.markets[q.Get("symbol")]
+	if !ok {
+		writeError(w, newAPIError(CodeInvalidSymbol, "Invalid symbol."))
+		return
+	}
+	startTime, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+	endTime, _ := strconv.ParseInt(q.Get("endTime"), 10, 64)
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 500
+	}
+	out := make([]map[string]interface{}, 0)
+	for _, trade := range m.trades {
+		ts := trade.Time.UnixMilli()
+		if (startTime > 0 && ts < startTime) || (endTime > 0 && ts > endTime) {
+			continue
+		}
+		if len(out) >= limit {
+			break
+		}
+		out = append(out, map[string]interface{}{
+			"a": nil,
+			"f": nil,
+			"l": nil,
+			"p": formatFloat(trade.Price),
+			"q": formatFloat(trade.Qty),
+			"T": ts,
+			"m": trade.BuyerMaker,
+			"M": true,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (e *Exchange) handleTicker24h(w http.ResponseWriter, _ *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make(mexc.Tickers24h, 0, len(e.symbolOrder))
+	for _, name := range e.symbolOrder {
+		m := e.markets[name]
+		out = append(out, mexc.Ticker24h{
+			Symbol:             name,
+			LastPrice:          formatFloat(m.lastPrice),
+			PriceChangePercent: formatFloat(m.changePercent),
+			QuoteVolume:        formatFloat(m.quoteVolume24h),
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (e *Exchange) handleTickerPrice(w http.ResponseWriter, _ *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make(mexc.TickersWithPrice, 0, len(e.symbolOrder))
+	for _, name := range e.symbolOrder {
+		out = append(out, mexc.TickerWithPrice{Symbol: name, Price: formatFloat(e.markets[name].lastPrice)})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (e *Exchange) handleExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	symbol := r.URL.Query().Get("symbol")
+	info := mexc.SymbolInfo{Timezone: "CST", ServerTime: e.now().UnixMilli(), Symbols: []mexc.SymbolDetail{}}
+	for _, name := range e.symbolOrder {
+		if symbol != "" && name != symbol {
+			continue
+		}
+		info.Symbols = append(info.Symbols, e.markets[name].detail)
+	}
+	if symbol != "" && len(info.Symbols) == 0 {
+		writeError(w, newAPIError(CodeInvalidSymbol, "Invalid symbol."))
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// handleKlines отдаёт заданную через SetKlines историю; interval не
+// учитывается, фильтруются только startTime и limit.
+func (e *Exchange) handleKlines(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	q := r.URL.Query()
+	m, ok := e.markets[q.Get("symbol")]
+	if !ok {
+		writeError(w, newAPIError(CodeInvalidSymbol, "Invalid symbol."))
+		return
+	}
+	startTime, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 500
+	}
+	out := make([][]interface{}, 0)
+	for _, k := range m.klines {
+		if startTime > 0 && k.OpenTime < startTime {
+			continue
+		}
+		if len(out) >= limit {
+			break
+		}
+		out = append(out, []interface{}{
+			k.OpenTime,
+			formatFloat(k.Open),
+			formatFloat(k.High),
+			formatFloat(k.Low),
+			formatFloat(k.Close),
+			formatFloat(k.Volume),
+			k.CloseTime,
+			formatFloat(k.QuoteAssetVolume),
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (e *Exchange) handleAvgPrice(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	m, ok := e.markets[r.URL.Query().Get("symbol")]
+	if !ok {
+		writeError(w, newAPIError(CodeInvalidSymbol, "Invalid symbol."))
+		return
+	}
+	price := m.lastPrice
+	if len(m.bids) > 0 && len(m.asks) > 0 {
+		price = (m.bids[0].Price + m.asks[0].Price) / 2
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"mins": 5, "price": formatFloat(price)})
+}
+
+func (e *Exchange) handleAccount(w http.ResponseWriter, _ *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	type balanceJSON struct {
+		Asset     string `json:"asset"`
+		Free      string `json:"free"`
+		Locked    string `json:"locked"`
+		Available string `json:"available"`
+	}
+	balances := make([]balanceJSON, 0, len(e.balances))
+	for _, asset := range sortedKeys(e.balances) {
+		b := e.balances[asset]
+		balances = append(balances, balanceJSON{
+			Asset:     asset,
+			Free:      formatFloat(b.free),
+			Locked:    formatFloat(b.locked),
+			Available: formatFloat(b.free),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"makerCommission":  0,
+		"takerCommission":  0,
+		"buyerCommission":  0,
+		"sellerCommission": 0,
+		"canTrade":         true,
+		"canWithdraw":      true,
+		"canDeposit":       true,
+		"updateTime":       nil,
+		"accountType":      "SPOT",
+		"balances":         balances,
+		"permissions":      []string{"SPOT"},
+	})
+}
+
+func (e *Exchange) handleNewOrder(w http.ResponseWriter, r *http.Request) {
+	q := requestParams(r)
+	req := OrderRequest{
+		Symbol:        q.Get("symbol"),
+		Side:          q.Get("side"),
+		Type:          q.Get("type"),
+		Price:         parseFloat(q.Get("price")),
+		Quantity:      parseFloat(q.Get("quantity")),
+		QuoteOrderQty: parseFloat(q.Get("quoteOrderQty")),
+		ClientOrderID: q.Get("newClientOrderId"),
+	}
+	o, err := e.PlaceOrder(req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if e.takeDropOrderResponse() {
+		writeJSON(w, http.StatusGatewayTimeout, map[string]interface{}{"code": 504, "msg": "gateway timeout"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"symbol":       o.Symbol,
+		"orderId":      o.OrderID,
+		"orderListId":  -1,
+		"price":        formatFloat(o.Price),
+		"origQty":      formatFloat(o.OrigQty),
+		"type":         o.Type,
+		"side":         o.Side,
+		"stpMode":      "",
+		"transactTime": o.Time,
+	})
+}
+
+func (e *Exchange) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	q := requestParams(r)
+	o, err := e.CancelOrder(q.Get("symbol"), q.Get("orderId"), q.Get("origClientOrderId"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, orderJSON(o))
+}
+
+func (e *Exchange) handleQueryOrder(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	o, err := e.QueryOrder(q.Get("symbol"), q.Get("orderId"), q.Get("origClientOrderId"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, orderJSON(o))
+}
+
+func (e *Exchange) handleOpenOrders(w http.ResponseWriter, r *http.Request) {
+	orders := e.OpenOrders(r.URL.Query().Get("symbol"))
+	out := make([]map[string]interface{}, 0, len(orders))
+	for _, o := range orders {
+		out = append(out, orderJSON(o))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (e *Exchange) handleCreateListenKey(w http.ResponseWriter, _ *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nextListenKey++
+	listenKey := "sim-listen-key-" + strconv.Itoa(e.nextListenKey)
+	e.listenKeys[listenKey] = true
+	writeJSON(w, http.StatusOK, map[string]string{"listenKey": listenKey})
+}
+
+func (e *Exchange) handleKeepAliveListenKey(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	listenKey := requestParams(r).Get("listenKey")
+	if !e.listenKeys[listenKey] {
+		writeError(w, newAPIError(CodeBadParameter, "listenKey does not exist"))
+		return
+	}
+	e.keepAlives++
+	writeJSON(w, http.StatusOK, map[string]string{"listenKey": listenKey})
+}
+
+func (e *Exchange) handleCloseListenKey(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	listenKey := requestParams(r).Get("listenKey")
+	delete(e.listenKeys, listenKey)
+	writeJSON(w, http.StatusOK, map[string]string{"listenKey": listenKey})
+}
+
+func orderJSON(o Order) map[string]interface{} {
+	return map[string]interface{}{
+		"symbol":              o.Symbol,
+		"orderId":             o.OrderID,
+		"orderListId":         -1,
+		"clientOrderId":       o.ClientOrderID,
+		"price":               formatFloat(o.Price),
+		"origQty":             formatFloat(o.OrigQty),
+		"executedQty":         formatFloat(o.ExecutedQty),
+		"cummulativeQuoteQty": formatFloat(o.CummulativeQuoteQty),
+		"status":              o.Status,
+		"timeInForce":         "",
+		"type":                o.Type,
+		"side":                o.Side,
+		"stopPrice":           "",
+		"icebergQty":          "",
+		"time":                o.Time,
+		"updateTime":          o.UpdateTime,
+		"isWorking":           true,
+		"stpMode":             "",
+		"origQuoteOrderQty":   "",
+	}
+}
+
+// requestParams объединяет query и form: MEXC принимает подписанные
+// параметры в любом из них.
+func requestParams(r *http.Request) url.Values {
+	_ = r.ParseForm()
+	return r.Form
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		apiErr = &APIError{HTTPStatus: http.StatusInternalServerError, Code: 500, Msg: err.Error()}
+	}
+	writeJSON(w, apiErr.HTTPStatus, map[string]interface{}{"code": apiErr.Code, "msg": apiErr.Msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func sortedKeys(m map[string]*balance) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
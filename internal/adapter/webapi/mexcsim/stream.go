@@ -0,0 +1,204 @@
+package mexcsim
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"golang.org/x/net/websocket"
+)
+
+// Stream — стенд публичного WebSocket MEXC spot v3 (JSON протокол):
+// принимает SUBSCRIPTION/PING и рассылает подписчикам то, что тест
+// публикует через PublishBookTicker/PublishKline. Приватный канал ордеров
+// доступен соединениям с listenKey в URL; его наполняет PublishOrder.
+// DropConnections рвёт все соединения, чтобы проверить переподключение
+// клиента.
+type Stream struct {
+	mu    sync.Mutex
+	conns map[*websocket.Conn]map[string]bool
+	subs  int
+	subCh chan struct{}
+}
+
+func NewStream() *Stream {
+	return &Stream{
+		conns: map[*websocket.Conn]map[string]bool{},
+		subCh: make(chan struct{}, 64),
+	}
+}
+
+// NewStreamServer поднимает httptest-сервер стенда; адрес для клиента —
+// StreamURL(server).
+func NewStreamServer(s *Stream) *httptest.Server {
+	return httptest.NewServer(s.Handler())
+}
+
+// StreamURL переводит адрес httptest-сервера в ws://.
+func StreamURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func (s *Stream) Handler() http.Handler {
+	// websocket.Server без Handshake не проверяет Origin.
+	return websocket.Server{Handler: s.serve}
+}
+
+func (s *Stream) serve(conn *websocket.Conn) {
+	private := conn.Request().URL.Query().Get("listenKey") != ""
+	s.mu.Lock()
+	s.conns[conn] = map[string]bool{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	for {
+		var req struct {
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		if err := websocket.JSON.Receive(conn, &req); err != nil {
+			return
+		}
+		switch req.Method {
+		case "PING":
+			s.send(conn, map[string]any{"id": 0, "code": 0, "msg": "PONG"})
+		case "SUBSCRIPTION":
+			s.mu.Lock()
+			channels, ok := s.conns[conn]
+			if ok {
+				for _, channel := range req.Params {
+					if strings.HasPrefix(channel, "spot@private.") && !private {
+						continue
+					}
+					channels[channel] = true
+				}
+			}
+			s.subs++
+			s.mu.Unlock()
+			s.send(conn, map[string]any{"id": 0, "code": 0, "msg": strings.Join(req.Params, ",")})
+			select {
+			case s.subCh <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Subscriptions — сколько SUBSCRIPTION-запросов принял стенд за всё время.
+func (s *Stream) Subscriptions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subs
+}
+
+// WaitSubscriptions ждёт, пока число принятых подписок достигнет n.
+func (s *Stream) WaitSubscriptions(n int, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for s.Subscriptions() < n {
+		select {
+		case <-s.subCh:
+		case <-deadline:
+			return false
+		}
+	}
+	return true
+}
+
+// DropConnections закрывает все открытые соединения.
+func (s *Stream) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		_ = conn.Close()
+		delete(s.conns, conn)
+	}
+}
+
+func (s *Stream) PublishBookTicker(symbol string, bid, bidQty, ask, askQty float64) {
+	s.publish("spot@public.bookTicker.v3.api@"+symbol, symbol, map[string]string{
+		"a": formatFloat(ask),
+		"A": formatFloat(askQty),
+		"b": formatFloat(bid),
+		"B": formatFloat(bidQty),
+	})
+}
+
+// PublishKline рассылает состояние минутной свечи; время окна в стриме
+// передаётся в секундах.
+func (s *Stream) PublishKline(symbol string, k mexc.Kline) {
+	s.publish("spot@public.kline.v3.api@"+symbol+"@Min1", symbol, map[string]any{
+		"k": map[string]any{
+			"t": k.OpenTime / 1000,
+			"T": (k.CloseTime + 1) / 1000,
+			"o": k.Open,
+			"c": k.Close,
+			"h": k.High,
+			"l": k.Low,
+			"v": k.Volume,
+			"a": k.QuoteAssetVolume,
+			"i": "Min1",
+		},
+	})
+}
+
+// Коды полей S, o и s приватного канала ордеров.
+var (
+	streamSideCodes      = map[string]int{"BUY": 1, "SELL": 2}
+	streamOrderTypeCodes = map[string]int{"LIMIT": 1, "LIMIT_MAKER": 2, "IMMEDIATE_OR_CANCEL": 3, "FILL_OR_KILL": 4, "MARKET": 5}
+)
+
+// PublishOrder рассылает состояние ордера в spot@private.orders.v3.api.
+// Подходит как listener для Exchange.SetOrderListener.
+func (s *Stream) PublishOrder(o Order) {
+	avgPrice := 0.0
+	if o.ExecutedQty > 0 {
+		avgPrice = o.CummulativeQuoteQty / o.ExecutedQty
+	}
+	s.publish("spot@private.orders.v3.api", o.Symbol, map[string]any{
+		"i":  o.OrderID,
+		"c":  o.ClientOrderID,
+		"S":  streamSideCodes[o.Side],
+		"o":  streamOrderTypeCodes[o.Type],
+		"p":  o.Price,
+		"v":  o.OrigQty,
+		"a":  o.Price * o.OrigQty,
+		"s":  mexc.StreamOrderStatusCode(o.Status),
+		"ap": avgPrice,
+		"cv": o.ExecutedQty,
+		"ca": o.CummulativeQuoteQty,
+		"V":  o.OrigQty - o.ExecutedQty,
+		"O":  o.Time,
+	})
+}
+
+func (s *Stream) publish(channel, symbol string, data any) {
+	msg := map[string]any{"c": channel, "d": data, "s": symbol, "t": time.Now().UnixMilli()}
+	s.mu.Lock()
+	targets := make([]*websocket.Conn, 0, len(s.conns))
+	for conn, channels := range s.conns {
+		if channels[channel] {
+			targets = append(targets, conn)
+		}
+	}
+	s.mu.Unlock()
+	for _, conn := range targets {
+		s.send(conn, msg)
+	}
+}
+
+func (s *Stream) send(conn *websocket.Conn, msg any) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	_ = websocket.Message.Send(conn, string(body))
+}
@@ -37,6 +37,8 @@ func Run(config *config.Config) error {
 		command.NewPalisadeProcessManualCommand(cnt.Usecases.PalisadeProcessManual),
 		command.NewPalisadeProcessSellManualCommand(cnt.Usecases.PalisadeProcessSellManual),
 		command.NewSwapProcessCommand(cnt.Usecases.SwapProcess),
+		command.NewSwapReportCommand(cnt.Usecases.SwapReport),
+		command.NewSwapMonitorCommand(cnt.Usecases.SwapMonitor),
 		command.NewGetCoinListCommand(cnt.Usecases.GetCoinList),
 		command.NewCheckPalisadeCoinListCommand(cnt.Usecases.CheckPalisadeCoinList),
 		command.NewCheckPalisadeCoinCommand(cnt.Usecases.CheckPalisadeCoin),
@@ -44,10 +46,16 @@ func Run(config *config.Config) error {
 		command.NewSyncTrendBarsCommand(cnt.Usecases.SyncTrendBars),
 		command.NewCheckTrendRetestCommand(cnt.Usecases.CheckTrendRetest),
 		command.NewCollectMarketDataCommand(cnt.Usecases.CollectMarketData),
+		command.NewStreamMarketDataCommand(cnt.Usecases.StreamMarketData),
+		command.NewCollectTradeTapeCommand(cnt.Usecases.CollectTradeTape),
 		command.NewScorePalisadeCandidatesCommand(cnt.Usecases.ScorePalisadeCandidates),
 		command.NewExecutePalisadeSignalsCommand(cnt.Usecases.ExecutePalisadeSignals),
+		command.NewGridTradeCommand(cnt.Usecases.GridTrade),
 		command.NewReconcileOrdersCommand(cnt.Usecases.ReconcileOrders),
+		command.NewWatchOrderFillsCommand(cnt.Usecases.WatchOrderFills),
 		command.NewPaperTradeCommand(cnt.Usecases.PaperTrade),
+		command.NewBacktestCommand(cnt.Usecases.Backtest),
+		command.NewDaemonCommand(cnt.Usecases.Daemon),
 	}
 
 	return app.Run(os.Args)
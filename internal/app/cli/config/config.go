@@ -10,11 +10,15 @@ import (
 )
 
 type Config struct {
-	ServiceName string
-	PassPhrase  string
-	TgConfig    TgConfig
-	MexcConfig  MexcConfig
-	PostgreeDsn string
+	ServiceName  string
+	PassPhrase   string
+	TgConfig     TgConfig
+	MexcConfig   MexcConfig
+	RiskConfig   RiskConfig
+	SizingConfig SizingConfig
+	// StrategyConfig — пороги стратегии палисады (config/strategy.go).
+	StrategyConfig StrategyConfig
+	PostgreeDsn    string
 }
 
 type TgConfig struct {
@@ -29,6 +33,54 @@ type MexcConfig struct {
 	Secret    string
 	BaseUrl   string
 	BaseUrlV2 string
+	WsUrl     string
+	// RateLimitWeight — суммарный вес REST-запросов за RateLimitWindow;
+	// 0 отключает лимитер.
+	RateLimitWeight int
+	RateLimitWindow time.Duration
+	// RetryAttempts — попыток на запрос вместе с первой; 0 и 1 — без повторов.
+	RetryAttempts  int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// RecvWindow — окно приёма подписанного запроса на бирже.
+	RecvWindow time.Duration
+	// MaxClockSkew — допустимое расхождение часов с биржей, при большем
+	// ордера не выставляются; 0 отключает проверку и пересинхронизацию по
+	// ClockSyncInterval, но часы всё равно синхронизируются перед первым
+	// ордером.
+	MaxClockSkew time.Duration
+	// ClockSyncInterval — как часто пересинхронизировать часы перед ордером.
+	ClockSyncInterval time.Duration
+}
+
+// RiskConfig — портфельные лимиты на вход в позицию; нулевое значение
+// отключает лимит.
+type RiskConfig struct {
+	MaxOpenPositions      int
+	MaxSymbolExposureUSDT float64
+	MaxTotalExposureUSDT  float64
+	MaxDailyLossUSDT      float64
+	MaxConsecutiveLosses  int
+	LossCooldown          time.Duration
+}
+
+// SizingConfig — расчёт объёма входа для каждой стратегии.
+type SizingConfig struct {
+	Process      PositionSizingConfig
+	ProcessMulti PositionSizingConfig
+	Signals      PositionSizingConfig
+}
+
+// PositionSizingConfig — метод (fixed, percent, volatility, kelly) и его
+// параметры; читается из SIZING_<СТРАТЕГИЯ>_*.
+type PositionSizingConfig struct {
+	Method           string
+	QuoteUSDT        float64
+	PercentOfFree    float64
+	TargetVolatility float64
+	KellyFraction    float64
+	KellyMinTrades   int
+	MaxQuoteUSDT     float64
 }
 
 func (c Config) Validate() error {
@@ -48,9 +100,35 @@ func (c Config) Validate() error {
 		return wrap.Errorf("failed to validate cli config: %w", err)
 	}
 
+	err = c.SizingConfig.Validate()
+	if err != nil {
+		return wrap.Errorf("failed to validate cli config: %w", err)
+	}
+
 	return errors.Join(errs...)
 }
 
+func (c SizingConfig) Validate() error {
+	err := validation.ValidateStruct(&c,
+		validation.Field(&c.Process),
+		validation.Field(&c.ProcessMulti),
+		validation.Field(&c.Signals),
+	)
+	if err != nil {
+		return wrap.Errorf("failed to validate sizing config: %w", err)
+	}
+	return nil
+}
+
+func (c PositionSizingConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Method, validation.Required, validation.In("fixed", "percent", "volatility", "kelly")),
+		validation.Field(&c.QuoteUSDT, validation.Min(0.0)),
+		validation.Field(&c.PercentOfFree, validation.Min(0.0), validation.Max(100.0)),
+		validation.Field(&c.KellyFraction, validation.Min(0.0), validation.Max(1.0)),
+	)
+}
+
 func (c MexcConfig) Validate() error {
 	var errs []error
 
@@ -59,6 +137,8 @@ func (c MexcConfig) Validate() error {
 		validation.Field(&c.Secret, validation.Required),
 		validation.Field(&c.BaseUrl, validation.Required),
 		validation.Field(&c.BaseUrlV2, validation.Required),
+		// MEXC принимает recvWindow не больше 60 секунд.
+		validation.Field(&c.RecvWindow, validation.Min(time.Duration(0)), validation.Max(60*time.Second)),
 	)
 	if err != nil {
 		return wrap.Errorf("failed to validate MexC config: %w", err)
@@ -72,6 +152,12 @@ func InitConfig() (*Config, error) {
 		ServiceName: env.GetString("APP_NAME", "palisade"),
 		TgConfig:    initTgConfig(),
 		MexcConfig:  initMexcConfig(),
+		RiskConfig:  initRiskConfig(),
+		SizingConfig: SizingConfig{
+			Process:      initPositionSizingConfig("SIZING_PROCESS", 10),
+			ProcessMulti: initPositionSizingConfig("SIZING_PROCESS_MULTI", 2),
+			Signals:      initPositionSizingConfig("SIZING_SIGNALS", 10),
+		},
 		PostgreeDsn: env.GetString("POSTGREE_DSN", ""),
 	}
 
@@ -79,6 +165,12 @@ func InitConfig() (*Config, error) {
 		return nil, err
 	}
 
+	strategy, err := LoadStrategyConfig(env.GetString("STRATEGY_CONFIG", "config/strategy.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	config.StrategyConfig = strategy
+
 	return &config, nil
 }
 
@@ -97,5 +189,41 @@ func initMexcConfig() MexcConfig {
 		Secret:    env.GetString("MEXC_SECRET", ""),
 		BaseUrl:   env.GetString("MEXC_API_URL", ""),
 		BaseUrlV2: env.GetString("MEXC_API_URL_V2", ""),
+		WsUrl:     env.GetString("MEXC_WS_URL", "wss://wbs.mexc.com/ws"),
+
+		RateLimitWeight: env.GetInt("MEXC_RATE_LIMIT_WEIGHT", 500),
+		RateLimitWindow: env.GetDuration("MEXC_RATE_LIMIT_WINDOW", 10*time.Second),
+		RetryAttempts:   env.GetInt("MEXC_RETRY_ATTEMPTS", 3),
+		RetryBaseDelay:  env.GetDuration("MEXC_RETRY_BASE_DELAY", 500*time.Millisecond),
+		RetryMaxDelay:   env.GetDuration("MEXC_RETRY_MAX_DELAY", 5*time.Second),
+
+		RecvWindow:        env.GetDuration("MEXC_RECV_WINDOW", 5*time.Second),
+		MaxClockSkew:      env.GetDuration("MEXC_MAX_CLOCK_SKEW", 2*time.Second),
+		ClockSyncInterval: env.GetDuration("MEXC_CLOCK_SYNC_INTERVAL", 10*time.Minute),
+	}
+}
+
+func initRiskConfig() RiskConfig {
+	return RiskConfig{
+		MaxOpenPositions:      env.GetInt("RISK_MAX_OPEN_POSITIONS", 5),
+		MaxSymbolExposureUSDT: env.GetFloat("RISK_MAX_SYMBOL_EXPOSURE_USDT", 30),
+		MaxTotalExposureUSDT:  env.GetFloat("RISK_MAX_TOTAL_EXPOSURE_USDT", 100),
+		MaxDailyLossUSDT:      env.GetFloat("RISK_MAX_DAILY_LOSS_USDT", 5),
+		MaxConsecutiveLosses:  env.GetInt("RISK_MAX_CONSECUTIVE_LOSSES", 3),
+		LossCooldown:          env.GetDuration("RISK_LOSS_COOLDOWN", 2*time.Hour),
+	}
+}
+
+// initPositionSizingConfig по умолчанию сохраняет прежний фиксированный
+// объём стратегии.
+func initPositionSizingConfig(prefix string, quoteUSDT float64) PositionSizingConfig {
+	return PositionSizingConfig{
+		Method:           env.GetString(prefix+"_METHOD", "fixed"),
+		QuoteUSDT:        env.GetFloat(prefix+"_QUOTE_USDT", quoteUSDT),
+		PercentOfFree:    env.GetFloat(prefix+"_PERCENT", 5),
+		TargetVolatility: env.GetFloat(prefix+"_TARGET_VOLATILITY", 2),
+		KellyFraction:    env.GetFloat(prefix+"_KELLY_FRACTION", 0.25),
+		KellyMinTrades:   env.GetInt(prefix+"_KELLY_MIN_TRADES", 30),
+		MaxQuoteUSDT:     env.GetFloat(prefix+"_MAX_QUOTE_USDT", 0),
 	}
 }
@@ -0,0 +1,392 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/strategy"
+	"github.com/drybin/palisade/pkg/env"
+	"github.com/drybin/palisade/pkg/wrap"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// StrategyConfig — пороги стратегии палисады. Значения по умолчанию совпадают
+// с прежними константами; поверх них читается YAML (STRATEGY_CONFIG), затем
+// переменные окружения STRATEGY_<СЕКЦИЯ>_<ПАРАМЕТР>.
+type StrategyConfig struct {
+	// Label — имя набора настроек; входит в Version.
+	Label string `yaml:"label" json:"label"`
+	// Algorithm — реализация стратегии из реестра, ключ имя/vN: её входы и
+	// выходы используют сигналы, бумажная и живая торговля и бэктест.
+	Algorithm string                  `yaml:"algorithm" json:"algorithm"`
+	Signals   SignalStrategyConfig    `yaml:"signals" json:"signals"`
+	Execution ExecutionStrategyConfig `yaml:"execution" json:"execution"`
+	Paper     PaperStrategyConfig     `yaml:"paper" json:"paper"`
+	CoinCheck CoinCheckStrategyConfig `yaml:"coin_check" json:"coin_check"`
+	Grid      GridStrategyConfig      `yaml:"grid" json:"grid"`
+}
+
+// SignalStrategyConfig — отбор кандидатов в score-palisade-candidates и
+// бэктесте. Доли цены задаются дробью: 0.006 = 0.6%.
+type SignalStrategyConfig struct {
+	// OrderQuoteUSDT — расчётный объём сигнала и бумажной сделки.
+	OrderQuoteUSDT   float64       `yaml:"order_quote_usdt" json:"order_quote_usdt"`
+	MinVolume24h     float64       `yaml:"min_volume_24h" json:"min_volume_24h"`
+	MinNetProfit     float64       `yaml:"min_net_profit" json:"min_net_profit"`
+	MaxEntryRange    float64       `yaml:"max_entry_range" json:"max_entry_range"`
+	MinRebound       float64       `yaml:"min_rebound" json:"min_rebound"`
+	Pullback         float64       `yaml:"pullback" json:"pullback"`
+	TargetRangeShare float64       `yaml:"target_range_share" json:"target_range_share"`
+	MaxBTCDecline30m float64       `yaml:"max_btc_decline_30m" json:"max_btc_decline_30m"`
+	Cooldown         time.Duration `yaml:"cooldown" json:"cooldown"`
+	MaxCandidates    int           `yaml:"max_candidates" json:"max_candidates"`
+	MaxPerRun        int           `yaml:"max_per_run" json:"max_per_run"`
+	// DepthLimit — уровней стакана /api/v3/depth для проверки сигнала и
+	// бумажных исполнений; 0 — только лучший bid/ask.
+	DepthLimit int `yaml:"depth_limit" json:"depth_limit"`
+	// MaxSlippage — допустимое проскальзывание продажи объёма сигнала по
+	// стакану относительно лучшего bid.
+	MaxSlippage float64 `yaml:"max_slippage" json:"max_slippage"`
+	// MinRangeLiquidityUSDT — минимум заявок в стакане внутри диапазона
+	// support…resistance, USDT по обе стороны.
+	MinRangeLiquidityUSDT float64 `yaml:"min_range_liquidity_usdt" json:"min_range_liquidity_usdt"`
+	// TapeWindow — окно ленты сделок для подтверждения отскока от
+	// поддержки; 0 — лента не проверяется.
+	TapeWindow time.Duration `yaml:"tape_window" json:"tape_window"`
+	// MinTapeBuyImbalance — минимум (покупки − продажи) / (покупки +
+	// продажи) тейкеров в окне, от -1 до 1.
+	MinTapeBuyImbalance float64 `yaml:"min_tape_buy_imbalance" json:"min_tape_buy_imbalance"`
+	// MinTapeTradesPerMinute — минимум сделок в минуту в окне.
+	MinTapeTradesPerMinute float64 `yaml:"min_tape_trades_per_minute" json:"min_tape_trades_per_minute"`
+	// LargePrintMultiplier — сделка крупная, если её объём во столько раз
+	// больше среднего в окне; 0 — крупные сделки не выделяются.
+	LargePrintMultiplier float64 `yaml:"large_print_multiplier" json:"large_print_multiplier"`
+}
+
+// ExecutionStrategyConfig — сопровождение позиции в execute-palisade-signals;
+// аварийные выходы общие с бумажной торговлей.
+type ExecutionStrategyConfig struct {
+	BuyTimeout             time.Duration `yaml:"buy_timeout" json:"buy_timeout"`
+	RepriceDelta           float64       `yaml:"reprice_delta" json:"reprice_delta"`
+	SupportBreak           float64       `yaml:"support_break" json:"support_break"`
+	HardLoss               float64       `yaml:"hard_loss" json:"hard_loss"`
+	MaxPositionHold        time.Duration `yaml:"max_position_hold" json:"max_position_hold"`
+	MaxEmergencySpread     float64       `yaml:"max_emergency_spread" json:"max_emergency_spread"`
+	EmergencyPriceDiscount float64       `yaml:"emergency_price_discount" json:"emergency_price_discount"`
+	// StopLimitOffset — насколько лимитная цена стоп-ноги брекета ниже
+	// стоп-цены, чтобы SELL исполнился и при проскальзывании.
+	StopLimitOffset float64 `yaml:"stop_limit_offset" json:"stop_limit_offset"`
+	// PostOnly — BUY и SELL по цели выставляются как LIMIT_MAKER: ордер,
+	// который исполнился бы сразу, биржа отклоняет, и он переставляется на
+	// шаг цены дальше от рынка не больше PostOnlyRequeues раз.
+	PostOnly         bool `yaml:"post_only" json:"post_only"`
+	PostOnlyRequeues int  `yaml:"post_only_requeues" json:"post_only_requeues"`
+}
+
+// PaperStrategyConfig — вход и выход бумажной сделки paper-trade.
+type PaperStrategyConfig struct {
+	MaxOpenTrades       int           `yaml:"max_open_trades" json:"max_open_trades"`
+	ReboundEntry        float64       `yaml:"rebound_entry" json:"rebound_entry"`
+	MaxPullbackDepth    float64       `yaml:"max_pullback_depth" json:"max_pullback_depth"`
+	QuickProfitNet      float64       `yaml:"quick_profit_net" json:"quick_profit_net"`
+	QuickProfitShare    float64       `yaml:"quick_profit_share" json:"quick_profit_share"`
+	TrailingTrigger     float64       `yaml:"trailing_trigger" json:"trailing_trigger"`
+	TrailingDistance    float64       `yaml:"trailing_distance" json:"trailing_distance"`
+	MinimumLockedProfit float64       `yaml:"minimum_locked_profit" json:"minimum_locked_profit"`
+	EntryRunaway        float64       `yaml:"entry_runaway" json:"entry_runaway"`
+	PullbackTimeout     time.Duration `yaml:"pullback_timeout" json:"pullback_timeout"`
+}
+
+// CoinCheckStrategyConfig — перепроверка монет в check-palisade-coin-list.
+type CoinCheckStrategyConfig struct {
+	MinTimeSinceLastCheck time.Duration `yaml:"min_time_since_last_check" json:"min_time_since_last_check"`
+	// MaxVolatilityPercent — в процентах, как FlatAnalysisResult.Volatility.
+	MaxVolatilityPercent float64 `yaml:"max_volatility_percent" json:"max_volatility_percent"`
+}
+
+// GridStrategyConfig — сетка ордеров grid-trade внутри диапазона палисады.
+type GridStrategyConfig struct {
+	// Levels — уровней покупки от поддержки до середины диапазона.
+	Levels            int     `yaml:"levels" json:"levels"`
+	QuotePerLevelUSDT float64 `yaml:"quote_per_level_usdt" json:"quote_per_level_usdt"`
+	// MinLevelProfit — минимальная прибыль пары BUY/SELL после комиссий;
+	// на более узком диапазоне сетка не запускается.
+	MinLevelProfit float64 `yaml:"min_level_profit" json:"min_level_profit"`
+	// RangeBreak — насколько цена должна выйти за поддержку или
+	// сопротивление, чтобы сетка остановилась.
+	RangeBreak     float64 `yaml:"range_break" json:"range_break"`
+	MaxActiveGrids int     `yaml:"max_active_grids" json:"max_active_grids"`
+}
+
+func DefaultStrategyConfig() StrategyConfig {
+	return StrategyConfig{
+		Label:     "default",
+		Algorithm: "palisade/v8",
+		Signals: SignalStrategyConfig{
+			OrderQuoteUSDT:   10,
+			MinVolume24h:     50000,
+			MinNetProfit:     0.006,
+			MaxEntryRange:    0.20,
+			MinRebound:       0.001,
+			Pullback:         0.002,
+			TargetRangeShare: 0.40,
+			MaxBTCDecline30m: 0.005,
+			Cooldown:         60 * time.Minute,
+			MaxCandidates:    100,
+			MaxPerRun:        3,
+
+			DepthLimit:            0,
+			MaxSlippage:           0.003,
+			MinRangeLiquidityUSDT: 100,
+
+			TapeWindow:             0,
+			MinTapeBuyImbalance:    0,
+			MinTapeTradesPerMinute: 1,
+			LargePrintMultiplier:   5,
+		},
+		Execution: ExecutionStrategyConfig{
+			BuyTimeout:             10 * time.Minute,
+			RepriceDelta:           0.002,
+			SupportBreak:           0.003,
+			HardLoss:               0.008,
+			MaxPositionHold:        120 * time.Minute,
+			MaxEmergencySpread:     0.01,
+			EmergencyPriceDiscount: 0.001,
+			StopLimitOffset:        0.002,
+			PostOnlyRequeues:       3,
+		},
+		Paper: PaperStrategyConfig{
+			MaxOpenTrades:       1,
+			ReboundEntry:        0.0015,
+			MaxPullbackDepth:    0.004,
+			QuickProfitNet:      0.002,
+			QuickProfitShare:    0.5,
+			TrailingTrigger:     0.006,
+			TrailingDistance:    0.0025,
+			MinimumLockedProfit: 0.00025,
+			EntryRunaway:        0.004,
+			PullbackTimeout:     30 * time.Minute,
+		},
+		CoinCheck: CoinCheckStrategyConfig{
+			MinTimeSinceLastCheck: 180 * time.Minute,
+			MaxVolatilityPercent:  5,
+		},
+		Grid: GridStrategyConfig{
+			Levels:            4,
+			QuotePerLevelUSDT: 5,
+			MinLevelProfit:    0.004,
+			RangeBreak:        0.003,
+			MaxActiveGrids:    1,
+		},
+	}
+}
+
+// LoadStrategyConfig читает YAML из path поверх значений по умолчанию и
+// применяет переменные окружения. Отсутствующий файл не ошибка: остаются
+// значения по умолчанию.
+func LoadStrategyConfig(path string) (StrategyConfig, error) {
+	config := DefaultStrategyConfig()
+	if path != "" {
+		b, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return StrategyConfig{}, wrap.Errorf("read strategy config %s: %w", path, err)
+		default:
+			if err := yaml.Unmarshal(b, &config); err != nil {
+				return StrategyConfig{}, wrap.Errorf("parse strategy config %s: %w", path, err)
+			}
+		}
+	}
+	applyStrategyEnv(&config)
+
+	if err := config.Validate(); err != nil {
+		return StrategyConfig{}, err
+	}
+	return config, nil
+}
+
+func applyStrategyEnv(c *StrategyConfig) {
+	c.Label = env.GetString("STRATEGY_LABEL", c.Label)
+	c.Algorithm = env.GetString("STRATEGY_ALGORITHM", c.Algorithm)
+
+	s := &c.Signals
+	s.OrderQuoteUSDT = env.GetFloat("STRATEGY_SIGNALS_ORDER_QUOTE_USDT", s.OrderQuoteUSDT)
+	s.MinVolume24h = env.GetFloat("STRATEGY_SIGNALS_MIN_VOLUME_24H", s.MinVolume24h)
+	s.MinNetProfit = env.GetFloat("STRATEGY_SIGNALS_MIN_NET_PROFIT", s.MinNetProfit)
+	s.MaxEntryRange = env.GetFloat("STRATEGY_SIGNALS_MAX_ENTRY_RANGE", s.MaxEntryRange)
+	s.MinRebound = env.GetFloat("STRATEGY_SIGNALS_MIN_REBOUND", s.MinRebound)
+	s.Pullback = env.GetFloat("STRATEGY_SIGNALS_PULLBACK", s.Pullback)
+	s.TargetRangeShare = env.GetFloat("STRATEGY_SIGNALS_TARGET_RANGE_SHARE", s.TargetRangeShare)
+	s.MaxBTCDecline30m = env.GetFloat("STRATEGY_SIGNALS_MAX_BTC_DECLINE_30M", s.MaxBTCDecline30m)
+	s.Cooldown = env.GetDuration("STRATEGY_SIGNALS_COOLDOWN", s.Cooldown)
+	s.MaxCandidates = env.GetInt("STRATEGY_SIGNALS_MAX_CANDIDATES", s.MaxCandidates)
+	s.MaxPerRun = env.GetInt("STRATEGY_SIGNALS_MAX_PER_RUN", s.MaxPerRun)
+	s.DepthLimit = env.GetInt("STRATEGY_SIGNALS_DEPTH_LIMIT", s.DepthLimit)
+	s.MaxSlippage = env.GetFloat("STRATEGY_SIGNALS_MAX_SLIPPAGE", s.MaxSlippage)
+	s.MinRangeLiquidityUSDT = env.GetFloat("STRATEGY_SIGNALS_MIN_RANGE_LIQUIDITY_USDT", s.MinRangeLiquidityUSDT)
+	s.TapeWindow = env.GetDuration("STRATEGY_SIGNALS_TAPE_WINDOW", s.TapeWindow)
+	s.MinTapeBuyImbalance = env.GetFloat("STRATEGY_SIGNALS_MIN_TAPE_BUY_IMBALANCE", s.MinTapeBuyImbalance)
+	s.MinTapeTradesPerMinute = env.GetFloat("STRATEGY_SIGNALS_MIN_TAPE_TRADES_PER_MINUTE", s.MinTapeTradesPerMinute)
+	s.LargePrintMultiplier = env.GetFloat("STRATEGY_SIGNALS_LARGE_PRINT_MULTIPLIER", s.LargePrintMultiplier)
+
+	e := &c.Execution
+	e.BuyTimeout = env.GetDuration("STRATEGY_EXECUTION_BUY_TIMEOUT", e.BuyTimeout)
+	e.RepriceDelta = env.GetFloat("STRATEGY_EXECUTION_REPRICE_DELTA", e.RepriceDelta)
+	e.SupportBreak = env.GetFloat("STRATEGY_EXECUTION_SUPPORT_BREAK", e.SupportBreak)
+	e.HardLoss = env.GetFloat("STRATEGY_EXECUTION_HARD_LOSS", e.HardLoss)
+	e.MaxPositionHold = env.GetDuration("STRATEGY_EXECUTION_MAX_POSITION_HOLD", e.MaxPositionHold)
+	e.MaxEmergencySpread = env.GetFloat("STRATEGY_EXECUTION_MAX_EMERGENCY_SPREAD", e.MaxEmergencySpread)
+	e.EmergencyPriceDiscount = env.GetFloat("STRATEGY_EXECUTION_EMERGENCY_PRICE_DISCOUNT", e.EmergencyPriceDiscount)
+	e.StopLimitOffset = env.GetFloat("STRATEGY_EXECUTION_STOP_LIMIT_OFFSET", e.StopLimitOffset)
+	e.PostOnly = env.GetBool("STRATEGY_EXECUTION_POST_ONLY", e.PostOnly)
+	e.PostOnlyRequeues = env.GetInt("STRATEGY_EXECUTION_POST_ONLY_REQUEUES", e.PostOnlyRequeues)
+
+	p := &c.Paper
+	p.MaxOpenTrades = env.GetInt("STRATEGY_PAPER_MAX_OPEN_TRADES", p.MaxOpenTrades)
+	p.ReboundEntry = env.GetFloat("STRATEGY_PAPER_REBOUND_ENTRY", p.ReboundEntry)
+	p.MaxPullbackDepth = env.GetFloat("STRATEGY_PAPER_MAX_PULLBACK_DEPTH", p.MaxPullbackDepth)
+	p.QuickProfitNet = env.GetFloat("STRATEGY_PAPER_QUICK_PROFIT_NET", p.QuickProfitNet)
+	p.QuickProfitShare = env.GetFloat("STRATEGY_PAPER_QUICK_PROFIT_SHARE", p.QuickProfitShare)
+	p.TrailingTrigger = env.GetFloat("STRATEGY_PAPER_TRAILING_TRIGGER", p.TrailingTrigger)
+	p.TrailingDistance = env.GetFloat("STRATEGY_PAPER_TRAILING_DISTANCE", p.TrailingDistance)
+	p.MinimumLockedProfit = env.GetFloat("STRATEGY_PAPER_MINIMUM_LOCKED_PROFIT", p.MinimumLockedProfit)
+	p.EntryRunaway = env.GetFloat("STRATEGY_PAPER_ENTRY_RUNAWAY", p.EntryRunaway)
+	p.PullbackTimeout = env.GetDuration("STRATEGY_PAPER_PULLBACK_TIMEOUT", p.PullbackTimeout)
+
+	k := &c.CoinCheck
+	k.MinTimeSinceLastCheck = env.GetDuration("STRATEGY_COIN_CHECK_MIN_TIME_SINCE_LAST_CHECK", k.MinTimeSinceLastCheck)
+	k.MaxVolatilityPercent = env.GetFloat("STRATEGY_COIN_CHECK_MAX_VOLATILITY_PERCENT", k.MaxVolatilityPercent)
+
+	g := &c.Grid
+	g.Levels = env.GetInt("STRATEGY_GRID_LEVELS", g.Levels)
+	g.QuotePerLevelUSDT = env.GetFloat("STRATEGY_GRID_QUOTE_PER_LEVEL_USDT", g.QuotePerLevelUSDT)
+	g.MinLevelProfit = env.GetFloat("STRATEGY_GRID_MIN_LEVEL_PROFIT", g.MinLevelProfit)
+	g.RangeBreak = env.GetFloat("STRATEGY_GRID_RANGE_BREAK", g.RangeBreak)
+	g.MaxActiveGrids = env.GetInt("STRATEGY_GRID_MAX_ACTIVE_GRIDS", g.MaxActiveGrids)
+}
+
+func (c StrategyConfig) Validate() error {
+	err := validation.ValidateStruct(&c,
+		validation.Field(&c.Label, validation.Required),
+		validation.Field(&c.Algorithm, validation.Required, validation.By(validateStrategyKey)),
+		validation.Field(&c.Signals),
+		validation.Field(&c.Execution),
+		validation.Field(&c.Paper),
+		validation.Field(&c.CoinCheck),
+		validation.Field(&c.Grid),
+	)
+	if err != nil {
+		return wrap.Errorf("failed to validate strategy config: %w", err)
+	}
+	return nil
+}
+
+// validateStrategyKey проверяет формат ключа имя/vN; есть ли такая стратегия
+// в реестре, проверяет usecase.ValidateStrategyAlgorithm при старте.
+func validateStrategyKey(value interface{}) error {
+	key, _ := value.(string)
+	if key == "" {
+		return nil
+	}
+	_, _, err := strategy.ParseKey(key)
+	return err
+}
+
+func (c SignalStrategyConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.OrderQuoteUSDT, validation.Required, validation.Min(0.0)),
+		validation.Field(&c.MinVolume24h, validation.Min(0.0)),
+		validation.Field(&c.MinNetProfit, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.MaxEntryRange, validation.Required, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.MinRebound, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.Pullback, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.TargetRangeShare, validation.Required, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.MaxBTCDecline30m, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.Cooldown, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxCandidates, validation.Required, validation.Min(1)),
+		validation.Field(&c.MaxPerRun, validation.Required, validation.Min(1)),
+		// MEXC отдаёт не больше 5000 уровней.
+		validation.Field(&c.DepthLimit, validation.Min(0), validation.Max(5000)),
+		validation.Field(&c.MaxSlippage, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.MinRangeLiquidityUSDT, validation.Min(0.0)),
+		// aggTrades отдаёт окно не больше часа.
+		validation.Field(&c.TapeWindow, validation.Min(time.Duration(0)), validation.Max(time.Hour)),
+		validation.Field(&c.MinTapeBuyImbalance, validation.Min(-1.0), validation.Max(1.0)),
+		validation.Field(&c.MinTapeTradesPerMinute, validation.Min(0.0)),
+		validation.Field(&c.LargePrintMultiplier, validation.Min(0.0)),
+	)
+}
+
+func (c ExecutionStrategyConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.BuyTimeout, validation.Required, validation.Min(time.Duration(0))),
+		validation.Field(&c.RepriceDelta, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.SupportBreak, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.HardLoss, validation.Required, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.MaxPositionHold, validation.Required, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxEmergencySpread, validation.Required, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.EmergencyPriceDiscount, validation.Min(0.0), validation.Max(0.1)),
+		validation.Field(&c.StopLimitOffset, validation.Min(0.0), validation.Max(0.1)),
+		validation.Field(&c.PostOnlyRequeues, validation.Min(0), validation.Max(20)),
+	)
+}
+
+func (c PaperStrategyConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxOpenTrades, validation.Required, validation.Min(1)),
+		validation.Field(&c.ReboundEntry, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.MaxPullbackDepth, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.QuickProfitNet, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.QuickProfitShare, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.TrailingTrigger, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.TrailingDistance, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.MinimumLockedProfit, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.EntryRunaway, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.PullbackTimeout, validation.Required, validation.Min(time.Duration(0))),
+	)
+}
+
+func (c CoinCheckStrategyConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MinTimeSinceLastCheck, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxVolatilityPercent, validation.Required, validation.Min(0.0)),
+	)
+}
+
+func (c GridStrategyConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Levels, validation.Required, validation.Min(1), validation.Max(50)),
+		validation.Field(&c.QuotePerLevelUSDT, validation.Required, validation.Min(0.0)),
+		validation.Field(&c.MinLevelProfit, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.RangeBreak, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.MaxActiveGrids, validation.Required, validation.Min(1)),
+	)
+}
+
+// Snapshot — JSON конфигурации для strategy_config.
+func (c StrategyConfig) Snapshot() ([]byte, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, wrap.Errorf("marshal strategy config: %w", err)
+	}
+	return b, nil
+}
+
+// Version — Label и первые 8 hex-символов sha256 от Snapshot: любое изменение
+// параметра даёт новую версию, которую palisade_signal и paper_trade
+// записывают в config_version.
+func (c StrategyConfig) Version() string {
+	b, err := c.Snapshot()
+	if err != nil {
+		return c.Label
+	}
+	sum := sha256.Sum256(b)
+	return c.Label + "-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadStrategyConfig_yamlAndEnvOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strategy.yaml")
+	yaml := "label: tight\nsignals:\n  min_net_profit: 0.008\n  cooldown: 90m\npaper:\n  max_open_trades: 2\n"
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write yaml: %v", err)
+	}
+	t.Setenv("STRATEGY_SIGNALS_MAX_PER_RUN", "5")
+	t.Setenv("STRATEGY_PAPER_MAX_OPEN_TRADES", "3")
+
+	cfg, err := LoadStrategyConfig(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Label != "tight" || cfg.Signals.MinNetProfit != 0.008 || cfg.Signals.Cooldown != 90*time.Minute {
+		t.Fatalf("yaml values not applied: %+v", cfg.Signals)
+	}
+	if cfg.Signals.MaxPerRun != 5 || cfg.Paper.MaxOpenTrades != 3 {
+		t.Fatalf("env must override yaml, got max_per_run=%d max_open_trades=%d", cfg.Signals.MaxPerRun, cfg.Paper.MaxOpenTrades)
+	}
+	if cfg.Execution != DefaultStrategyConfig().Execution {
+		t.Fatalf("unset section must keep defaults, got %+v", cfg.Execution)
+	}
+}
+
+func TestLoadStrategyConfig_missingFileUsesDefaults(t *testing.T) {
+	cfg, err := LoadStrategyConfig(filepath.Join(t.TempDir(), "absent.yaml"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg != DefaultStrategyConfig() {
+		t.Fatalf("expected defaults, got %+v", cfg)
+	}
+}
+
+func TestLoadStrategyConfig_invalid(t *testing.T) {
+	t.Setenv("STRATEGY_EXECUTION_HARD_LOSS", "1.5")
+	if _, err := LoadStrategyConfig(""); err == nil {
+		t.Fatalf("hard loss above 100%% must fail validation")
+	}
+}
+
+func TestLoadStrategyConfig_invalidAlgorithm(t *testing.T) {
+	for _, key := range []string{"palisade", "palisade/8", "palisade/v0"} {
+		t.Setenv("STRATEGY_ALGORITHM", key)
+		if _, err := LoadStrategyConfig(""); err == nil {
+			t.Fatalf("algorithm %q must fail validation", key)
+		}
+	}
+}
+
+func TestStrategyConfig_Version(t *testing.T) {
+	base := DefaultStrategyConfig()
+	changed := DefaultStrategyConfig()
+	changed.Paper.TrailingDistance = 0.003
+
+	if base.Version() != DefaultStrategyConfig().Version() {
+		t.Fatalf("same config must give the same version")
+	}
+	if base.Version() == changed.Version() {
+		t.Fatalf("changed threshold must give a new version, got %s", base.Version())
+	}
+	if got := base.Version(); len(got) != len("default-")+8 || got[:8] != "default-" {
+		t.Fatalf("unexpected version format %q", got)
+	}
+}
@@ -3,8 +3,6 @@ package registry
 import (
 	"context"
 
-	"mexc-sdk/mexcsdk"
-
 	registry "github.com/drybin/palisade/internal/adapter/pg"
 	repo "github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/app/cli/config"
@@ -19,7 +17,6 @@ import (
 type Container struct {
 	Logger   logger.ILogger
 	Usecases *Usecases
-	MexcSpot mexcsdk.Spot
 	Clean    func()
 }
 
@@ -32,6 +29,8 @@ type Usecases struct {
 	PalisadeProcessManual     *usecase.PalisadeProcessManual
 	PalisadeProcessSellManual *usecase.PalisadeProcessSell
 	SwapProcess               *usecase.SwapProcess
+	SwapReport                *usecase.SwapReport
+	SwapMonitor               *usecase.SwapMonitor
 	GetCoinList               *usecase.GetCoinList
 	CheckPalisadeCoinList     *usecase.CheckPalisadeCoinList
 	CheckPalisadeCoin         *usecase.CheckPalisadeCoin
@@ -39,26 +38,38 @@ type Usecases struct {
 	SyncTrendBars             *usecase.SyncTrendBars
 	CheckTrendRetest          *usecase.CheckTrendRetest
 	CollectMarketData         *usecase.CollectMarketData
+	StreamMarketData          *usecase.StreamMarketData
+	CollectTradeTape          *usecase.CollectTradeTape
 	ScorePalisadeCandidates   *usecase.ScorePalisadeCandidates
 	ExecutePalisadeSignals    *usecase.ExecutePalisadeSignals
+	GridTrade                 *usecase.GridTrade
 	ReconcileOrders           *usecase.ReconcileOrders
+	WatchOrderFills           *usecase.WatchOrderFills
 	PaperTrade                *usecase.PaperTradeRunner
+	Backtest                  *usecase.Backtest
+	Daemon                    *usecase.Daemon
 }
 
 func NewContainer(
 	config *config.Config,
 ) (*Container, error) {
 
+	if err := usecase.ValidateStrategyAlgorithm(config.StrategyConfig); err != nil {
+		return nil, err
+	}
+
 	httpClient := resty.New()
 	httpClient.SetBaseURL(config.MexcConfig.BaseUrl)
 	httpClient.SetHeader("Content-Type", "application/json")
 	httpClient.SetHeader("X-MEXC-APIKEY", config.MexcConfig.ApiKey)
 
-	// Создаем экземпляр spot Spot SDK
-	mexcSpot := mexcsdk.NewSpot(&config.MexcConfig.ApiKey, &config.MexcConfig.Secret)
+	// Подписанный клиент приватных эндпоинтов spot API
+	mexcSpot := repo.NewMexcSpotClient(config.MexcConfig)
 
 	mexcApi := repo.NewMexcWebapi(httpClient, mexcSpot, config.MexcConfig)
 	mexcV2Api := repo.NewMexcV2Webapi(httpClient, config.MexcConfig)
+	marketStream := repo.NewMexcMarketStream(config.MexcConfig)
+	userStream := repo.NewMexcUserStream(config.MexcConfig, mexcSpot)
 
 	// Создаем отдельный HTTP клиент для Telegram API (опционально через TG_SOCKS5_PROXY)
 	telegramHttpClient, err := newTelegramRestyClient(config.TgConfig)
@@ -77,6 +88,17 @@ func NewContainer(
 
 	// Создаем сервисы
 	palisadeCheckerService := service.NewPalisadeCheckerService(mexcApi, stateRepo)
+	riskManager := service.NewRiskManager(stateRepo, telegramApi, service.RiskLimits{
+		MaxOpenPositions:      config.RiskConfig.MaxOpenPositions,
+		MaxSymbolExposureUSDT: config.RiskConfig.MaxSymbolExposureUSDT,
+		MaxTotalExposureUSDT:  config.RiskConfig.MaxTotalExposureUSDT,
+		MaxDailyLossUSDT:      config.RiskConfig.MaxDailyLossUSDT,
+		MaxConsecutiveLosses:  config.RiskConfig.MaxConsecutiveLosses,
+		LossCooldown:          config.RiskConfig.LossCooldown,
+	})
+	processSizer := service.NewPositionSizer(stateRepo, sizingRule(config.SizingConfig.Process))
+	processMultiSizer := service.NewPositionSizer(stateRepo, sizingRule(config.SizingConfig.ProcessMulti))
+	signalsSizer := service.NewPositionSizer(stateRepo, sizingRule(config.SizingConfig.Signals))
 
 	container := Container{
 		Usecases: &Usecases{
@@ -91,6 +113,8 @@ func NewContainer(
 				service.NewByuService(mexcApi, mexcV2Api, stateRepo),
 				palisadeCheckerService,
 				stateRepo,
+				riskManager,
+				processSizer,
 			),
 			PalisadeProcessMulti: usecase.NewPalisadeProcessMultiUsecase(
 				mexcApi,
@@ -101,35 +125,55 @@ func NewContainer(
 				service.NewByuService(mexcApi, mexcV2Api, stateRepo),
 				palisadeCheckerService,
 				stateRepo,
+				riskManager,
+				processMultiSizer,
 			),
 			PalisadeProcessSell:       usecase.NewPalisadeProcessSellUsecase(mexcApi, stateRepo, telegramApi),
-			PalisadeProcessManual:     usecase.NewPalisadeProcessManualUsecase(mexcApi, stateRepo, telegramApi),
+			PalisadeProcessManual:     usecase.NewPalisadeProcessManualUsecase(mexcApi, stateRepo, telegramApi, riskManager),
 			PalisadeProcessSellManual: usecase.NewPalisadeProcessSellManualUsecase(mexcApi, stateRepo, telegramApi),
-			SwapProcess:               usecase.NewSwapProcessUsecase(mexcApi, stateRepo),
+			SwapProcess:               usecase.NewSwapProcessUsecase(mexcApi, stateRepo, riskManager),
+			SwapReport:                usecase.NewSwapReportUsecase(stateRepo),
+			SwapMonitor:               usecase.NewSwapMonitorUsecase(mexcApi, stateRepo),
 			GetCoinList:               usecase.NewGetCoinListUsecase(mexcApi, stateRepo),
-			CheckPalisadeCoinList:     usecase.NewCheckPalisadeCoinListUsecase(palisadeCheckerService, stateRepo),
+			CheckPalisadeCoinList:     usecase.NewCheckPalisadeCoinListUsecase(palisadeCheckerService, stateRepo, config.StrategyConfig.CoinCheck),
 			CheckPalisadeCoin:         usecase.NewCheckPalisadeCoinUsecase(palisadeCheckerService, stateRepo),
 			BackfillTrendBars:         usecase.NewBackfillTrendBarsUsecase(mexcApi, trendRepo),
 			SyncTrendBars:             usecase.NewSyncTrendBarsUsecase(mexcApi, trendRepo),
 			CheckTrendRetest:          usecase.NewCheckTrendRetestUsecase(mexcApi, trendRepo, telegramApi),
 			CollectMarketData:         usecase.NewCollectMarketDataUsecase(mexcApi, stateRepo),
-			ScorePalisadeCandidates:   usecase.NewScorePalisadeCandidatesUsecase(mexcApi, stateRepo, telegramApi),
-			ExecutePalisadeSignals:    usecase.NewExecutePalisadeSignalsUsecase(mexcApi, stateRepo, telegramApi),
+			StreamMarketData:          usecase.NewStreamMarketDataUsecase(mexcApi, marketStream, stateRepo, trendRepo),
+			CollectTradeTape:          usecase.NewCollectTradeTapeUsecase(mexcApi, stateRepo),
+			ScorePalisadeCandidates:   usecase.NewScorePalisadeCandidatesUsecase(mexcApi, stateRepo, telegramApi, config.StrategyConfig),
+			ExecutePalisadeSignals:    usecase.NewExecutePalisadeSignalsUsecase(mexcApi, stateRepo, telegramApi, riskManager, signalsSizer, config.StrategyConfig),
+			GridTrade:                 usecase.NewGridTradeUsecase(mexcApi, stateRepo, telegramApi, riskManager, config.StrategyConfig.Grid),
 			ReconcileOrders:           usecase.NewReconcileOrdersUsecase(mexcApi, stateRepo, telegramApi),
-			PaperTrade:                usecase.NewPaperTradeUsecase(mexcApi, stateRepo),
+			PaperTrade:                usecase.NewPaperTradeUsecase(mexcApi, stateRepo, config.StrategyConfig),
+			Backtest:                  usecase.NewBacktestUsecase(mexcApi, stateRepo, trendRepo, config.StrategyConfig),
 		},
-		MexcSpot: mexcSpot,
 		Clean: func() {
-			// // SDK не предоставляет API shutdown — останавливаем Node/jsii процессы вручную,
-			// // чтобы избежать зависших jsii-kernel и sync-rpc workers.
-			// killPatterns := []string{"jsii-kernel", "jsii-runtime", "sync-rpc"}
-			// for _, pattern := range killPatterns {
-			// 	cmd := exec.Command("pkill", "-f", pattern)
-			// 	_ = cmd.Run() // игнорируем: pkill=1 если нет совпадений (норма при чистом выходе)
-			// }
+			_ = db.Close(context.Background())
 		},
 	}
 
+	u := container.Usecases
+	u.WatchOrderFills = usecase.NewWatchOrderFillsUsecase(u.ExecutePalisadeSignals, userStream)
+
+	// Задачи daemon называются так же, как соответствующие команды CLI.
+	tapeOptions := usecase.DefaultCollectTradeTapeOptions()
+	u.Daemon = usecase.NewDaemonUsecase(stateRepo, mexcSpot, map[string]usecase.DaemonJobFunc{
+		"process":                       u.PalisadeProcess.Process,
+		"process-sell":                  u.PalisadeProcessSell.Process,
+		"paper-palisade-signals":        func(ctx context.Context) error { return u.PaperTrade.Process(ctx, false) },
+		"collect-market-data":           func(ctx context.Context) error { return u.CollectMarketData.Process(ctx, false) },
+		"collect-trade-tape":            func(ctx context.Context) error { return u.CollectTradeTape.Process(ctx, tapeOptions) },
+		"score-palisade-candidates":     func(ctx context.Context) error { return u.ScorePalisadeCandidates.Process(ctx, false) },
+		"execute-palisade-signals":      func(ctx context.Context) error { return u.ExecutePalisadeSignals.Process(ctx, false) },
+		"execute-palisade-signals-live": func(ctx context.Context) error { return u.ExecutePalisadeSignals.Process(ctx, true) },
+		"grid-trade":                    func(ctx context.Context) error { return u.GridTrade.Process(ctx, usecase.GridTradeOptions{}) },
+		"grid-trade-live":               func(ctx context.Context) error { return u.GridTrade.Process(ctx, usecase.GridTradeOptions{Live: true}) },
+		"reconcile-orders":              u.ReconcileOrders.Process,
+	})
+
 	return &container, nil
 }
 
@@ -151,3 +195,15 @@ func newDbConn(config *config.Config) (*pgx.Conn, error) {
 
 	return conn, nil
 }
+
+func sizingRule(cfg config.PositionSizingConfig) service.SizingRule {
+	return service.SizingRule{
+		Method:           service.SizingMethod(cfg.Method),
+		QuoteUSDT:        cfg.QuoteUSDT,
+		PercentOfFree:    cfg.PercentOfFree,
+		TargetVolatility: cfg.TargetVolatility,
+		KellyFraction:    cfg.KellyFraction,
+		KellyMinTrades:   cfg.KellyMinTrades,
+		MaxQuoteUSDT:     cfg.MaxQuoteUSDT,
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/domain/repo"
 	"github.com/drybin/palisade/pkg/wrap"
 )
@@ -15,11 +14,11 @@ type IBackfillTrendBars interface {
 }
 
 type BackfillTrendBars struct {
-	mexcAPI   *webapi.MexcWebapi
+	mexcAPI   repo.IMexcRepository
 	trendRepo repo.ITrendRepository
 }
 
-func NewBackfillTrendBarsUsecase(mexcAPI *webapi.MexcWebapi, trendRepo repo.ITrendRepository) *BackfillTrendBars {
+func NewBackfillTrendBarsUsecase(mexcAPI repo.IMexcRepository, trendRepo repo.ITrendRepository) *BackfillTrendBars {
 	return &BackfillTrendBars{mexcAPI: mexcAPI, trendRepo: trendRepo}
 }
 
@@ -31,9 +30,6 @@ func (u *BackfillTrendBars) Process(ctx context.Context, opts TrendOptions) erro
 		opts.Days = defaultTrendDays
 	}
 	sleep := time.Duration(opts.SleepMs) * time.Millisecond
-	if sleep <= 0 {
-		sleep = defaultTrendSleepMs * time.Millisecond
-	}
 
 	fmt.Printf("backfill-trend-bars: %d symbols, %d daily candles each\n", len(opts.Symbols), opts.Days)
 
@@ -41,12 +37,16 @@ func (u *BackfillTrendBars) Process(ctx context.Context, opts TrendOptions) erro
 		n, err := fetchAndPersistDaily(ctx, u.mexcAPI, u.trendRepo, symbol, opts.Days)
 		if err != nil {
 			fmt.Printf("[%d/%d] %s ERROR: %v\n", i+1, len(opts.Symbols), symbol, err)
-			time.Sleep(sleep)
+			if sleep > 0 {
+				time.Sleep(sleep)
+			}
 			continue
 		}
 		cnt, _ := u.trendRepo.CountDailyBars(ctx, symbol)
 		fmt.Printf("[%d/%d] %s: saved %d klines, total in db: %d\n", i+1, len(opts.Symbols), symbol, n, cnt)
-		time.Sleep(sleep)
+		if sleep > 0 {
+			time.Sleep(sleep)
+		}
 	}
 	fmt.Println("backfill done")
 	return nil
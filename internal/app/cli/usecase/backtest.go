@@ -0,0 +1,505 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/enum"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/strategy"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const (
+	defaultBacktestDays   = 7
+	defaultBacktestSpread = 0.001
+	backtestSourceDB      = "db"
+	backtestSourceAPI     = "api"
+	backtestKlineInterval = 15 * time.Minute
+	backtestKlineWindow   = 100
+	// backtestWarmup — история перед началом периода, нужная входу палисады
+	// (96 закрытых 15m свечей).
+	backtestWarmup = 24*time.Hour + backtestKlineInterval
+	// backtestBookQty — объём лучшего уровня синтетического стакана: бэктест не
+	// моделирует ликвидность, ордер исполняется целиком.
+	backtestBookQty = 1e9
+)
+
+type BacktestOptions struct {
+	Symbols  []string
+	From     time.Time
+	To       time.Time
+	Versions []int
+	Spread   float64
+	Source   string
+	DryRun   bool
+	Debug    bool
+}
+
+func DefaultBacktestOptions() BacktestOptions {
+	to := time.Now().UTC().Truncate(time.Minute)
+	return BacktestOptions{
+		From:   to.Add(-defaultBacktestDays * 24 * time.Hour),
+		To:     to,
+		Spread: defaultBacktestSpread,
+		Source: backtestSourceDB,
+	}
+}
+
+type IBacktest interface {
+	Process(context.Context, BacktestOptions) error
+}
+
+// Backtest прогоняет историю минутных свечей через вход стратегии и
+// бумажный движок advancePaperTrade и сохраняет итоги по каждой версии
+// стратегии в backtest_run. Версии берутся из реестра под именем стратегии
+// из конфига; без Versions — версия из конфига.
+type Backtest struct {
+	api        repo.IMexcRepository
+	stateRepo  repo.IStateRepository
+	trendRepo  repo.ITrendRepository
+	strategy   config.StrategyConfig
+	strategies *strategy.Registry
+}
+
+func NewBacktestUsecase(
+	api repo.IMexcRepository,
+	stateRepo repo.IStateRepository,
+	trendRepo repo.ITrendRepository,
+	strategy config.StrategyConfig,
+) *Backtest {
+	return &Backtest{api: api, stateRepo: stateRepo, trendRepo: trendRepo, strategy: strategy, strategies: NewStrategyRegistry(strategy)}
+}
+
+type backtestMarket struct {
+	symbol mexc.SymbolDetail
+	bars   []repo.MarketMinuteBar
+	klines mexc.Klines
+}
+
+type backtestReplay struct {
+	signals int
+	trades  []repo.PaperTrade
+}
+
+type backtestMetrics struct {
+	Signals     int
+	Trades      int
+	Canceled    int
+	Open        int
+	Wins        int
+	Losses      int
+	WinRate     float64
+	TotalPnL    float64
+	OpenPnL     float64
+	MaxDrawdown float64
+	ExitReasons map[string]int
+}
+
+func (u *Backtest) Process(ctx context.Context, opts BacktestOptions) error {
+	if len(opts.Symbols) == 0 {
+		return wrap.Errorf("no symbols to backtest")
+	}
+	if !opts.From.Before(opts.To) {
+		return wrap.Errorf("backtest period is empty: %s - %s", opts.From.Format(time.RFC3339), opts.To.Format(time.RFC3339))
+	}
+	selected, err := u.strategies.Get(u.strategy.Algorithm)
+	if err != nil {
+		return err
+	}
+	if len(opts.Versions) == 0 {
+		opts.Versions = []int{selected.Version()}
+	}
+	replayed := make([]strategy.Strategy, 0, len(opts.Versions))
+	for _, version := range opts.Versions {
+		s, err := u.strategies.Version(selected.Name(), version)
+		if err != nil {
+			return err
+		}
+		replayed = append(replayed, s)
+	}
+	if opts.Source != backtestSourceDB && opts.Source != backtestSourceAPI {
+		return wrap.Errorf("unknown bar source %q", opts.Source)
+	}
+	if opts.Spread < 0 {
+		return wrap.Errorf("spread must not be negative")
+	}
+
+	info, err := u.api.GetExchangeInfoAll(ctx)
+	if err != nil {
+		return wrap.Errorf("get exchange info for backtest: %w", err)
+	}
+	bySymbol := make(map[string]mexc.SymbolDetail, len(info.Symbols))
+	for _, symbol := range info.Symbols {
+		if symbol.Symbol != "" {
+			bySymbol[symbol.Symbol] = symbol
+		}
+	}
+
+	btcBars, err := u.loadMinuteBars(ctx, "BTCUSDT", opts)
+	if err != nil {
+		return err
+	}
+	btcKlines := aggregateBacktestKlines(btcBars)
+	if len(btcKlines) == 0 {
+		fmt.Println("Бэктест: нет истории BTCUSDT, фильтр рынка BTC отключён")
+	}
+
+	markets := make([]backtestMarket, 0, len(opts.Symbols))
+	bars := 0
+	for _, name := range opts.Symbols {
+		symbol, ok := bySymbol[name]
+		if !ok {
+			fmt.Printf("%s: пара не найдена в exchangeInfo\n", name)
+			continue
+		}
+		symbolBars, err := u.loadMinuteBars(ctx, name, opts)
+		if err != nil {
+			return err
+		}
+		if len(symbolBars) == 0 {
+			fmt.Printf("%s: нет минутных свечей за период\n", name)
+			continue
+		}
+		bars += len(symbolBars)
+		markets = append(markets, backtestMarket{symbol: symbol, bars: symbolBars, klines: aggregateBacktestKlines(symbolBars)})
+	}
+	if len(markets) == 0 {
+		return wrap.Errorf("no market history to backtest")
+	}
+
+	symbols := make([]string, 0, len(markets))
+	for _, market := range markets {
+		symbols = append(symbols, market.symbol.Symbol)
+	}
+	for _, s := range replayed {
+		version := s.Version()
+		signals := 0
+		trades := make([]repo.PaperTrade, 0)
+		for _, market := range markets {
+			replay, err := replayPalisadeBacktest(u.strategy, s, market, btcKlines, opts)
+			if err != nil {
+				return wrap.Errorf("backtest %s v%d: %w", market.symbol.Symbol, version, err)
+			}
+			signals += replay.signals
+			trades = append(trades, replay.trades...)
+			if opts.Debug {
+				for _, trade := range replay.trades {
+					fmt.Printf("backtest v%d %s: signal=%s status=%s exit=%s target=%.8f buy=%.8f sell=%.8f filled=%.8f pnl=%.8f\n",
+						version, trade.Symbol, trade.SignalAt.Format(time.RFC3339), trade.Status, trade.ExitReason, trade.TargetPrice,
+						trade.BuyQuote, trade.SellQuote, trade.FilledQuantity, trade.PnL)
+				}
+			}
+		}
+		metrics := summarizeBacktest(signals, trades)
+		reasons, err := json.Marshal(metrics.ExitReasons)
+		if err != nil {
+			return wrap.Errorf("marshal exit reasons: %w", err)
+		}
+		fmt.Printf("Бэктест v%d %s - %s: пар=%d, свечей=%d, сигналов=%d, закрыто=%d, отменено=%d, открыто=%d, win=%d, loss=%d, win rate=%.1f%%, P/L=%.8f USDT, P/L открытых=%.8f USDT, макс. просадка=%.8f USDT\n",
+			version, opts.From.Format("2006-01-02 15:04"), opts.To.Format("2006-01-02 15:04"), len(markets), bars,
+			metrics.Signals, metrics.Trades, metrics.Canceled, metrics.Open, metrics.Wins, metrics.Losses,
+			metrics.WinRate*100, metrics.TotalPnL, metrics.OpenPnL, metrics.MaxDrawdown)
+		fmt.Printf("Причины выхода v%d: %s\n", version, reasons)
+		if opts.DryRun {
+			continue
+		}
+		if _, err := u.stateRepo.SaveBacktestRun(ctx, repo.BacktestRun{
+			CreatedAt:       time.Now().UTC(),
+			StrategyVersion: version,
+			Symbols:         strings.Join(symbols, ","),
+			PeriodFrom:      opts.From,
+			PeriodTo:        opts.To,
+			Spread:          opts.Spread,
+			Bars:            bars,
+			Signals:         metrics.Signals,
+			Trades:          metrics.Trades,
+			Canceled:        metrics.Canceled,
+			Wins:            metrics.Wins,
+			Losses:          metrics.Losses,
+			WinRate:         metrics.WinRate,
+			TotalPnL:        metrics.TotalPnL,
+			MaxDrawdown:     metrics.MaxDrawdown,
+			ExitReasons:     string(reasons),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadMinuteBars возвращает минутные свечи [From-warmup, To) из
+// market_minute_bar или, при Source=api, напрямую с биржи.
+func (u *Backtest) loadMinuteBars(ctx context.Context, symbol string, opts BacktestOptions) ([]repo.MarketMinuteBar, error) {
+	from := opts.From.Add(-backtestWarmup)
+	if opts.Source == backtestSourceAPI {
+		return u.fetchMinuteBars(ctx, symbol, from, opts.To)
+	}
+	stored, err := u.trendRepo.ListMinuteBarsFrom(ctx, symbol, from)
+	if err != nil {
+		return nil, wrap.Errorf("list minute bars %s: %w", symbol, err)
+	}
+	bars := make([]repo.MarketMinuteBar, 0, len(stored))
+	for _, bar := range stored {
+		if bar.OpenTime.Before(opts.To) {
+			bars = append(bars, bar)
+		}
+	}
+	return bars, nil
+}
+
+func (u *Backtest) fetchMinuteBars(ctx context.Context, symbol string, from, to time.Time) ([]repo.MarketMinuteBar, error) {
+	startMs := from.UnixMilli()
+	bars := make([]repo.MarketMinuteBar, 0)
+	for startMs < to.UnixMilli() {
+		klines, err := u.api.GetKlinesPublic(ctx, symbol, enum.MINUTES_1, 1000, &startMs)
+		if err != nil {
+			return nil, wrap.Errorf("get minute klines %s: %w", symbol, err)
+		}
+		if len(*klines) == 0 {
+			break
+		}
+		for _, k := range *klines {
+			openTime := time.UnixMilli(k.OpenTime).UTC()
+			if !openTime.Before(to) {
+				break
+			}
+			bars = append(bars, repo.MarketMinuteBar{
+				Symbol:   symbol,
+				OpenTime: openTime,
+				Open:     k.Open,
+				High:     k.High,
+				Low:      k.Low,
+				Close:    k.Close,
+			})
+		}
+		nextStart := (*klines)[len(*klines)-1].CloseTime + 1
+		if nextStart <= startMs || len(*klines) < 1000 {
+			break
+		}
+		startMs = nextStart
+	}
+	return bars, nil
+}
+
+// aggregateBacktestKlines собирает 15m свечи из минутных. Объём в
+// market_minute_bar не хранится, поэтому Volume остаётся нулевым.
+func aggregateBacktestKlines(bars []repo.MarketMinuteBar) mexc.Klines {
+	klines := make(mexc.Klines, 0, len(bars)/15+1)
+	for _, bar := range bars {
+		openTime := bar.OpenTime.UTC().Truncate(backtestKlineInterval).UnixMilli()
+		if len(klines) > 0 && klines[len(klines)-1].OpenTime == openTime {
+			last := &klines[len(klines)-1]
+			last.High = math.Max(last.High, bar.High)
+			last.Low = math.Min(last.Low, bar.Low)
+			last.Close = bar.Close
+			continue
+		}
+		klines = append(klines, mexc.Kline{
+			OpenTime:  openTime,
+			Open:      bar.Open,
+			High:      bar.High,
+			Low:       bar.Low,
+			Close:     bar.Close,
+			CloseTime: openTime + backtestKlineInterval.Milliseconds() - 1,
+		})
+	}
+	return klines
+}
+
+// replayPalisadeBacktest проходит по минутным свечам одной пары. На закрытии
+// каждой минуты, если сделки нет, вход ищется стратегией s так же, как в
+// ScorePalisadeCandidates; открытая сделка продвигается по синтетическим тикам
+// внутри минуты (O-L-H-C для растущей свечи, O-H-L-C для падающей).
+func replayPalisadeBacktest(cfg config.StrategyConfig, s strategy.Strategy, market backtestMarket, btcKlines mexc.Klines, opts BacktestOptions) (backtestReplay, error) {
+	result := backtestReplay{trades: make([]repo.PaperTrade, 0)}
+	var trade *repo.PaperTrade
+	var signal repo.PalisadeSignalState
+	var lastSignal time.Time
+	closedKlines, closedBTC := 0, 0
+	lastBid, lastFee := 0.0, 0.0
+
+	for _, bar := range market.bars {
+		if trade != nil {
+			path := backtestBarPath(bar)
+			for i, price := range path {
+				now := bar.OpenTime.Add(time.Duration(i+1) * time.Minute / time.Duration(len(path)))
+				activeSignal := signal
+				if !now.Before(signal.ValidUntil) {
+					activeSignal = repo.PalisadeSignalState{}
+				}
+				bid, fee, err := advancePaperTrade(cfg, s, trade, activeSignal, backtestBook(market.symbol, price, opts.Spread), nil, market.symbol, now)
+				if err != nil {
+					return result, err
+				}
+				markPaperPnL(trade, bid, fee)
+				trade.UpdatedAt = now
+				lastBid, lastFee = bid, fee
+				if !isOpenPaperTrade(*trade) {
+					result.trades = append(result.trades, *trade)
+					trade = nil
+					break
+				}
+			}
+		}
+
+		now := bar.OpenTime.Add(time.Minute)
+		for closedKlines < len(market.klines) && market.klines[closedKlines].CloseTime < now.UnixMilli() {
+			closedKlines++
+		}
+		for closedBTC < len(btcKlines) && btcKlines[closedBTC].CloseTime < now.UnixMilli() {
+			closedBTC++
+		}
+		if trade != nil || now.Before(opts.From) || now.Sub(lastSignal) < cfg.Signals.Cooldown {
+			continue
+		}
+		if len(btcKlines) > 0 && !isBTCMarketSafe(cfg.Signals, btcKlines[max(0, closedBTC-4):closedBTC], now) {
+			continue
+		}
+		book := backtestBook(market.symbol, bar.Close, opts.Spread)
+		bid, ask, err := parseBook(book)
+		if err != nil {
+			return result, err
+		}
+		snapshot := repo.MarketSnapshot{
+			Symbol:      market.symbol.Symbol,
+			CollectedAt: now,
+			LastPrice:   bar.Close,
+			BidPrice:    bid,
+			BidQty:      backtestBookQty,
+			AskPrice:    ask,
+			AskQty:      backtestBookQty,
+		}
+		candidate, ok := s.EvaluateEntry(strategy.MarketContext{
+			Snapshot: snapshot,
+			Symbol:   market.symbol,
+			Klines:   market.klines[max(0, closedKlines-backtestKlineWindow):closedKlines],
+			Now:      now,
+		})
+		if !ok {
+			continue
+		}
+		result.signals++
+		lastSignal = now
+		signal = repo.PalisadeSignalState{
+			Symbol:          candidate.Symbol,
+			SentAt:          now,
+			StrategyVersion: s.Version(),
+			SupportPrice:    candidate.Support,
+			EntryPrice:      candidate.EntryPrice,
+			TargetPrice:     candidate.Target,
+			MinExitPrice:    candidate.MinExitPrice,
+			NetProfit:       candidate.NetProfit,
+			Score:           candidate.Score,
+			Status:          "ACTIVE",
+			ValidUntil:      now.Add(30 * time.Minute),
+			UpdatedAt:       now,
+		}
+		created, ok, err := buildPaperTrade(cfg, s, signal, book, market.symbol, now)
+		if err != nil || !ok {
+			continue
+		}
+		if _, _, err := advancePaperTrade(cfg, s, &created, signal, book, nil, market.symbol, now); err != nil {
+			return result, err
+		}
+		if !isOpenPaperTrade(created) {
+			result.trades = append(result.trades, created)
+			continue
+		}
+		trade = &created
+	}
+
+	if trade != nil {
+		markPaperPnL(trade, lastBid, lastFee)
+		result.trades = append(result.trades, *trade)
+	}
+	return result, nil
+}
+
+func backtestBarPath(bar repo.MarketMinuteBar) []float64 {
+	if bar.Close >= bar.Open {
+		return []float64{bar.Open, bar.Low, bar.High, bar.Close}
+	}
+	return []float64{bar.Open, bar.High, bar.Low, bar.Close}
+}
+
+// backtestBook строит лучший bid/ask вокруг цены с заданным спредом,
+// выровненные по шагу цены пары.
+func backtestBook(symbol mexc.SymbolDetail, price, spread float64) mexc.BookTicker {
+	step := signalPriceStep(&symbol)
+	bid := roundPriceDown(price*(1-spread/2), step)
+	ask := roundPriceUp(price*(1+spread/2), step)
+	if ask <= bid {
+		ask = bid + step
+	}
+	qty := strconv.FormatFloat(backtestBookQty, 'f', -1, 64)
+	return mexc.BookTicker{
+		Symbol:   symbol.Symbol,
+		BidPrice: strconv.FormatFloat(bid, 'f', -1, 64),
+		BidQty:   qty,
+		AskPrice: strconv.FormatFloat(ask, 'f', -1, 64),
+		AskQty:   qty,
+	}
+}
+
+func summarizeBacktest(signals int, trades []repo.PaperTrade) backtestMetrics {
+	metrics := backtestMetrics{Signals: signals, ExitReasons: map[string]int{}}
+	closed := make([]repo.PaperTrade, 0, len(trades))
+	for _, trade := range trades {
+		switch trade.Status {
+		case "CLOSED":
+			closed = append(closed, trade)
+			metrics.Trades++
+			metrics.TotalPnL += trade.PnL
+			if trade.PnL > 0 {
+				metrics.Wins++
+			} else if trade.PnL < 0 {
+				metrics.Losses++
+			}
+			metrics.ExitReasons[trade.ExitReason]++
+		case "CANCELED":
+			metrics.Canceled++
+			metrics.ExitReasons[trade.ExitReason]++
+		default:
+			metrics.Open++
+			metrics.OpenPnL += trade.PnL
+		}
+	}
+	if metrics.Trades > 0 {
+		metrics.WinRate = float64(metrics.Wins) / float64(metrics.Trades)
+	}
+	sort.SliceStable(closed, func(i, j int) bool { return paperClosedAt(closed[i]).Before(paperClosedAt(closed[j])) })
+	pnls := make([]float64, 0, len(closed))
+	for _, trade := range closed {
+		pnls = append(pnls, trade.PnL)
+	}
+	metrics.MaxDrawdown = backtestMaxDrawdown(pnls)
+	return metrics
+}
+
+// backtestMaxDrawdown — наибольшее падение кривой накопленного P/L от
+// предыдущего максимума (кривая стартует с нуля).
+func backtestMaxDrawdown(pnls []float64) float64 {
+	equity, peak, drawdown := 0.0, 0.0, 0.0
+	for _, pnl := range pnls {
+		equity += pnl
+		peak = math.Max(peak, equity)
+		drawdown = math.Max(drawdown, peak-equity)
+	}
+	return drawdown
+}
+
+func paperClosedAt(trade repo.PaperTrade) time.Time {
+	if trade.ClosedAt != nil {
+		return *trade.ClosedAt
+	}
+	return trade.UpdatedAt
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/strategy"
+)
+
+type memTrend struct {
+	repo.ITrendRepository
+
+	mu   sync.Mutex
+	bars map[string][]repo.MarketMinuteBar
+}
+
+func (m *memTrend) ListMinuteBarsFrom(_ context.Context, symbol string, from time.Time) ([]repo.MarketMinuteBar, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []repo.MarketMinuteBar{}
+	for _, bar := range m.bars[symbol] {
+		if !bar.OpenTime.Before(from) {
+			out = append(out, bar)
+		}
+	}
+	return out, nil
+}
+
+func backtestTestSymbol() mexc.SymbolDetail {
+	return mexc.SymbolDetail{
+		Symbol:               "AAAUSDT",
+		Status:               "1",
+		BaseAsset:            "AAA",
+		QuoteAsset:           "USDT",
+		QuotePrecision:       4,
+		OrderTypes:           []string{"LIMIT", "MARKET"},
+		IsSpotTradingAllowed: true,
+		QuoteAmountPrecision: "1",
+		MaxQuoteAmount:       "100000",
+		MakerCommission:      "0",
+		TakerCommission:      "0.001",
+		Filters:              []mexc.SymbolFilter{{FilterType: "LOT_SIZE", StepSize: "0.01"}},
+	}
+}
+
+// appendBacktestBlock добавляет 15 минутных свечей, которые агрегируются в
+// одну 15m свечу с заданными O/H/L/C.
+func appendBacktestBlock(bars []repo.MarketMinuteBar, start time.Time, open, high, low, close float64) []repo.MarketMinuteBar {
+	for i := 0; i < 15; i++ {
+		bars = append(bars, repo.MarketMinuteBar{
+			Symbol: "AAAUSDT", OpenTime: start.Add(time.Duration(i) * time.Minute),
+			Open: open, High: high, Low: low, Close: close,
+		})
+	}
+	return bars
+}
+
+// palisadeBacktestBars строит сутки боковика 1.00-1.05, касание поддержки и
+// подтверждающую свечу, после чего цена откатывается и уходит к цели.
+func palisadeBacktestBars(start time.Time) ([]repo.MarketMinuteBar, time.Time) {
+	bars := []repo.MarketMinuteBar{}
+	for i := 0; i < 94; i++ {
+		bars = appendBacktestBlock(bars, start.Add(time.Duration(i)*15*time.Minute), 1.02, 1.05, 1.00, 1.015)
+	}
+	bars = appendBacktestBlock(bars, start.Add(94*15*time.Minute), 1.01, 1.011, 1.00, 1.004)
+	bars = appendBacktestBlock(bars, start.Add(95*15*time.Minute), 1.004, 1.008, 1.002, 1.007)
+	signalAt := start.Add(96 * 15 * time.Minute)
+	for i, price := range []float64{1.006, 1.015, 1.03} {
+		bars = append(bars, repo.MarketMinuteBar{
+			Symbol: "AAAUSDT", OpenTime: signalAt.Add(time.Duration(i) * time.Minute),
+			Open: price, High: price, Low: price, Close: price,
+		})
+	}
+	return bars, signalAt
+}
+
+func TestAggregateBacktestKlines_buildsQuarterHourBars(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	bars := []repo.MarketMinuteBar{
+		{OpenTime: start, Open: 1, High: 1.2, Low: 0.9, Close: 1.1},
+		{OpenTime: start.Add(14 * time.Minute), Open: 1.1, High: 1.5, Low: 1.0, Close: 1.3},
+		{OpenTime: start.Add(15 * time.Minute), Open: 1.3, High: 1.4, Low: 0.8, Close: 0.9},
+	}
+	klines := aggregateBacktestKlines(bars)
+	if len(klines) != 2 {
+		t.Fatalf("expected 2 klines, got %d", len(klines))
+	}
+	first := klines[0]
+	if first.Open != 1 || first.High != 1.5 || first.Low != 0.9 || first.Close != 1.3 {
+		t.Fatalf("unexpected first kline: %+v", first)
+	}
+	if first.CloseTime != start.Add(15*time.Minute).UnixMilli()-1 {
+		t.Fatalf("unexpected close time %d", first.CloseTime)
+	}
+	if klines[1].Low != 0.8 || klines[1].OpenTime != start.Add(15*time.Minute).UnixMilli() {
+		t.Fatalf("unexpected second kline: %+v", klines[1])
+	}
+}
+
+func TestBacktestMaxDrawdown_fromPeakOfCumulativePnL(t *testing.T) {
+	drawdown := backtestMaxDrawdown([]float64{0.1, 0.2, -0.15, -0.1, 0.3, -0.05})
+	if math.Abs(drawdown-0.25) > 1e-12 {
+		t.Fatalf("expected drawdown 0.25, got %f", drawdown)
+	}
+	if drawdown := backtestMaxDrawdown([]float64{-0.1, 0.05}); math.Abs(drawdown-0.1) > 1e-12 {
+		t.Fatalf("expected drawdown below zero start 0.1, got %f", drawdown)
+	}
+}
+
+func TestBacktest_replaysSignalToTarget(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars, signalAt := palisadeBacktestBars(start)
+	state := newMemState()
+	u := NewBacktestUsecase(
+		&fakeExchange{symbols: []mexc.SymbolDetail{backtestTestSymbol()}},
+		state,
+		&memTrend{bars: map[string][]repo.MarketMinuteBar{"AAAUSDT": bars}},
+		testStrategy,
+	)
+	opts := DefaultBacktestOptions()
+	opts.Symbols = []string{"AAAUSDT"}
+	opts.From = signalAt
+	opts.To = signalAt.Add(time.Hour)
+
+	if err := u.Process(context.Background(), opts); err != nil {
+		t.Fatalf("backtest: %v", err)
+	}
+	if len(state.runs) != 1 {
+		t.Fatalf("expected one backtest_run, got %d", len(state.runs))
+	}
+	run := state.runs[0]
+	if run.StrategyVersion != paperStrategyVersion || run.Signals != 1 || run.Trades != 1 || run.Wins != 1 {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if run.TotalPnL <= 0 || run.WinRate != 1 || run.MaxDrawdown != 0 {
+		t.Fatalf("expected profitable run without drawdown, got %+v", run)
+	}
+	if !strings.Contains(run.ExitReasons, `"TARGET_REACHED":1`) {
+		t.Fatalf("unexpected exit reasons %s", run.ExitReasons)
+	}
+}
+
+func TestBacktest_versionFromConfiguredAlgorithm(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars, signalAt := palisadeBacktestBars(start)
+	cfg := testStrategy
+	cfg.Algorithm = "palisade/v7"
+	state := newMemState()
+	u := NewBacktestUsecase(
+		&fakeExchange{symbols: []mexc.SymbolDetail{backtestTestSymbol()}},
+		state,
+		&memTrend{bars: map[string][]repo.MarketMinuteBar{"AAAUSDT": bars}},
+		cfg,
+	)
+	opts := DefaultBacktestOptions()
+	opts.Symbols = []string{"AAAUSDT"}
+	opts.From = signalAt
+	opts.To = signalAt.Add(time.Hour)
+
+	if err := u.Process(context.Background(), opts); err != nil {
+		t.Fatalf("backtest: %v", err)
+	}
+	if len(state.runs) != 1 || state.runs[0].StrategyVersion != 7 {
+		t.Fatalf("expected one run of configured v7, got %+v", state.runs)
+	}
+
+	opts.Versions = []int{paperStrategyVersion + 1}
+	if err := u.Process(context.Background(), opts); !errors.Is(err, strategy.ErrUnknownStrategy) {
+		t.Fatalf("expected unknown strategy version, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/drybin/palisade/internal/app/cli/config"
 	"github.com/drybin/palisade/internal/domain/repo"
 	"github.com/drybin/palisade/internal/domain/service"
 	"github.com/drybin/palisade/pkg/wrap"
@@ -17,15 +18,18 @@ type ICheckPalisadeCoinList interface {
 type CheckPalisadeCoinList struct {
 	checkerService *service.PalisadeCheckerService
 	stateRepo      repo.IStateRepository
+	strategy       config.CoinCheckStrategyConfig
 }
 
 func NewCheckPalisadeCoinListUsecase(
 	checkerService *service.PalisadeCheckerService,
 	stateRepo repo.IStateRepository,
+	strategy config.CoinCheckStrategyConfig,
 ) *CheckPalisadeCoinList {
 	return &CheckPalisadeCoinList{
 		checkerService: checkerService,
 		stateRepo:      stateRepo,
+		strategy:       strategy,
 	}
 }
 
@@ -59,13 +63,13 @@ func (u *CheckPalisadeCoinList) Process(ctx context.Context, debug bool) error {
 
 		// Используем сервис для проверки и обновления монеты
 		result, err := u.checkerService.CheckAndUpdateCoin(ctx, service.CheckCoinParams{
-			Symbol:               coin.Symbol,
-			BaseAsset:            coin.BaseAsset,
-			QuoteAsset:           coin.QuoteAsset,
-			LastCheck:            coin.LastCheck,
-			MinTimeSinceLastCheck: 180 * time.Minute,
-			MaxVolatilityPercent: 5.0,
-			Debug:                debug,
+			Symbol:                coin.Symbol,
+			BaseAsset:             coin.BaseAsset,
+			QuoteAsset:            coin.QuoteAsset,
+			LastCheck:             coin.LastCheck,
+			MinTimeSinceLastCheck: u.strategy.MinTimeSinceLastCheck,
+			MaxVolatilityPercent:  u.strategy.MaxVolatilityPercent,
+			Debug:                 debug,
 		})
 
 		// Обрабатываем результат
@@ -91,7 +95,6 @@ func (u *CheckPalisadeCoinList) Process(ctx context.Context, debug bool) error {
 
 		// Монета успешно обработана
 		totalProcessed++
-		time.Sleep(3 * time.Second)
 	}
 
 	elapsed := time.Since(startTime)
@@ -3,29 +3,45 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strings"
 
-	"github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
 	"github.com/drybin/palisade/pkg/wrap"
 )
 
+// CheckSwapOptions — Starts: активы, из которых ищутся циклы; MaxLegs —
+// наибольшая длина цикла (3 или 4 ноги).
+type CheckSwapOptions struct {
+    Quiet   bool
+    Starts  []string
+    MaxLegs int
+}
+
+func DefaultCheckSwapOptions() CheckSwapOptions {
+    return CheckSwapOptions{Starts: []string{swapStartAsset}, MaxLegs: swapMaxCycleLegs}
+}
+
 type ICheckSwap interface {
-    Process(ctx context.Context, quiet bool) error
+    Process(ctx context.Context, opts CheckSwapOptions) error
 }
 
 type CheckSwap struct {
-    repo      *webapi.MexcWebapi
+    repo      repo.IMexcRepository
     stateRepo repo.IStateRepository
 }
 
-func NewCheckSwapUsecase(repo *webapi.MexcWebapi, stateRepo repo.IStateRepository) *CheckSwap {
+func NewCheckSwapUsecase(repo repo.IMexcRepository, stateRepo repo.IStateRepository) *CheckSwap {
     return &CheckSwap{repo: repo, stateRepo: stateRepo}
 }
 
-func (u *CheckSwap) Process(ctx context.Context, quiet bool) error {
+func (u *CheckSwap) Process(ctx context.Context, opts CheckSwapOptions) error {
+    if len(opts.Starts) == 0 {
+        return wrap.Errorf("no start assets for cycle search")
+    }
+    if opts.MaxLegs < swapMinCycleLegs || opts.MaxLegs > swapMaxCycleLegs {
+        return wrap.Errorf("max legs must be between %d and %d, got %d", swapMinCycleLegs, swapMaxCycleLegs, opts.MaxLegs)
+    }
     bookRows, err := u.repo.GetAllBookTickers(ctx)
     if err != nil {
         return wrap.Errorf("failed to get book tickers: %w", err)
@@ -38,54 +54,29 @@ func (u *CheckSwap) Process(ctx context.Context, quiet bool) error {
     }
     symbolIndex := BuildSymbolDetailIndex(exchangeInfoAll)
 
-    // Тип и расчёт allChains вынесены для использования в обоих режимах
-    type chainProfit struct {
-        baseA, baseB string
-        profit       float64
-    }
-    allowedHubs := map[string]bool{"BTC": true, "ETH": true, "USDC": true}
-    baseAssets := make([]string, 0)
-    for symbol := range book {
-        if strings.HasSuffix(symbol, "USDT") && len(symbol) > 4 {
-            base := symbol[:len(symbol)-4]
-            baseAssets = append(baseAssets, base)
-        }
-    }
-    sort.Strings(baseAssets)
-    var allChains []chainProfit
-    for i := 0; i < len(baseAssets); i++ {
-        for j := 0; j < len(baseAssets); j++ {
-            if i == j {
-                continue
-            }
-            baseA, baseB := baseAssets[i], baseAssets[j]
-            if !allowedHubs[baseA] && !allowedHubs[baseB] {
-                continue
-            }
-            symbolAUSDT := baseA + "USDT"
-            symbolBUSDT := baseB + "USDT"
-            coinA := mexc.SymbolDetail{BaseAsset: baseA, Symbol: symbolAUSDT, QuoteAsset: "USDT"}
-            coinB := mexc.SymbolDetail{BaseAsset: baseB, Symbol: symbolBUSDT, QuoteAsset: "USDT"}
-            res, ok := calcSwapChainFromBook(book, &coinA, &coinB, &SwapChainMeta{
-                Index:       symbolIndex,
-                InterBuffer: swapIntermediateBuffer,
-            })
-            if !ok {
-                continue
-            }
-            allChains = append(allChains, chainProfit{baseA, baseB, res.profitPercent})
-        }
-    }
-    sort.Slice(allChains, func(i, j int) bool { return allChains[i].profit > allChains[j].profit })
-    
-    if quiet {
+    // Циклы по графу всех пар; в quiet-режиме нужны только прибыльные > 1%.
+    search := swapCycleSearch{
+        Starts:           opts.Starts,
+        MinLegs:          swapMinCycleLegs,
+        MaxLegs:          opts.MaxLegs,
+        InterBuffer:      swapIntermediateBuffer,
+        MinProfitPercent: swapNoProfitFloor,
+        Limit:            swapTopCycles,
+    }
+    if opts.Quiet {
+        search.MinProfitPercent = 1
+        search.Limit = 0
+    }
+    allChains := findSwapCycles(buildSwapGraph(book, symbolIndex, nil), search)
+
+    if opts.Quiet {
         n := 0
         for _, c := range allChains {
-            if c.profit <= 1 {
+            if c.ProfitPercent <= 1 {
                 continue
             }
             n++
-            fmt.Printf("%d. USDT -> %s -> %s -> USDT  |  %.4f%%\n", n, c.baseA, c.baseB, c.profit)
+            fmt.Printf("%d. %s  |  %.4f%%\n", n, c.Path(), c.ProfitPercent)
         }
         return nil
     }
@@ -204,15 +195,15 @@ func (u *CheckSwap) Process(ctx context.Context, quiet bool) error {
     }
     
     // Все цепочки с прибылью (allChains уже посчитаны выше)
-    fmt.Printf("\n--- Все цепочки с прибылью (USDT -> A -> B -> USDT, через BTC/ETH/USDC) ---\n")
+    fmt.Printf("\n--- Циклы с прибылью (%d–%d ноги из %s, лучшие %d) ---\n", swapMinCycleLegs, opts.MaxLegs, strings.Join(opts.Starts, ", "), swapTopCycles)
     var profitable int
     for _, c := range allChains {
-        if c.profit <= 0 {
+        if c.ProfitPercent <= 0 {
             continue
         }
         profitable++
-        fmt.Printf("  %d. USDT -> %s -> %s -> USDT  |  прибыль: %.4f%%\n",
-            profitable, c.baseA, c.baseB, c.profit)
+        fmt.Printf("  %d. %s  |  прибыль: %.4f%%\n",
+            profitable, c.Path(), c.ProfitPercent)
     }
     if profitable == 0 {
         fmt.Println("  Нет цепочек с прибылью.")
@@ -220,17 +211,17 @@ func (u *CheckSwap) Process(ctx context.Context, quiet bool) error {
         fmt.Printf("\nВсего цепочек с прибылью: %d\n", profitable)
     }
     
-    fmt.Printf("\n--- Топ 5 связок с минимальным минусом (только через BTC/ETH/USDC) ---\n")
+    fmt.Printf("\n--- Топ 5 циклов с минимальным минусом ---\n")
     top := 5
     if len(allChains) < top {
         top = len(allChains)
     }
     for i := 0; i < top; i++ {
         c := allChains[i]
-        fmt.Printf("  %d. USDT -> %s -> %s -> USDT  |  %.4f%%\n", i+1, c.baseA, c.baseB, c.profit)
+        fmt.Printf("  %d. %s  |  %.4f%%\n", i+1, c.Path(), c.ProfitPercent)
     }
     if top == 0 {
-        fmt.Println("  Нет цепочек с полными данными (нет пар A-B в book ticker).")
+        fmt.Println("  Нет циклов с полными данными (нет пар между активами в book ticker).")
     }
     
     return nil
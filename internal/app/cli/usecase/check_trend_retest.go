@@ -16,13 +16,13 @@ type ICheckTrendRetest interface {
 }
 
 type CheckTrendRetest struct {
-	mexcAPI     *webapi.MexcWebapi
+	mexcAPI     repo.IMexcRepository
 	trendRepo   repo.ITrendRepository
 	telegramAPI *webapi.TelegramWebapi
 }
 
 func NewCheckTrendRetestUsecase(
-	mexcAPI *webapi.MexcWebapi,
+	mexcAPI repo.IMexcRepository,
 	trendRepo repo.ITrendRepository,
 	telegramAPI *webapi.TelegramWebapi,
 ) *CheckTrendRetest {
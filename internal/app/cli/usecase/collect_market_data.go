@@ -6,7 +6,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/domain/repo"
 	"github.com/drybin/palisade/pkg/wrap"
 )
@@ -16,11 +15,11 @@ type ICollectMarketData interface {
 }
 
 type CollectMarketData struct {
-	api       *webapi.MexcWebapi
+	api       repo.IMexcRepository
 	stateRepo repo.IStateRepository
 }
 
-func NewCollectMarketDataUsecase(api *webapi.MexcWebapi, stateRepo repo.IStateRepository) *CollectMarketData {
+func NewCollectMarketDataUsecase(api repo.IMexcRepository, stateRepo repo.IStateRepository) *CollectMarketData {
 	return &CollectMarketData{api: api, stateRepo: stateRepo}
 }
 
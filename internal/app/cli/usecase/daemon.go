@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const (
+	daemonLockPrefix             = "palisade:daemon:"
+	defaultDaemonShutdownTimeout = 5 * time.Minute
+	DefaultDaemonJobs            = "process=1m,process-sell=1m,paper-palisade-signals=1m"
+	daemonSaveJobRunTimeout      = 10 * time.Second
+	daemonClockCheckTimeout      = 10 * time.Second
+)
+
+// DaemonJobFunc — один запуск задачи daemon.
+type DaemonJobFunc func(context.Context) error
+
+type DaemonOptions struct {
+	// Jobs — интервал запуска по имени задачи.
+	Jobs            map[string]time.Duration
+	ShutdownTimeout time.Duration
+}
+
+func DefaultDaemonOptions() DaemonOptions {
+	jobs, _ := ParseDaemonJobs(DefaultDaemonJobs)
+	return DaemonOptions{Jobs: jobs, ShutdownTimeout: defaultDaemonShutdownTimeout}
+}
+
+// ParseDaemonJobs разбирает список вида "process=1m,process-sell=30s".
+func ParseDaemonJobs(raw string) (map[string]time.Duration, error) {
+	jobs := map[string]time.Duration{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, rawInterval, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, wrap.Errorf("daemon job %q: expected name=interval", part)
+		}
+		interval, err := time.ParseDuration(strings.TrimSpace(rawInterval))
+		if err != nil || interval <= 0 {
+			return nil, wrap.Errorf("daemon job %q: bad interval %q", name, rawInterval)
+		}
+		jobs[strings.TrimSpace(name)] = interval
+	}
+	return jobs, nil
+}
+
+type IDaemon interface {
+	Process(context.Context, DaemonOptions) error
+}
+
+// Daemon держит задачи, которые раньше запускал run-cron.sh, в одном
+// процессе. Задачи выполняются по очереди одной горутиной: у процесса одно
+// соединение с Postgres, а pgx.Conn не потокобезопасен. От пересечения с
+// другим daemon или ручным запуском той же команды задачу защищает
+// advisory lock palisade:daemon:<name>; торговые usecase'ы внутри, как и
+// раньше, берут общий palisade:spot-trading.
+//
+// При отмене ctx новые запуски не начинаются, а текущая задача дорабатывает
+// с неотменённым контекстом — чтобы не бросить ордер между выставлением и
+// записью в trade_log. Если она не успела за ShutdownTimeout, её контекст
+// отменяется.
+//
+// При старте daemon сверяет часы с биржей (clock может быть nil) и пишет
+// смещение в лог. Большое расхождение не останавливает процесс: ордера
+// отклоняет сам клиент биржи, а задачи без ордеров продолжают работать.
+type Daemon struct {
+	stateRepo repo.IStateRepository
+	clock     repo.IExchangeClock
+	jobs      map[string]DaemonJobFunc
+}
+
+func NewDaemonUsecase(stateRepo repo.IStateRepository, clock repo.IExchangeClock, jobs map[string]DaemonJobFunc) *Daemon {
+	return &Daemon{stateRepo: stateRepo, clock: clock, jobs: jobs}
+}
+
+type daemonJob struct {
+	name     string
+	interval time.Duration
+	run      DaemonJobFunc
+	next     time.Time
+}
+
+func (u *Daemon) Process(ctx context.Context, opts DaemonOptions) error {
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = defaultDaemonShutdownTimeout
+	}
+	jobs, err := u.resolveJobs(opts.Jobs)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		fmt.Printf("daemon: %s каждые %s\n", job.name, job.interval)
+	}
+	u.checkClock(ctx)
+
+	for {
+		job := jobs[0]
+		for _, candidate := range jobs[1:] {
+			if candidate.next.Before(job.next) {
+				job = candidate
+			}
+		}
+		if wait := time.Until(job.next); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				fmt.Println("daemon: остановлен")
+				return nil
+			case <-timer.C:
+			}
+		}
+		if ctx.Err() != nil {
+			fmt.Println("daemon: остановлен")
+			return nil
+		}
+
+		startedAt := time.Now()
+		u.runJob(ctx, job, opts.ShutdownTimeout)
+		job.next = startedAt.Add(job.interval)
+		if now := time.Now(); job.next.Before(now) {
+			job.next = now
+		}
+	}
+}
+
+func (u *Daemon) checkClock(ctx context.Context) {
+	if u.clock == nil {
+		return
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, daemonClockCheckTimeout)
+	defer cancel()
+	status, err := u.clock.SyncClock(checkCtx)
+	switch {
+	case errors.Is(err, mexc.ErrClockSkew):
+		fmt.Printf("daemon: ВНИМАНИЕ: часы расходятся с биржей (%v), ордера будут отклоняться\n", err)
+	case err != nil:
+		fmt.Printf("daemon: не удалось сверить часы с биржей: %v\n", err)
+	default:
+		fmt.Printf("daemon: часы биржи: смещение %s, round-trip %s\n", status.Offset, status.RoundTrip)
+	}
+}
+
+func (u *Daemon) resolveJobs(intervals map[string]time.Duration) ([]*daemonJob, error) {
+	if len(intervals) == 0 {
+		return nil, wrap.Errorf("daemon: no jobs")
+	}
+	names := make([]string, 0, len(intervals))
+	for name := range intervals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	jobs := make([]*daemonJob, 0, len(names))
+	for _, name := range names {
+		run, ok := u.jobs[name]
+		if !ok {
+			return nil, wrap.Errorf("daemon: unknown job %q, available: %s", name, strings.Join(u.availableJobs(), ", "))
+		}
+		jobs = append(jobs, &daemonJob{name: name, interval: intervals[name], run: run, next: now})
+	}
+	return jobs, nil
+}
+
+func (u *Daemon) availableJobs() []string {
+	names := make([]string, 0, len(u.jobs))
+	for name := range u.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (u *Daemon) runJob(ctx context.Context, job *daemonJob, shutdownTimeout time.Duration) {
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stopWatch := context.AfterFunc(ctx, func() {
+		fmt.Printf("daemon: остановка, ждём завершения %s (не дольше %s)\n", job.name, shutdownTimeout)
+		timer := time.AfterFunc(shutdownTimeout, cancel)
+		context.AfterFunc(jobCtx, func() { timer.Stop() })
+	})
+	defer stopWatch()
+
+	releaseLock, acquired, err := acquireNamedLock(jobCtx, u.stateRepo, daemonLockPrefix+job.name)
+	if err != nil {
+		fmt.Printf("daemon: %s: %v\n", job.name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	startedAt := time.Now().UTC()
+	err = runDaemonJob(jobCtx, job.run)
+	finishedAt := time.Now().UTC()
+	releaseLock()
+
+	run := repo.DaemonJobRun{Name: job.name, StartedAt: startedAt, FinishedAt: finishedAt}
+	if err != nil {
+		run.Error = err.Error()
+		fmt.Printf("daemon: %s завершился с ошибкой за %s: %v\n", job.name, finishedAt.Sub(startedAt).Round(time.Millisecond), err)
+	}
+	saveCtx, cancelSave := context.WithTimeout(context.WithoutCancel(ctx), daemonSaveJobRunTimeout)
+	defer cancelSave()
+	if err := u.stateRepo.SaveDaemonJobRun(saveCtx, run); err != nil {
+		fmt.Printf("daemon: %s: %v\n", job.name, err)
+	}
+}
+
+// runDaemonJob превращает панику задачи в ошибку, чтобы она не уронила
+// остальные задачи процесса.
+func runDaemonJob(ctx context.Context, run DaemonJobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = wrap.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
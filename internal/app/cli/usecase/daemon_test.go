@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+func (s *memState) daemonRunsOf(name string) []repo.DaemonJobRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.DaemonJobRun{}
+	for _, run := range s.daemonRuns {
+		if run.Name == name {
+			out = append(out, run)
+		}
+	}
+	return out
+}
+
+func TestDaemon_runsJobsOnIntervalsAndRecordsErrors(t *testing.T) {
+	state := newMemState()
+	var okRuns atomic.Int32
+	u := NewDaemonUsecase(state, nil, map[string]DaemonJobFunc{
+		"ok": func(context.Context) error {
+			okRuns.Add(1)
+			return nil
+		},
+		"fail":  func(context.Context) error { return errors.New("boom") },
+		"panic": func(context.Context) error { panic("unexpected") },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- u.Process(ctx, DaemonOptions{Jobs: map[string]time.Duration{
+			"ok": 5 * time.Millisecond, "fail": 5 * time.Millisecond, "panic": time.Hour,
+		}})
+	}()
+	waitFor(t, "several runs of every job", func() bool {
+		return len(state.daemonRunsOf("ok")) >= 3 && len(state.daemonRunsOf("fail")) >= 3
+	})
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	for _, run := range state.daemonRunsOf("ok") {
+		if run.Error != "" || run.FinishedAt.Before(run.StartedAt) {
+			t.Fatalf("unexpected ok run %+v", run)
+		}
+	}
+	if int(okRuns.Load()) != len(state.daemonRunsOf("ok")) {
+		t.Fatalf("every run must be recorded: %d runs, %d records", okRuns.Load(), len(state.daemonRunsOf("ok")))
+	}
+	if runs := state.daemonRunsOf("fail"); !strings.Contains(runs[0].Error, "boom") {
+		t.Fatalf("expected recorded error, got %+v", runs[0])
+	}
+	if runs := state.daemonRunsOf("panic"); len(runs) != 1 || !strings.Contains(runs[0].Error, "panic: unexpected") {
+		t.Fatalf("panic must be recorded once as error, got %+v", runs)
+	}
+	if len(state.locks) != 0 {
+		t.Fatalf("job locks must be released, got %v", state.locks)
+	}
+}
+
+func TestDaemon_shutdownWaitsForInFlightJob(t *testing.T) {
+	state := newMemState()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	jobCtxErr := make(chan error, 1)
+	u := NewDaemonUsecase(state, nil, map[string]DaemonJobFunc{
+		"order": func(ctx context.Context) error {
+			close(started)
+			<-release
+			jobCtxErr <- ctx.Err()
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- u.Process(ctx, DaemonOptions{Jobs: map[string]time.Duration{"order": time.Hour}})
+	}()
+	<-started
+	cancel()
+	select {
+	case <-done:
+		t.Fatalf("daemon must wait for the in-flight job")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if err := <-jobCtxErr; err != nil {
+		t.Fatalf("in-flight job context must stay alive during shutdown, got %v", err)
+	}
+	if runs := state.daemonRunsOf("order"); len(runs) != 1 || runs[0].Error != "" {
+		t.Fatalf("expected one successful run, got %+v", runs)
+	}
+}
+
+func TestDaemon_unknownJob(t *testing.T) {
+	u := NewDaemonUsecase(newMemState(), nil, map[string]DaemonJobFunc{"process": func(context.Context) error { return nil }})
+	err := u.Process(context.Background(), DaemonOptions{Jobs: map[string]time.Duration{"nope": time.Minute}})
+	if err == nil || !strings.Contains(err.Error(), "available: process") {
+		t.Fatalf("expected unknown job error, got %v", err)
+	}
+}
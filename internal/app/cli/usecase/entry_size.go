@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/service"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+// sizeEntry подтягивает из exchangeInfo ограничения symbol и считает объём
+// входа. Если объём не проходит минимум биржи, причина печатается и
+// возвращается ok=false — вход пропускается без ошибки.
+func sizeEntry(
+	ctx context.Context,
+	api repo.IMexcRepository,
+	sizer *service.PositionSizer,
+	symbol string,
+	req service.SizingRequest,
+) (service.PositionSize, bool, error) {
+	info, err := api.GetSymbolInfo(ctx, symbol)
+	if err != nil {
+		return service.PositionSize{}, false, wrap.Errorf("GetSymbolInfo %s: %w", symbol, err)
+	}
+	detail := findSymbolDetailManual(info, symbol)
+	if detail == nil {
+		return service.PositionSize{}, false, wrap.Errorf("symbol %s not found in exchange info", symbol)
+	}
+	step, err := swapLotStep(detail)
+	if err != nil {
+		return service.PositionSize{}, false, err
+	}
+	req.Symbol = *detail
+	req.LotStep = step
+
+	size, err := sizer.Size(ctx, req)
+	if errors.Is(err, service.ErrPositionTooSmall) {
+		fmt.Printf("❌ Объём входа %s: %v\n", symbol, err)
+		return service.PositionSize{}, false, nil
+	}
+	if err != nil {
+		return service.PositionSize{}, false, err
+	}
+	fmt.Printf("📏 Объём входа %s: %.8f (%.2f USDT, %s)\n", symbol, size.Quantity, size.QuoteUSDT, size.Method)
+	return size, true, nil
+}
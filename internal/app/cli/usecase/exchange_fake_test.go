@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+// fakeExchange — минимальная подмена биржи для unit-тестов usecase'ов.
+// Методы, не переопределённые в тесте, паникуют через nil-интерфейс.
+type fakeExchange struct {
+	repo.IMexcRepository
+	books   mexc.BookTickers
+	symbols []mexc.SymbolDetail
+}
+
+func (f *fakeExchange) GetAllBookTickers(ctx context.Context) (*mexc.BookTickers, error) {
+	return &f.books, nil
+}
+
+func (f *fakeExchange) GetExchangeInfoAll(ctx context.Context) (*mexc.SymbolInfo, error) {
+	return &mexc.SymbolInfo{Symbols: f.symbols}, nil
+}
+
+func TestGetMarketQuote_fakeExchange(t *testing.T) {
+	api := &fakeExchange{books: mexc.BookTickers{
+		{Symbol: "AAAUSDT", BidPrice: "1.0", BidQty: "5", AskPrice: "1.1", AskQty: "7"},
+		{Symbol: "BBBUSDT", BidPrice: "2.0", BidQty: "3", AskPrice: "2.1", AskQty: "4"},
+	}}
+	u := NewExecutePalisadeSignalsUsecase(api, nil, nil, nil, nil, testStrategy)
+
+	quote, err := u.getMarketQuote(context.Background(), "BBBUSDT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quote.bid != 2.0 || quote.ask != 2.1 || quote.bidQty != 3 || quote.askQty != 4 {
+		t.Fatalf("unexpected quote: %+v", quote)
+	}
+}
+
+func TestGetMarketQuote_fakeExchangeMissingSymbol(t *testing.T) {
+	u := NewExecutePalisadeSignalsUsecase(&fakeExchange{}, nil, nil, nil, nil, testStrategy)
+	if _, err := u.getMarketQuote(context.Background(), "CCCUSDT"); err == nil {
+		t.Fatalf("expected error for missing symbol")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
@@ -11,37 +12,47 @@ import (
 	"time"
 
 	"github.com/drybin/palisade/internal/adapter/webapi"
+	"github.com/drybin/palisade/internal/app/cli/config"
 	"github.com/drybin/palisade/internal/domain/enum/order"
 	"github.com/drybin/palisade/internal/domain/helpers"
-	"github.com/drybin/palisade/internal/domain/model"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/service"
+	"github.com/drybin/palisade/internal/domain/strategy"
 	"github.com/drybin/palisade/pkg/wrap"
 )
 
-const (
-	signalOrderQuoteUSDT   = 10.0
-	signalBuyTimeout       = 10 * time.Minute
-	signalRepriceDelta     = 0.002
-	supportBreakPercent    = 0.003
-	hardLossPercent        = 0.008
-	maxPositionHold        = 120 * time.Minute
-	maxEmergencySpread     = 0.01
-	emergencyPriceDiscount = 0.001
-)
-
 type IExecutePalisadeSignals interface {
 	Process(context.Context, bool) error
 }
 
 type ExecutePalisadeSignals struct {
-	api       *webapi.MexcWebapi
-	stateRepo repo.IStateRepository
-	telegram  *webapi.TelegramWebapi
+	api        repo.IMexcRepository
+	stateRepo  repo.IStateRepository
+	telegram   *webapi.TelegramWebapi
+	risk       *service.RiskManager
+	sizer      *service.PositionSizer
+	strategy   config.StrategyConfig
+	strategies *strategy.Registry
 }
 
-func NewExecutePalisadeSignalsUsecase(api *webapi.MexcWebapi, stateRepo repo.IStateRepository, telegram *webapi.TelegramWebapi) *ExecutePalisadeSignals {
-	return &ExecutePalisadeSignals{api: api, stateRepo: stateRepo, telegram: telegram}
+func NewExecutePalisadeSignalsUsecase(
+	api repo.IMexcRepository,
+	stateRepo repo.IStateRepository,
+	telegram *webapi.TelegramWebapi,
+	risk *service.RiskManager,
+	sizer *service.PositionSizer,
+	strategy config.StrategyConfig,
+) *ExecutePalisadeSignals {
+	return &ExecutePalisadeSignals{
+		api:        api,
+		stateRepo:  stateRepo,
+		telegram:   telegram,
+		risk:       risk,
+		sizer:      sizer,
+		strategy:   strategy,
+		strategies: NewStrategyRegistry(strategy),
+	}
 }
 
 // Process executes at most one new signal per run. The live flag is deliberately
@@ -72,35 +83,142 @@ func (u *ExecutePalisadeSignals) Process(ctx context.Context, live bool) error {
 		return err
 	}
 	fmt.Printf("Свободный USDT: %.8f, заблокировано: %.8f\n", usdt.Free, usdt.Locked)
+	if u.strategy.Execution.PostOnly {
+		if err := u.printMakerFeeSavings(ctx); err != nil {
+			return err
+		}
+	}
 
-	unresolvedIntents, err := u.stateRepo.ListRecoverableOrderIntents(ctx)
+	if paused, err := u.pausedByUnresolvedIntents(ctx); err != nil || paused {
+		return err
+	}
+
+	openTrades, err := u.stateRepo.GetOpenOrders(ctx)
 	if err != nil {
 		return err
 	}
+	if len(openTrades) > 0 {
+		return u.reconcileOpenTrades(ctx, openTrades, live)
+	}
+
+	if usdt.Free <= 0 {
+		fmt.Println("Нет свободного USDT для новой сделки")
+		return nil
+	}
+
+	return u.openBestSignal(ctx, usdt.Free, live)
+}
+
+// placeErrorIntentStatus — статус intent после ошибки выставления. Если
+// биржа ответила отказом или запрос не отправлялся из-за часов, ордера точно
+// нет и intent закрывается как REJECTED; иначе (таймаут, 5xx) ордер мог быть
+// создан — UNKNOWN разбирает reconcile-orders.
+func placeErrorIntentStatus(err error) string {
+	if mexc.IsRejected(err) || errors.Is(err, mexc.ErrClockSkew) {
+		return "REJECTED"
+	}
+	return "UNKNOWN"
+}
+
+func (u *ExecutePalisadeSignals) pausedByUnresolvedIntents(ctx context.Context) (bool, error) {
+	unresolvedIntents, err := u.stateRepo.ListRecoverableOrderIntents(ctx)
+	if err != nil {
+		return false, err
+	}
 	if len(unresolvedIntents) > 0 {
 		fmt.Printf("Торговля приостановлена: незавершённых order intents: %d. Запустите reconcile-orders\n", len(unresolvedIntents))
-		return nil
+		return true, nil
+	}
+	return false, nil
+}
+
+// ReconcileOpenTrades опрашивает биржу по открытым сделкам, не открывая
+// новых. Это поллинг-часть Process, которую watch-order-fills запускает,
+// пока user data stream недоступен. acquired=false — торговая блокировка
+// занята другим процессом.
+func (u *ExecutePalisadeSignals) ReconcileOpenTrades(ctx context.Context, live bool) (acquired bool, err error) {
+	releaseLock, acquired, err := acquireTradingLock(ctx, u.stateRepo)
+	if err != nil || !acquired {
+		return acquired, err
 	}
+	defer releaseLock()
 
+	if paused, err := u.pausedByUnresolvedIntents(ctx); err != nil || paused {
+		return true, err
+	}
 	openTrades, err := u.stateRepo.GetOpenOrders(ctx)
 	if err != nil {
-		return err
+		return true, err
 	}
-	if len(openTrades) > 0 {
-		for i := range openTrades {
-			if err := u.reconcileTrade(ctx, openTrades[i], live); err != nil {
-				return err
-			}
+	return true, u.reconcileOpenTrades(ctx, openTrades, live)
+}
+
+func (u *ExecutePalisadeSignals) reconcileOpenTrades(ctx context.Context, openTrades []repo.TradeLog, live bool) error {
+	for i := range openTrades {
+		if err := u.reconcileTrade(ctx, openTrades[i], live); err != nil {
+			return err
 		}
-		return nil
 	}
+	return nil
+}
+
+// HandleExecutionReport применяет push-обновление ордера из user data stream
+// так же, как reconcileTrade применяет ответ GET /api/v3/order: исполненный
+// BUY сразу получает SELL, исполненный SELL закрывает сделку. Промежуточные
+// статусы только записываются в order intent — частичные исполнения и
+// таймауты по-прежнему обрабатывает поллинг. acquired=false — торговая
+// блокировка занята, и обновление нужно догнать поллингом.
+func (u *ExecutePalisadeSignals) HandleExecutionReport(ctx context.Context, report mexc.ExecutionReport, live bool) (acquired bool, err error) {
+	if !strings.HasPrefix(report.ClientOrderID, "Signal_") {
+		return true, nil
+	}
+	releaseLock, acquired, err := acquireTradingLock(ctx, u.stateRepo)
+	if err != nil || !acquired {
+		return acquired, err
+	}
+	defer releaseLock()
 
-	if usdt.Free < signalOrderQuoteUSDT {
-		fmt.Printf("Недостаточно свободного USDT для новой сделки: нужно %.2f\n", signalOrderQuoteUSDT)
-		return nil
+	openTrades, err := u.stateRepo.GetOpenOrders(ctx)
+	if err != nil {
+		return true, err
+	}
+	var trade *repo.TradeLog
+	for i := range openTrades {
+		current := openTrades[i].OrderId
+		if openTrades[i].OrderId_sell != "" {
+			current = openTrades[i].OrderId_sell
+		}
+		if current == report.OrderID {
+			trade = &openTrades[i]
+			break
+		}
+	}
+	if trade == nil {
+		// Ордер уже заменён или сделка закрыта — обновление устарело.
+		return true, nil
 	}
 
-	return u.openBestSignal(ctx, usdt.Free, live)
+	result := report.QueryOrderResult()
+	executed, quote, err := orderFill(&result)
+	if err != nil {
+		return true, err
+	}
+	fmt.Printf("Ордер %s %s %s: %s, исполнено %.8f\n", trade.Symbol, result.Side, result.OrderID, result.Status, executed)
+	switch result.Status {
+	case "FILLED", "CANCELED", "PARTIALLY_CANCELED":
+	default:
+		return true, u.recordOrderIntentState(ctx, trade.ID, &result, executed, quote)
+	}
+	if result.Side == order.BUY.String() {
+		if err := u.recordOrderIntentState(ctx, trade.ID, &result, executed, quote); err != nil {
+			return true, err
+		}
+		return true, u.reconcileBuy(ctx, *trade, &result, live)
+	}
+	if result.Side == order.SELL.String() {
+		return true, u.reconcileSell(ctx, *trade, &result, live)
+	}
+	return true, wrap.Errorf("unknown order side %q for %s", result.Side, trade.Symbol)
 }
 
 func (u *ExecutePalisadeSignals) openBestSignal(ctx context.Context, freeUSDT float64, live bool) error {
@@ -158,11 +276,21 @@ func (u *ExecutePalisadeSignals) openBestSignal(ctx context.Context, freeUSDT fl
 		}
 		priceStep := signalPriceStep(&symbol)
 		entryPrice := roundPriceDown(signal.EntryPrice, priceStep)
-		quantity := swapRoundQtyDown(math.Min(signalOrderQuoteUSDT/entryPrice, freeUSDT/entryPrice), step)
-		if quantity <= 0 || quantity*entryPrice < signalOrderQuoteUSDT*0.99 {
-			fmt.Printf("%s: после округления объём слишком мал: %.8f\n", signal.Symbol, quantity)
+		size, err := u.sizer.Size(ctx, service.SizingRequest{
+			Symbol:          symbol,
+			Price:           entryPrice,
+			LotStep:         step,
+			FreeUSDT:        freeUSDT,
+			StrategyVersion: signal.StrategyVersion,
+		})
+		if errors.Is(err, service.ErrPositionTooSmall) {
+			fmt.Printf("%s: объём входа: %v\n", signal.Symbol, err)
 			continue
 		}
+		if err != nil {
+			return err
+		}
+		quantity := size.Quantity
 
 		targetPrice := roundPriceDown(signal.TargetPrice, priceStep)
 		if targetPrice < signal.MinExitPrice {
@@ -175,7 +303,8 @@ func (u *ExecutePalisadeSignals) openBestSignal(ctx context.Context, freeUSDT fl
 			fmt.Printf("%s: BUY отклонён до отправки: %v\n", signal.Symbol, err)
 			continue
 		}
-		fmt.Printf("Сигнал к исполнению: %s, BUY %.8f @ %.8f, цель %.8f\n", signal.Symbol, quantity, entryPrice, targetPrice)
+		fmt.Printf("Сигнал к исполнению: %s, BUY %.8f @ %.8f (%.2f USDT, %s), цель %.8f\n",
+			signal.Symbol, quantity, entryPrice, size.QuoteUSDT, size.Method, targetPrice)
 		if !live {
 			return nil
 		}
@@ -190,67 +319,43 @@ func (u *ExecutePalisadeSignals) placeBuy(ctx context.Context, signal repo.Palis
 	if err := validateLimitOrder(symbol, order.BUY, signal.EntryPrice, quantity); err != nil {
 		return wrap.Errorf("BUY constraints %s: %w", signal.Symbol, err)
 	}
-	clientID, err := newSignalClientOrderID(order.BUY)
-	if err != nil {
-		return err
-	}
-	intent, err := u.stateRepo.CreateOrderIntent(ctx, repo.OrderIntent{
-		ClientOrderID: clientID,
-		Symbol:        signal.Symbol,
-		Side:          order.BUY.String(),
-		Price:         signal.EntryPrice,
-		Quantity:      quantity,
-		OpenBalance:   balance.free,
-		TargetPrice:   signal.TargetPrice,
-		Status:        "PLACING",
-		CreatedAt:     now,
-		UpdatedAt:     now,
-	})
-	if err != nil {
+	if blocked, err := entryBlockedByRisk(ctx, u.risk, signal.Symbol, signal.EntryPrice*quantity); err != nil || blocked {
 		return err
 	}
-	result, err := u.api.NewOrder(model.OrderParams{
-		Symbol:           signal.Symbol,
-		Side:             order.BUY,
-		OrderType:        order.LIMIT,
-		Quantity:         quantity,
-		QuoteOrderQty:    quantity,
-		Price:            signal.EntryPrice,
-		NewClientOrderId: clientID,
-	})
+	placed, err := u.submitLimitOrder(ctx, symbol, order.BUY, repo.OrderIntent{
+		Symbol:      signal.Symbol,
+		Price:       signal.EntryPrice,
+		Quantity:    quantity,
+		OpenBalance: balance.free,
+		TargetPrice: signal.TargetPrice,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, u.strategy.Execution.PostOnly)
 	if err != nil {
-		_ = u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "UNKNOWN", "", 0, 0, err.Error())
-		return wrap.Errorf("place signal BUY %s: %w", signal.Symbol, err)
-	}
-	if result == nil || result.OrderID == "" {
-		err = wrap.Errorf("empty order response")
-		_ = u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "UNKNOWN", "", 0, 0, err.Error())
-		return wrap.Errorf("place signal BUY %s: %w", signal.Symbol, err)
-	}
-	if err := u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "ACKNOWLEDGED", result.OrderID, 0, 0, ""); err != nil {
 		return err
 	}
+	signal.EntryPrice = placed.price
 	trade, err := u.stateRepo.SaveTradeLog(ctx, repo.SaveTradeLogParams{
 		OpenDate:    now,
 		OpenBalance: balance.free,
 		Symbol:      signal.Symbol,
 		BuyPrice:    signal.EntryPrice,
 		Amount:      quantity,
-		OrderId:     result.OrderID,
+		OrderId:     placed.orderID,
 		UpLevel:     signal.TargetPrice,
 		DownLevel:   signal.EntryPrice,
 	})
 	if err != nil {
 		return wrap.Errorf("save signal BUY %s: %w", signal.Symbol, err)
 	}
-	if err := u.stateRepo.UpdateOrderIntentTradeID(ctx, intent.ID, trade.ID); err != nil {
+	if err := u.stateRepo.UpdateOrderIntentTradeID(ctx, placed.intent.ID, trade.ID); err != nil {
 		return err
 	}
-	if err := u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "LINKED", result.OrderID, 0, 0, ""); err != nil {
+	if err := u.stateRepo.UpdateOrderIntent(ctx, placed.intent.ID, "LINKED", placed.orderID, 0, 0, ""); err != nil {
 		return err
 	}
-	fmt.Printf("BUY размещён: %s, trade_log=%d, order=%s\n", signal.Symbol, trade.ID, result.OrderID)
-	u.notify(fmt.Sprintf("<b>📥 Signal BUY</b> %s · <code>%s</code> · %.8f×%.8f · target %.8f", signal.Symbol, result.OrderID, signal.EntryPrice, quantity, signal.TargetPrice))
+	fmt.Printf("BUY размещён: %s, trade_log=%d, order=%s\n", signal.Symbol, trade.ID, placed.orderID)
+	u.notify(fmt.Sprintf("<b>📥 Signal BUY</b> %s · <code>%s</code> · %.8f×%.8f · target %.8f", signal.Symbol, placed.orderID, signal.EntryPrice, quantity, signal.TargetPrice))
 	return nil
 }
 
@@ -259,7 +364,7 @@ func (u *ExecutePalisadeSignals) reconcileTrade(ctx context.Context, trade repo.
 	if trade.OrderId_sell != "" {
 		orderID = trade.OrderId_sell
 	}
-	result, err := u.api.GetOrderQuery(trade.Symbol, orderID)
+	result, err := u.api.GetOrderQuery(ctx, trade.Symbol, orderID)
 	if err != nil {
 		return wrap.Errorf("query order %s/%s: %w", trade.Symbol, orderID, err)
 	}
@@ -302,10 +407,10 @@ func (u *ExecutePalisadeSignals) reconcileBuy(ctx context.Context, trade repo.Tr
 			return nil
 		}
 		if result.Status == "NEW" || result.Status == "PARTIALLY_FILLED" {
-			if _, err := u.api.CancelOrder(trade.Symbol, trade.OrderId); err != nil {
+			if _, err := u.api.CancelOrder(ctx, trade.Symbol, trade.OrderId); err != nil && !mexc.IsOrderNotFound(err) {
 				return wrap.Errorf("cancel BUY for emergency exit %s: %w", trade.Symbol, err)
 			}
-			result, err = u.api.GetOrderQuery(trade.Symbol, trade.OrderId)
+			result, err = u.api.GetOrderQuery(ctx, trade.Symbol, trade.OrderId)
 			if err != nil || result == nil {
 				return wrap.Errorf("query canceled BUY for emergency exit %s: %v", trade.Symbol, err)
 			}
@@ -328,17 +433,20 @@ func (u *ExecutePalisadeSignals) reconcileBuy(ctx context.Context, trade repo.Tr
 			return err
 		}
 		trade.BuyPrice = averageBuyPrice
+		if err := u.recordOrderIntentState(ctx, trade.ID, result, executed, quote); err != nil {
+			return err
+		}
 		return u.placeEmergencySell(ctx, trade, executed, reason, live)
 	}
-	if result.Status == "NEW" && time.Since(trade.OpenDate) < signalBuyTimeout {
+	if result.Status == "NEW" && time.Since(trade.OpenDate) < u.strategy.Execution.BuyTimeout {
 		fmt.Printf("BUY ожидает исполнения: %s %s\n", trade.Symbol, trade.OrderId)
 		return nil
 	}
 	if result.Status == "NEW" && live {
-		if _, err := u.api.CancelOrder(trade.Symbol, trade.OrderId); err != nil {
+		if _, err := u.api.CancelOrder(ctx, trade.Symbol, trade.OrderId); err != nil && !mexc.IsOrderNotFound(err) {
 			return wrap.Errorf("cancel stale BUY %s: %w", trade.OrderId, err)
 		}
-		result, err = u.api.GetOrderQuery(trade.Symbol, trade.OrderId)
+		result, err = u.api.GetOrderQuery(ctx, trade.Symbol, trade.OrderId)
 		if err != nil || result == nil {
 			return wrap.Errorf("query canceled BUY %s: %v", trade.OrderId, err)
 		}
@@ -362,10 +470,10 @@ func (u *ExecutePalisadeSignals) reconcileBuy(ctx context.Context, trade repo.Tr
 	}
 	trade.BuyPrice = averageBuyPrice
 	if result.Status == "PARTIALLY_FILLED" && live {
-		if _, err := u.api.CancelOrder(trade.Symbol, trade.OrderId); err != nil {
+		if _, err := u.api.CancelOrder(ctx, trade.Symbol, trade.OrderId); err != nil && !mexc.IsOrderNotFound(err) {
 			return wrap.Errorf("cancel partially filled BUY %s: %w", trade.OrderId, err)
 		}
-		result, err = u.api.GetOrderQuery(trade.Symbol, trade.OrderId)
+		result, err = u.api.GetOrderQuery(ctx, trade.Symbol, trade.OrderId)
 		if err != nil || result == nil {
 			return wrap.Errorf("query canceled partial BUY %s: %v", trade.OrderId, err)
 		}
@@ -384,6 +492,10 @@ func (u *ExecutePalisadeSignals) reconcileBuy(ctx context.Context, trade repo.Tr
 			trade.BuyPrice = averageBuyPrice
 		}
 	}
+	// Исполнение BUY нужно intent для учёта экономии post-only.
+	if err := u.recordOrderIntentState(ctx, trade.ID, result, executed, quote); err != nil {
+		return err
+	}
 	return u.placeSellForTrade(ctx, trade, executed, live)
 }
 
@@ -398,11 +510,17 @@ func (u *ExecutePalisadeSignals) reconcileSell(ctx context.Context, trade repo.T
 	if result.Status == "FILLED" {
 		return u.replaceSellRemainder(ctx, trade, live, 0, "")
 	}
-	reason, _, err := u.emergencyExitReason(ctx, trade, trade.BuyPrice)
+	market, err := u.getMarketQuote(ctx, trade.Symbol)
 	if err != nil {
 		return err
 	}
-	if reason != "" {
+	decision, exit, err := u.evaluateExit(ctx, trade, market.bid, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	active := result.Status == "NEW" || result.Status == "PARTIALLY_FILLED"
+	if decision.IsStop() {
+		reason := decision.Reason
 		fmt.Printf("Аварийный выход %s: %s\n", trade.Symbol, reason)
 		if !live {
 			return nil
@@ -422,6 +540,17 @@ func (u *ExecutePalisadeSignals) reconcileSell(ctx context.Context, trade repo.T
 		}
 		return u.placeEmergencySell(ctx, trade, progress.remaining(), reason, live)
 	}
+	if live && active && decision.Reason == strategy.ExitReasonPartial {
+		return u.takePartialProfit(ctx, trade, result, exit, decision)
+	}
+	if live && active && exit != nil && exit.PartialOrderID == result.OrderID {
+		// Доля быстрой прибыли не исполнилась за интервал опроса: остаток
+		// позиции возвращается под тейк-профит.
+		if _, err := u.cancelAndRefreshSell(ctx, trade, result); err != nil {
+			return wrap.Errorf("cancel unfilled partial SELL %s: %w", trade.Symbol, err)
+		}
+		return u.replaceSellRemainder(ctx, trade, true, 0, "")
+	}
 	if result.Status == "NEW" {
 		return u.maybeRepriceSell(ctx, trade, result, live)
 	}
@@ -452,6 +581,9 @@ func (u *ExecutePalisadeSignals) finishSell(ctx context.Context, trade repo.Trad
 	if err := u.stateRepo.UpdateSuccesTradeLog(ctx, trade.ID, closeAt, progress.quote, progress.quote/progress.executed); err != nil {
 		return err
 	}
+	if err := u.stateRepo.UpdateOrderBracketStatus(ctx, trade.ID, repo.OrderBracketClosed); err != nil {
+		return err
+	}
 	fmt.Printf("SELL завершён: %s, qty %.8f, %.8f USDT\n", trade.Symbol, progress.executed, progress.quote)
 	u.notify(fmt.Sprintf("<b>💰 Signal SELL</b> %s · qty %.8f · %.8f USDT", trade.Symbol, progress.executed, progress.quote))
 	return nil
@@ -469,7 +601,7 @@ func (u *ExecutePalisadeSignals) maybeRepriceSell(ctx context.Context, trade rep
 		if signal.Symbol != trade.Symbol || signal.TargetPrice <= 0 {
 			continue
 		}
-		if math.Abs(signal.TargetPrice-trade.UpLevel)/trade.UpLevel < signalRepriceDelta {
+		if math.Abs(signal.TargetPrice-trade.UpLevel)/trade.UpLevel < u.strategy.Execution.RepriceDelta {
 			return nil
 		}
 		if signal.TargetPrice < signal.MinExitPrice {
@@ -497,8 +629,8 @@ func (u *ExecutePalisadeSignals) cancelAndRefreshSell(
 	if current != nil && current.OrderID != "" {
 		orderID = current.OrderID
 	}
-	_, cancelErr := u.api.CancelOrder(trade.Symbol, orderID)
-	finalOrder, queryErr := u.api.GetOrderQuery(trade.Symbol, orderID)
+	_, cancelErr := u.api.CancelOrder(ctx, trade.Symbol, orderID)
+	finalOrder, queryErr := u.api.GetOrderQuery(ctx, trade.Symbol, orderID)
 	if queryErr != nil {
 		return nil, wrap.Errorf("query SELL %s after cancel: %w", orderID, queryErr)
 	}
@@ -547,12 +679,12 @@ func (u *ExecutePalisadeSignals) placeEmergencySell(ctx context.Context, trade r
 	if err != nil {
 		return err
 	}
-	if quote.bid <= 0 || quote.ask <= quote.bid || (quote.ask-quote.bid)/quote.bid > maxEmergencySpread {
+	if quote.bid <= 0 || quote.ask <= quote.bid || (quote.ask-quote.bid)/quote.bid > u.strategy.Execution.MaxEmergencySpread {
 		u.notify(fmt.Sprintf("<b>⚠️ Emergency exit delayed</b> %s · %s · spread %.3f%%", trade.Symbol, reason, (quote.ask/quote.bid-1)*100))
 		fmt.Printf("Аварийная продажа отложена: %s, спред слишком широк или стакан некорректен\n", trade.Symbol)
 		return nil
 	}
-	price := quote.bid * (1 - emergencyPriceDiscount)
+	price := quote.bid * (1 - u.strategy.Execution.EmergencyPriceDiscount)
 	return u.placeSellForTradeAtPrice(ctx, trade, requested, live, price, "EMERGENCY: "+reason)
 }
 
@@ -605,63 +737,330 @@ func (u *ExecutePalisadeSignals) placeSellForTradeAtPrice(ctx context.Context, t
 	if !live {
 		return nil
 	}
-	clientID, err := newSignalClientOrderID(order.SELL)
+	// Post-only выставляется только тейк-профит: аварийный SELL и доля
+	// быстрой прибыли должны исполниться, а не ждать в стакане.
+	placed, err := u.submitLimitOrder(ctx, *symbol, order.SELL, repo.OrderIntent{
+		Symbol:    trade.Symbol,
+		Price:     price,
+		Quantity:  quantity,
+		LastError: reason,
+		TradeID:   trade.ID,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}, u.strategy.Execution.PostOnly && reason == "")
 	if err != nil {
 		return err
 	}
-	intent, err := u.stateRepo.CreateOrderIntent(ctx, repo.OrderIntent{
-		ClientOrderID: clientID,
-		Symbol:        trade.Symbol,
-		Side:          order.SELL.String(),
-		Price:         price,
-		Quantity:      quantity,
-		TargetPrice:   price,
-		LastError:     reason,
-		Status:        "PLACING",
-		TradeID:       trade.ID,
-		CreatedAt:     time.Now().UTC(),
-		UpdatedAt:     time.Now().UTC(),
-	})
-	if err != nil {
+	price = placed.price
+	if err := u.stateRepo.UpdateSellOrderIdTradeLog(ctx, trade.ID, placed.orderID); err != nil {
 		return err
 	}
-	result, err := u.api.NewOrder(model.OrderParams{
-		Symbol:           trade.Symbol,
-		Side:             order.SELL,
-		OrderType:        order.LIMIT,
-		Quantity:         quantity,
-		QuoteOrderQty:    quantity,
-		Price:            price,
-		NewClientOrderId: clientID,
-	})
-	if err != nil {
-		_ = u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "UNKNOWN", "", 0, 0, err.Error())
-		return wrap.Errorf("place signal SELL %s: %w", trade.Symbol, err)
+	if err := u.stateRepo.UpdateDealDateTradeLog(ctx, trade.ID, time.Now().UTC()); err != nil {
+		return err
 	}
-	if result == nil || result.OrderID == "" {
-		err = wrap.Errorf("empty order response")
-		_ = u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "UNKNOWN", "", 0, 0, err.Error())
-		return wrap.Errorf("place signal SELL %s: %w", trade.Symbol, err)
+	if err := u.stateRepo.UpdateOrderIntent(ctx, placed.intent.ID, "LINKED", placed.orderID, 0, 0, ""); err != nil {
+		return err
 	}
-	if err := u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "ACKNOWLEDGED", result.OrderID, 0, 0, ""); err != nil {
+	if err := u.armExit(ctx, trade, *symbol, placed.orderID, price, reason); err != nil {
 		return err
 	}
-	if err := u.stateRepo.UpdateSellOrderIdTradeLog(ctx, trade.ID, result.OrderID); err != nil {
+	fmt.Printf("SELL размещён: %s order=%s qty=%.8f\n", trade.Symbol, placed.orderID, quantity)
+	if reason == strategy.ExitReasonPartial {
+		u.notify(fmt.Sprintf("<b>💵 Partial SELL</b> %s · <code>%s</code> · %.8f×%.8f", trade.Symbol, placed.orderID, price, quantity))
+	} else if reason != "" {
+		u.notify(fmt.Sprintf("<b>🚨 Emergency SELL</b> %s · <code>%s</code> · %.8f×%.8f · %s", trade.Symbol, placed.orderID, price, quantity, reason))
+	} else {
+		u.notify(fmt.Sprintf("<b>📤 Signal SELL</b> %s · <code>%s</code> · %.8f×%.8f", trade.Symbol, placed.orderID, price, quantity))
+	}
+	return nil
+}
+
+// armExit связывает выставленный SELL с выходом из сделки. Тейк-профит
+// (reason пуст) получает брекет со стоп-ногой, а первый — ещё и состояние
+// exit-политики; SELL доли быстрой прибыли запоминается до замены
+// тейк-профитом; аварийный SELL и есть сработавшая стоп-нога, брекет больше
+// не следит за ценой.
+func (u *ExecutePalisadeSignals) armExit(ctx context.Context, trade repo.TradeLog, symbol mexc.SymbolDetail, orderID string, takeProfit float64, reason string) error {
+	now := time.Now().UTC()
+	switch reason {
+	case "":
+	case strategy.ExitReasonPartial:
+		exit, err := u.stateRepo.GetTradeExitState(ctx, trade.ID)
+		if err != nil || exit == nil {
+			return err
+		}
+		exit.PartialOrderID = orderID
+		exit.UpdatedAt = now
+		return u.stateRepo.SaveTradeExitState(ctx, *exit)
+	default:
+		return u.stateRepo.UpdateOrderBracketStatus(ctx, trade.ID, repo.OrderBracketTriggered)
+	}
+
+	exit, err := u.stateRepo.GetTradeExitState(ctx, trade.ID)
+	if err != nil {
 		return err
 	}
-	if err := u.stateRepo.UpdateDealDateTradeLog(ctx, trade.ID, time.Now().UTC()); err != nil {
+	if exit == nil {
+		selected, err := u.strategies.Get(u.strategy.Algorithm)
+		if err != nil {
+			return err
+		}
+		lotStep, err := swapLotStep(&symbol)
+		if err != nil {
+			return err
+		}
+		if err := u.stateRepo.SaveTradeExitState(ctx, repo.TradeExitState{
+			TradeID:         trade.ID,
+			StrategyVersion: selected.Version(),
+			StrategyKey:     strategy.Key(selected.Name(), selected.Version()),
+			Fee:             math.Max(parseDecimal(symbol.MakerCommission), parseDecimal(symbol.TakerCommission)),
+			LotStep:         lotStep,
+			UpdatedAt:       now,
+		}); err != nil {
+			return err
+		}
+	}
+	return u.stateRepo.SaveOrderBracket(ctx, newOrderBracket(u.strategy.Execution, trade, takeProfit, now))
+}
+
+// evaluateExit применяет к живой сделке выход стратегии той версии, по которой
+// она открыта, и сохраняет его состояние. Сделки без состояния — открытые до
+// exit-политики — получают только аварийные выходы. exit == nil, если
+// состояния нет.
+func (u *ExecutePalisadeSignals) evaluateExit(ctx context.Context, trade repo.TradeLog, bid float64, now time.Time) (strategy.ExitDecision, *repo.TradeExitState, error) {
+	if bid <= 0 {
+		return strategy.ExitDecision{}, nil, nil
+	}
+	openedAt := trade.OpenDate
+	if trade.DealDate != nil {
+		openedAt = *trade.DealDate
+	}
+	exit, err := u.stateRepo.GetTradeExitState(ctx, trade.ID)
+	if err != nil {
+		return strategy.ExitDecision{}, nil, err
+	}
+	if exit == nil {
+		return strategy.ExitDecision{Reason: emergencyReason(u.strategy.Execution, now, openedAt, bid, trade.DownLevel, trade.BuyPrice)}, nil, nil
+	}
+	progress, err := u.getSellProgress(ctx, trade.ID)
+	if err != nil {
+		return strategy.ExitDecision{}, nil, err
+	}
+	exitStrategy, err := u.strategies.Get(exit.StrategyKey)
+	if err != nil {
+		return strategy.ExitDecision{}, nil, err
+	}
+	state := strategy.ExitState{
+		BreakEvenArmed:     exit.BreakEvenArmed,
+		PartialProfitTaken: exit.PartialProfitTaken,
+		MaxBidPrice:        exit.MaxBidPrice,
+		MinBidPrice:        exit.MinBidPrice,
+	}
+	decision := exitStrategy.DecideExit(&state, strategy.Position{
+		BuyPrice:       trade.BuyPrice,
+		FilledQuantity: progress.planned,
+		SoldQuantity:   progress.executed,
+		SupportPrice:   trade.DownLevel,
+		TargetPrice:    trade.UpLevel,
+		OpenedAt:       openedAt,
+		Fee:            exit.Fee,
+		LotStep:        exit.LotStep,
+	}, now, bid)
+	exit.BreakEvenArmed = state.BreakEvenArmed
+	exit.MaxBidPrice = state.MaxBidPrice
+	exit.MinBidPrice = state.MinBidPrice
+	exit.UpdatedAt = now
+	if err := u.stateRepo.SaveTradeExitState(ctx, *exit); err != nil {
+		return strategy.ExitDecision{}, nil, err
+	}
+	return decision, exit, nil
+}
+
+// takePartialProfit снимает тейк-профит и продаёт долю быстрой прибыли;
+// остаток снова получит тейк-профит, когда доля исполнится. Доля отмечается
+// взятой до выставления: если SELL не пройдёт, позиция вернётся под
+// тейк-профит, а не будет дробиться повторно.
+func (u *ExecutePalisadeSignals) takePartialProfit(ctx context.Context, trade repo.TradeLog, current *mexc.QueryOrderResult, exit *repo.TradeExitState, decision strategy.ExitDecision) error {
+	exit.PartialProfitTaken = true
+	if err := u.stateRepo.SaveTradeExitState(ctx, *exit); err != nil {
 		return err
 	}
-	if err := u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "LINKED", result.OrderID, 0, 0, ""); err != nil {
+	if _, err := u.cancelAndRefreshSell(ctx, trade, current); err != nil {
+		return wrap.Errorf("cancel take-profit for partial profit %s: %w", trade.Symbol, err)
+	}
+	progress, err := u.getSellProgress(ctx, trade.ID)
+	if err != nil {
 		return err
 	}
-	fmt.Printf("SELL размещён: %s order=%s qty=%.8f\n", trade.Symbol, result.OrderID, quantity)
-	if reason != "" {
-		u.notify(fmt.Sprintf("<b>🚨 Emergency SELL</b> %s · <code>%s</code> · %.8f×%.8f · %s", trade.Symbol, result.OrderID, price, quantity, reason))
+	if progress.remaining() <= sellQuantityTolerance(progress.planned) {
+		return u.finishSell(ctx, trade)
+	}
+	quantity := math.Min(decision.Quantity, progress.remaining())
+	fmt.Printf("Быстрая прибыль %s: продаётся %.8f из %.8f\n", trade.Symbol, quantity, progress.remaining())
+	return u.placeSellForTradeAtPrice(ctx, trade, quantity, true, decision.Limit, strategy.ExitReasonPartial)
+}
+
+// newOrderBracket строит брекет с теми же порогами, что emergencyReason:
+// стоп — выше из пробоя поддержки и жёсткого убытка, срок — MaxPositionHold
+// от выставления тейк-профита (от него же считается DealDate).
+func newOrderBracket(cfg config.ExecutionStrategyConfig, trade repo.TradeLog, takeProfit float64, now time.Time) repo.OrderBracket {
+	stop := 0.0
+	if trade.DownLevel > 0 {
+		stop = trade.DownLevel * (1 - cfg.SupportBreak)
+	}
+	if trade.BuyPrice > 0 {
+		stop = math.Max(stop, trade.BuyPrice*(1-cfg.HardLoss))
+	}
+	bracket := repo.OrderBracket{
+		TradeID:         trade.ID,
+		Symbol:          trade.Symbol,
+		TakeProfitPrice: takeProfit,
+		StopPrice:       stop,
+		StopLimitPrice:  stop * (1 - cfg.StopLimitOffset),
+		Status:          repo.OrderBracketActive,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if cfg.MaxPositionHold > 0 {
+		expiresAt := now.Add(cfg.MaxPositionHold)
+		bracket.ExpiresAt = &expiresAt
+	}
+	return bracket
+}
+
+// CheckBrackets — клиентская стоп-нога OCO-брекетов: сверяет bid с
+// активными брекетами и при срабатывании снимает тейк-профит и продаёт
+// остаток позиции. watch-order-fills вызывает его между запусками
+// execute-palisade-signals, чтобы позиция не оставалась без стопа.
+// acquired=false — торговая блокировка занята другим процессом.
+func (u *ExecutePalisadeSignals) CheckBrackets(ctx context.Context, live bool) (acquired bool, err error) {
+	releaseLock, acquired, err := acquireTradingLock(ctx, u.stateRepo)
+	if err != nil || !acquired {
+		return acquired, err
+	}
+	defer releaseLock()
+
+	if paused, err := u.pausedByUnresolvedIntents(ctx); err != nil || paused {
+		return true, err
+	}
+	brackets, err := u.stateRepo.ListActiveOrderBrackets(ctx)
+	if err != nil || len(brackets) == 0 {
+		return true, err
+	}
+	openTrades, err := u.stateRepo.GetOpenOrders(ctx)
+	if err != nil {
+		return true, err
+	}
+	trades := make(map[int]repo.TradeLog, len(openTrades))
+	for _, trade := range openTrades {
+		trades[trade.ID] = trade
+	}
+	books, err := u.api.GetAllBookTickers(ctx)
+	if err != nil {
+		return true, wrap.Errorf("get book tickers for brackets: %w", err)
+	}
+	bids := make(map[string]float64, len(brackets))
+	for _, book := range *books {
+		if bid, _, err := parseBook(book); err == nil {
+			bids[book.Symbol] = bid
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, bracket := range brackets {
+		trade, ok := trades[bracket.TradeID]
+		if !ok {
+			// Сделка закрыта или отменена в обход брекета.
+			if err := u.stateRepo.UpdateOrderBracketStatus(ctx, bracket.TradeID, repo.OrderBracketClosed); err != nil {
+				return true, err
+			}
+			continue
+		}
+		bid := bids[bracket.Symbol]
+		reason := ""
+		if bracket.StopTriggered(bid) {
+			reason = "BRACKET_STOP"
+		} else {
+			// Трейлинг и быстрая прибыль exit-политики тоже работают между
+			// запусками: стоп брекета — только нижняя граница.
+			decision, exit, err := u.evaluateExit(ctx, trade, bid, now)
+			if err != nil {
+				return true, err
+			}
+			switch {
+			case decision.IsStop():
+				reason = decision.Reason
+			case decision.Reason == strategy.ExitReasonPartial && live:
+				if err := u.takePartialProfitNow(ctx, trade, exit, decision); err != nil {
+					return true, err
+				}
+				continue
+			case bracket.Expired(now):
+				reason = "MAX_HOLD_TIME"
+			default:
+				continue
+			}
+		}
+		fmt.Printf("Выход по брекету %s: %s, bid %.8f\n", trade.Symbol, reason, bid)
+		if !live {
+			continue
+		}
+		if err := u.triggerBracket(ctx, trade, bracket, reason); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// takePartialProfitNow — takePartialProfit по текущему SELL сделки, если
+// он ещё стоит в стакане.
+func (u *ExecutePalisadeSignals) takePartialProfitNow(ctx context.Context, trade repo.TradeLog, exit *repo.TradeExitState, decision strategy.ExitDecision) error {
+	current, err := u.api.GetOrderQuery(ctx, trade.Symbol, trade.OrderId_sell)
+	if err != nil {
+		return wrap.Errorf("query SELL %s/%s: %w", trade.Symbol, trade.OrderId_sell, err)
+	}
+	if current == nil || (current.Status != "NEW" && current.Status != "PARTIALLY_FILLED") {
+		return nil
+	}
+	return u.takePartialProfit(ctx, trade, current, exit, decision)
+}
+
+// triggerBracket отменяет тейк-профит и продаёт остаток: по стопу брекета —
+// лимитом StopLimitPrice, по остальным причинам — как аварийный выход со
+// сверкой спреда.
+// Если тейк-профит успел исполниться целиком, сделка просто закрывается.
+func (u *ExecutePalisadeSignals) triggerBracket(ctx context.Context, trade repo.TradeLog, bracket repo.OrderBracket, reason string) error {
+	current, err := u.api.GetOrderQuery(ctx, trade.Symbol, trade.OrderId_sell)
+	if err != nil {
+		return wrap.Errorf("query take-profit %s/%s: %w", trade.Symbol, trade.OrderId_sell, err)
+	}
+	if current == nil {
+		return wrap.Errorf("take-profit %s for %s is missing from exchange; manual recovery required", trade.OrderId_sell, trade.Symbol)
+	}
+	if current.Status == "NEW" || current.Status == "PARTIALLY_FILLED" {
+		if _, err := u.cancelAndRefreshSell(ctx, trade, current); err != nil {
+			return wrap.Errorf("cancel take-profit for bracket %s: %w", trade.Symbol, err)
+		}
 	} else {
-		u.notify(fmt.Sprintf("<b>📤 Signal SELL</b> %s · <code>%s</code> · %.8f×%.8f", trade.Symbol, result.OrderID, price, quantity))
+		executed, quote, err := orderFill(current)
+		if err != nil {
+			return err
+		}
+		if err := u.recordSellOrderState(ctx, trade.ID, current, executed, quote); err != nil {
+			return err
+		}
 	}
-	return nil
+	progress, err := u.getSellProgress(ctx, trade.ID)
+	if err != nil {
+		return err
+	}
+	if progress.remaining() <= sellQuantityTolerance(progress.planned) {
+		return u.finishSell(ctx, trade)
+	}
+	if reason != "BRACKET_STOP" {
+		return u.placeEmergencySell(ctx, trade, progress.remaining(), reason, true)
+	}
+	return u.placeSellForTradeAtPrice(ctx, trade, progress.remaining(), true, bracket.StopLimitPrice, "EMERGENCY: "+reason)
 }
 
 type sellProgress struct {
@@ -741,6 +1140,29 @@ func (u *ExecutePalisadeSignals) recordSellOrderState(
 	return wrap.Errorf("SELL intent for trade %d and order %s not found", tradeID, result.OrderID)
 }
 
+// recordOrderIntentState записывает статус и исполнение ордера в его order
+// intent любой стороны; в отличие от recordSellOrderState, отсутствие intent
+// не ошибка — у сделок, открытых до order intents, его нет.
+func (u *ExecutePalisadeSignals) recordOrderIntentState(
+	ctx context.Context,
+	tradeID int,
+	result *mexc.QueryOrderResult,
+	executed float64,
+	quote float64,
+) error {
+	intents, err := u.stateRepo.ListOrderIntentsByTradeID(ctx, tradeID)
+	if err != nil {
+		return err
+	}
+	for _, intent := range intents {
+		if intent.ExchangeOrderID != result.OrderID && intent.ClientOrderID != result.ClientOrderID {
+			continue
+		}
+		return u.stateRepo.UpdateOrderIntent(ctx, intent.ID, result.Status, result.OrderID, executed, quote, "")
+	}
+	return nil
+}
+
 type marketQuote struct {
 	bid, bidQty, ask, askQty float64
 }
@@ -777,17 +1199,17 @@ func (u *ExecutePalisadeSignals) emergencyExitReason(ctx context.Context, trade
 	if trade.DealDate != nil {
 		openedAt = *trade.DealDate
 	}
-	return emergencyReason(time.Now().UTC(), openedAt, quote.bid, trade.DownLevel, buyPrice), quote, nil
+	return emergencyReason(u.strategy.Execution, time.Now().UTC(), openedAt, quote.bid, trade.DownLevel, buyPrice), quote, nil
 }
 
-func emergencyReason(now, openedAt time.Time, bid, support, buyPrice float64) string {
-	if support > 0 && bid < support*(1-supportBreakPercent) {
+func emergencyReason(cfg config.ExecutionStrategyConfig, now, openedAt time.Time, bid, support, buyPrice float64) string {
+	if support > 0 && bid < support*(1-cfg.SupportBreak) {
 		return "SUPPORT_BROKEN"
 	}
-	if buyPrice > 0 && bid < buyPrice*(1-hardLossPercent) {
+	if buyPrice > 0 && bid < buyPrice*(1-cfg.HardLoss) {
 		return "MAX_LOSS"
 	}
-	if buyPrice > 0 && now.Sub(openedAt) > maxPositionHold {
+	if buyPrice > 0 && now.Sub(openedAt) > cfg.MaxPositionHold {
 		return "MAX_HOLD_TIME"
 	}
 	return ""
@@ -809,6 +1231,12 @@ func orderFill(orderInfo *mexc.QueryOrderResult) (executed, quote float64, err e
 }
 
 func newSignalClientOrderID(side order.Side) (string, error) {
+	return newClientOrderID("Signal_", side)
+}
+
+// newClientOrderID — prefix, сторона и случайный хвост: по префиксу
+// обработчики отчётов отличают ордера своей стратегии.
+func newClientOrderID(prefix string, side order.Side) (string, error) {
 	var entropy [10]byte
 	if _, err := rand.Read(entropy[:]); err != nil {
 		return "", wrap.Errorf("generate client order id: %w", err)
@@ -817,7 +1245,7 @@ func newSignalClientOrderID(side order.Side) (string, error) {
 	if side == order.SELL {
 		sideCode = "S"
 	}
-	return "Signal_" + sideCode + "_" + hex.EncodeToString(entropy[:]), nil
+	return prefix + sideCode + "_" + hex.EncodeToString(entropy[:]), nil
 }
 
 func signalPriceStep(symbol *mexc.SymbolDetail) float64 {
@@ -0,0 +1,440 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/adapter/webapi"
+	"github.com/drybin/palisade/internal/adapter/webapi/mexcsim"
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/model"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/service"
+	"github.com/go-resty/resty/v2"
+)
+
+// newSimWebapi собирает настоящий MexcWebapi поверх httptest-сервера симулятора.
+func newSimWebapi(t *testing.T, ex *mexcsim.Exchange) *webapi.MexcWebapi {
+	t.Helper()
+	cfg := newSimConfig(t, ex)
+	return newSimWebapiFromConfig(cfg, webapi.NewMexcSpotClient(cfg))
+}
+
+// newSimConfig поднимает REST-сервер симулятора с проверкой подписи.
+func newSimConfig(t *testing.T, ex *mexcsim.Exchange) config.MexcConfig {
+	t.Helper()
+	ex.RequireSignature("sim-key", "sim-secret")
+	server := mexcsim.NewServer(ex)
+	t.Cleanup(server.Close)
+	return config.MexcConfig{ApiKey: "sim-key", Secret: "sim-secret", BaseUrl: server.URL}
+}
+
+func newSimWebapiFromConfig(cfg config.MexcConfig, spot *webapi.MexcSpotClient) *webapi.MexcWebapi {
+	client := resty.New()
+	client.SetBaseURL(cfg.BaseUrl)
+	return webapi.NewMexcWebapi(client, spot, cfg)
+}
+
+func newSimSignalExchange() *mexcsim.Exchange {
+	ex := mexcsim.NewExchange()
+	ex.AddSymbol(mexc.SymbolDetail{
+		Symbol:               "AAAUSDT",
+		Status:               "1",
+		BaseAsset:            "AAA",
+		QuoteAsset:           "USDT",
+		QuotePrecision:       4,
+		OrderTypes:           []string{"LIMIT", "MARKET", "LIMIT_MAKER"},
+		IsSpotTradingAllowed: true,
+		QuoteAmountPrecision: "1",
+		MaxQuoteAmount:       "100000",
+		MakerCommission:      "0",
+		TakerCommission:      "0.001",
+		Filters:              []mexc.SymbolFilter{{FilterType: "LOT_SIZE", StepSize: "0.01"}},
+	})
+	ex.SetBalance("USDT", 100)
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 1.0, Qty: 500}}, []mexcsim.Level{{Price: 1.01, Qty: 500}})
+	return ex
+}
+
+// newFixedSignalSizer — прежний фиксированный объём сигнального входа.
+func newFixedSignalSizer() *service.PositionSizer {
+	return service.NewPositionSizer(nil, service.SizingRule{Method: service.SizingFixed, QuoteUSDT: testStrategy.Signals.OrderQuoteUSDT})
+}
+
+func newSimSignal() repo.PalisadeSignalState {
+	now := time.Now().UTC()
+	return repo.PalisadeSignalState{
+		Symbol:       "AAAUSDT",
+		SentAt:       now,
+		SupportPrice: 0.99,
+		EntryPrice:   1.0,
+		TargetPrice:  1.05,
+		MinExitPrice: 1.02,
+		Status:       "ACTIVE",
+		ValidUntil:   now.Add(time.Hour),
+	}
+}
+
+func TestExecutePalisadeSignals_simFullCycle(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	u := NewExecutePalisadeSignalsUsecase(newSimWebapi(t, ex), state, nil, nil, newFixedSignalSizer(), testStrategy)
+	ctx := context.Background()
+
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("open signal: %v", err)
+	}
+	buy := state.trade(1)
+	if buy.OrderId == "" || buy.Amount != 10 {
+		t.Fatalf("expected BUY 10 placed, got %+v", buy)
+	}
+
+	// Продавцы спускаются к нашей цене: BUY исполняется как maker.
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.999, Qty: 500}}, []mexcsim.Level{{Price: 1.0, Qty: 500}})
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("reconcile BUY: %v", err)
+	}
+	if state.trade(1).OrderId_sell == "" {
+		t.Fatalf("expected SELL to be placed after BUY fill")
+	}
+
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 1.05, Qty: 500}}, []mexcsim.Level{{Price: 1.06, Qty: 500}})
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("reconcile SELL: %v", err)
+	}
+	closed := state.trade(1)
+	if closed.CloseDate == nil || math.Abs(closed.SellPrice-1.05) > 1e-9 {
+		t.Fatalf("expected trade closed at 1.05, got %+v", closed)
+	}
+	usdt, locked := ex.Balance("USDT")
+	if math.Abs(usdt-100.5) > 1e-9 || locked != 0 {
+		t.Fatalf("unexpected final USDT free=%f locked=%f", usdt, locked)
+	}
+}
+
+func TestExecutePalisadeSignals_simPostOnlyRequeueAndFeeSaving(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	strategy := testStrategy
+	strategy.Execution.PostOnly = true
+	u := NewExecutePalisadeSignalsUsecase(newSimWebapi(t, ex), state, nil, nil, newFixedSignalSizer(), strategy)
+	ctx := context.Background()
+
+	// ask на цене входа: LIMIT_MAKER по 1.0 исполнился бы сразу и
+	// переставляется на шаг ниже.
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.999, Qty: 500}}, []mexcsim.Level{{Price: 1.0, Qty: 500}})
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("open signal: %v", err)
+	}
+	if rejected := state.intent(1); rejected.Status != "REJECTED" || rejected.OrderType != "LIMIT_MAKER" {
+		t.Fatalf("expected crossing post-only BUY rejected, got %+v", rejected)
+	}
+	requeued := state.intent(2)
+	if requeued.Status != "LINKED" || requeued.OrderType != "LIMIT_MAKER" || math.Abs(requeued.Price-0.9999) > 1e-9 {
+		t.Fatalf("expected post-only BUY requeued at 0.9999, got %+v", requeued)
+	}
+	if buy := state.trade(1); math.Abs(buy.BuyPrice-0.9999) > 1e-9 {
+		t.Fatalf("trade must keep the requeued price, got %+v", buy)
+	}
+
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.998, Qty: 500}}, []mexcsim.Level{{Price: 0.9999, Qty: 500}})
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("reconcile BUY: %v", err)
+	}
+	takeProfit := state.intent(3)
+	if takeProfit.Side != "SELL" || takeProfit.OrderType != "LIMIT_MAKER" || math.Abs(takeProfit.Price-1.05) > 1e-9 {
+		t.Fatalf("expected post-only take-profit at 1.05, got %+v", takeProfit)
+	}
+	savings, err := state.GetMakerFeeSavings(ctx)
+	if err != nil {
+		t.Fatalf("fee savings: %v", err)
+	}
+	if want := 10 * 0.9999 * 0.001; savings.Orders != 1 || math.Abs(savings.FeeSavedUSDT-want) > 1e-9 {
+		t.Fatalf("expected one filled maker order saving %.8f, got %+v", want, savings)
+	}
+}
+
+func TestExecutePalisadeSignals_simBracketStopReplacesTakeProfit(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	u := NewExecutePalisadeSignalsUsecase(newSimWebapi(t, ex), state, nil, nil, newFixedSignalSizer(), testStrategy)
+	ctx := context.Background()
+
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("open signal: %v", err)
+	}
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.999, Qty: 500}}, []mexcsim.Level{{Price: 1.0, Qty: 500}})
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("reconcile BUY: %v", err)
+	}
+	takeProfit := state.trade(1).OrderId_sell
+	bracket := state.bracket(1)
+	if takeProfit == "" || bracket.Status != repo.OrderBracketActive || math.Abs(bracket.TakeProfitPrice-1.05) > 1e-9 {
+		t.Fatalf("expected take-profit with active bracket, got %+v", bracket)
+	}
+	// Стоп — пробой поддержки 1.0 на SupportBreak, он выше жёсткого убытка.
+	if math.Abs(bracket.StopPrice-0.997) > 1e-9 || bracket.StopLimitPrice >= bracket.StopPrice {
+		t.Fatalf("unexpected stop leg %+v", bracket)
+	}
+
+	// Выше стопа брекет не трогает тейк-профит.
+	if _, err := u.CheckBrackets(ctx, true); err != nil {
+		t.Fatalf("check brackets: %v", err)
+	}
+	if state.trade(1).OrderId_sell != takeProfit {
+		t.Fatalf("take-profit must stay while bid is above the stop")
+	}
+
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.996, Qty: 500}}, []mexcsim.Level{{Price: 0.998, Qty: 500}})
+	if _, err := u.CheckBrackets(ctx, true); err != nil {
+		t.Fatalf("trigger bracket: %v", err)
+	}
+	if state.trade(1).OrderId_sell == takeProfit {
+		t.Fatalf("stop must replace the take-profit SELL")
+	}
+	if state.bracket(1).Status != repo.OrderBracketTriggered {
+		t.Fatalf("expected TRIGGERED bracket, got %+v", state.bracket(1))
+	}
+	for _, open := range ex.OpenOrders("AAAUSDT") {
+		if open.OrderID == takeProfit {
+			t.Fatalf("take-profit must be canceled on the exchange")
+		}
+	}
+
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("reconcile stop SELL: %v", err)
+	}
+	closed := state.trade(1)
+	if closed.CloseDate == nil || math.Abs(closed.SellPrice-0.996) > 1e-9 {
+		t.Fatalf("expected trade closed by stop at 0.996, got %+v", closed)
+	}
+	if state.bracket(1).Status != repo.OrderBracketClosed {
+		t.Fatalf("expected CLOSED bracket, got %+v", state.bracket(1))
+	}
+}
+
+// renamedStrategy — палисада под другим именем: стратегия, на которую
+// переключили конфиг, пока сделка открыта.
+type renamedStrategy struct {
+	palisadeStrategy
+}
+
+func (renamedStrategy) Name() string {
+	return "renamed"
+}
+
+func TestExecutePalisadeSignals_simExitUsesStoredStrategyKey(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	u := NewExecutePalisadeSignalsUsecase(newSimWebapi(t, ex), state, nil, nil, newFixedSignalSizer(), testStrategy)
+	ctx := context.Background()
+
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("open signal: %v", err)
+	}
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.999, Qty: 500}}, []mexcsim.Level{{Price: 1.0, Qty: 500}})
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("reconcile BUY: %v", err)
+	}
+
+	// Конфиг переключили на другую стратегию: открытая сделка доводится
+	// палисадой v8, по которой открыта.
+	if err := u.strategies.Register(renamedStrategy{palisadeStrategy{cfg: testStrategy, version: 1}}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	u.strategy.Algorithm = "renamed/v1"
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 1.005, Qty: 500}}, []mexcsim.Level{{Price: 1.006, Qty: 500}})
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("partial profit after strategy switch: %v", err)
+	}
+	if exit := state.exitState(1); exit.StrategyKey != "palisade/v8" || !exit.PartialProfitTaken {
+		t.Fatalf("expected palisade/v8 partial profit, got %+v", exit)
+	}
+}
+
+func TestExecutePalisadeSignals_simPartialProfitAndTrailingStop(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	u := NewExecutePalisadeSignalsUsecase(newSimWebapi(t, ex), state, nil, nil, newFixedSignalSizer(), testStrategy)
+	ctx := context.Background()
+
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("open signal: %v", err)
+	}
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.999, Qty: 500}}, []mexcsim.Level{{Price: 1.0, Qty: 500}})
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("reconcile BUY: %v", err)
+	}
+	if exit := state.exitState(1); exit.StrategyVersion != paperStrategyVersion || exit.StrategyKey != "palisade/v8" || exit.LotStep != 0.01 {
+		t.Fatalf("expected exit state with paper strategy key, got %+v", exit)
+	}
+
+	// Bid выше цены быстрой прибыли: половина позиции продаётся, остаток
+	// возвращается под тейк-профит.
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 1.005, Qty: 500}}, []mexcsim.Level{{Price: 1.006, Qty: 500}})
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("partial profit: %v", err)
+	}
+	if exit := state.exitState(1); !exit.PartialProfitTaken || exit.PartialOrderID != state.trade(1).OrderId_sell {
+		t.Fatalf("expected partial SELL recorded in exit state, got %+v", exit)
+	}
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("take-profit for remainder: %v", err)
+	}
+	orders := ex.OpenOrders("AAAUSDT")
+	if len(orders) != 1 || math.Abs(orders[0].OrigQty-5) > 1e-9 {
+		t.Fatalf("expected take-profit for the remaining 5, got %+v", orders)
+	}
+
+	// Рост взводит трейлинг, откат на TrailingDistance от максимума закрывает
+	// позицию между запусками.
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 1.02, Qty: 500}}, []mexcsim.Level{{Price: 1.021, Qty: 500}})
+	if _, err := u.CheckBrackets(ctx, true); err != nil {
+		t.Fatalf("arm trailing: %v", err)
+	}
+	if exit := state.exitState(1); !exit.BreakEvenArmed || exit.MaxBidPrice != 1.02 {
+		t.Fatalf("expected armed trailing at 1.02, got %+v", exit)
+	}
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 1.015, Qty: 500}}, []mexcsim.Level{{Price: 1.016, Qty: 500}})
+	if _, err := u.CheckBrackets(ctx, true); err != nil {
+		t.Fatalf("trailing stop: %v", err)
+	}
+	if err := u.Process(ctx, true); err != nil {
+		t.Fatalf("reconcile trailing SELL: %v", err)
+	}
+	closed := state.trade(1)
+	if closed.CloseDate == nil || math.Abs(closed.SellPrice-1.01) > 1e-9 {
+		t.Fatalf("expected trade closed at average 1.01, got %+v", closed)
+	}
+}
+
+func TestExecutePalisadeSignals_simRiskLimitBlocksEntry(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	// Вход на 10 USDT больше лимита экспозиции.
+	risk := service.NewRiskManager(state, nil, service.RiskLimits{MaxTotalExposureUSDT: 5})
+	u := NewExecutePalisadeSignalsUsecase(newSimWebapi(t, ex), state, nil, risk, newFixedSignalSizer(), testStrategy)
+
+	if err := u.Process(context.Background(), true); err != nil {
+		t.Fatalf("blocked entry must not fail the run: %v", err)
+	}
+	if orders := ex.OpenOrders("AAAUSDT"); len(orders) != 0 {
+		t.Fatalf("expected no BUY placed, got %+v", orders)
+	}
+	if len(state.intents) != 0 || len(state.trades) != 0 {
+		t.Fatalf("expected no intent and no trade, got %+v %+v", state.intents, state.trades)
+	}
+}
+
+// rejectingExchange отклоняет каждый новый ордер ошибкой биржи.
+type rejectingExchange struct {
+	repo.IMexcRepository
+	err error
+}
+
+func (r rejectingExchange) NewOrder(context.Context, model.OrderParams) (*mexc.PlaceOrderResult, error) {
+	return nil, r.err
+}
+
+func TestExecutePalisadeSignals_simRejectedBuyDoesNotPauseTrading(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	api := rejectingExchange{
+		IMexcRepository: newSimWebapi(t, ex),
+		err:             &mexc.APIError{HTTPStatus: 400, Code: mexc.ErrCodeInsufficientBalance, Msg: "Insufficient balance", Endpoint: "POST /api/v3/order"},
+	}
+	u := NewExecutePalisadeSignalsUsecase(api, state, nil, nil, newFixedSignalSizer(), testStrategy)
+	ctx := context.Background()
+
+	if err := u.Process(ctx, true); !mexc.IsInsufficientBalance(err) {
+		t.Fatalf("expected typed rejection, got %v", err)
+	}
+	if len(state.intents) != 1 || state.intents[0].Status != "REJECTED" {
+		t.Fatalf("expected REJECTED intent, got %+v", state.intents)
+	}
+	paused, err := u.pausedByUnresolvedIntents(ctx)
+	if err != nil || paused {
+		t.Fatalf("rejected order must not pause trading, paused=%v err=%v", paused, err)
+	}
+}
+
+// lostResponseExchange выставляет ордер, но теряет ответ, как если бы не
+// помог и поиск по clientOrderId в webapi.
+type lostResponseExchange struct {
+	repo.IMexcRepository
+}
+
+func (l lostResponseExchange) NewOrder(ctx context.Context, params model.OrderParams) (*mexc.PlaceOrderResult, error) {
+	if _, err := l.IMexcRepository.NewOrder(ctx, params); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("read: connection reset by peer")
+}
+
+func TestExecutePalisadeSignals_simLostBuyResponseFoundByClientID(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	ctx := context.Background()
+
+	ex.DropNextOrderResponse()
+	if err := NewExecutePalisadeSignalsUsecase(newSimWebapi(t, ex), state, nil, nil, newFixedSignalSizer(), testStrategy).Process(ctx, true); err != nil {
+		t.Fatalf("lost response must be recovered by client order id: %v", err)
+	}
+	open := ex.OpenOrders("AAAUSDT")
+	if len(open) != 1 {
+		t.Fatalf("expected exactly one BUY on the exchange, got %+v", open)
+	}
+	if trade := state.trade(1); trade.OrderId != open[0].OrderID {
+		t.Fatalf("trade must point at the exchange order, got %+v", trade)
+	}
+	if intents, _ := state.ListRecoverableOrderIntents(ctx); len(intents) != 0 {
+		t.Fatalf("expected no intents left for reconcile, got %+v", intents)
+	}
+}
+
+func TestReconcileOrders_simRecoversLostBuyResponse(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	api := newSimWebapi(t, ex)
+	ctx := context.Background()
+
+	if err := NewExecutePalisadeSignalsUsecase(lostResponseExchange{api}, state, nil, nil, newFixedSignalSizer(), testStrategy).Process(ctx, true); err == nil {
+		t.Fatalf("expected lost order response to surface as error")
+	}
+	intents, _ := state.ListRecoverableOrderIntents(ctx)
+	if len(intents) != 1 || intents[0].Status != "UNKNOWN" {
+		t.Fatalf("expected one UNKNOWN intent, got %+v", intents)
+	}
+	if len(ex.OpenOrders("AAAUSDT")) != 1 {
+		t.Fatalf("exchange must have accepted the order despite the lost response")
+	}
+
+	if err := NewReconcileOrdersUsecase(api, state, nil).Process(ctx); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	intents, _ = state.ListRecoverableOrderIntents(ctx)
+	if len(intents) != 0 {
+		t.Fatalf("expected all intents resolved, got %+v", intents)
+	}
+	trade := state.trade(1)
+	if trade.OrderId != ex.OpenOrders("AAAUSDT")[0].OrderID {
+		t.Fatalf("recreated trade must point at the exchange order, got %+v", trade)
+	}
+	if !strings.HasPrefix(state.intents[0].ClientOrderID, "Signal_B_") || state.intents[0].Status != "LINKED" {
+		t.Fatalf("unexpected intent after recovery: %+v", state.intents[0])
+	}
+}
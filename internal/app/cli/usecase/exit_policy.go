@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"math"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/strategy"
+)
+
+// decideExit — выход версии version палисады: продвигает state на bid и
+// решает, что продавать. Стопы (трейлинг, безубыток, аварийные) важнее доли
+// быстрой прибыли, та — цели. Политика общая для paper-trade,
+// execute-palisade-signals и бэктеста, поэтому бумажные результаты одной
+// версии стратегии предсказывают живые.
+func decideExit(cfg config.StrategyConfig, version int, state *strategy.ExitState, pos strategy.Position, now time.Time, bid float64) strategy.ExitDecision {
+	fee, lotStep := pos.Fee, pos.LotStep
+	trackExitExcursion(state, bid)
+	armExitTrailing(cfg, version, state, bid, pos.BuyPrice, pos.TargetPrice, fee)
+
+	reason := exitStopReason(cfg, version, *state, now, pos.OpenedAt, bid, pos.SupportPrice, pos.BuyPrice, fee)
+	if reason == "" {
+		reason = pos.PendingReason
+	}
+	partialTarget := exitPartialQuantity(cfg.Paper, pos.FilledQuantity, lotStep)
+	quickProfitBid := paperQuickProfitBidPrice(cfg.Paper, pos.BuyPrice, fee)
+	partialPending := version >= 8 && !state.PartialProfitTaken &&
+		!paperQuantityReached(pos.SoldQuantity, partialTarget, lotStep) && bid >= quickProfitBid
+	if reason == "" && partialPending {
+		reason = strategy.ExitReasonPartial
+	}
+	if reason == "" && bid >= pos.TargetPrice {
+		reason = strategy.ExitReasonTarget
+	}
+	if reason == "" {
+		return strategy.ExitDecision{PartialTarget: partialTarget}
+	}
+
+	decision := strategy.ExitDecision{
+		Reason:        reason,
+		Quantity:      pos.FilledQuantity - pos.SoldQuantity,
+		Limit:         bid * (1 - cfg.Execution.EmergencyPriceDiscount),
+		PartialTarget: partialTarget,
+	}
+	switch reason {
+	case strategy.ExitReasonTarget:
+		decision.Limit = math.Min(bid, pos.TargetPrice)
+	case strategy.ExitReasonPartial:
+		decision.Quantity = partialTarget - pos.SoldQuantity
+		decision.Limit = math.Min(bid, quickProfitBid)
+	}
+	return decision
+}
+
+func trackExitExcursion(state *strategy.ExitState, bid float64) {
+	if bid <= 0 {
+		return
+	}
+	if state.MaxBidPrice <= 0 || bid > state.MaxBidPrice {
+		state.MaxBidPrice = bid
+	}
+	if state.MinBidPrice <= 0 || bid < state.MinBidPrice {
+		state.MinBidPrice = bid
+	}
+}
+
+// armExitTrailing взводит трейлинг (v7+) или стоп в безубыток (v4–v6).
+func armExitTrailing(cfg config.StrategyConfig, version int, state *strategy.ExitState, bid, buyPrice, targetPrice, fee float64) {
+	if state.BreakEvenArmed {
+		return
+	}
+	switch {
+	case version >= 8:
+		state.BreakEvenArmed = bid >= paperTrailingActivationPrice(cfg, buyPrice, fee)
+	case version == 7:
+		state.BreakEvenArmed = bid >= paperV7TrailingActivationPrice(cfg, buyPrice, fee)
+	case version >= 4:
+		state.BreakEvenArmed = bid >= paperBreakEvenTrigger(cfg, buyPrice, targetPrice, fee)
+	}
+}
+
+func exitStopReason(cfg config.StrategyConfig, version int, state strategy.ExitState, now, openedAt time.Time, bid, support, buyPrice, fee float64) string {
+	if version >= 7 && state.BreakEvenArmed && bid <= exitTrailingStopPrice(cfg, state, buyPrice, fee) {
+		return "TRAILING_STOP"
+	}
+	if version >= 4 && state.BreakEvenArmed && bid <= paperBreakEvenBidPrice(cfg.Execution, buyPrice, fee) {
+		return "BREAKEVEN_STOP"
+	}
+	return emergencyReason(cfg.Execution, now, openedAt, bid, support, buyPrice)
+}
+
+func exitTrailingStopPrice(cfg config.StrategyConfig, state strategy.ExitState, buyPrice, fee float64) float64 {
+	trailing := state.MaxBidPrice * (1 - cfg.Paper.TrailingDistance)
+	return math.Max(trailing, paperPositiveStopBidPrice(cfg, buyPrice, fee))
+}
+
+func exitPartialQuantity(cfg config.PaperStrategyConfig, filledQuantity, lotStep float64) float64 {
+	return swapRoundQtyDown(filledQuantity*cfg.QuickProfitShare, lotStep)
+}
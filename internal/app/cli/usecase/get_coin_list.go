@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/domain/repo"
 	"github.com/drybin/palisade/pkg/wrap"
 )
@@ -15,12 +14,12 @@ type IGetCoinList interface {
 }
 
 type GetCoinList struct {
-	repo      *webapi.MexcWebapi
+	repo      repo.IMexcRepository
 	stateRepo repo.IStateRepository
 }
 
 func NewGetCoinListUsecase(
-	repo *webapi.MexcWebapi,
+	repo repo.IMexcRepository,
 	stateRepo repo.IStateRepository,
 ) *GetCoinList {
 	return &GetCoinList{
@@ -73,7 +72,6 @@ func (u *GetCoinList) Process(ctx context.Context, debug bool) error {
 
 		newCoinsCount++
 		// fmt.Println("ok")
-		time.Sleep(2 * time.Second)
 	}
 
 	elapsed := time.Since(startTime)
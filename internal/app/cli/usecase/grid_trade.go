@@ -0,0 +1,532 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/drybin/palisade/internal/adapter/webapi"
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/model"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/service"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+// gridClientOrderPrefix отличает ордера сетки от ордеров сигналов.
+const gridClientOrderPrefix = "Grid_"
+
+type GridTradeOptions struct {
+	// Symbols — монеты, на которых запускается новая сетка; без них
+	// grid-trade только сопровождает уже работающие сетки.
+	Symbols []string
+	Live    bool
+}
+
+type IGridTrade interface {
+	Process(context.Context, GridTradeOptions) error
+}
+
+// GridTrade ведёт сетки ордеров внутри диапазона палисады: покупки
+// лесенкой от поддержки до середины диапазона, над каждой — парная продажа
+// на полдиапазона выше. Исполненный BUY уровня сменяется его SELL, SELL —
+// снова BUY. Сетка останавливается, когда монета перестаёт быть палисадой
+// или цена выходит из диапазона.
+type GridTrade struct {
+	api       repo.IMexcRepository
+	stateRepo repo.IStateRepository
+	telegram  *webapi.TelegramWebapi
+	risk      *service.RiskManager
+	cfg       config.GridStrategyConfig
+}
+
+func NewGridTradeUsecase(
+	api repo.IMexcRepository,
+	stateRepo repo.IStateRepository,
+	telegram *webapi.TelegramWebapi,
+	risk *service.RiskManager,
+	cfg config.GridStrategyConfig,
+) *GridTrade {
+	return &GridTrade{api: api, stateRepo: stateRepo, telegram: telegram, risk: risk, cfg: cfg}
+}
+
+// Process сопровождает активные сетки, затем запускает новые по
+// opts.Symbols. Без opts.Live ордера не размещаются и не снимаются, а
+// новые сетки только рассчитываются. Ошибка по одной сетке не
+// останавливает остальные и возвращается в конце.
+func (u *GridTrade) Process(ctx context.Context, opts GridTradeOptions) error {
+	releaseLock, acquired, err := acquireTradingLock(ctx, u.stateRepo)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return nil
+	}
+	defer releaseLock()
+
+	if !opts.Live {
+		fmt.Println("Режим просмотра: ордера не размещаются. Для торговли нужен флаг --live")
+	}
+
+	grids, err := u.stateRepo.ListActiveGrids(ctx)
+	if err != nil {
+		return err
+	}
+	books, err := u.api.GetAllBookTickers(ctx)
+	if err != nil {
+		return wrap.Errorf("get book tickers: %w", err)
+	}
+	bookBySymbol := make(map[string]mexc.BookTicker, len(*books))
+	for _, book := range *books {
+		bookBySymbol[book.Symbol] = book
+	}
+
+	var firstErr error
+	active := map[string]bool{}
+	for _, grid := range grids {
+		stopped, err := u.maintainGrid(ctx, grid, bookBySymbol[grid.Symbol], opts.Live)
+		if err != nil {
+			fmt.Printf("grid-trade: %s: %v\n", grid.Symbol, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if !stopped {
+			active[grid.Symbol] = true
+		}
+	}
+
+	for _, symbol := range opts.Symbols {
+		if active[symbol] {
+			fmt.Printf("%s: сетка уже работает\n", symbol)
+			continue
+		}
+		if len(active) >= u.cfg.MaxActiveGrids {
+			fmt.Printf("Активных сеток %d, новые не запускаются\n", len(active))
+			break
+		}
+		started, err := u.startGrid(ctx, symbol, bookBySymbol[symbol], opts.Live)
+		if err != nil {
+			fmt.Printf("grid-trade: %s: %v\n", symbol, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if started {
+			active[symbol] = true
+		}
+	}
+	return firstErr
+}
+
+func (u *GridTrade) startGrid(ctx context.Context, symbol string, book mexc.BookTicker, live bool) (bool, error) {
+	coin, err := u.stateRepo.GetCoinInfo(ctx, symbol)
+	if err != nil {
+		return false, err
+	}
+	if coin == nil || !coin.IsPalisade || coin.Support <= 0 || coin.Resistance <= coin.Support {
+		fmt.Printf("%s: монета не палисада, сетка не запускается\n", symbol)
+		return false, nil
+	}
+	bid, ask, err := parseBook(book)
+	if err != nil || bid <= 0 {
+		return false, wrap.Errorf("book ticker for %s not found", symbol)
+	}
+	if bid < coin.Support || ask > coin.Resistance {
+		fmt.Printf("%s: цена вне диапазона %.8f…%.8f, bid=%.8f ask=%.8f\n", symbol, coin.Support, coin.Resistance, bid, ask)
+		return false, nil
+	}
+
+	info, err := u.api.GetSymbolInfo(ctx, symbol)
+	if err != nil {
+		return false, wrap.Errorf("get symbol info %s: %w", symbol, err)
+	}
+	detail := findSymbolDetailManual(info, symbol)
+	if detail == nil {
+		return false, wrap.Errorf("symbol %s not found in exchange info", symbol)
+	}
+	levels, err := planGridLevels(u.cfg, *detail, coin.Support, coin.Resistance)
+	if err != nil {
+		fmt.Printf("%s: сетка не запускается: %v\n", symbol, err)
+		return false, nil
+	}
+	fmt.Printf("Сетка %s: диапазон %.8f…%.8f, уровней %d\n", symbol, coin.Support, coin.Resistance, len(levels))
+	for _, level := range levels {
+		fmt.Printf("  #%d BUY %.8f × %.8f → SELL %.8f\n", level.Level, level.BuyPrice, level.Quantity, level.SellPrice)
+	}
+	if !live {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	grid, err := u.stateRepo.CreateGrid(ctx, repo.Grid{
+		Symbol:          symbol,
+		SupportPrice:    coin.Support,
+		ResistancePrice: coin.Resistance,
+		Levels:          len(levels),
+		QuotePerLevel:   u.cfg.QuotePerLevelUSDT,
+		Status:          repo.GridActive,
+		CreatedAt:       now,
+	})
+	if err != nil {
+		return false, err
+	}
+	for i := range levels {
+		levels[i].GridID = grid.ID
+		levels[i].UpdatedAt = now
+		if err := u.stateRepo.SaveGridLevel(ctx, levels[i]); err != nil {
+			return true, err
+		}
+	}
+	u.notify(fmt.Sprintf("<b>🕸 Grid started</b> %s · %.8f…%.8f · %d levels × %.2f USDT", symbol, coin.Support, coin.Resistance, len(levels), u.cfg.QuotePerLevelUSDT))
+	for i := range levels {
+		if err := u.advanceLevel(ctx, *grid, *detail, &levels[i], bid); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// planGridLevels раскладывает cfg.Levels покупок с равным шагом от support
+// до середины диапазона; продажа уровня — на полдиапазона выше покупки, то
+// есть между серединой и resistance.
+func planGridLevels(cfg config.GridStrategyConfig, symbol mexc.SymbolDetail, support, resistance float64) ([]repo.GridLevel, error) {
+	lotStep, err := swapLotStep(&symbol)
+	if err != nil {
+		return nil, err
+	}
+	priceStep := signalPriceStep(&symbol)
+	fee := math.Max(parseDecimal(symbol.MakerCommission), parseDecimal(symbol.TakerCommission))
+	half := (resistance - support) / 2
+	spacing := half / float64(cfg.Levels)
+
+	levels := make([]repo.GridLevel, 0, cfg.Levels)
+	for i := 0; i < cfg.Levels; i++ {
+		buy := roundPriceDown(support+spacing*float64(i), priceStep)
+		sell := roundPriceDown(support+spacing*float64(i)+half, priceStep)
+		if len(levels) > 0 && buy <= levels[len(levels)-1].BuyPrice {
+			return nil, wrap.Errorf("range is too narrow for %d levels at price step %g", cfg.Levels, priceStep)
+		}
+		if profit := sell/buy - 1 - 2*fee; profit < cfg.MinLevelProfit {
+			return nil, wrap.Errorf("level %d profit %.4f is below %.4f", i, profit, cfg.MinLevelProfit)
+		}
+		quantity := swapRoundQtyDown(cfg.QuotePerLevelUSDT/buy, lotStep)
+		if err := validateLimitOrder(symbol, order.BUY, buy, quantity); err != nil {
+			return nil, wrap.Errorf("level %d BUY: %w", i, err)
+		}
+		levels = append(levels, repo.GridLevel{
+			Level:     i,
+			BuyPrice:  buy,
+			SellPrice: sell,
+			Quantity:  quantity,
+			Status:    repo.GridLevelWaiting,
+		})
+	}
+	return levels, nil
+}
+
+// maintainGrid продвигает уровни сетки или останавливает её; stopped —
+// сетка больше не активна.
+func (u *GridTrade) maintainGrid(ctx context.Context, grid repo.Grid, book mexc.BookTicker, live bool) (stopped bool, err error) {
+	coin, err := u.stateRepo.GetCoinInfo(ctx, grid.Symbol)
+	if err != nil {
+		return false, err
+	}
+	bid, ask, err := parseBook(book)
+	if err != nil || bid <= 0 {
+		return false, wrap.Errorf("book ticker for %s not found", grid.Symbol)
+	}
+	levels, err := u.stateRepo.ListGridLevels(ctx, grid.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if reason := gridStopReason(u.cfg, grid, coin, bid, ask); reason != "" {
+		fmt.Printf("Сетка %s останавливается: %s, bid=%.8f ask=%.8f\n", grid.Symbol, reason, bid, ask)
+		if !live {
+			return false, nil
+		}
+		return true, u.stopGrid(ctx, grid, levels, reason)
+	}
+	if !live {
+		for _, level := range levels {
+			fmt.Printf("  %s #%d %s, на руках %.8f, циклов %d\n", grid.Symbol, level.Level, level.Status, level.HeldQuantity, level.Cycles)
+		}
+		return false, nil
+	}
+
+	info, err := u.api.GetSymbolInfo(ctx, grid.Symbol)
+	if err != nil {
+		return false, wrap.Errorf("get symbol info %s: %w", grid.Symbol, err)
+	}
+	detail := findSymbolDetailManual(info, grid.Symbol)
+	if detail == nil {
+		return false, wrap.Errorf("symbol %s not found in exchange info", grid.Symbol)
+	}
+	for i := range levels {
+		if err := u.advanceLevel(ctx, grid, *detail, &levels[i], bid); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// gridStopReason — почему сетку пора остановить; пустая строка — диапазон
+// держится.
+func gridStopReason(cfg config.GridStrategyConfig, grid repo.Grid, coin *mexc.SymbolDetail, bid, ask float64) string {
+	if coin == nil || !coin.IsPalisade {
+		return "NOT_PALISADE"
+	}
+	if bid < grid.SupportPrice*(1-cfg.RangeBreak) {
+		return "SUPPORT_BROKEN"
+	}
+	if ask > grid.ResistancePrice*(1+cfg.RangeBreak) {
+		return "RESISTANCE_BROKEN"
+	}
+	return ""
+}
+
+// advanceLevel сверяет ордер уровня с биржей и, если уровень свободен,
+// выставляет следующий: SELL купленного или BUY, если продавать нечего.
+// BUY выше bid не выставляется — он исполнился бы сразу по
+// рынку; уровень ждёт, пока цена поднимется.
+func (u *GridTrade) advanceLevel(ctx context.Context, grid repo.Grid, symbol mexc.SymbolDetail, level *repo.GridLevel, bid float64) error {
+	if err := u.resolvePlacingLevel(ctx, grid, level); err != nil {
+		return err
+	}
+	if level.Status == repo.GridLevelBuyOpen || level.Status == repo.GridLevelSellOpen {
+		result, err := u.api.GetOrderQuery(ctx, grid.Symbol, level.OrderID)
+		if err != nil {
+			return wrap.Errorf("query grid order %s: %w", level.OrderID, err)
+		}
+		if result == nil {
+			return wrap.Errorf("grid order %s is missing", level.OrderID)
+		}
+		if result.Status == "NEW" || result.Status == "PARTIALLY_FILLED" {
+			return nil
+		}
+		if err := u.closeLevelOrder(ctx, grid, level, result, repo.GridLevelWaiting); err != nil {
+			return err
+		}
+	}
+	if level.Status != repo.GridLevelWaiting {
+		return nil
+	}
+
+	lotStep, err := swapLotStep(&symbol)
+	if err != nil {
+		return err
+	}
+	// Остаток меньше лота не продаётся: он уйдёт со следующим SELL уровня.
+	sellQuantity := swapRoundQtyDown(level.HeldQuantity, lotStep)
+	if sellQuantity > 0 && validateLimitOrder(symbol, order.SELL, level.SellPrice, sellQuantity) == nil {
+		return u.placeLevelOrder(ctx, grid, level, order.SELL, sellQuantity)
+	}
+	if level.BuyPrice >= bid {
+		return nil
+	}
+	if blocked, err := entryBlockedByRisk(ctx, u.risk, grid.Symbol, level.BuyPrice*level.Quantity); err != nil || blocked {
+		return err
+	}
+	return u.placeLevelOrder(ctx, grid, level, order.BUY, level.Quantity)
+}
+
+// resolvePlacingLevel разбирает уровень, ордер которого отправлялся, когда
+// процесс упал: ордер ищется по ClientOrderID, а если биржа его не знает,
+// уровень снова свободен.
+func (u *GridTrade) resolvePlacingLevel(ctx context.Context, grid repo.Grid, level *repo.GridLevel) error {
+	var open string
+	switch level.Status {
+	case repo.GridLevelBuyPlacing:
+		open = repo.GridLevelBuyOpen
+	case repo.GridLevelSellPlacing:
+		open = repo.GridLevelSellOpen
+	default:
+		return nil
+	}
+	result, err := u.api.GetOrderQueryByClientID(ctx, grid.Symbol, level.ClientOrderID)
+	if err != nil {
+		return wrap.Errorf("query grid order %s: %w", level.ClientOrderID, err)
+	}
+	if result == nil {
+		level.Status = repo.GridLevelWaiting
+		level.ClientOrderID = ""
+	} else {
+		level.Status = open
+		level.OrderID = result.OrderID
+	}
+	return u.saveLevel(ctx, level)
+}
+
+func (u *GridTrade) placeLevelOrder(ctx context.Context, grid repo.Grid, level *repo.GridLevel, side order.Side, quantity float64) error {
+	price, placing, open := level.BuyPrice, repo.GridLevelBuyPlacing, repo.GridLevelBuyOpen
+	if side == order.SELL {
+		price, placing, open = level.SellPrice, repo.GridLevelSellPlacing, repo.GridLevelSellOpen
+	}
+	clientID, err := newClientOrderID(gridClientOrderPrefix, side)
+	if err != nil {
+		return err
+	}
+	level.Status = placing
+	level.OrderID = ""
+	level.ClientOrderID = clientID
+	if err := u.saveLevel(ctx, level); err != nil {
+		return err
+	}
+
+	result, err := u.api.NewOrder(ctx, model.OrderParams{
+		Symbol:           grid.Symbol,
+		Side:             side,
+		OrderType:        order.LIMIT,
+		Quantity:         quantity,
+		Price:            price,
+		NewClientOrderId: clientID,
+	})
+	if err != nil {
+		// Отказ биржи — ордера точно нет; иначе уровень остаётся *_PLACING
+		// до следующего запуска.
+		if placeErrorIntentStatus(err) == "REJECTED" {
+			level.Status = repo.GridLevelWaiting
+			level.ClientOrderID = ""
+			_ = u.saveLevel(ctx, level)
+		}
+		return wrap.Errorf("place grid %s %s level %d: %w", side, grid.Symbol, level.Level, err)
+	}
+	if result == nil || result.OrderID == "" {
+		return wrap.Errorf("place grid %s %s level %d: empty order response", side, grid.Symbol, level.Level)
+	}
+	level.Status = open
+	level.OrderID = result.OrderID
+	if err := u.saveLevel(ctx, level); err != nil {
+		return err
+	}
+	fmt.Printf("Сетка %s #%d: %s %.8f × %.8f, order=%s\n", grid.Symbol, level.Level, side, price, quantity, result.OrderID)
+	return nil
+}
+
+// closeLevelOrder учитывает исполнение завершённого ордера уровня и
+// переводит уровень в status.
+func (u *GridTrade) closeLevelOrder(ctx context.Context, grid repo.Grid, level *repo.GridLevel, result *mexc.QueryOrderResult, status string) error {
+	executed, quote, err := orderFill(result)
+	if err != nil {
+		return err
+	}
+	buy := level.Status == repo.GridLevelBuyOpen
+	applyGridFill(level, buy, result.Status == "FILLED", executed, quote)
+	level.Status = status
+	level.OrderID = ""
+	level.ClientOrderID = ""
+	if err := u.saveLevel(ctx, level); err != nil {
+		return err
+	}
+	if executed <= 0 {
+		return nil
+	}
+	if buy {
+		fmt.Printf("Сетка %s #%d: куплено %.8f на %.8f USDT\n", grid.Symbol, level.Level, executed, quote)
+		return nil
+	}
+	fmt.Printf("Сетка %s #%d: продано %.8f на %.8f USDT, PnL уровня %.8f\n", grid.Symbol, level.Level, executed, quote, level.RealizedPnl)
+	if result.Status == "FILLED" {
+		u.notify(fmt.Sprintf("<b>🕸 Grid cycle</b> %s · level %d · cycles %d · PnL %.4f USDT", grid.Symbol, level.Level, level.Cycles, level.RealizedPnl))
+	}
+	return nil
+}
+
+// applyGridFill переносит исполнение ордера в запас уровня: покупка
+// пополняет HeldQuantity и HeldCost, продажа списывает их пропорционально и
+// фиксирует PnL. Полностью исполненный SELL завершает цикл уровня.
+func applyGridFill(level *repo.GridLevel, buy, filled bool, executed, quote float64) {
+	if buy {
+		level.HeldQuantity += executed
+		level.HeldCost += quote
+		return
+	}
+	if level.HeldQuantity > 0 {
+		cost := level.HeldCost * math.Min(executed/level.HeldQuantity, 1)
+		level.RealizedPnl += quote - cost
+		level.HeldCost -= cost
+	}
+	level.HeldQuantity = math.Max(level.HeldQuantity-executed, 0)
+	if level.HeldQuantity == 0 {
+		level.HeldCost = 0
+	}
+	if filled {
+		level.Cycles++
+	}
+}
+
+// stopGrid снимает все ордера сетки и закрывает её. Купленное уровнями
+// остаётся на балансе: сетка не продаёт его по рынку при пробое.
+func (u *GridTrade) stopGrid(ctx context.Context, grid repo.Grid, levels []repo.GridLevel, reason string) error {
+	var held, cost, pnl float64
+	for i := range levels {
+		level := &levels[i]
+		if err := u.resolvePlacingLevel(ctx, grid, level); err != nil {
+			return err
+		}
+		if level.Status == repo.GridLevelBuyOpen || level.Status == repo.GridLevelSellOpen {
+			result, err := u.cancelLevelOrder(ctx, grid, *level)
+			if err != nil {
+				return err
+			}
+			if err := u.closeLevelOrder(ctx, grid, level, result, repo.GridLevelClosed); err != nil {
+				return err
+			}
+		} else if level.Status != repo.GridLevelClosed {
+			level.Status = repo.GridLevelClosed
+			if err := u.saveLevel(ctx, level); err != nil {
+				return err
+			}
+		}
+		held += level.HeldQuantity
+		cost += level.HeldCost
+		pnl += level.RealizedPnl
+	}
+	if err := u.stateRepo.UpdateGridStatus(ctx, grid.ID, repo.GridStopped, reason); err != nil {
+		return err
+	}
+	fmt.Printf("Сетка %s остановлена: %s, на руках %.8f (%.8f USDT), PnL %.8f\n", grid.Symbol, reason, held, cost, pnl)
+	u.notify(fmt.Sprintf("<b>🕸 Grid stopped</b> %s · %s · held %.8f (%.4f USDT) · PnL %.4f USDT", grid.Symbol, reason, held, cost, pnl))
+	return nil
+}
+
+// cancelLevelOrder снимает ордер уровня и возвращает его итоговое
+// состояние с учётом исполнения до отмены.
+func (u *GridTrade) cancelLevelOrder(ctx context.Context, grid repo.Grid, level repo.GridLevel) (*mexc.QueryOrderResult, error) {
+	_, cancelErr := u.api.CancelOrder(ctx, grid.Symbol, level.OrderID)
+	result, err := u.api.GetOrderQuery(ctx, grid.Symbol, level.OrderID)
+	if err != nil {
+		return nil, wrap.Errorf("query grid order %s after cancel: %w", level.OrderID, err)
+	}
+	if result == nil {
+		if cancelErr != nil {
+			return nil, wrap.Errorf("cancel grid order %s: %w", level.OrderID, cancelErr)
+		}
+		return nil, wrap.Errorf("grid order %s is missing after cancel", level.OrderID)
+	}
+	if result.Status == "NEW" || result.Status == "PARTIALLY_FILLED" {
+		if cancelErr != nil {
+			return nil, wrap.Errorf("cancel grid order %s: %w", level.OrderID, cancelErr)
+		}
+		return nil, wrap.Errorf("grid order %s is still active after cancel, status=%s", level.OrderID, result.Status)
+	}
+	return result, nil
+}
+
+func (u *GridTrade) saveLevel(ctx context.Context, level *repo.GridLevel) error {
+	level.UpdatedAt = time.Now().UTC()
+	return u.stateRepo.SaveGridLevel(ctx, *level)
+}
+
+func (u *GridTrade) notify(message string) {
+	if u.telegram == nil || !u.telegram.Configured() {
+		return
+	}
+	if _, err := u.telegram.Send(message); err != nil {
+		fmt.Printf("Telegram: %v\n", err)
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/drybin/palisade/internal/adapter/webapi/mexcsim"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+func TestGridTrade_simLadderRebalanceAndShutdown(t *testing.T) {
+	ex := newSimSignalExchange()
+	state := newMemState()
+	state.coins["AAAUSDT"] = mexc.SymbolDetail{Symbol: "AAAUSDT", IsPalisade: true, Support: 0.96, Resistance: 1.04}
+	u := NewGridTradeUsecase(newSimWebapi(t, ex), state, nil, nil, testStrategy.Grid)
+	ctx := context.Background()
+	opts := GridTradeOptions{Symbols: []string{"AAAUSDT"}, Live: true}
+
+	if err := u.Process(ctx, opts); err != nil {
+		t.Fatalf("start grid: %v", err)
+	}
+	if grid := state.grid(1); grid.Status != repo.GridActive || grid.Levels != 4 {
+		t.Fatalf("expected active grid with 4 levels, got %+v", grid)
+	}
+	// Покупки 0.96…0.99 с шагом 0.01, продажи на полдиапазона (0.04) выше.
+	for i, buy := range []float64{0.96, 0.97, 0.98, 0.99} {
+		level := state.gridLevel(1, i)
+		if level.Status != repo.GridLevelBuyOpen || math.Abs(level.BuyPrice-buy) > 1e-9 || math.Abs(level.SellPrice-buy-0.04) > 1e-9 {
+			t.Fatalf("level %d: expected BUY %.2f open, got %+v", i, buy, level)
+		}
+	}
+	if open := ex.OpenOrders("AAAUSDT"); len(open) != 4 {
+		t.Fatalf("expected 4 resting BUY orders, got %d", len(open))
+	}
+
+	// Цена опускается к 0.985: исполняется только верхний уровень.
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.984, Qty: 500}}, []mexcsim.Level{{Price: 0.985, Qty: 500}})
+	if err := u.Process(ctx, GridTradeOptions{Live: true}); err != nil {
+		t.Fatalf("reconcile BUY: %v", err)
+	}
+	top := state.gridLevel(1, 3)
+	if top.Status != repo.GridLevelSellOpen || math.Abs(top.HeldQuantity-top.Quantity) > 1e-9 {
+		t.Fatalf("expected paired SELL after BUY fill, got %+v", top)
+	}
+	if state.gridLevel(1, 2).Status != repo.GridLevelBuyOpen {
+		t.Fatalf("lower levels must keep their BUY orders")
+	}
+
+	// Цена доходит до 1.03: SELL уровня исполняется, BUY выставляется снова.
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 1.03, Qty: 500}}, []mexcsim.Level{{Price: 1.031, Qty: 500}})
+	if err := u.Process(ctx, GridTradeOptions{Live: true}); err != nil {
+		t.Fatalf("reconcile SELL: %v", err)
+	}
+	top = state.gridLevel(1, 3)
+	wantPnl := top.Quantity * 0.04
+	if top.Status != repo.GridLevelBuyOpen || top.Cycles != 1 || top.HeldQuantity != 0 || math.Abs(top.RealizedPnl-wantPnl) > 1e-9 {
+		t.Fatalf("expected level rebalanced to BUY with one cycle and pnl %.4f, got %+v", wantPnl, top)
+	}
+
+	// check-palisade-coin-list снял флаг палисады: все ордера сетки снимаются.
+	state.coins["AAAUSDT"] = mexc.SymbolDetail{Symbol: "AAAUSDT", IsPalisade: false}
+	if err := u.Process(ctx, GridTradeOptions{Live: true}); err != nil {
+		t.Fatalf("stop grid: %v", err)
+	}
+	if grid := state.grid(1); grid.Status != repo.GridStopped || grid.StopReason != "NOT_PALISADE" {
+		t.Fatalf("expected grid stopped, got %+v", grid)
+	}
+	if open := ex.OpenOrders("AAAUSDT"); len(open) != 0 {
+		t.Fatalf("expected all grid orders canceled, got %d", len(open))
+	}
+	for i := 0; i < 4; i++ {
+		if level := state.gridLevel(1, i); level.Status != repo.GridLevelClosed || level.OrderID != "" {
+			t.Fatalf("level %d must be closed, got %+v", i, level)
+		}
+	}
+	if _, locked := ex.Balance("USDT"); locked != 0 {
+		t.Fatalf("expected no locked USDT after shutdown, got %f", locked)
+	}
+}
@@ -3,8 +3,6 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"math"
-	"strconv"
 	"time"
 
 	"github.com/drybin/palisade/internal/adapter/webapi"
@@ -16,15 +14,12 @@ import (
 	"github.com/drybin/palisade/pkg/wrap"
 )
 
-// orderQuoteUSDT is the target USDT notional for the single-order palisade process buy.
-const orderQuoteUSDT = 10.0
-
 type IPalisadeProcess interface {
 	Process(ctx context.Context) error
 }
 
 type PalisadeProcess struct {
-	repo                  *webapi.MexcWebapi
+	repo                  repo.IMexcRepository
 	repoV2                *webapi.MexcV2Webapi
 	telegramApi           *webapi.TelegramWebapi
 	traidingPairsService  *service.TradingPair
@@ -32,10 +27,12 @@ type PalisadeProcess struct {
 	buyService            *service.ByuService
 	checkerService        *service.PalisadeCheckerService
 	stateRepo             repo.IStateRepository
+	risk                  *service.RiskManager
+	sizer                 *service.PositionSizer
 }
 
 func NewPalisadeProcessUsecase(
-	repo *webapi.MexcWebapi,
+	repo repo.IMexcRepository,
 	repoV2 *webapi.MexcV2Webapi,
 	telegramApi *webapi.TelegramWebapi,
 	traidingPairsService *service.TradingPair,
@@ -43,6 +40,8 @@ func NewPalisadeProcessUsecase(
 	buyService *service.ByuService,
 	checkerService *service.PalisadeCheckerService,
 	stateRepo repo.IStateRepository,
+	risk *service.RiskManager,
+	sizer *service.PositionSizer,
 ) *PalisadeProcess {
 	return &PalisadeProcess{
 		repo:                  repo,
@@ -53,6 +52,8 @@ func NewPalisadeProcessUsecase(
 		buyService:            buyService,
 		checkerService:        checkerService,
 		stateRepo:             stateRepo,
+		risk:                  risk,
+		sizer:                 sizer,
 	}
 }
 
@@ -189,28 +190,18 @@ func (u *PalisadeProcess) Process(ctx context.Context) error {
 		return nil
 	}
 
-	quantity := orderQuoteUSDT / coin.Support
-
-	// Округлить количество согласно baseSizePrecision
-	baseSizePrecision, err := strconv.ParseFloat(coin.BaseSizePrecision, 64)
-	if err != nil {
-		return wrap.Errorf("failed to parse baseSizePrecision for %s: %w", coin.Symbol, err)
-	}
-
-	if baseSizePrecision == 0 {
-		// Если baseSizePrecision равно 0, округлить до ближайшего целого в меньшую сторону
-		quantity = math.Floor(quantity)
-		fmt.Printf("📏 Округление количества до целого: %.8f → %.8f (baseSizePrecision: %.8f)\n",
-			orderQuoteUSDT/coin.Support, quantity, baseSizePrecision)
-	} else {
-		// Округлить количество до ближайшего кратного baseSizePrecision
-		quantity = math.Floor(quantity/baseSizePrecision) * baseSizePrecision
-		fmt.Printf("📏 Округление количества: %.8f → %.8f (baseSizePrecision: %.8f)\n",
-			orderQuoteUSDT/coin.Support, quantity, baseSizePrecision)
+	size, ok, err := sizeEntry(ctx, u.repo, u.sizer, coin.Symbol, service.SizingRequest{
+		Price:      coin.Support,
+		FreeUSDT:   usdtBalance.Free,
+		Volatility: coin.Volatility,
+	})
+	if err != nil || !ok {
+		return err
 	}
+	quantity := size.Quantity
 
-	if quantity <= 0 {
-		return wrap.Errorf("rounded quantity %f is invalid for order", quantity)
+	if blocked, err := entryBlockedByRisk(ctx, u.risk, coin.Symbol, coin.Support*quantity); err != nil || blocked {
+		return err
 	}
 
 	nextOrderId, err := u.stateRepo.GetNextTradeId(ctx)
@@ -224,6 +215,7 @@ func (u *PalisadeProcess) Process(ctx context.Context) error {
 	fmt.Printf("Количество: %.8f\n", quantity)
 
 	placeOrderResult, err := u.repo.NewOrder(
+		ctx,
 		model.OrderParams{
 			Symbol:           coin.Symbol,
 			Side:             order.BUY,
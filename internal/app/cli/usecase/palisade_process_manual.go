@@ -14,6 +14,7 @@ import (
 	"github.com/drybin/palisade/internal/domain/model"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/service"
 	"github.com/drybin/palisade/pkg/wrap"
 	"gopkg.in/yaml.v3"
 )
@@ -28,20 +29,23 @@ type ProcessManualConfig struct {
 }
 
 type PalisadeProcessManual struct {
-	repo        *webapi.MexcWebapi
+	repo        repo.IMexcRepository
 	stateRepo   repo.IStateRepository
 	telegramApi *webapi.TelegramWebapi
+	risk        *service.RiskManager
 }
 
 func NewPalisadeProcessManualUsecase(
-	repo *webapi.MexcWebapi,
+	repo repo.IMexcRepository,
 	stateRepo repo.IStateRepository,
 	telegramApi *webapi.TelegramWebapi,
+	risk *service.RiskManager,
 ) *PalisadeProcessManual {
 	return &PalisadeProcessManual{
 		repo:        repo,
 		stateRepo:   stateRepo,
 		telegramApi: telegramApi,
+		risk:        risk,
 	}
 }
 
@@ -150,6 +154,9 @@ func (u *PalisadeProcessManual) Process(ctx context.Context, configPath string)
 	if quantity <= 0 {
 		return wrap.Errorf("rounded quantity invalid for %s", cfg.Symbol)
 	}
+	if blocked, err := entryBlockedByRisk(ctx, u.risk, cfg.Symbol, cfg.Support*quantity); err != nil || blocked {
+		return err
+	}
 
 	nextID, err := u.stateRepo.GetNextTradeIdManual(ctx)
 	if err != nil {
@@ -158,7 +165,7 @@ func (u *PalisadeProcessManual) Process(ctx context.Context, configPath string)
 	clientOrderID := fmt.Sprintf("Manual_buy_%d", nextID)
 
 	fmt.Printf("Размещаем лимит BUY %s @ %.8f qty %.8f\n", cfg.Symbol, cfg.Support, quantity)
-	placeOrderResult, err := u.repo.NewOrder(model.OrderParams{
+	placeOrderResult, err := u.repo.NewOrder(ctx, model.OrderParams{
 		Symbol:           cfg.Symbol,
 		Side:             order.BUY,
 		OrderType:        order.LIMIT,
@@ -3,15 +3,14 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"math"
 	"math/rand"
-	"strconv"
 	"time"
 
 	"github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/domain/enum/order"
 	"github.com/drybin/palisade/internal/domain/helpers"
 	"github.com/drybin/palisade/internal/domain/model"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
 	"github.com/drybin/palisade/internal/domain/service"
 	"github.com/drybin/palisade/pkg/wrap"
@@ -22,7 +21,7 @@ type IPalisadeProcessMulti interface {
 }
 
 type PalisadeProcessMulti struct {
-	repo                  *webapi.MexcWebapi
+	repo                  repo.IMexcRepository
 	repoV2                *webapi.MexcV2Webapi
 	telegramApi           *webapi.TelegramWebapi
 	traidingPairsService  *service.TradingPair
@@ -30,10 +29,12 @@ type PalisadeProcessMulti struct {
 	buyService            *service.ByuService
 	checkerService        *service.PalisadeCheckerService
 	stateRepo             repo.IStateRepository
+	risk                  *service.RiskManager
+	sizer                 *service.PositionSizer
 }
 
 func NewPalisadeProcessMultiUsecase(
-	repo *webapi.MexcWebapi,
+	repo repo.IMexcRepository,
 	repoV2 *webapi.MexcV2Webapi,
 	telegramApi *webapi.TelegramWebapi,
 	traidingPairsService *service.TradingPair,
@@ -41,6 +42,8 @@ func NewPalisadeProcessMultiUsecase(
 	buyService *service.ByuService,
 	checkerService *service.PalisadeCheckerService,
 	stateRepo repo.IStateRepository,
+	risk *service.RiskManager,
+	sizer *service.PositionSizer,
 ) *PalisadeProcessMulti {
 	return &PalisadeProcessMulti{
 		repo:                  repo,
@@ -51,6 +54,8 @@ func NewPalisadeProcessMultiUsecase(
 		buyService:            buyService,
 		checkerService:        checkerService,
 		stateRepo:             stateRepo,
+		risk:                  risk,
+		sizer:                 sizer,
 	}
 }
 
@@ -104,6 +109,7 @@ func (u *PalisadeProcessMulti) Process(ctx context.Context) error {
 	}
 
 	fmt.Printf("✅ Баланса достаточно для размещения %d ордеров\n", remainingOrders)
+	freeUSDT := usdtBalance.Free
 
 	coins, err := u.stateRepo.GetCoinsToProcess(ctx, 50, 0)
 	if err != nil {
@@ -244,29 +250,26 @@ func (u *PalisadeProcessMulti) Process(ctx context.Context) error {
 			continue
 		}
 
-		quantity := 2.0 / coin.Support
-
-		// Округлить количество согласно baseSizePrecision
-		baseSizePrecision, err := strconv.ParseFloat(coin.BaseSizePrecision, 64)
+		size, ok, err := sizeEntry(ctx, u.repo, u.sizer, coin.Symbol, service.SizingRequest{
+			Price:      coin.Support,
+			FreeUSDT:   freeUSDT,
+			Volatility: coin.Volatility,
+		})
 		if err != nil {
-			fmt.Printf("❌ Ошибка парсинга baseSizePrecision для %s: %v\n", coin.Symbol, err)
+			fmt.Printf("❌ Ошибка расчёта объёма для %s: %v\n", coin.Symbol, err)
 			continue
 		}
-
-		if baseSizePrecision == 0 {
-			// Если baseSizePrecision равно 0, округлить до ближайшего целого в меньшую сторону
-			quantity = math.Floor(quantity)
-			fmt.Printf("📏 Округление количества до целого: %.8f → %.8f (baseSizePrecision: %.8f)\n",
-				2.0/coin.Support, quantity, baseSizePrecision)
-		} else {
-			// Округлить количество до ближайшего кратного baseSizePrecision
-			quantity = math.Floor(quantity/baseSizePrecision) * baseSizePrecision
-			fmt.Printf("📏 Округление количества: %.8f → %.8f (baseSizePrecision: %.8f)\n",
-				2.0/coin.Support, quantity, baseSizePrecision)
+		if !ok {
+			continue
 		}
+		quantity := size.Quantity
 
-		if quantity <= 0 {
-			fmt.Printf("❌ Округленное количество %f недопустимо для ордера %s\n", quantity, coin.Symbol)
+		blocked, err := entryBlockedByRisk(ctx, u.risk, coin.Symbol, coin.Support*quantity)
+		if err != nil {
+			fmt.Printf("❌ Ошибка проверки риск-лимитов для %s: %v\n", coin.Symbol, err)
+			continue
+		}
+		if blocked {
 			continue
 		}
 
@@ -282,6 +285,7 @@ func (u *PalisadeProcessMulti) Process(ctx context.Context) error {
 		fmt.Printf("Количество: %.8f\n", quantity)
 
 		placeOrderResult, err := u.repo.NewOrder(
+			ctx,
 			model.OrderParams{
 				Symbol:           coin.Symbol,
 				Side:             order.BUY,
@@ -295,6 +299,13 @@ func (u *PalisadeProcessMulti) Process(ctx context.Context) error {
 
 		if err != nil {
 			fmt.Printf("❌ Ошибка размещения ордера для %s: %v\n", coin.Symbol, err)
+			if mexc.ClassifyError(err) == mexc.ErrorClassFatal {
+				return wrap.Errorf("failed to place order for %s: %w", coin.Symbol, err)
+			}
+			if mexc.IsInsufficientBalance(err) {
+				fmt.Println("Недостаточно баланса, остальные ордера не размещаем")
+				break
+			}
 			continue
 		}
 
@@ -330,6 +341,7 @@ func (u *PalisadeProcessMulti) Process(ctx context.Context) error {
 		})
 
 		successCount++
+		freeUSDT -= size.QuoteUSDT
 		fmt.Printf("\n✅ Монета %s успешно обработана! (%d/%d)\n", coin.Symbol, successCount, len(selectedCoins))
 
 		// Обновляем баланс после успешного размещения ордера
@@ -24,7 +24,7 @@ type IPalisadeProcessSell interface {
 }
 
 type PalisadeProcessSell struct {
-	repo           *webapi.MexcWebapi
+	repo           repo.IMexcRepository
 	stateRepo      repo.IStateRepository
 	telegramApi    *webapi.TelegramWebapi
 	useManualLog   bool
@@ -32,7 +32,7 @@ type PalisadeProcessSell struct {
 }
 
 func NewPalisadeProcessSellUsecase(
-	repo *webapi.MexcWebapi,
+	repo repo.IMexcRepository,
 	stateRepo repo.IStateRepository,
 	telegramApi *webapi.TelegramWebapi,
 ) *PalisadeProcessSell {
@@ -46,7 +46,7 @@ func NewPalisadeProcessSellUsecase(
 
 // NewPalisadeProcessSellManualUsecase — process-sell-manual (таблица trade_log_manual).
 func NewPalisadeProcessSellManualUsecase(
-	repo *webapi.MexcWebapi,
+	repo repo.IMexcRepository,
 	stateRepo repo.IStateRepository,
 	telegramApi *webapi.TelegramWebapi,
 ) *PalisadeProcessSell {
@@ -161,6 +161,7 @@ func (u *PalisadeProcessSell) placeLimitSell(ctx context.Context, dbOrder repo.T
 	clientOrderId := fmt.Sprintf("%s_order_sell_%d", u.clientOrderPrefix(), nextOrderId)
 
 	placeOrderResult, err := u.repo.NewOrder(
+		ctx,
 		model.OrderParams{
 			Symbol:           dbOrder.Symbol,
 			Side:             order.SELL,
@@ -301,7 +302,7 @@ func (u *PalisadeProcessSell) Process(ctx context.Context) error {
 		fmt.Printf("Проверяем статус ордера на покупку (BUY): %s\n", orderID)
 	}
 
-	queryResult, err := u.repo.GetOrderQuery(dbOrder.Symbol, orderID)
+	queryResult, err := u.repo.GetOrderQuery(ctx, dbOrder.Symbol, orderID)
 	if err != nil {
 		return wrap.Errorf("ошибка при получении ордера с биржи для %s: %w", dbOrder.Symbol, err)
 	}
@@ -455,7 +456,7 @@ func (u *PalisadeProcessSell) Process(ctx context.Context) error {
 			fmt.Printf("\n--- Отменяем текущий ордер на продажу ---\n")
 			fmt.Printf("OrderID: %s\n", orderID)
 
-			cancelResp, err := u.repo.CancelOrder(dbOrder.Symbol, orderID)
+			cancelResp, err := u.repo.CancelOrder(ctx, dbOrder.Symbol, orderID)
 			if err != nil {
 				fmt.Printf("❌ Ошибка при отмене ордера: %v\n", err)
 				return wrap.Errorf("failed to cancel order %s: %w", orderID, err)
@@ -526,6 +527,7 @@ func (u *PalisadeProcessSell) Process(ctx context.Context) error {
 			}
 
 			placeOrderResult, err := u.repo.NewOrder(
+				ctx,
 				model.OrderParams{
 					Symbol:           dbOrder.Symbol,
 					Side:             order.SELL,
@@ -590,7 +592,7 @@ func (u *PalisadeProcessSell) Process(ctx context.Context) error {
 
 		// Если прошло больше 2 часов, помечаем как отмененный
 		if timeSinceOpen > 120*time.Minute {
-			cancelResp, err := u.repo.CancelOrder(dbOrder.Symbol, dbOrder.OrderId)
+			cancelResp, err := u.repo.CancelOrder(ctx, dbOrder.Symbol, dbOrder.OrderId)
 			if err != nil {
 				fmt.Printf("   ❌ Ошибка при отмене ордера: %v\n", err)
 			} else {
@@ -696,6 +698,7 @@ func (u *PalisadeProcessSell) Process(ctx context.Context) error {
 		clientOrderId := fmt.Sprintf("%s_order_sell_%d", u.clientOrderPrefix(), nextOrderId)
 
 		placeOrderResult, err := u.repo.NewOrder(
+			ctx,
 			model.OrderParams{
 				Symbol:           dbOrder.Symbol,
 				Side:             order.SELL,
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/strategy"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const palisadeStrategyName = "palisade"
+
+// palisadeStrategy — палисада версии version с порогами из
+// config.StrategyConfig. Вход у всех версий один — отскок от поддержки
+// 15m-диапазона; версии различаются выходом (безубыток с v4, трейлинг с v7,
+// доля быстрой прибыли с v8) и входом бумажной сделки (откат с v8, см.
+// advancePaperTrade).
+type palisadeStrategy struct {
+	cfg     config.StrategyConfig
+	version int
+}
+
+func (s palisadeStrategy) Name() string {
+	return palisadeStrategyName
+}
+
+func (s palisadeStrategy) Version() int {
+	return s.version
+}
+
+func (s palisadeStrategy) EvaluateEntry(market strategy.MarketContext) (strategy.Entry, bool) {
+	entry, ok := buildPalisadeSignal(s.cfg.Signals, market.Snapshot, market.Symbol, market.Klines, market.Now)
+	if !ok || !isExecutablePalisadeSignal(s.cfg.Signals, entry, market.Symbol, market.Depth) {
+		return strategy.Entry{}, false
+	}
+	return entry, true
+}
+
+func (s palisadeStrategy) DecideExit(state *strategy.ExitState, pos strategy.Position, now time.Time, bid float64) strategy.ExitDecision {
+	return decideExit(s.cfg, s.version, state, pos, now, bid)
+}
+
+// NewStrategyRegistry регистрирует все версии стратегий на порогах cfg.
+// Новая реализация стратегии добавляется сюда, после чего выбирается в
+// конфиге ключом имя/vN. Набор стратегий задан в коде, поэтому ошибка
+// регистрации (повтор ключа) — ошибка сборки и паникует при старте.
+func NewStrategyRegistry(cfg config.StrategyConfig) *strategy.Registry {
+	registry := strategy.NewRegistry()
+	for version := 1; version <= paperStrategyVersion; version++ {
+		if err := registry.Register(palisadeStrategy{cfg: cfg, version: version}); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// ValidateStrategyAlgorithm проверяет, что cfg.Algorithm есть в реестре:
+// опечатка в ключе останавливает запуск, а не каждую команду стратегии.
+func ValidateStrategyAlgorithm(cfg config.StrategyConfig) error {
+	registry := NewStrategyRegistry(cfg)
+	if _, err := registry.Get(cfg.Algorithm); err != nil {
+		return wrap.Errorf("strategy algorithm, known %s: %w", strings.Join(registry.Keys(), ", "), err)
+	}
+	return nil
+}
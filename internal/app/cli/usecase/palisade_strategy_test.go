@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/drybin/palisade/internal/domain/strategy"
+)
+
+func TestValidateStrategyAlgorithm_unknownKey(t *testing.T) {
+	cfg := testStrategy
+	if err := ValidateStrategyAlgorithm(cfg); err != nil {
+		t.Fatalf("default algorithm must be registered: %v", err)
+	}
+	cfg.Algorithm = "palisade/v99"
+	if err := ValidateStrategyAlgorithm(cfg); !errors.Is(err, strategy.ErrUnknownStrategy) {
+		t.Fatalf("expected unknown strategy, got %v", err)
+	}
+}
@@ -7,26 +7,19 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/drybin/palisade/internal/adapter/webapi"
+	"github.com/drybin/palisade/internal/app/cli/config"
 	"github.com/drybin/palisade/internal/domain/enum/order"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/strategy"
 	"github.com/drybin/palisade/pkg/wrap"
 )
 
 const (
-	paperLockKey             = "palisade:paper-trading"
-	paperStrategyVersion     = 8
-	maxPaperOpenTrades       = 1
-	paperReboundEntryPercent = 0.0015
-	paperMaxPullbackDepth    = 0.004
-	paperQuickProfitNet      = 0.002
-	paperQuickProfitShare    = 0.5
-	paperTrailingTrigger     = 0.006
-	paperTrailingDistance    = 0.0025
-	paperMinimumLockedProfit = 0.00025
-	paperEntryRunawayPercent = 0.004
-	paperPullbackTimeout     = 30 * time.Minute
+	paperLockKey = "palisade:paper-trading"
+	// paperStrategyVersion — версия алгоритма; пороги задаются в
+	// config.StrategyConfig и версионируются отдельно (config_version).
+	paperStrategyVersion = 8
 )
 
 type IPaperTrade interface {
@@ -34,12 +27,14 @@ type IPaperTrade interface {
 }
 
 type PaperTradeRunner struct {
-	api       *webapi.MexcWebapi
-	stateRepo repo.IStateRepository
+	api        repo.IMexcRepository
+	stateRepo  repo.IStateRepository
+	strategy   config.StrategyConfig
+	strategies *strategy.Registry
 }
 
-func NewPaperTradeUsecase(api *webapi.MexcWebapi, stateRepo repo.IStateRepository) *PaperTradeRunner {
-	return &PaperTradeRunner{api: api, stateRepo: stateRepo}
+func NewPaperTradeUsecase(api repo.IMexcRepository, stateRepo repo.IStateRepository, strategy config.StrategyConfig) *PaperTradeRunner {
+	return &PaperTradeRunner{api: api, stateRepo: stateRepo, strategy: strategy, strategies: NewStrategyRegistry(strategy)}
 }
 
 func (u *PaperTradeRunner) Process(ctx context.Context, debug bool) error {
@@ -52,6 +47,15 @@ func (u *PaperTradeRunner) Process(ctx context.Context, debug bool) error {
 	}
 	defer releaseLock()
 
+	selected, err := u.strategies.Get(u.strategy.Algorithm)
+	if err != nil {
+		return err
+	}
+	version := selected.Version()
+	configVersion, err := recordStrategyConfig(ctx, u.stateRepo, u.strategy)
+	if err != nil {
+		return err
+	}
 	signals, err := u.stateRepo.ListActivePalisadeSignals(ctx)
 	if err != nil {
 		return err
@@ -75,18 +79,18 @@ func (u *PaperTradeRunner) Process(ctx context.Context, debug bool) error {
 		}
 	}
 
-	openTrades, err := u.stateRepo.ListOpenPaperTrades(ctx, paperStrategyVersion)
+	openTrades, err := u.stateRepo.ListOpenPaperTrades(ctx, version)
 	if err != nil {
 		return err
 	}
-	legacyOpenTrades, err := u.stateRepo.ListOpenPaperTrades(ctx, paperStrategyVersion-1)
+	legacyOpenTrades, err := u.stateRepo.ListOpenPaperTrades(ctx, version-1)
 	if err != nil {
 		return err
 	}
 	openTrades = append(legacyOpenTrades, openTrades...)
 	openBySymbol := make(map[string]repo.PaperTrade, len(openTrades))
 	for _, trade := range openTrades {
-		if trade.StrategyVersion == paperStrategyVersion {
+		if trade.StrategyVersion == version {
 			openBySymbol[trade.Symbol] = trade
 		}
 	}
@@ -97,7 +101,7 @@ func (u *PaperTradeRunner) Process(ctx context.Context, debug bool) error {
 		if err := u.processPaperTrade(ctx, &trade, signalFor(signals, trade.Symbol), bookBySymbol[trade.Symbol], bySymbol[trade.Symbol], time.Now().UTC()); err != nil {
 			return err
 		}
-		if trade.StrategyVersion == paperStrategyVersion && isOpenPaperTrade(trade) {
+		if trade.StrategyVersion == version && isOpenPaperTrade(trade) {
 			openSlotsUsed++
 		}
 		if debug {
@@ -107,10 +111,10 @@ func (u *PaperTradeRunner) Process(ctx context.Context, debug bool) error {
 
 	created := 0
 	for _, signal := range signals {
-		if openSlotsUsed >= maxPaperOpenTrades {
+		if openSlotsUsed >= u.strategy.Paper.MaxOpenTrades {
 			break
 		}
-		if signal.StrategyVersion != paperStrategyVersion {
+		if signal.StrategyVersion != version {
 			continue
 		}
 		if _, exists := openBySymbol[signal.Symbol]; exists {
@@ -123,7 +127,7 @@ func (u *PaperTradeRunner) Process(ctx context.Context, debug bool) error {
 			}
 			continue
 		}
-		existing, err := u.stateRepo.GetPaperTradeBySignal(ctx, signal.Symbol, signalAt, paperStrategyVersion)
+		existing, err := u.stateRepo.GetPaperTradeBySignal(ctx, signal.Symbol, signalAt, version)
 		if err != nil {
 			return err
 		}
@@ -135,7 +139,7 @@ func (u *PaperTradeRunner) Process(ctx context.Context, debug bool) error {
 		if !ok || !symbolOK {
 			continue
 		}
-		trade, ok, err := buildPaperTrade(signal, book, symbol, time.Now().UTC())
+		trade, ok, err := buildPaperTrade(u.strategy, selected, signal, book, symbol, time.Now().UTC())
 		if err != nil {
 			if debug {
 				fmt.Printf("paper %s: %v\n", signal.Symbol, err)
@@ -145,6 +149,7 @@ func (u *PaperTradeRunner) Process(ctx context.Context, debug bool) error {
 		if !ok {
 			continue
 		}
+		trade.ConfigVersion = configVersion
 		createdTrade, err := u.stateRepo.CreatePaperTrade(ctx, trade)
 		if err != nil {
 			return err
@@ -156,12 +161,12 @@ func (u *PaperTradeRunner) Process(ctx context.Context, debug bool) error {
 		}
 	}
 
-	stats, err := u.stateRepo.GetPaperTradeStats(ctx, paperStrategyVersion)
+	stats, err := u.stateRepo.GetPaperTradeStats(ctx, version)
 	if err != nil {
 		return err
 	}
 	fmt.Printf("Paper trading v%d: открытых=%d, новых=%d, закрыто=%d, отменено=%d, всего=%d, P/L закрытых=%.8f USDT, P/L открытых=%.8f USDT, win=%d, loss=%d\n",
-		paperStrategyVersion, stats.Open, created, stats.Closed, stats.Canceled, stats.Total,
+		version, stats.Open, created, stats.Closed, stats.Canceled, stats.Total,
 		stats.TotalPnL, stats.OpenPnL, stats.Wins, stats.Losses)
 	return nil
 }
@@ -170,7 +175,7 @@ func isOpenPaperTrade(trade repo.PaperTrade) bool {
 	return trade.Status == "BUY_PENDING" || trade.Status == "PULLBACK_SEEN" || trade.Status == "POSITION_OPEN" || trade.Status == "SELL_PENDING"
 }
 
-func buildPaperTrade(signal repo.PalisadeSignalState, book mexc.BookTicker, symbol mexc.SymbolDetail, now time.Time) (repo.PaperTrade, bool, error) {
+func buildPaperTrade(cfg config.StrategyConfig, selected strategy.Strategy, signal repo.PalisadeSignalState, book mexc.BookTicker, symbol mexc.SymbolDetail, now time.Time) (repo.PaperTrade, bool, error) {
 	bid, ask, err := parseBook(book)
 	if err != nil || ask <= bid || ask <= 0 {
 		return repo.PaperTrade{}, false, nil
@@ -185,12 +190,13 @@ func buildPaperTrade(signal repo.PalisadeSignalState, book mexc.BookTicker, symb
 	if err != nil {
 		return repo.PaperTrade{}, false, err
 	}
-	quantity := swapRoundQtyDown(signalOrderQuoteUSDT/entry, step)
+	quantity := swapRoundQtyDown(cfg.Signals.OrderQuoteUSDT/entry, step)
 	if quantity <= 0 || !isValidPaperOrder(symbol, order.BUY, entry, quantity) {
 		return repo.PaperTrade{}, false, nil
 	}
 	return repo.PaperTrade{
-		StrategyVersion:   paperStrategyVersion,
+		StrategyVersion:   selected.Version(),
+		StrategyKey:       strategy.Key(selected.Name(), selected.Version()),
 		Symbol:            signal.Symbol,
 		SignalAt:          paperSignalAt(signal),
 		Status:            "BUY_PENDING",
@@ -206,21 +212,52 @@ func buildPaperTrade(signal repo.PalisadeSignalState, book mexc.BookTicker, symb
 	}, true, nil
 }
 
+// paperDepth — стакан для исполнения открытой бумажной сделки. При
+// DepthLimit=0 или ошибке запроса сделка исполняется по лучшему bid/ask.
+func (u *PaperTradeRunner) paperDepth(ctx context.Context, trade repo.PaperTrade) *mexc.OrderBook {
+	if u.strategy.Signals.DepthLimit <= 0 || !isOpenPaperTrade(trade) {
+		return nil
+	}
+	depth, err := u.api.GetDepth(ctx, trade.Symbol, u.strategy.Signals.DepthLimit)
+	if err != nil {
+		fmt.Printf("paper %s: стакан недоступен, исполнение по лучшему bid/ask: %v\n", trade.Symbol, err)
+		return nil
+	}
+	return depth
+}
+
 func (u *PaperTradeRunner) processPaperTrade(ctx context.Context, trade *repo.PaperTrade, signal repo.PalisadeSignalState, book mexc.BookTicker, symbol mexc.SymbolDetail, now time.Time) error {
 	if book.Symbol == "" || symbol.Symbol == "" {
 		return nil
 	}
-	bid, ask, err := parseBook(book)
+	exitStrategy, err := u.strategies.Get(trade.StrategyKey)
+	if err != nil {
+		return err
+	}
+	bid, fee, err := advancePaperTrade(u.strategy, exitStrategy, trade, signal, book, u.paperDepth(ctx, *trade), symbol, now)
 	if err != nil {
 		return err
 	}
+	return u.persistPaperTrade(ctx, trade, now, bid, fee)
+}
+
+// advancePaperTrade продвигает бумажную сделку на один тик стакана и
+// возвращает bid и комиссию для оценки P/L. Состояние не сохраняется, поэтому
+// та же логика используется и бэктестом. Выход решает exitStrategy — версия,
+// по которой открыта сделка. Со стаканом depth исполнение идёт по уровням в
+// пределах цены ордера, без него (бэктест) — по лучшему уровню.
+func advancePaperTrade(cfg config.StrategyConfig, exitStrategy strategy.Strategy, trade *repo.PaperTrade, signal repo.PalisadeSignalState, book mexc.BookTicker, depth *mexc.OrderBook, symbol mexc.SymbolDetail, now time.Time) (float64, float64, error) {
+	bid, ask, err := parseBook(book)
+	if err != nil {
+		return 0, 0, err
+	}
 	bidQty, _ := parseDecimalValue(book.BidQty)
 	askQty, _ := parseDecimalValue(book.AskQty)
 	trade.LastPrice = (bid + ask) / 2
 	fee := math.Max(parseDecimal(symbol.MakerCommission), parseDecimal(symbol.TakerCommission))
 	lotStep, err := swapLotStep(&symbol)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
 	if signal.Symbol == trade.Symbol && signal.StrategyVersion == trade.StrategyVersion && signal.TargetPrice >= signal.MinExitPrice {
@@ -228,12 +265,12 @@ func (u *PaperTradeRunner) processPaperTrade(ctx context.Context, trade *repo.Pa
 	}
 
 	if trade.Status == "BUY_PENDING" {
-		entryCancelReason := paperEntryCancelReason(*trade, now, bid)
+		entryCancelReason := paperEntryCancelReason(cfg, *trade, now, bid)
 		if entryCancelReason != "" {
 			if trade.FilledQuantity == 0 {
 				trade.Status = "CANCELED"
 				trade.ExitReason = entryCancelReason
-				return u.persistPaperTrade(ctx, trade, now, bid, fee)
+				return bid, fee, nil
 			}
 			trade.Status = "POSITION_OPEN"
 		}
@@ -241,12 +278,11 @@ func (u *PaperTradeRunner) processPaperTrade(ctx context.Context, trade *repo.Pa
 			if trade.StrategyVersion >= 8 {
 				trade.Status = "PULLBACK_SEEN"
 				trade.EntryLowPrice = bid
-				return u.persistPaperTrade(ctx, trade, now, bid, fee)
+				return bid, fee, nil
 			}
 			remaining := trade.Quantity - trade.FilledQuantity
-			fillQty := swapRoundQtyDown(paperFillQuantity(remaining, askQty), lotStep)
+			fillQty, fillPrice := paperBuyFill(depth, remaining, trade.EntryPrice, math.Min(ask, trade.EntryPrice), askQty, lotStep)
 			if fillQty > 0 {
-				fillPrice := math.Min(ask, trade.EntryPrice)
 				trade.FilledQuantity += fillQty
 				trade.BuyQuote += fillPrice * fillQty
 				trade.Fees += fillPrice * fillQty * fee
@@ -258,22 +294,21 @@ func (u *PaperTradeRunner) processPaperTrade(ctx context.Context, trade *repo.Pa
 	}
 
 	if trade.Status == "PULLBACK_SEEN" {
-		entryCancelReason := paperEntryCancelReason(*trade, now, bid)
+		entryCancelReason := paperEntryCancelReason(cfg, *trade, now, bid)
 		if entryCancelReason != "" {
 			trade.Status = "CANCELED"
 			trade.ExitReason = entryCancelReason
-			return u.persistPaperTrade(ctx, trade, now, bid, fee)
+			return bid, fee, nil
 		}
-		if paperReboundConfirmed(trade, bid) {
-			quantityAtAsk := swapRoundQtyDown(signalOrderQuoteUSDT/ask, lotStep)
+		if paperReboundConfirmed(cfg.Paper, trade, bid) {
+			quantityAtAsk := swapRoundQtyDown(cfg.Signals.OrderQuoteUSDT/ask, lotStep)
 			remaining := math.Min(trade.Quantity, quantityAtAsk) - trade.FilledQuantity
-			fillQty := swapRoundQtyDown(paperFillQuantity(remaining, askQty), lotStep)
+			fillQty, fillPrice := paperBuyFill(depth, remaining, ask, ask, askQty, lotStep)
 			if fillQty > 0 {
-				fillPrice := ask
 				if !isValidPaperOrder(symbol, order.BUY, fillPrice, fillQty) {
 					trade.Status = "CANCELED"
 					trade.ExitReason = "REBOUND_ORDER_INVALID"
-					return u.persistPaperTrade(ctx, trade, now, bid, fee)
+					return bid, fee, nil
 				}
 				trade.FilledQuantity += fillQty
 				trade.BuyQuote += fillPrice * fillQty
@@ -296,52 +331,43 @@ func (u *PaperTradeRunner) processPaperTrade(ctx context.Context, trade *repo.Pa
 			}
 			closed := now
 			trade.ClosedAt = &closed
-			return u.persistPaperTrade(ctx, trade, now, bid, fee)
+			return bid, fee, nil
 		}
 		buyPrice := trade.BuyQuote / trade.FilledQuantity
-		trackPaperExcursion(trade, bid)
 		support := trade.SupportPrice
 		if support <= 0 {
 			support = trade.EntryPrice
 		}
-		if trade.StrategyVersion >= 8 && !trade.BreakEvenArmed && bid >= paperTrailingActivationPrice(buyPrice, fee) {
-			trade.BreakEvenArmed = true
-		} else if trade.StrategyVersion == 7 && !trade.BreakEvenArmed && bid >= paperV7TrailingActivationPrice(buyPrice, fee) {
-			trade.BreakEvenArmed = true
-		} else if trade.StrategyVersion >= 4 && trade.StrategyVersion < 7 && !trade.BreakEvenArmed && bid >= paperBreakEvenTrigger(buyPrice, trade.TargetPrice, fee) {
-			trade.BreakEvenArmed = true
-		}
-		reason := paperExitReason(*trade, now, bid, support, buyPrice, fee)
-		if reason == "" && trade.Status == "SELL_PENDING" && trade.ExitReason != "" {
-			reason = trade.ExitReason
+		position := strategy.Position{
+			BuyPrice:       buyPrice,
+			FilledQuantity: trade.FilledQuantity,
+			SoldQuantity:   trade.SoldQuantity,
+			SupportPrice:   support,
+			TargetPrice:    trade.TargetPrice,
+			OpenedAt:       paperOpenedAt(*trade),
+			Fee:            fee,
+			LotStep:        lotStep,
 		}
-		partialTarget := paperPartialProfitQuantity(trade.FilledQuantity, symbol)
-		partialPending := trade.StrategyVersion >= 8 && !trade.PartialProfitTaken && !paperQuantityReached(trade.SoldQuantity, partialTarget, lotStep) &&
-			bid >= paperQuickProfitBidPrice(buyPrice, fee)
-		if reason == "" && partialPending && bid >= paperQuickProfitBidPrice(buyPrice, fee) {
-			reason = "PARTIAL_PROFIT"
+		if trade.Status == "SELL_PENDING" {
+			position.PendingReason = trade.ExitReason
 		}
-		shouldSell := reason != "" || bid >= trade.TargetPrice
-		if shouldSell {
-			if reason == "" {
-				reason = "TARGET_REACHED"
+		state := paperExitState(*trade)
+		decision := exitStrategy.DecideExit(&state, position, now, bid)
+		applyPaperExitState(trade, state)
+		if reason := decision.Reason; reason != "" {
+			remaining, limit, partialTarget := decision.Quantity, decision.Limit, decision.PartialTarget
+			topPrice := bid
+			if decision.IsStop() {
+				topPrice = limit
 			}
-			remaining := trade.FilledQuantity - trade.SoldQuantity
-			if reason == "PARTIAL_PROFIT" {
-				remaining = partialTarget - trade.SoldQuantity
-			}
-			fillQty := swapRoundQtyDown(paperFillQuantity(remaining, bidQty), lotStep)
+			fillQty, fillPrice := paperSellFill(depth, remaining, limit, topPrice, bidQty, lotStep)
 			if fillQty > 0 {
-				fillPrice := bid
-				if reason != "TARGET_REACHED" && reason != "PARTIAL_PROFIT" {
-					fillPrice = bid * (1 - emergencyPriceDiscount)
-				}
 				trade.SoldQuantity += fillQty
 				trade.SellQuote += fillPrice * fillQty
 				trade.Fees += fillPrice * fillQty * fee
 				trade.ExitReason = reason
 				trade.Status = "SELL_PENDING"
-				if reason == "PARTIAL_PROFIT" {
+				if reason == strategy.ExitReasonPartial {
 					trade.Status = "POSITION_OPEN"
 					if paperQuantityReached(trade.SoldQuantity, partialTarget, lotStep) {
 						trade.PartialProfitTaken = true
@@ -356,7 +382,7 @@ func (u *PaperTradeRunner) processPaperTrade(ctx context.Context, trade *repo.Pa
 			trade.ClosedAt = &closed
 		}
 	}
-	return u.persistPaperTrade(ctx, trade, now, bid, fee)
+	return bid, fee, nil
 }
 
 func paperQuantityReached(actual, target, lotStep float64) bool {
@@ -370,7 +396,7 @@ func paperQuantityReached(actual, target, lotStep float64) bool {
 	return actual >= target-tolerance
 }
 
-func paperReboundConfirmed(trade *repo.PaperTrade, bid float64) bool {
+func paperReboundConfirmed(cfg config.PaperStrategyConfig, trade *repo.PaperTrade, bid float64) bool {
 	if bid <= 0 {
 		return false
 	}
@@ -378,7 +404,7 @@ func paperReboundConfirmed(trade *repo.PaperTrade, bid float64) bool {
 		trade.EntryLowPrice = bid
 		return false
 	}
-	return bid >= trade.EntryLowPrice*(1+paperReboundEntryPercent)
+	return bid >= trade.EntryLowPrice*(1+cfg.ReboundEntry)
 }
 
 func updatePaperTarget(trade *repo.PaperTrade, target, priceStep float64) {
@@ -388,95 +414,98 @@ func updatePaperTarget(trade *repo.PaperTrade, target, priceStep float64) {
 	}
 }
 
-func paperEntryCancelReason(trade repo.PaperTrade, now time.Time, bid float64) string {
+func paperEntryCancelReason(cfg config.StrategyConfig, trade repo.PaperTrade, now time.Time, bid float64) string {
 	if trade.StrategyVersion < 7 {
-		if bid < trade.EntryPrice*(1-supportBreakPercent) || now.Sub(trade.SignalAt) > signalBuyTimeout {
+		if bid < trade.EntryPrice*(1-cfg.Execution.SupportBreak) || now.Sub(trade.SignalAt) > cfg.Execution.BuyTimeout {
 			return "BUY_NOT_FILLED"
 		}
 		return ""
 	}
-	if bid < trade.SupportPrice*(1-supportBreakPercent) {
+	if bid < trade.SupportPrice*(1-cfg.Execution.SupportBreak) {
 		return "SUPPORT_BROKEN_BEFORE_ENTRY"
 	}
-	if now.Sub(trade.SignalAt) > paperPullbackTimeout {
+	if now.Sub(trade.SignalAt) > cfg.Paper.PullbackTimeout {
 		return "PULLBACK_TIMEOUT"
 	}
-	if trade.StrategyVersion >= 8 && trade.Status == "PULLBACK_SEEN" && bid < trade.EntryPrice*(1-paperMaxPullbackDepth) {
+	if trade.StrategyVersion >= 8 && trade.Status == "PULLBACK_SEEN" && bid < trade.EntryPrice*(1-cfg.Paper.MaxPullbackDepth) {
 		return "PULLBACK_TOO_DEEP"
 	}
-	if trade.Status == "BUY_PENDING" && bid > trade.EntryPrice*(1+paperEntryRunawayPercent) {
+	if trade.Status == "BUY_PENDING" && bid > trade.EntryPrice*(1+cfg.Paper.EntryRunaway) {
 		return "ENTRY_RAN_AWAY"
 	}
 	return ""
 }
 
-func paperExitReason(trade repo.PaperTrade, now time.Time, bid, support, buyPrice, fee float64) string {
-	if trade.StrategyVersion >= 7 && trade.BreakEvenArmed && bid <= paperTrailingStopPrice(trade, buyPrice, fee) {
-		return "TRAILING_STOP"
-	}
-	if trade.StrategyVersion >= 4 && trade.BreakEvenArmed && bid <= paperBreakEvenBidPrice(buyPrice, fee) {
-		return "BREAKEVEN_STOP"
-	}
-	return emergencyReason(now, paperOpenedAt(trade), bid, support, buyPrice)
+func paperExitReason(cfg config.StrategyConfig, trade repo.PaperTrade, now time.Time, bid, support, buyPrice, fee float64) string {
+	return exitStopReason(cfg, trade.StrategyVersion, paperExitState(trade), now, paperOpenedAt(trade), bid, support, buyPrice, fee)
 }
 
 func trackPaperExcursion(trade *repo.PaperTrade, bid float64) {
-	if bid <= 0 {
-		return
-	}
-	if trade.MaxBidPrice <= 0 || bid > trade.MaxBidPrice {
-		trade.MaxBidPrice = bid
-	}
-	if trade.MinBidPrice <= 0 || bid < trade.MinBidPrice {
-		trade.MinBidPrice = bid
+	state := paperExitState(*trade)
+	trackExitExcursion(&state, bid)
+	applyPaperExitState(trade, state)
+}
+
+func paperExitState(trade repo.PaperTrade) strategy.ExitState {
+	return strategy.ExitState{
+		BreakEvenArmed:     trade.BreakEvenArmed,
+		PartialProfitTaken: trade.PartialProfitTaken,
+		MaxBidPrice:        trade.MaxBidPrice,
+		MinBidPrice:        trade.MinBidPrice,
 	}
 }
 
-func paperBreakEvenTrigger(buyPrice, targetPrice, fee float64) float64 {
+func applyPaperExitState(trade *repo.PaperTrade, state strategy.ExitState) {
+	trade.BreakEvenArmed = state.BreakEvenArmed
+	trade.PartialProfitTaken = state.PartialProfitTaken
+	trade.MaxBidPrice = state.MaxBidPrice
+	trade.MinBidPrice = state.MinBidPrice
+}
+
+func paperBreakEvenTrigger(cfg config.StrategyConfig, buyPrice, targetPrice, fee float64) float64 {
 	trigger := buyPrice + (targetPrice-buyPrice)*0.35
-	return math.Max(trigger, paperBreakEvenBidPrice(buyPrice, fee)*1.0005)
+	return math.Max(trigger, paperBreakEvenBidPrice(cfg.Execution, buyPrice, fee)*1.0005)
 }
 
-func paperTrailingActivationPrice(buyPrice, fee float64) float64 {
-	return math.Max(buyPrice*(1+paperTrailingTrigger), paperPositiveStopBidPrice(buyPrice, fee))
+func paperTrailingActivationPrice(cfg config.StrategyConfig, buyPrice, fee float64) float64 {
+	return math.Max(buyPrice*(1+cfg.Paper.TrailingTrigger), paperPositiveStopBidPrice(cfg, buyPrice, fee))
 }
 
-func paperV7TrailingActivationPrice(buyPrice, fee float64) float64 {
-	return math.Max(buyPrice*1.004, paperPositiveStopBidPrice(buyPrice, fee))
+func paperV7TrailingActivationPrice(cfg config.StrategyConfig, buyPrice, fee float64) float64 {
+	return math.Max(buyPrice*1.004, paperPositiveStopBidPrice(cfg, buyPrice, fee))
 }
 
-func paperQuickProfitBidPrice(buyPrice, fee float64) float64 {
+func paperQuickProfitBidPrice(cfg config.PaperStrategyConfig, buyPrice, fee float64) float64 {
 	if buyPrice <= 0 || fee < 0 || fee >= 1 {
 		return math.Inf(1)
 	}
-	return buyPrice * (1 + fee) * (1 + paperQuickProfitNet) / (1 - fee)
+	return buyPrice * (1 + fee) * (1 + cfg.QuickProfitNet) / (1 - fee)
 }
 
-func paperPartialProfitQuantity(filledQuantity float64, symbol mexc.SymbolDetail) float64 {
+func paperPartialProfitQuantity(cfg config.PaperStrategyConfig, filledQuantity float64, symbol mexc.SymbolDetail) float64 {
 	step, err := swapLotStep(&symbol)
 	if err != nil {
 		return 0
 	}
-	return swapRoundQtyDown(filledQuantity*paperQuickProfitShare, step)
+	return exitPartialQuantity(cfg, filledQuantity, step)
 }
 
-func paperTrailingStopPrice(trade repo.PaperTrade, buyPrice, fee float64) float64 {
-	trailing := trade.MaxBidPrice * (1 - paperTrailingDistance)
-	return math.Max(trailing, paperPositiveStopBidPrice(buyPrice, fee))
+func paperTrailingStopPrice(cfg config.StrategyConfig, trade repo.PaperTrade, buyPrice, fee float64) float64 {
+	return exitTrailingStopPrice(cfg, paperExitState(trade), buyPrice, fee)
 }
 
-func paperPositiveStopBidPrice(buyPrice, fee float64) float64 {
+func paperPositiveStopBidPrice(cfg config.StrategyConfig, buyPrice, fee float64) float64 {
 	if buyPrice <= 0 || fee < 0 || fee >= 1 {
 		return math.Inf(1)
 	}
-	return buyPrice * (1 + fee) * (1 + paperMinimumLockedProfit) / ((1 - emergencyPriceDiscount) * (1 - fee))
+	return buyPrice * (1 + fee) * (1 + cfg.Paper.MinimumLockedProfit) / ((1 - cfg.Execution.EmergencyPriceDiscount) * (1 - fee))
 }
 
-func paperBreakEvenBidPrice(buyPrice, fee float64) float64 {
+func paperBreakEvenBidPrice(cfg config.ExecutionStrategyConfig, buyPrice, fee float64) float64 {
 	if buyPrice <= 0 || fee < 0 || fee >= 1 {
 		return math.Inf(1)
 	}
-	return buyPrice * (1 + fee) / ((1 - emergencyPriceDiscount) * (1 - fee))
+	return buyPrice * (1 + fee) / ((1 - cfg.EmergencyPriceDiscount) * (1 - fee))
 }
 
 func (u *PaperTradeRunner) persistPaperTrade(
@@ -514,6 +543,26 @@ func signalFor(signals []repo.PalisadeSignalState, symbol string) repo.PalisadeS
 	return repo.PalisadeSignalState{}
 }
 
+// paperBuyFill — исполнение бумажной покупки: по стакану все ask не дороже
+// limit со средней ценой, без стакана — верхний уровень по topPrice.
+func paperBuyFill(depth *mexc.OrderBook, remaining, limit, topPrice, topQty, lotStep float64) (qty, price float64) {
+	if depth == nil {
+		return swapRoundQtyDown(paperFillQuantity(remaining, topQty), lotStep), topPrice
+	}
+	filled, avg := depth.FillBuy(math.Max(remaining, 0), limit)
+	return swapRoundQtyDown(filled, lotStep), avg
+}
+
+// paperSellFill — исполнение бумажной продажи: по стакану все bid не дешевле
+// limit, без стакана — верхний уровень по topPrice.
+func paperSellFill(depth *mexc.OrderBook, remaining, limit, topPrice, topQty, lotStep float64) (qty, price float64) {
+	if depth == nil {
+		return swapRoundQtyDown(paperFillQuantity(remaining, topQty), lotStep), topPrice
+	}
+	filled, avg := depth.FillSell(math.Max(remaining, 0), limit)
+	return swapRoundQtyDown(filled, lotStep), avg
+}
+
 func paperFillQuantity(remaining, topLevelQuantity float64) float64 {
 	if remaining <= 0 {
 		return 0
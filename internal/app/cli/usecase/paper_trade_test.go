@@ -5,10 +5,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/drybin/palisade/internal/app/cli/config"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
 )
 
+// testStrategy — пороги по умолчанию, общие для тестов стратегии палисады.
+var testStrategy = config.DefaultStrategyConfig()
+
 func TestPaperFillQuantity_respectsTopLevelLiquidity(t *testing.T) {
 	if got := paperFillQuantity(10, 3); got != 3 {
 		t.Fatalf("expected 3, got %.8f", got)
@@ -64,6 +68,8 @@ func TestPaperFillQuantity_doesNotExceedRemaining(t *testing.T) {
 
 func TestBuildPaperTrade_v8WaitsForPullback(t *testing.T) {
 	trade, ok, err := buildPaperTrade(
+		testStrategy,
+		palisadeStrategy{cfg: testStrategy, version: paperStrategyVersion},
 		repo.PalisadeSignalState{
 			Symbol: "TESTUSDT", SupportPrice: 100, EntryPrice: 100.1,
 			TargetPrice: 102, MinExitPrice: 101, NetProfit: 0.01,
@@ -79,6 +85,9 @@ func TestBuildPaperTrade_v8WaitsForPullback(t *testing.T) {
 	if err != nil || !ok {
 		t.Fatalf("expected valid v8 paper trade, ok=%v err=%v", ok, err)
 	}
+	if trade.StrategyVersion != 8 || trade.StrategyKey != "palisade/v8" {
+		t.Fatalf("expected trade keyed palisade/v8, got v%d %q", trade.StrategyVersion, trade.StrategyKey)
+	}
 	if trade.EntryMode != "PULLBACK_RECLAIM_PARTIAL_V8" || trade.Status != "BUY_PENDING" || math.Abs(trade.SupportPrice-100) > 1e-12 || math.Abs(trade.EntryPrice-100.1) > 1e-12 {
 		t.Fatalf("unexpected v8 levels: mode=%s status=%s support=%.4f entry=%.4f", trade.EntryMode, trade.Status, trade.SupportPrice, trade.EntryPrice)
 	}
@@ -98,11 +107,11 @@ func TestPaperTrailingStopLocksPositiveNetProfit(t *testing.T) {
 		BreakEvenArmed:  true,
 		MaxBidPrice:     100.8,
 	}
-	stop := paperTrailingStopPrice(trade, buyPrice, fee)
-	if stop < paperPositiveStopBidPrice(buyPrice, fee) {
+	stop := paperTrailingStopPrice(testStrategy, trade, buyPrice, fee)
+	if stop < paperPositiveStopBidPrice(testStrategy, buyPrice, fee) {
 		t.Fatalf("expected trailing stop to preserve positive net result, got %.8f", stop)
 	}
-	if got := paperExitReason(trade, time.Now().UTC(), stop, 90, buyPrice, fee); got != "TRAILING_STOP" {
+	if got := paperExitReason(testStrategy, trade, time.Now().UTC(), stop, 90, buyPrice, fee); got != "TRAILING_STOP" {
 		t.Fatalf("expected trailing stop, got %q", got)
 	}
 }
@@ -116,13 +125,13 @@ func TestPaperEntryCancelReason_v8DistinguishesInvalidation(t *testing.T) {
 		SupportPrice:    100,
 		EntryPrice:      100.2,
 	}
-	if got := paperEntryCancelReason(trade, now, 99.6); got != "SUPPORT_BROKEN_BEFORE_ENTRY" {
+	if got := paperEntryCancelReason(testStrategy, trade, now, 99.6); got != "SUPPORT_BROKEN_BEFORE_ENTRY" {
 		t.Fatalf("expected support invalidation, got %q", got)
 	}
-	if got := paperEntryCancelReason(trade, now.Add(paperPullbackTimeout+time.Second), 100.3); got != "PULLBACK_TIMEOUT" {
+	if got := paperEntryCancelReason(testStrategy, trade, now.Add(testStrategy.Paper.PullbackTimeout+time.Second), 100.3); got != "PULLBACK_TIMEOUT" {
 		t.Fatalf("expected pullback timeout, got %q", got)
 	}
-	if got := paperEntryCancelReason(trade, now, trade.EntryPrice*(1+paperEntryRunawayPercent)+0.01); got != "ENTRY_RAN_AWAY" {
+	if got := paperEntryCancelReason(testStrategy, trade, now, trade.EntryPrice*(1+testStrategy.Paper.EntryRunaway)+0.01); got != "ENTRY_RAN_AWAY" {
 		t.Fatalf("expected runaway cancellation, got %q", got)
 	}
 }
@@ -136,23 +145,23 @@ func TestPaperEntryCancelReason_v8RejectsDeepPullback(t *testing.T) {
 		SupportPrice:    90,
 		EntryPrice:      100,
 	}
-	if got := paperEntryCancelReason(trade, now, 99.7); got != "" {
+	if got := paperEntryCancelReason(testStrategy, trade, now, 99.7); got != "" {
 		t.Fatalf("expected shallow pullback to remain active, got %q", got)
 	}
-	if got := paperEntryCancelReason(trade, now, 99.5); got != "PULLBACK_TOO_DEEP" {
+	if got := paperEntryCancelReason(testStrategy, trade, now, 99.5); got != "PULLBACK_TOO_DEEP" {
 		t.Fatalf("expected deep pullback rejection, got %q", got)
 	}
 }
 
 func TestPaperReboundConfirmed_requiresRecoveryFromObservedLow(t *testing.T) {
 	trade := repo.PaperTrade{EntryLowPrice: 100}
-	if paperReboundConfirmed(&trade, 99.8) {
+	if paperReboundConfirmed(testStrategy.Paper, &trade, 99.8) {
 		t.Fatal("expected a new low not to confirm entry")
 	}
-	if paperReboundConfirmed(&trade, 99.9) {
+	if paperReboundConfirmed(testStrategy.Paper, &trade, 99.9) {
 		t.Fatal("expected recovery below threshold not to confirm entry")
 	}
-	if !paperReboundConfirmed(&trade, 99.95) {
+	if !paperReboundConfirmed(testStrategy.Paper, &trade, 99.95) {
 		t.Fatal("expected 0.15% recovery from the low to confirm entry")
 	}
 }
@@ -160,16 +169,16 @@ func TestPaperReboundConfirmed_requiresRecoveryFromObservedLow(t *testing.T) {
 func TestPaperQuickProfitPriceCoversFees(t *testing.T) {
 	fee := 0.001
 	buyPrice := 100.0
-	sellPrice := paperQuickProfitBidPrice(buyPrice, fee)
+	sellPrice := paperQuickProfitBidPrice(testStrategy.Paper, buyPrice, fee)
 	net := sellPrice*(1-fee)/(buyPrice*(1+fee)) - 1
-	if math.Abs(net-paperQuickProfitNet) > 1e-12 {
-		t.Fatalf("expected %.6f net profit, got %.6f", paperQuickProfitNet, net)
+	if math.Abs(net-testStrategy.Paper.QuickProfitNet) > 1e-12 {
+		t.Fatalf("expected %.6f net profit, got %.6f", testStrategy.Paper.QuickProfitNet, net)
 	}
 }
 
 func TestPaperPartialProfitQuantityRoundsDownToLotStep(t *testing.T) {
 	symbol := mexc.SymbolDetail{Filters: []mexc.SymbolFilter{{FilterType: "LOT_SIZE", StepSize: "0.1"}}}
-	if got := paperPartialProfitQuantity(1.1, symbol); math.Abs(got-0.5) > 1e-12 {
+	if got := paperPartialProfitQuantity(testStrategy.Paper, 1.1, symbol); math.Abs(got-0.5) > 1e-12 {
 		t.Fatalf("expected partial quantity 0.5, got %.8f", got)
 	}
 }
@@ -200,29 +209,29 @@ func TestUpdatePaperTarget_currentStrategyNeverLowersTarget(t *testing.T) {
 func TestPaperExitReason_separatesTrailingFromLegacyBreakEven(t *testing.T) {
 	fee := 0.001
 	buyPrice := 100.0
-	breakEvenBid := paperBreakEvenBidPrice(buyPrice, fee)
+	breakEvenBid := paperBreakEvenBidPrice(testStrategy.Execution, buyPrice, fee)
 	now := time.Now().UTC()
 
 	currentTrade := repo.PaperTrade{StrategyVersion: paperStrategyVersion, BreakEvenArmed: true}
-	if got := paperExitReason(currentTrade, now, breakEvenBid, 90, buyPrice, fee); got != "TRAILING_STOP" {
+	if got := paperExitReason(testStrategy, currentTrade, now, breakEvenBid, 90, buyPrice, fee); got != "TRAILING_STOP" {
 		t.Fatalf("expected trailing stop, got %q", got)
 	}
-	if trigger := paperBreakEvenTrigger(buyPrice, 101, fee); trigger <= breakEvenBid {
+	if trigger := paperBreakEvenTrigger(testStrategy, buyPrice, 101, fee); trigger <= breakEvenBid {
 		t.Fatalf("expected trigger %.8f above break-even %.8f", trigger, breakEvenBid)
 	}
 
 	v4Trade := repo.PaperTrade{StrategyVersion: 4, BreakEvenArmed: true, SignalAt: now}
-	if got := paperExitReason(v4Trade, now, breakEvenBid, 90, buyPrice, fee); got != "BREAKEVEN_STOP" {
+	if got := paperExitReason(testStrategy, v4Trade, now, breakEvenBid, 90, buyPrice, fee); got != "BREAKEVEN_STOP" {
 		t.Fatalf("expected v4 break-even stop, got %q", got)
 	}
 
 	v6Trade := repo.PaperTrade{StrategyVersion: 6, BreakEvenArmed: true, SignalAt: now}
-	if got := paperExitReason(v6Trade, now, breakEvenBid, 90, buyPrice, fee); got != "BREAKEVEN_STOP" {
+	if got := paperExitReason(testStrategy, v6Trade, now, breakEvenBid, 90, buyPrice, fee); got != "BREAKEVEN_STOP" {
 		t.Fatalf("expected v6 break-even stop, got %q", got)
 	}
 
 	legacyTrade := repo.PaperTrade{StrategyVersion: 3, BreakEvenArmed: true, SignalAt: now}
-	if got := paperExitReason(legacyTrade, now, breakEvenBid, 90, buyPrice, fee); got != "" {
+	if got := paperExitReason(testStrategy, legacyTrade, now, breakEvenBid, 90, buyPrice, fee); got != "" {
 		t.Fatalf("expected no v3 break-even stop, got %q", got)
 	}
 }
@@ -250,3 +259,22 @@ func TestTrackPaperExcursion(t *testing.T) {
 		t.Fatalf("expected max/min 102/99, got %.4f/%.4f", trade.MaxBidPrice, trade.MinBidPrice)
 	}
 }
+
+func TestPaperFill_walksDepthWithinLimit(t *testing.T) {
+	depth := &mexc.OrderBook{
+		Bids: []mexc.BookLevel{{Price: 1.00, Qty: 4}, {Price: 0.99, Qty: 4}, {Price: 0.90, Qty: 100}},
+		Asks: []mexc.BookLevel{{Price: 1.01, Qty: 4}, {Price: 1.02, Qty: 4}, {Price: 1.10, Qty: 100}},
+	}
+	qty, price := paperBuyFill(depth, 10, 1.02, 1.01, 4, 0.1)
+	if math.Abs(qty-8) > 1e-9 || math.Abs(price-1.015) > 1e-9 {
+		t.Fatalf("expected 8 @ 1.015 from two ask levels, got %.8f @ %.8f", qty, price)
+	}
+	qty, price = paperSellFill(depth, 10, 0.99, 1.00, 4, 0.1)
+	if math.Abs(qty-8) > 1e-9 || math.Abs(price-0.995) > 1e-9 {
+		t.Fatalf("expected 8 @ 0.995 from two bid levels, got %.8f @ %.8f", qty, price)
+	}
+	qty, price = paperSellFill(nil, 10, 0.99, 1.00, 4, 0.1)
+	if math.Abs(qty-4) > 1e-9 || price != 1.00 {
+		t.Fatalf("without depth only the top level fills, got %.8f @ %.8f", qty, price)
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/model"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+// placedLimitOrder — ордер, принятый биржей, и его order intent. Price может
+// отличаться от запрошенной: post-only ордер переставляется от рынка.
+type placedLimitOrder struct {
+	intent  *repo.OrderIntent
+	orderID string
+	price   float64
+}
+
+// submitLimitOrder создаёт order intent по шаблону и выставляет по нему
+// лимитный ордер. При postOnly ордер уходит как LIMIT_MAKER; если биржа
+// отклоняет его как тейкер, intent закрывается REJECTED, а ордер с новым
+// intent переставляется на шаг цены от текущего стакана — не больше
+// Execution.PostOnlyRequeues раз.
+func (u *ExecutePalisadeSignals) submitLimitOrder(
+	ctx context.Context,
+	symbol mexc.SymbolDetail,
+	side order.Side,
+	template repo.OrderIntent,
+	postOnly bool,
+) (placedLimitOrder, error) {
+	orderType := order.LIMIT
+	if postOnly && supportsOrderType(symbol.OrderTypes, order.LIMIT_MAKER) {
+		orderType = order.LIMIT_MAKER
+	}
+	feeSaving := 0.0
+	if orderType == order.LIMIT_MAKER {
+		feeSaving = math.Max(parseDecimal(symbol.TakerCommission)-parseDecimal(symbol.MakerCommission), 0)
+	}
+
+	price := template.Price
+	for requeues := 0; ; requeues++ {
+		clientID, err := newSignalClientOrderID(side)
+		if err != nil {
+			return placedLimitOrder{}, err
+		}
+		params := template
+		params.ClientOrderID = clientID
+		params.Side = side.String()
+		params.Price = price
+		params.Status = "PLACING"
+		params.OrderType = orderType.String()
+		params.MakerFeeSaving = feeSaving
+		if side == order.SELL {
+			params.TargetPrice = price
+		}
+		intent, err := u.stateRepo.CreateOrderIntent(ctx, params)
+		if err != nil {
+			return placedLimitOrder{}, err
+		}
+		result, err := u.api.NewOrder(ctx, model.OrderParams{
+			Symbol:           symbol.Symbol,
+			Side:             side,
+			OrderType:        orderType,
+			Quantity:         params.Quantity,
+			QuoteOrderQty:    params.Quantity,
+			Price:            price,
+			NewClientOrderId: clientID,
+		})
+		if err != nil && orderType == order.LIMIT_MAKER && mexc.IsPostOnlyRejected(err) && requeues < u.strategy.Execution.PostOnlyRequeues {
+			if err := u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "REJECTED", "", 0, 0, err.Error()); err != nil {
+				return placedLimitOrder{}, err
+			}
+			next, err := u.postOnlyRequeuePrice(ctx, symbol, side, price, params.Quantity)
+			if err != nil {
+				return placedLimitOrder{}, wrap.Errorf("requeue post-only %s %s: %w", side.String(), symbol.Symbol, err)
+			}
+			fmt.Printf("%s: post-only %s %.8f пересёк стакан, переставляем на %.8f\n", symbol.Symbol, side.String(), price, next)
+			price = next
+			continue
+		}
+		if err != nil {
+			_ = u.stateRepo.UpdateOrderIntent(ctx, intent.ID, placeErrorIntentStatus(err), "", 0, 0, err.Error())
+			return placedLimitOrder{}, wrap.Errorf("place signal %s %s: %w", side.String(), symbol.Symbol, err)
+		}
+		if result == nil || result.OrderID == "" {
+			err = wrap.Errorf("empty order response")
+			_ = u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "UNKNOWN", "", 0, 0, err.Error())
+			return placedLimitOrder{}, wrap.Errorf("place signal %s %s: %w", side.String(), symbol.Symbol, err)
+		}
+		if err := u.stateRepo.UpdateOrderIntent(ctx, intent.ID, "ACKNOWLEDGED", result.OrderID, 0, 0, ""); err != nil {
+			return placedLimitOrder{}, err
+		}
+		return placedLimitOrder{intent: intent, orderID: result.OrderID, price: price}, nil
+	}
+}
+
+// postOnlyRequeuePrice — цена на шаг пассивнее лучшей встречной: BUY ниже
+// ask, SELL выше bid, но не агрессивнее прежней цены.
+func (u *ExecutePalisadeSignals) postOnlyRequeuePrice(ctx context.Context, symbol mexc.SymbolDetail, side order.Side, price, quantity float64) (float64, error) {
+	quote, err := u.getMarketQuote(ctx, symbol.Symbol)
+	if err != nil {
+		return 0, err
+	}
+	step := signalPriceStep(&symbol)
+	next := roundPriceUp(math.Max(price, quote.bid)+step, step)
+	if side == order.BUY {
+		next = roundPriceDown(math.Min(price, quote.ask)-step, step)
+	}
+	if err := validateLimitOrder(symbol, side, next, quantity); err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+func supportsOrderType(orderTypes []string, orderType order.Type) bool {
+	for _, allowed := range orderTypes {
+		if strings.EqualFold(allowed, orderType.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// printMakerFeeSavings печатает, сколько комиссии сэкономили исполненные
+// post-only ордера против тейкерской ставки.
+func (u *ExecutePalisadeSignals) printMakerFeeSavings(ctx context.Context) error {
+	savings, err := u.stateRepo.GetMakerFeeSavings(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Post-only: исполнено ордеров %d, сэкономлено комиссии %.8f USDT\n", savings.Orders, savings.FeeSavedUSDT)
+	return nil
+}
@@ -15,12 +15,12 @@ type IReconcileOrders interface {
 }
 
 type ReconcileOrders struct {
-	api       *webapi.MexcWebapi
+	api       repo.IMexcRepository
 	stateRepo repo.IStateRepository
 	telegram  *webapi.TelegramWebapi
 }
 
-func NewReconcileOrdersUsecase(api *webapi.MexcWebapi, stateRepo repo.IStateRepository, telegram *webapi.TelegramWebapi) *ReconcileOrders {
+func NewReconcileOrdersUsecase(api repo.IMexcRepository, stateRepo repo.IStateRepository, telegram *webapi.TelegramWebapi) *ReconcileOrders {
 	return &ReconcileOrders{api: api, stateRepo: stateRepo, telegram: telegram}
 }
 
@@ -54,12 +54,12 @@ func (u *ReconcileOrders) Process(ctx context.Context) error {
 }
 
 func (u *ReconcileOrders) reconcileIntent(ctx context.Context, intent repo.OrderIntent) error {
-	orderInfo, err := u.api.GetOrderQueryByClientID(intent.Symbol, intent.ClientOrderID)
+	orderInfo, err := u.api.GetOrderQueryByClientID(ctx, intent.Symbol, intent.ClientOrderID)
 	if err != nil {
 		return wrap.Errorf("find intent %s: %w", intent.ClientOrderID, err)
 	}
 	if orderInfo == nil && intent.ExchangeOrderID != "" {
-		orderInfo, err = u.api.GetOrderQuery(intent.Symbol, intent.ExchangeOrderID)
+		orderInfo, err = u.api.GetOrderQuery(ctx, intent.Symbol, intent.ExchangeOrderID)
 		if err != nil {
 			return wrap.Errorf("query intent %s by exchange id: %w", intent.ClientOrderID, err)
 		}
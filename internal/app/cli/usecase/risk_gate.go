@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/drybin/palisade/internal/domain/service"
+)
+
+// entryBlockedByRisk спрашивает RiskManager перед ордером, открывающим
+// позицию. Сработавший лимит — не ошибка запуска: причина печатается, и
+// usecase пропускает вход.
+func entryBlockedByRisk(ctx context.Context, risk *service.RiskManager, symbol string, quoteUSDT float64) (bool, error) {
+	err := risk.CheckEntry(ctx, symbol, quoteUSDT)
+	if errors.Is(err, service.ErrRiskLimit) {
+		fmt.Printf("Вход в %s заблокирован: %v\n", symbol, err)
+		return true, nil
+	}
+	return false, err
+}
@@ -10,52 +10,55 @@ import (
 	"time"
 
 	"github.com/drybin/palisade/internal/adapter/webapi"
+	"github.com/drybin/palisade/internal/app/cli/config"
 	"github.com/drybin/palisade/internal/domain/enum"
 	"github.com/drybin/palisade/internal/domain/enum/order"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/strategy"
 	"github.com/drybin/palisade/pkg/wrap"
 )
 
-const (
-	defaultSignalOrderUSDT = 10.0
-	minSignalVolume24h     = 50000.0
-	minSignalNetProfit     = 0.006
-	maxSignalEntryRange    = 0.20
-	minimumReboundPercent  = 0.001
-	signalPullbackPercent  = 0.002
-	signalTargetRangeShare = 0.40
-	maxBTCDecline30m       = 0.005
-	signalCooldown         = 60 * time.Minute
-	maxSignalCandidates    = 100
-	maxSignalsPerRun       = 3
-)
-
 type IScorePalisadeCandidates interface {
 	Process(context.Context, bool) error
 }
 
 type ScorePalisadeCandidates struct {
-	api       *webapi.MexcWebapi
-	stateRepo repo.IStateRepository
-	telegram  *webapi.TelegramWebapi
-}
-
-func NewScorePalisadeCandidatesUsecase(api *webapi.MexcWebapi, stateRepo repo.IStateRepository, telegram *webapi.TelegramWebapi) *ScorePalisadeCandidates {
-	return &ScorePalisadeCandidates{api: api, stateRepo: stateRepo, telegram: telegram}
+	api        repo.IMexcRepository
+	stateRepo  repo.IStateRepository
+	telegram   *webapi.TelegramWebapi
+	strategy   config.StrategyConfig
+	strategies *strategy.Registry
 }
 
-type palisadeSignal struct {
-	symbol, baseAsset                                 string
-	current, entry, support, resistance, minExitPrice float64
-	netProfit, spread, volume                         float64
-	touchesSupport, touchesResistance, score          int
+func NewScorePalisadeCandidatesUsecase(
+	api repo.IMexcRepository,
+	stateRepo repo.IStateRepository,
+	telegram *webapi.TelegramWebapi,
+	strategy config.StrategyConfig,
+) *ScorePalisadeCandidates {
+	return &ScorePalisadeCandidates{
+		api:        api,
+		stateRepo:  stateRepo,
+		telegram:   telegram,
+		strategy:   strategy,
+		strategies: NewStrategyRegistry(strategy),
+	}
 }
 
 func (u *ScorePalisadeCandidates) Process(ctx context.Context, debug bool) error {
 	if !u.telegram.Configured() {
 		return wrap.Errorf("telegram is not configured")
 	}
+	cfg := u.strategy.Signals
+	selected, err := u.strategies.Get(u.strategy.Algorithm)
+	if err != nil {
+		return err
+	}
+	configVersion, err := recordStrategyConfig(ctx, u.stateRepo, u.strategy)
+	if err != nil {
+		return err
+	}
 	snapshots, err := u.stateRepo.ListMarketSnapshots(ctx)
 	if err != nil {
 		return err
@@ -74,12 +77,12 @@ func (u *ScorePalisadeCandidates) Process(ctx context.Context, debug bool) error
 	if err != nil {
 		return wrap.Errorf("get BTC market regime: %w", err)
 	}
-	if !isBTCMarketSafe(*btcKlines, time.Now().UTC()) {
-		fmt.Println("Новые сигналы пропущены: BTC снизился более чем на 0.5% за последние 30 минут")
+	if !isBTCMarketSafe(cfg, *btcKlines, time.Now().UTC()) {
+		fmt.Printf("Новые сигналы пропущены: BTC снизился более чем на %.2f%% за последние 30 минут\n", cfg.MaxBTCDecline30m*100)
 		return nil
 	}
 
-	candidates := make([]palisadeSignal, 0, maxSignalCandidates)
+	candidates := make([]strategy.Entry, 0, cfg.MaxCandidates)
 	klinesChecked := 0
 	for _, snapshot := range snapshots {
 		if snapshot.CollectedAt.IsZero() || time.Since(snapshot.CollectedAt) > 5*time.Minute {
@@ -88,7 +91,7 @@ func (u *ScorePalisadeCandidates) Process(ctx context.Context, debug bool) error
 			}
 			continue
 		}
-		if snapshot.QuoteVolume24h < minSignalVolume24h || snapshot.BidPrice <= 0 || snapshot.AskPrice <= snapshot.BidPrice {
+		if snapshot.QuoteVolume24h < cfg.MinVolume24h || snapshot.BidPrice <= 0 || snapshot.AskPrice <= snapshot.BidPrice {
 			continue
 		}
 		symbol, ok := bySymbol[snapshot.Symbol]
@@ -107,60 +110,93 @@ func (u *ScorePalisadeCandidates) Process(ctx context.Context, debug bool) error
 			}
 			continue
 		}
-		signal, ok := buildPalisadeSignal(snapshot, symbol, *klines, time.Now().UTC())
-		if ok && isExecutablePalisadeSignal(signal, symbol) {
-			candidates = append(candidates, signal)
+		market := strategy.MarketContext{Snapshot: snapshot, Symbol: symbol, Klines: *klines, Now: time.Now().UTC()}
+		signal, ok := selected.EvaluateEntry(market)
+		if !ok {
+			continue
+		}
+		// Стакан запрашивается только для пар, где вход нашёлся без него.
+		if cfg.DepthLimit > 0 {
+			market.Depth, err = u.api.GetDepth(ctx, snapshot.Symbol, cfg.DepthLimit)
+			if err != nil {
+				if debug {
+					fmt.Printf("%s: стакан: %v\n", snapshot.Symbol, err)
+				}
+				continue
+			}
+			if signal, ok = selected.EvaluateEntry(market); !ok {
+				continue
+			}
+		}
+		if cfg.TapeWindow > 0 {
+			tape, err := u.loadTape(ctx, snapshot.Symbol, cfg)
+			if err != nil {
+				if debug {
+					fmt.Printf("%s: лента: %v\n", snapshot.Symbol, err)
+				}
+				continue
+			}
+			if !isTapeConfirmed(cfg, tape) {
+				if debug {
+					fmt.Printf("%s: лента не подтверждает отскок: сделок/мин=%.1f дисбаланс=%+.2f крупные=+%d/-%d\n",
+						snapshot.Symbol, tape.tradesPerMinute, tape.imbalance, tape.largeBuys, tape.largeSells)
+				}
+				continue
+			}
+			signal.Score += (tape.largeBuys - tape.largeSells) * 10
 		}
-		if len(candidates) >= maxSignalCandidates {
+		candidates = append(candidates, signal)
+		if len(candidates) >= cfg.MaxCandidates {
 			break
 		}
 	}
 
-	sort.Slice(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
 	fmt.Printf("Snapshots загружено: %d\nСвечи запрошены для: %d пар\nОтобрано кандидатов: %d\n", len(snapshots), klinesChecked, len(candidates))
 	for i, candidate := range candidates {
 		fmt.Printf("%2d. %-16s цена=%-12s вход=%-12s цель=%-12s net=%.2f%% объём=%.0f спред=%.3f%% касания=S%d/R%d score=%d\n",
-			i+1, candidate.symbol, formatPrice(candidate.current), formatPrice(candidate.entry), formatPrice(candidate.resistance),
-			candidate.netProfit*100, candidate.volume, candidate.spread*100, candidate.touchesSupport, candidate.touchesResistance, candidate.score)
+			i+1, candidate.Symbol, formatPrice(candidate.Current), formatPrice(candidate.EntryPrice), formatPrice(candidate.Target),
+			candidate.NetProfit*100, candidate.Volume, candidate.Spread*100, candidate.TouchesSupport, candidate.TouchesResistance, candidate.Score)
 	}
 	sent := 0
 	now := time.Now().UTC()
 	for _, candidate := range candidates {
-		lastSent, err := u.stateRepo.GetLastPalisadeSignal(ctx, candidate.symbol)
+		lastSent, err := u.stateRepo.GetLastPalisadeSignal(ctx, candidate.Symbol)
 		if err != nil {
 			return err
 		}
 		state := repo.PalisadeSignalState{
-			Symbol:          candidate.symbol,
-			StrategyVersion: paperStrategyVersion,
-			SupportPrice:    candidate.support,
-			EntryPrice:      candidate.entry,
-			TargetPrice:     candidate.resistance,
-			MinExitPrice:    candidate.minExitPrice,
-			NetProfit:       candidate.netProfit,
-			Score:           candidate.score,
+			Symbol:          candidate.Symbol,
+			StrategyVersion: selected.Version(),
+			SupportPrice:    candidate.Support,
+			EntryPrice:      candidate.EntryPrice,
+			TargetPrice:     candidate.Target,
+			MinExitPrice:    candidate.MinExitPrice,
+			NetProfit:       candidate.NetProfit,
+			Score:           candidate.Score,
 			Status:          "ACTIVE",
 			ValidUntil:      now.Add(30 * time.Minute),
 			UpdatedAt:       now,
+			ConfigVersion:   configVersion,
 		}
-		if lastSent != nil && now.Sub(*lastSent) < signalCooldown {
+		if lastSent != nil && now.Sub(*lastSent) < cfg.Cooldown {
 			if err := u.stateRepo.SavePalisadeSignalState(ctx, state); err != nil {
-				return wrap.Errorf("update signal state %s: %w", candidate.symbol, err)
+				return wrap.Errorf("update signal state %s: %w", candidate.Symbol, err)
 			}
 			continue
 		}
-		message := formatPalisadeSignal(candidate, now)
+		message := formatPalisadeSignal(cfg, selected.Version(), candidate, now)
 		if _, err := u.telegram.Send(message); err != nil {
-			return wrap.Errorf("send signal %s: %w", candidate.symbol, err)
+			return wrap.Errorf("send signal %s: %w", candidate.Symbol, err)
 		}
-		if err := u.stateRepo.SavePalisadeSignal(ctx, candidate.symbol, now, float64(candidate.score)); err != nil {
-			return wrap.Errorf("save signal %s: %w", candidate.symbol, err)
+		if err := u.stateRepo.SavePalisadeSignal(ctx, candidate.Symbol, now, float64(candidate.Score)); err != nil {
+			return wrap.Errorf("save signal %s: %w", candidate.Symbol, err)
 		}
 		if err := u.stateRepo.SavePalisadeSignalState(ctx, state); err != nil {
-			return wrap.Errorf("save signal state %s: %w", candidate.symbol, err)
+			return wrap.Errorf("save signal state %s: %w", candidate.Symbol, err)
 		}
 		sent++
-		if sent >= maxSignalsPerRun {
+		if sent >= cfg.MaxPerRun {
 			break
 		}
 	}
@@ -169,7 +205,21 @@ func (u *ScorePalisadeCandidates) Process(ctx context.Context, debug bool) error
 	return nil
 }
 
-func buildPalisadeSignal(snapshot repo.MarketSnapshot, symbol mexc.SymbolDetail, klines mexc.Klines, now time.Time) (palisadeSignal, bool) {
+// loadTape догружает ленту символа за TapeWindow и считает по ней метрики.
+func (u *ScorePalisadeCandidates) loadTape(ctx context.Context, symbol string, cfg config.SignalStrategyConfig) (tapeMetrics, error) {
+	now := time.Now().UTC()
+	since := now.Add(-cfg.TapeWindow)
+	if _, err := syncTradeTape(ctx, u.api, u.stateRepo, symbol, since, now); err != nil {
+		return tapeMetrics{}, err
+	}
+	trades, err := u.stateRepo.ListMarketTradesSince(ctx, symbol, since)
+	if err != nil {
+		return tapeMetrics{}, err
+	}
+	return computeTapeMetrics(trades, cfg.TapeWindow, cfg.LargePrintMultiplier), nil
+}
+
+func buildPalisadeSignal(cfg config.SignalStrategyConfig, snapshot repo.MarketSnapshot, symbol mexc.SymbolDetail, klines mexc.Klines, now time.Time) (strategy.Entry, bool) {
 	closed := make(mexc.Klines, 0, len(klines))
 	for _, kline := range klines {
 		if kline.CloseTime > 0 && time.UnixMilli(kline.CloseTime).UTC().Before(now) {
@@ -177,7 +227,7 @@ func buildPalisadeSignal(snapshot repo.MarketSnapshot, symbol mexc.SymbolDetail,
 		}
 	}
 	if len(closed) < 48 {
-		return palisadeSignal{}, false
+		return strategy.Entry{}, false
 	}
 	if len(closed) > 96 {
 		closed = closed[len(closed)-96:]
@@ -196,27 +246,27 @@ func buildPalisadeSignal(snapshot repo.MarketSnapshot, symbol mexc.SymbolDetail,
 		}
 	}
 	if len(lows) < 48 {
-		return palisadeSignal{}, false
+		return strategy.Entry{}, false
 	}
 	sort.Float64s(lows)
 	sort.Float64s(highs)
 	support := percentileValue(lows, 0.10)
 	resistance := percentileValue(highs, 0.90)
 	if support <= 0 || resistance <= support {
-		return palisadeSignal{}, false
+		return strategy.Entry{}, false
 	}
 	rangeValue := resistance - support
 	if snapshot.BidPrice < support {
-		return palisadeSignal{}, false
+		return strategy.Entry{}, false
 	}
 	current := snapshot.AskPrice
-	if current < support || current > support+rangeValue*maxSignalEntryRange {
-		return palisadeSignal{}, false
+	if current < support || current > support+rangeValue*cfg.MaxEntryRange {
+		return strategy.Entry{}, false
 	}
 	first := closed[0].Close
 	last := closed[len(closed)-1].Close
 	if first <= 0 || math.Abs((last-first)/first) > 0.015 {
-		return palisadeSignal{}, false
+		return strategy.Entry{}, false
 	}
 	tolerance := math.Max(rangeValue*0.12, support*0.0015)
 	supportTouches, resistanceTouches := 0, 0
@@ -229,61 +279,76 @@ func buildPalisadeSignal(snapshot repo.MarketSnapshot, symbol mexc.SymbolDetail,
 		}
 	}
 	if supportTouches < 2 || resistanceTouches < 2 {
-		return palisadeSignal{}, false
+		return strategy.Entry{}, false
 	}
 	touchCandle := rangeKlines[len(rangeKlines)-2]
 	confirmationCandle := rangeKlines[len(rangeKlines)-1]
-	if touchCandle.Low > support+tolerance || touchCandle.Close < support*(1+minimumReboundPercent) {
-		return palisadeSignal{}, false
+	if touchCandle.Low > support+tolerance || touchCandle.Close < support*(1+cfg.MinRebound) {
+		return strategy.Entry{}, false
 	}
 	if confirmationCandle.Close <= confirmationCandle.Open || confirmationCandle.Close <= touchCandle.Close {
-		return palisadeSignal{}, false
+		return strategy.Entry{}, false
 	}
 	makerFee := parseDecimal(symbol.MakerCommission)
 	takerFee := parseDecimal(symbol.TakerCommission)
 	fee := math.Max(makerFee, takerFee)
 	confirmationPeak := math.Max(current, math.Max(touchCandle.High, confirmationCandle.High))
-	entry := math.Max(confirmationPeak*(1-signalPullbackPercent), support*(1+minimumReboundPercent))
-	if entry > support+rangeValue*maxSignalEntryRange {
-		return palisadeSignal{}, false
+	entry := math.Max(confirmationPeak*(1-cfg.Pullback), support*(1+cfg.MinRebound))
+	if entry > support+rangeValue*cfg.MaxEntryRange {
+		return strategy.Entry{}, false
 	}
 	spread := (snapshot.AskPrice - snapshot.BidPrice) / snapshot.BidPrice
-	target, minExitPrice, ok := calculateDynamicTarget(entry, resistance, fee, spread)
+	target, minExitPrice, ok := calculateDynamicTarget(cfg, entry, resistance, fee, spread)
 	if !ok {
-		return palisadeSignal{}, false
+		return strategy.Entry{}, false
 	}
 	netProfit := target/entry - 1 - 2*fee - 0.001 - spread
-	if netProfit < minSignalNetProfit {
-		return palisadeSignal{}, false
+	if netProfit < cfg.MinNetProfit {
+		return strategy.Entry{}, false
 	}
 	score := int(netProfit*10000) + supportTouches*10 + resistanceTouches*10 - int(math.Abs((last-first)/first)*1000)
-	return palisadeSignal{
-		symbol: symbol.Symbol, baseAsset: symbol.BaseAsset, current: current, entry: entry, support: support,
-		resistance: target, minExitPrice: minExitPrice, netProfit: netProfit, spread: spread,
-		volume: snapshot.QuoteVolume24h, touchesSupport: supportTouches, touchesResistance: resistanceTouches, score: score,
+	return strategy.Entry{
+		Symbol: symbol.Symbol, BaseAsset: symbol.BaseAsset, Current: current, EntryPrice: entry, Support: support,
+		Target: target, MinExitPrice: minExitPrice, NetProfit: netProfit, Spread: spread,
+		Volume: snapshot.QuoteVolume24h, TouchesSupport: supportTouches, TouchesResistance: resistanceTouches, Score: score,
 	}, true
 }
 
-func isExecutablePalisadeSignal(signal palisadeSignal, symbol mexc.SymbolDetail) bool {
+// isExecutablePalisadeSignal проверяет ограничения биржи на ордер сигнала и,
+// если передан стакан, его глубину: аварийная продажа объёма сигнала по bid
+// укладывается в MaxSlippage, а заявок внутри support…resistance не меньше
+// MinRangeLiquidityUSDT. Без стакана (бэктест, DepthLimit=0) глубина не
+// проверяется.
+func isExecutablePalisadeSignal(cfg config.SignalStrategyConfig, signal strategy.Entry, symbol mexc.SymbolDetail, depth *mexc.OrderBook) bool {
 	step, err := swapLotStep(&symbol)
 	if err != nil {
 		return false
 	}
-	entry := roundPriceDown(signal.entry, signalPriceStep(&symbol))
-	quantity := swapRoundQtyDown(defaultSignalOrderUSDT/entry, step)
-	return quantity > 0 && isValidPaperOrder(symbol, order.BUY, entry, quantity)
+	entry := roundPriceDown(signal.EntryPrice, signalPriceStep(&symbol))
+	quantity := swapRoundQtyDown(cfg.OrderQuoteUSDT/entry, step)
+	if quantity <= 0 || !isValidPaperOrder(symbol, order.BUY, entry, quantity) {
+		return false
+	}
+	if depth == nil {
+		return true
+	}
+	slippage, ok := depth.SellSlippage(quantity)
+	if !ok || slippage > cfg.MaxSlippage {
+		return false
+	}
+	return depth.LiquidityInRange(signal.Support, signal.Target) >= cfg.MinRangeLiquidityUSDT
 }
 
-func calculateDynamicTarget(entry, resistance, fee, spread float64) (target, minExitPrice float64, ok bool) {
+func calculateDynamicTarget(cfg config.SignalStrategyConfig, entry, resistance, fee, spread float64) (target, minExitPrice float64, ok bool) {
 	if entry <= 0 || resistance <= entry || fee < 0 || spread < 0 {
 		return 0, 0, false
 	}
-	target = entry + (resistance-entry)*signalTargetRangeShare
-	minExitPrice = entry * (1 + 2*fee + 0.001 + spread + minSignalNetProfit)
+	target = entry + (resistance-entry)*cfg.TargetRangeShare
+	minExitPrice = entry * (1 + 2*fee + 0.001 + spread + cfg.MinNetProfit)
 	return target, minExitPrice, target >= minExitPrice
 }
 
-func isBTCMarketSafe(klines mexc.Klines, now time.Time) bool {
+func isBTCMarketSafe(cfg config.SignalStrategyConfig, klines mexc.Klines, now time.Time) bool {
 	closed := make(mexc.Klines, 0, len(klines))
 	for _, kline := range klines {
 		if kline.Close > 0 && kline.CloseTime > 0 && time.UnixMilli(kline.CloseTime).UTC().Before(now) {
@@ -295,7 +360,7 @@ func isBTCMarketSafe(klines mexc.Klines, now time.Time) bool {
 	}
 	start := closed[len(closed)-3].Close
 	end := closed[len(closed)-1].Close
-	return start > 0 && end/start-1 >= -maxBTCDecline30m
+	return start > 0 && end/start-1 >= -cfg.MaxBTCDecline30m
 }
 
 func percentileValue(values []float64, percentile float64) float64 {
@@ -320,18 +385,18 @@ func parseDecimal(value string) float64 {
 	return parsed
 }
 
-func formatPalisadeSignal(signal palisadeSignal, now time.Time) string {
+func formatPalisadeSignal(cfg config.SignalStrategyConfig, version int, signal strategy.Entry, now time.Time) string {
 	return fmt.Sprintf(
-		"<b>📊 Палисада-кандидат v8</b> %s (%s)\n"+
+		"<b>📊 Палисада-кандидат v%d</b> %s (%s)\n"+
 			"Цена: %s\nВход: %s | Цель: %s\n"+
 			"Net-прибыль: %.2f%%\nКасания: S=%d, R=%d\n"+
 			"Расчётный объём: %.2f USDT\n"+
 			"24h оборот: %.0f USDT | Спред: %.3f%%\n"+
 			"Score: %d\nАктуально до: %s\n"+
 			"<i>Dry-run: ордера не размещаются</i>",
-		signal.symbol, signal.baseAsset, formatPrice(signal.current), formatPrice(signal.entry), formatPrice(signal.resistance),
-		signal.netProfit*100, signal.touchesSupport, signal.touchesResistance, defaultSignalOrderUSDT, signal.volume, signal.spread*100,
-		signal.score, now.Add(30*time.Minute).Format("2006-01-02 15:04:05 MST"),
+		version, signal.Symbol, signal.BaseAsset, formatPrice(signal.Current), formatPrice(signal.EntryPrice), formatPrice(signal.Target),
+		signal.NetProfit*100, signal.TouchesSupport, signal.TouchesResistance, cfg.OrderQuoteUSDT, signal.Volume, signal.Spread*100,
+		signal.Score, now.Add(30*time.Minute).Format("2006-01-02 15:04:05 MST"),
 	)
 }
 
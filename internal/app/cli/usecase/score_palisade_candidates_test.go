@@ -6,6 +6,7 @@ import (
 
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/strategy"
 )
 
 func TestBuildPalisadeSignal_requiresStableRangeAndNetProfit(t *testing.T) {
@@ -46,21 +47,21 @@ func TestBuildPalisadeSignal_requiresStableRangeAndNetProfit(t *testing.T) {
 	}
 	symbol := mexc.SymbolDetail{Symbol: "TESTUSDT", BaseAsset: "TEST", MakerCommission: "0.0005", TakerCommission: "0.0005"}
 
-	signal, ok := buildPalisadeSignal(snapshot, symbol, klines, now)
+	signal, ok := buildPalisadeSignal(testStrategy.Signals, snapshot, symbol, klines, now)
 	if !ok {
 		t.Fatal("expected stable range to produce a signal")
 	}
-	if signal.netProfit < minSignalNetProfit {
-		t.Fatalf("expected net profit >= %.4f, got %.4f", minSignalNetProfit, signal.netProfit)
+	if signal.NetProfit < testStrategy.Signals.MinNetProfit {
+		t.Fatalf("expected net profit >= %.4f, got %.4f", testStrategy.Signals.MinNetProfit, signal.NetProfit)
 	}
-	if signal.current != snapshot.AskPrice {
-		t.Fatalf("expected current ask %.4f, got %.4f", snapshot.AskPrice, signal.current)
+	if signal.Current != snapshot.AskPrice {
+		t.Fatalf("expected current ask %.4f, got %.4f", snapshot.AskPrice, signal.Current)
 	}
-	if signal.support >= signal.entry || signal.entry >= signal.current {
-		t.Fatalf("expected pullback entry between support and current price, support=%.4f entry=%.4f current=%.4f", signal.support, signal.entry, signal.current)
+	if signal.Support >= signal.EntryPrice || signal.EntryPrice >= signal.Current {
+		t.Fatalf("expected pullback entry between support and current price, support=%.4f entry=%.4f current=%.4f", signal.Support, signal.EntryPrice, signal.Current)
 	}
-	if signal.touchesSupport < 2 || signal.touchesResistance < 2 {
-		t.Fatalf("expected repeated touches, got support=%d resistance=%d", signal.touchesSupport, signal.touchesResistance)
+	if signal.TouchesSupport < 2 || signal.TouchesResistance < 2 {
+		t.Fatalf("expected repeated touches, got support=%d resistance=%d", signal.TouchesSupport, signal.TouchesResistance)
 	}
 }
 
@@ -71,11 +72,11 @@ func TestIsBTCMarketSafe_rejectsSharpThirtyMinuteDecline(t *testing.T) {
 		{Close: 99.8, CloseTime: now.Add(-16 * time.Minute).UnixMilli()},
 		{Close: 99.4, CloseTime: now.Add(-time.Minute).UnixMilli()},
 	}
-	if isBTCMarketSafe(klines, now) {
+	if isBTCMarketSafe(testStrategy.Signals, klines, now) {
 		t.Fatal("expected a 0.6% BTC decline to block new signals")
 	}
 	klines[2].Close = 99.6
-	if !isBTCMarketSafe(klines, now) {
+	if !isBTCMarketSafe(testStrategy.Signals, klines, now) {
 		t.Fatal("expected a 0.4% BTC decline to allow new signals")
 	}
 }
@@ -102,7 +103,7 @@ func TestBuildPalisadeSignal_requiresLastClosedCandleRebound(t *testing.T) {
 
 	snapshot := repo.MarketSnapshot{Symbol: "NOREBOUNDUSDT", BidPrice: 100.2, AskPrice: 100.3, QuoteVolume24h: 100000}
 	symbol := mexc.SymbolDetail{Symbol: "NOREBOUNDUSDT", MakerCommission: "0", TakerCommission: "0"}
-	if _, ok := buildPalisadeSignal(snapshot, symbol, klines, now); ok {
+	if _, ok := buildPalisadeSignal(testStrategy.Signals, snapshot, symbol, klines, now); ok {
 		t.Fatal("expected a range without a bullish confirmation candle to be rejected")
 	}
 }
@@ -122,7 +123,7 @@ func TestBuildPalisadeSignal_rejectsTrend(t *testing.T) {
 
 	snapshot := repo.MarketSnapshot{Symbol: "TRENDUSDT", BidPrice: 100, AskPrice: 100.1, QuoteVolume24h: 100000}
 	symbol := mexc.SymbolDetail{Symbol: "TRENDUSDT", MakerCommission: "0", TakerCommission: "0"}
-	if _, ok := buildPalisadeSignal(snapshot, symbol, klines, now); ok {
+	if _, ok := buildPalisadeSignal(testStrategy.Signals, snapshot, symbol, klines, now); ok {
 		t.Fatal("expected trending market to be rejected")
 	}
 }
@@ -145,13 +146,13 @@ func TestBuildPalisadeSignal_rejectsPriceBelowSupport(t *testing.T) {
 
 	snapshot := repo.MarketSnapshot{Symbol: "BELOWUSDT", BidPrice: 98, AskPrice: 98.1, QuoteVolume24h: 100000}
 	symbol := mexc.SymbolDetail{Symbol: "BELOWUSDT", MakerCommission: "0", TakerCommission: "0"}
-	if _, ok := buildPalisadeSignal(snapshot, symbol, klines, now); ok {
+	if _, ok := buildPalisadeSignal(testStrategy.Signals, snapshot, symbol, klines, now); ok {
 		t.Fatal("expected price below support to be rejected")
 	}
 }
 
 func TestCalculateDynamicTarget_rejectsTargetBelowMinimumExit(t *testing.T) {
-	target, minExit, ok := calculateDynamicTarget(100, 101, 0.001, 0.003)
+	target, minExit, ok := calculateDynamicTarget(testStrategy.Signals, 100, 101, 0.001, 0.003)
 	if ok {
 		t.Fatal("expected target below minimum exit to be rejected")
 	}
@@ -161,7 +162,7 @@ func TestCalculateDynamicTarget_rejectsTargetBelowMinimumExit(t *testing.T) {
 }
 
 func TestCalculateDynamicTarget_acceptsTargetWithMinimumProfit(t *testing.T) {
-	target, minExit, ok := calculateDynamicTarget(100, 103, 0.0005, 0.001)
+	target, minExit, ok := calculateDynamicTarget(testStrategy.Signals, 100, 103, 0.0005, 0.001)
 	if !ok {
 		t.Fatal("expected target with minimum profit to be accepted")
 	}
@@ -172,3 +173,36 @@ func TestCalculateDynamicTarget_acceptsTargetWithMinimumProfit(t *testing.T) {
 		t.Fatalf("expected target at 40%% of range, got %.4f", target)
 	}
 }
+
+func TestIsExecutablePalisadeSignal_checksDepth(t *testing.T) {
+	symbol := backtestTestSymbol()
+	signal := strategy.Entry{EntryPrice: 1.0, Support: 0.98, Target: 1.03}
+
+	if !isExecutablePalisadeSignal(testStrategy.Signals, signal, symbol, nil) {
+		t.Fatal("expected signal without depth to pass exchange limits only")
+	}
+
+	deep := &mexc.OrderBook{
+		Bids: []mexc.BookLevel{{Price: 0.999, Qty: 100}, {Price: 0.998, Qty: 100}},
+		Asks: []mexc.BookLevel{{Price: 1.001, Qty: 100}},
+	}
+	if !isExecutablePalisadeSignal(testStrategy.Signals, signal, symbol, deep) {
+		t.Fatal("expected deep book to be executable")
+	}
+
+	thin := &mexc.OrderBook{
+		Bids: []mexc.BookLevel{{Price: 0.999, Qty: 2}, {Price: 0.95, Qty: 1000}},
+		Asks: []mexc.BookLevel{{Price: 1.001, Qty: 100}},
+	}
+	if isExecutablePalisadeSignal(testStrategy.Signals, signal, symbol, thin) {
+		t.Fatal("expected slippage through the thin bid side to reject the signal")
+	}
+
+	empty := &mexc.OrderBook{
+		Bids: []mexc.BookLevel{{Price: 0.999, Qty: 20}},
+		Asks: []mexc.BookLevel{{Price: 1.001, Qty: 20}},
+	}
+	if isExecutablePalisadeSignal(testStrategy.Signals, signal, symbol, empty) {
+		t.Fatal("expected range without liquidity to reject the signal")
+	}
+}
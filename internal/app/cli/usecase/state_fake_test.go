@@ -0,0 +1,573 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+// memState — in-memory IStateRepository для сквозных тестов с симулятором
+// биржи. Реализует только то, что нужно торговым usecase'ам; остальные
+// методы паникуют через nil-интерфейс.
+type memState struct {
+	repo.IStateRepository
+
+	mu      sync.Mutex
+	locks   map[string]bool
+	intents []repo.OrderIntent
+	trades  []repo.TradeLog
+	signals []repo.PalisadeSignalState
+	runs    []repo.BacktestRun
+
+	snapshots  []repo.MarketSnapshot
+	daemonRuns []repo.DaemonJobRun
+	tape       []repo.MarketTrade
+	brackets   map[int]repo.OrderBracket
+	exitStates map[int]repo.TradeExitState
+	coins      map[string]mexc.SymbolDetail
+	grids      []repo.Grid
+	gridLevels map[[2]int]repo.GridLevel
+	swapRuns   []repo.SwapRun
+	swapLegs   map[[2]int]repo.SwapLeg
+	swapOpps   []repo.SwapOpportunity
+	swapStock  map[string]repo.SwapInventory
+}
+
+func newMemState() *memState {
+	return &memState{
+		locks:      map[string]bool{},
+		brackets:   map[int]repo.OrderBracket{},
+		exitStates: map[int]repo.TradeExitState{},
+		coins:      map[string]mexc.SymbolDetail{},
+		gridLevels: map[[2]int]repo.GridLevel{},
+		swapLegs:   map[[2]int]repo.SwapLeg{},
+		swapStock:  map[string]repo.SwapInventory{},
+	}
+}
+
+func (s *memState) TryAcquireTradingLock(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locks[key] {
+		return false, nil
+	}
+	s.locks[key] = true
+	return true, nil
+}
+
+func (s *memState) ReleaseTradingLock(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.locks, key)
+	return nil
+}
+
+func (s *memState) ListActivePalisadeSignals(context.Context) ([]repo.PalisadeSignalState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]repo.PalisadeSignalState(nil), s.signals...), nil
+}
+
+func (s *memState) CreateOrderIntent(_ context.Context, intent repo.OrderIntent) (*repo.OrderIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	intent.ID = len(s.intents) + 1
+	s.intents = append(s.intents, intent)
+	return &intent, nil
+}
+
+func (s *memState) UpdateOrderIntent(_ context.Context, id int, status, exchangeOrderID string, executed, quote float64, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	intent := &s.intents[id-1]
+	intent.Status = status
+	if exchangeOrderID != "" {
+		intent.ExchangeOrderID = exchangeOrderID
+	}
+	intent.ExecutedQuantity = executed
+	intent.CumulativeQuoteQty = quote
+	intent.LastError = lastError
+	intent.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+func (s *memState) UpdateOrderIntentTradeID(_ context.Context, id, tradeID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.intents[id-1].TradeID = tradeID
+	return nil
+}
+
+func (s *memState) ListRecoverableOrderIntents(context.Context) ([]repo.OrderIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.OrderIntent{}
+	for _, intent := range s.intents {
+		switch intent.Status {
+		case "PLACING", "UNKNOWN", "ACKNOWLEDGED", "RECOVERY_REQUIRED":
+			out = append(out, intent)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (s *memState) ListOrderIntentsByTradeID(_ context.Context, tradeID int) ([]repo.OrderIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.OrderIntent{}
+	for _, intent := range s.intents {
+		if intent.TradeID == tradeID {
+			out = append(out, intent)
+		}
+	}
+	return out, nil
+}
+
+func (s *memState) GetMakerFeeSavings(context.Context) (repo.MakerFeeSavings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out repo.MakerFeeSavings
+	for _, intent := range s.intents {
+		if intent.OrderType == "LIMIT_MAKER" && intent.ExecutedQuantity > 0 {
+			out.Orders++
+			out.FeeSavedUSDT += intent.CumulativeQuoteQty * intent.MakerFeeSaving
+		}
+	}
+	return out, nil
+}
+
+func (s *memState) SaveOrderBracket(_ context.Context, bracket repo.OrderBracket) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.brackets[bracket.TradeID]; ok {
+		bracket.CreatedAt = prev.CreatedAt
+	}
+	s.brackets[bracket.TradeID] = bracket
+	return nil
+}
+
+func (s *memState) ListActiveOrderBrackets(context.Context) ([]repo.OrderBracket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.OrderBracket{}
+	for _, bracket := range s.brackets {
+		if bracket.Status == repo.OrderBracketActive {
+			out = append(out, bracket)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TradeID < out[j].TradeID })
+	return out, nil
+}
+
+func (s *memState) UpdateOrderBracketStatus(_ context.Context, tradeID int, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if bracket, ok := s.brackets[tradeID]; ok {
+		bracket.Status = status
+		s.brackets[tradeID] = bracket
+	}
+	return nil
+}
+
+func (s *memState) GetTradeExitState(_ context.Context, tradeID int) (*repo.TradeExitState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.exitStates[tradeID]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func (s *memState) SaveTradeExitState(_ context.Context, state repo.TradeExitState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.exitStates[state.TradeID]; ok {
+		state.StrategyVersion, state.StrategyKey = prev.StrategyVersion, prev.StrategyKey
+		state.Fee, state.LotStep = prev.Fee, prev.LotStep
+	}
+	s.exitStates[state.TradeID] = state
+	return nil
+}
+
+func (s *memState) SaveTradeLog(_ context.Context, params repo.SaveTradeLogParams) (*repo.TradeLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trade := repo.TradeLog{
+		ID:          len(s.trades) + 1,
+		OpenDate:    params.OpenDate,
+		OpenBalance: params.OpenBalance,
+		Symbol:      params.Symbol,
+		BuyPrice:    params.BuyPrice,
+		Amount:      params.Amount,
+		OrderId:     params.OrderId,
+		UpLevel:     params.UpLevel,
+		DownLevel:   params.DownLevel,
+	}
+	s.trades = append(s.trades, trade)
+	return &trade, nil
+}
+
+func (s *memState) GetOpenOrders(context.Context) ([]repo.TradeLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.TradeLog{}
+	for _, trade := range s.trades {
+		if trade.CloseDate == nil && trade.CancelDate == nil {
+			out = append(out, trade)
+		}
+	}
+	return out, nil
+}
+
+// Ручных сделок в сквозных тестах нет.
+func (s *memState) GetOpenOrdersManual(context.Context) ([]repo.TradeLog, error) {
+	return nil, nil
+}
+
+func (s *memState) UpdateTradeLevels(_ context.Context, id int, upLevel, downLevel float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades[id-1].UpLevel = upLevel
+	s.trades[id-1].DownLevel = downLevel
+	return nil
+}
+
+func (s *memState) UpdateTradeFill(_ context.Context, id int, buyPrice, amount float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades[id-1].BuyPrice = buyPrice
+	s.trades[id-1].Amount = amount
+	return nil
+}
+
+func (s *memState) UpdateDealDateTradeLog(_ context.Context, id int, dealDate time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades[id-1].DealDate = &dealDate
+	return nil
+}
+
+func (s *memState) UpdateCancelDateTradeLog(_ context.Context, id int, cancelDate time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades[id-1].CancelDate = &cancelDate
+	return nil
+}
+
+func (s *memState) UpdateSellOrderIdTradeLog(_ context.Context, id int, orderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades[id-1].OrderId_sell = orderID
+	return nil
+}
+
+func (s *memState) UpdateSuccesTradeLog(_ context.Context, id int, closeDate time.Time, closeBalance, sellPrice float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades[id-1].CloseDate = &closeDate
+	s.trades[id-1].CloseBalance = closeBalance
+	s.trades[id-1].SellPrice = sellPrice
+	return nil
+}
+
+func (s *memState) SaveBacktestRun(_ context.Context, run repo.BacktestRun) (*repo.BacktestRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run.ID = len(s.runs) + 1
+	s.runs = append(s.runs, run)
+	return &run, nil
+}
+
+func (s *memState) UpsertMarketSnapshots(_ context.Context, snapshots []repo.MarketSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots = append(s.snapshots, snapshots...)
+	return nil
+}
+
+func (s *memState) ListMarketSnapshots(context.Context) ([]repo.MarketSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]repo.MarketSnapshot(nil), s.snapshots...), nil
+}
+
+// SaveMarketTrades повторяет первичный ключ market_trade: сделка с теми же
+// временем, ценой, объёмом, стороной и номером не сохраняется второй раз.
+func (s *memState) SaveMarketTrades(_ context.Context, trades []repo.MarketTrade) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, trade := range trades {
+		duplicate := false
+		for _, saved := range s.tape {
+			if saved == trade {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			s.tape = append(s.tape, trade)
+		}
+	}
+	return nil
+}
+
+func (s *memState) GetLastMarketTradeTime(_ context.Context, symbol string) (*time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var last *time.Time
+	for _, trade := range s.tape {
+		if trade.Symbol == symbol && (last == nil || trade.TradedAt.After(*last)) {
+			t := trade.TradedAt
+			last = &t
+		}
+	}
+	return last, nil
+}
+
+func (s *memState) ListMarketTradesSince(_ context.Context, symbol string, since time.Time) ([]repo.MarketTrade, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []repo.MarketTrade
+	for _, trade := range s.tape {
+		if trade.Symbol == symbol && !trade.TradedAt.Before(since) {
+			out = append(out, trade)
+		}
+	}
+	return out, nil
+}
+
+func (s *memState) DeleteMarketTradesBefore(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.tape[:0]
+	for _, trade := range s.tape {
+		if !trade.TradedAt.Before(before) {
+			kept = append(kept, trade)
+		}
+	}
+	deleted := int64(len(s.tape) - len(kept))
+	s.tape = kept
+	return deleted, nil
+}
+
+func (s *memState) SaveDaemonJobRun(_ context.Context, run repo.DaemonJobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.daemonRuns = append(s.daemonRuns, run)
+	return nil
+}
+
+func (s *memState) trade(id int) repo.TradeLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.trades[id-1]
+}
+
+func (s *memState) intent(id int) repo.OrderIntent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.intents[id-1]
+}
+
+func (s *memState) bracket(tradeID int) repo.OrderBracket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.brackets[tradeID]
+}
+
+func (s *memState) exitState(tradeID int) repo.TradeExitState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exitStates[tradeID]
+}
+
+func (s *memState) GetCoins(_ context.Context, params repo.GetCoinsParams) ([]mexc.SymbolDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []mexc.SymbolDetail{}
+	for _, coin := range s.coins {
+		if params.IsSpotTradingAllowed != nil && coin.IsSpotTradingAllowed != *params.IsSpotTradingAllowed {
+			continue
+		}
+		if params.IsPalisade != nil && coin.IsPalisade != *params.IsPalisade {
+			continue
+		}
+		out = append(out, coin)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out, nil
+}
+
+func (s *memState) GetCoinInfo(_ context.Context, symbol string) (*mexc.SymbolDetail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	coin, ok := s.coins[symbol]
+	if !ok {
+		return nil, nil
+	}
+	return &coin, nil
+}
+
+func (s *memState) CreateGrid(_ context.Context, grid repo.Grid) (*repo.Grid, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	grid.ID = len(s.grids) + 1
+	grid.UpdatedAt = grid.CreatedAt
+	s.grids = append(s.grids, grid)
+	return &grid, nil
+}
+
+func (s *memState) ListActiveGrids(context.Context) ([]repo.Grid, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.Grid{}
+	for _, grid := range s.grids {
+		if grid.Status == repo.GridActive {
+			out = append(out, grid)
+		}
+	}
+	return out, nil
+}
+
+func (s *memState) UpdateGridStatus(_ context.Context, id int, status, stopReason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grids[id-1].Status = status
+	s.grids[id-1].StopReason = stopReason
+	return nil
+}
+
+func (s *memState) SaveGridLevel(_ context.Context, level repo.GridLevel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gridLevels[[2]int{level.GridID, level.Level}] = level
+	return nil
+}
+
+func (s *memState) ListGridLevels(_ context.Context, gridID int) ([]repo.GridLevel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.GridLevel{}
+	for key, level := range s.gridLevels {
+		if key[0] == gridID {
+			out = append(out, level)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Level < out[j].Level })
+	return out, nil
+}
+
+func (s *memState) grid(id int) repo.Grid {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.grids[id-1]
+}
+
+func (s *memState) gridLevel(gridID, level int) repo.GridLevel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gridLevels[[2]int{gridID, level}]
+}
+
+func (s *memState) CreateSwapRun(_ context.Context, run repo.SwapRun) (*repo.SwapRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run.ID = len(s.swapRuns) + 1
+	s.swapRuns = append(s.swapRuns, run)
+	return &run, nil
+}
+
+func (s *memState) FinishSwapRun(_ context.Context, run repo.SwapRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := &s.swapRuns[run.ID-1]
+	stored.Status = run.Status
+	stored.UnwindOutcome = run.UnwindOutcome
+	stored.FinalAmount = run.FinalAmount
+	stored.RealizedProfitPercent = run.RealizedProfitPercent
+	stored.LiquidatedAmount = run.LiquidatedAmount
+	stored.LastError = run.LastError
+	stored.FinishedAt = run.FinishedAt
+	return nil
+}
+
+func (s *memState) SaveSwapLeg(_ context.Context, leg repo.SwapLeg) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.swapLegs[[2]int{leg.RunID, leg.Leg}] = leg
+	return nil
+}
+
+func (s *memState) ListSwapRunsSince(_ context.Context, since time.Time) ([]repo.SwapRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.SwapRun{}
+	for _, run := range s.swapRuns {
+		if !run.StartedAt.Before(since) {
+			out = append(out, run)
+		}
+	}
+	return out, nil
+}
+
+func (s *memState) ListSwapLegsSince(_ context.Context, since time.Time) ([]repo.SwapLeg, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.SwapLeg{}
+	for key, leg := range s.swapLegs {
+		if !s.swapRuns[key[0]-1].StartedAt.Before(since) {
+			out = append(out, leg)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].RunID != out[j].RunID {
+			return out[i].RunID < out[j].RunID
+		}
+		return out[i].Leg < out[j].Leg
+	})
+	return out, nil
+}
+
+func (s *memState) SaveSwapOpportunity(_ context.Context, opp repo.SwapOpportunity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	opp.ID = len(s.swapOpps) + 1
+	s.swapOpps = append(s.swapOpps, opp)
+	return nil
+}
+
+func (s *memState) ListSwapOpportunitiesSince(_ context.Context, since time.Time) ([]repo.SwapOpportunity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := []repo.SwapOpportunity{}
+	for _, opp := range s.swapOpps {
+		if !opp.FirstSeenAt.Before(since) {
+			out = append(out, opp)
+		}
+	}
+	return out, nil
+}
+
+func (s *memState) ListSwapInventory(context.Context) ([]repo.SwapInventory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]repo.SwapInventory, 0, len(s.swapStock))
+	for _, item := range s.swapStock {
+		out = append(out, item)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Asset < out[j].Asset })
+	return out, nil
+}
+
+func (s *memState) SaveSwapInventory(_ context.Context, item repo.SwapInventory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.swapStock[item.Asset] = item
+	return nil
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+// recordStrategyConfig сохраняет снимок настроек стратегии в strategy_config
+// (повторная запись той же версии игнорируется) и возвращает версию для
+// config_version в palisade_signal и paper_trade.
+func recordStrategyConfig(ctx context.Context, stateRepo repo.IStateRepository, strategy config.StrategyConfig) (string, error) {
+	snapshot, err := strategy.Snapshot()
+	if err != nil {
+		return "", err
+	}
+	version := strategy.Version()
+	if err := stateRepo.SaveStrategyConfig(ctx, version, snapshot, time.Now().UTC()); err != nil {
+		return "", err
+	}
+	return version, nil
+}
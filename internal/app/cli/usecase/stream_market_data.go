@@ -0,0 +1,473 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/enum"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const (
+	defaultStreamTopSymbols    = 100
+	defaultStreamFlushInterval = 5 * time.Second
+	defaultStreamStatsInterval = time.Minute
+
+	streamReconnectMinDelay = time.Second
+	streamReconnectMaxDelay = 30 * time.Second
+	// Снимок без изменений стакана всё равно перезаписывается раз в минуту,
+	// иначе ScorePalisadeCandidates отбросит его как устаревший.
+	streamSnapshotRefresh = time.Minute
+	// Свеча, по которой стрим молчит, считается закрытой через grace после
+	// конца окна.
+	streamKlineCloseGrace = 5 * time.Second
+	// Глубже суток дыры не добираются: историю грузят sync/backfill-trend-bars.
+	streamBackfillMaxDepth = 24 * time.Hour
+	streamBackfillLimit    = 1000
+)
+
+type StreamMarketDataOptions struct {
+	Symbols       []string
+	Top           int
+	FlushInterval time.Duration
+	StatsInterval time.Duration
+	Debug         bool
+}
+
+func DefaultStreamMarketDataOptions() StreamMarketDataOptions {
+	return StreamMarketDataOptions{
+		Top:           defaultStreamTopSymbols,
+		FlushInterval: defaultStreamFlushInterval,
+		StatsInterval: defaultStreamStatsInterval,
+	}
+}
+
+type IStreamMarketData interface {
+	Process(context.Context, StreamMarketDataOptions) error
+}
+
+type streamDayStats struct {
+	lastPrice          float64
+	quoteVolume        float64
+	priceChangePercent float64
+}
+
+// streamGap — диапазон минутных свечей [from, to), который надо добрать
+// через REST; нулевой to — до последней закрытой свечи.
+type streamGap struct {
+	from time.Time
+	to   time.Time
+}
+
+// StreamMarketData держит живые bid/ask и минутные свечи по USDT-символам из
+// WebSocket-стрима и пачками пишет их в market_snapshot и market_minute_bar.
+// Обработчик стрима только обновляет состояние в памяти; в базу и REST ходит
+// одна горутина-писатель, так как pgx.Conn не потокобезопасен.
+type StreamMarketData struct {
+	api       repo.IMexcRepository
+	stream    repo.IMarketStream
+	stateRepo repo.IStateRepository
+	trendRepo repo.ITrendRepository
+
+	mu        sync.Mutex
+	books     map[string]mexc.StreamBookTicker
+	dirty     map[string]bool
+	written   map[string]time.Time
+	stats     map[string]streamDayStats
+	lastPrice map[string]float64
+	klines    map[string]mexc.Kline
+	lastBar   map[string]time.Time
+	gaps      map[string]streamGap
+	pending   []repo.MarketMinuteBar
+}
+
+func NewStreamMarketDataUsecase(
+	api repo.IMexcRepository,
+	stream repo.IMarketStream,
+	stateRepo repo.IStateRepository,
+	trendRepo repo.ITrendRepository,
+) *StreamMarketData {
+	return &StreamMarketData{api: api, stream: stream, stateRepo: stateRepo, trendRepo: trendRepo}
+}
+
+// Process работает до отмены ctx: переподключается к стриму с экспоненциальной
+// паузой, после каждого подключения добирает пропущенные свечи через
+// GetKlinesPublic и на выходе сбрасывает накопленное в базу.
+func (u *StreamMarketData) Process(ctx context.Context, opts StreamMarketDataOptions) error {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultStreamFlushInterval
+	}
+	if opts.StatsInterval <= 0 {
+		opts.StatsInterval = defaultStreamStatsInterval
+	}
+	u.reset()
+
+	if err := u.refreshStats(ctx); err != nil {
+		return err
+	}
+	symbols := opts.Symbols
+	if len(symbols) == 0 {
+		symbols = u.topSymbols(opts.Top)
+	}
+	if len(symbols) == 0 {
+		return wrap.Errorf("stream market data: no symbols")
+	}
+	for _, symbol := range symbols {
+		last, err := u.trendRepo.GetLastMinuteOpenTime(ctx, symbol)
+		if err != nil {
+			return wrap.Errorf("last minute bar %s: %w", symbol, err)
+		}
+		if last != nil {
+			u.lastBar[symbol] = last.UTC()
+		}
+	}
+	fmt.Printf("stream-market-data: %d symbols\n", len(symbols))
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		u.runWriter(ctx, opts)
+	}()
+
+	delay := streamReconnectMinDelay
+	for ctx.Err() == nil {
+		u.markReconnect()
+		startedAt := time.Now()
+		err := u.stream.Run(ctx, symbols, u.handleEvent)
+		if ctx.Err() != nil {
+			break
+		}
+		if time.Since(startedAt) > streamReconnectMaxDelay {
+			delay = streamReconnectMinDelay
+		}
+		fmt.Printf("stream-market-data: соединение потеряно: %v, переподключение через %s\n", err, delay)
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, streamReconnectMaxDelay)
+	}
+
+	<-writerDone
+	return nil
+}
+
+func (u *StreamMarketData) reset() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.books = map[string]mexc.StreamBookTicker{}
+	u.dirty = map[string]bool{}
+	u.written = map[string]time.Time{}
+	u.stats = map[string]streamDayStats{}
+	u.lastPrice = map[string]float64{}
+	u.klines = map[string]mexc.Kline{}
+	u.lastBar = map[string]time.Time{}
+	u.gaps = map[string]streamGap{}
+	u.pending = nil
+}
+
+func (u *StreamMarketData) runWriter(ctx context.Context, opts StreamMarketDataOptions) {
+	flushTicker := time.NewTicker(opts.FlushInterval)
+	defer flushTicker.Stop()
+	statsTicker := time.NewTicker(opts.StatsInterval)
+	defer statsTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Финальный сброс без добора дыр: их восстановит следующий запуск.
+			if err := u.flush(context.WithoutCancel(ctx), false, opts.Debug); err != nil {
+				fmt.Printf("stream-market-data: ошибка записи: %v\n", err)
+			}
+			return
+		case <-flushTicker.C:
+			if err := u.flush(ctx, true, opts.Debug); err != nil {
+				fmt.Printf("stream-market-data: ошибка записи: %v\n", err)
+			}
+		case <-statsTicker.C:
+			if err := u.refreshStats(ctx); err != nil {
+				fmt.Printf("stream-market-data: ошибка 24h статистики: %v\n", err)
+			}
+		}
+	}
+}
+
+func (u *StreamMarketData) handleEvent(event mexc.StreamEvent) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if book := event.BookTicker; book != nil {
+		if book.BidPrice <= 0 || book.AskPrice <= book.BidPrice {
+			return
+		}
+		u.books[event.Symbol] = *book
+		u.dirty[event.Symbol] = true
+	}
+	if k := event.Kline; k != nil {
+		u.applyKline(event.Symbol, *k)
+	}
+}
+
+// applyKline обновляет текущую свечу символа; приход свечи следующего окна
+// закрывает предыдущую.
+func (u *StreamMarketData) applyKline(symbol string, k mexc.Kline) {
+	if last, ok := u.lastBar[symbol]; ok && k.OpenTime <= last.UnixMilli() {
+		return
+	}
+	cur, ok := u.klines[symbol]
+	if ok && k.OpenTime < cur.OpenTime {
+		return
+	}
+	if ok && k.OpenTime > cur.OpenTime {
+		u.finalizeKline(symbol, cur)
+	}
+	u.klines[symbol] = k
+	u.lastPrice[symbol] = k.Close
+}
+
+func (u *StreamMarketData) finalizeKline(symbol string, k mexc.Kline) {
+	openTime := time.UnixMilli(k.OpenTime).UTC()
+	if last, ok := u.lastBar[symbol]; ok {
+		if !openTime.After(last) {
+			return
+		}
+		if next := last.Add(time.Minute); openTime.After(next) {
+			u.addGap(symbol, streamGap{from: next, to: openTime})
+		}
+	}
+	u.lastBar[symbol] = openTime
+	u.pending = append(u.pending, repo.MarketMinuteBar{
+		Symbol:   symbol,
+		OpenTime: openTime,
+		Open:     k.Open,
+		High:     k.High,
+		Low:      k.Low,
+		Close:    k.Close,
+	})
+}
+
+// markReconnect перед (пере)подключением отбрасывает незакрытые свечи — за
+// время обрыва они могли измениться — и помечает к добору всё после
+// последней закрытой свечи.
+func (u *StreamMarketData) markReconnect() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.klines = map[string]mexc.Kline{}
+	for symbol, last := range u.lastBar {
+		u.addGap(symbol, streamGap{from: last.Add(time.Minute)})
+	}
+}
+
+func (u *StreamMarketData) addGap(symbol string, gap streamGap) {
+	existing, ok := u.gaps[symbol]
+	if !ok {
+		u.gaps[symbol] = gap
+		return
+	}
+	if gap.from.Before(existing.from) {
+		existing.from = gap.from
+	}
+	if existing.to.IsZero() || gap.to.IsZero() {
+		existing.to = time.Time{}
+	} else if gap.to.After(existing.to) {
+		existing.to = gap.to
+	}
+	u.gaps[symbol] = existing
+}
+
+func (u *StreamMarketData) flush(ctx context.Context, backfill bool, debug bool) error {
+	now := time.Now().UTC()
+	backfilled := 0
+	if backfill {
+		u.mu.Lock()
+		gaps := u.gaps
+		u.gaps = map[string]streamGap{}
+		u.mu.Unlock()
+		for symbol, gap := range gaps {
+			n, err := u.backfillGap(ctx, symbol, gap, now)
+			if err != nil {
+				fmt.Printf("stream-market-data: добор %s: %v\n", symbol, err)
+				u.mu.Lock()
+				u.addGap(symbol, gap)
+				u.mu.Unlock()
+				continue
+			}
+			backfilled += n
+		}
+	}
+
+	bars, snapshots := u.drain(now)
+	if err := u.trendRepo.UpsertMinuteBars(ctx, bars); err != nil {
+		u.mu.Lock()
+		u.pending = append(bars, u.pending...)
+		u.mu.Unlock()
+		return wrap.Errorf("save minute bars: %w", err)
+	}
+	if err := u.stateRepo.UpsertMarketSnapshots(ctx, snapshots); err != nil {
+		u.mu.Lock()
+		for _, snapshot := range snapshots {
+			u.dirty[snapshot.Symbol] = true
+		}
+		u.mu.Unlock()
+		return wrap.Errorf("save market snapshots: %w", err)
+	}
+	u.mu.Lock()
+	for _, snapshot := range snapshots {
+		u.written[snapshot.Symbol] = snapshot.CollectedAt
+	}
+	u.mu.Unlock()
+
+	if debug {
+		fmt.Printf("stream-market-data: snapshots %d, minute bars %d (добрано %d)\n", len(snapshots), len(bars), backfilled)
+	}
+	return nil
+}
+
+// drain закрывает свечи, по которым окно уже истекло, и забирает накопленные
+// свечи и снимки для записи.
+func (u *StreamMarketData) drain(now time.Time) ([]repo.MarketMinuteBar, []repo.MarketSnapshot) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for symbol, k := range u.klines {
+		if now.UnixMilli() > k.CloseTime+streamKlineCloseGrace.Milliseconds() {
+			u.finalizeKline(symbol, k)
+			delete(u.klines, symbol)
+		}
+	}
+	bars := u.pending
+	u.pending = nil
+
+	snapshots := make([]repo.MarketSnapshot, 0, len(u.dirty))
+	for symbol, book := range u.books {
+		if !u.dirty[symbol] && now.Sub(u.written[symbol]) < streamSnapshotRefresh {
+			continue
+		}
+		stats, ok := u.stats[symbol]
+		if !ok {
+			continue
+		}
+		lastPrice := stats.lastPrice
+		if price, ok := u.lastPrice[symbol]; ok && price > 0 {
+			lastPrice = price
+		}
+		snapshots = append(snapshots, repo.MarketSnapshot{
+			Symbol:             symbol,
+			CollectedAt:        now,
+			LastPrice:          lastPrice,
+			BidPrice:           book.BidPrice,
+			BidQty:             book.BidQty,
+			AskPrice:           book.AskPrice,
+			AskQty:             book.AskQty,
+			QuoteVolume24h:     stats.quoteVolume,
+			PriceChangePercent: stats.priceChangePercent,
+		})
+		delete(u.dirty, symbol)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Symbol < snapshots[j].Symbol })
+	return bars, snapshots
+}
+
+// backfillGap добирает закрытые минутные свечи из REST. За вызов берётся не
+// больше streamBackfillLimit свечей, остаток дыры возвращается в очередь.
+func (u *StreamMarketData) backfillGap(ctx context.Context, symbol string, gap streamGap, now time.Time) (int, error) {
+	from := gap.from
+	if floor := now.Add(-streamBackfillMaxDepth); from.Before(floor) {
+		from = floor
+	}
+	startMs := from.UnixMilli()
+	klines, err := u.api.GetKlinesPublic(ctx, symbol, enum.MINUTES_1, streamBackfillLimit, &startMs)
+	if err != nil {
+		return 0, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	added := 0
+	var lastOpen time.Time
+	for _, k := range *klines {
+		openTime := time.UnixMilli(k.OpenTime).UTC()
+		if openTime.Before(from) || k.CloseTime >= now.UnixMilli() {
+			continue
+		}
+		if !gap.to.IsZero() && !openTime.Before(gap.to) {
+			continue
+		}
+		lastOpen = openTime
+		u.pending = append(u.pending, repo.MarketMinuteBar{
+			Symbol:   symbol,
+			OpenTime: openTime,
+			Open:     k.Open,
+			High:     k.High,
+			Low:      k.Low,
+			Close:    k.Close,
+		})
+		added++
+		if last, ok := u.lastBar[symbol]; !ok || openTime.After(last) {
+			u.lastBar[symbol] = openTime
+		}
+	}
+	if len(*klines) >= streamBackfillLimit && !lastOpen.IsZero() {
+		u.addGap(symbol, streamGap{from: lastOpen.Add(time.Minute), to: gap.to})
+	}
+	return added, nil
+}
+
+func (u *StreamMarketData) refreshStats(ctx context.Context) error {
+	tickers, err := u.api.GetAll24hTickers(ctx)
+	if err != nil {
+		return wrap.Errorf("get all 24h tickers: %w", err)
+	}
+	stats := make(map[string]streamDayStats, len(*tickers))
+	for _, ticker := range *tickers {
+		if !strings.HasSuffix(ticker.Symbol, "USDT") || len(ticker.Symbol) < 5 {
+			continue
+		}
+		lastPrice, err := strconv.ParseFloat(ticker.LastPrice, 64)
+		if err != nil || lastPrice <= 0 {
+			continue
+		}
+		quoteVolume, err := strconv.ParseFloat(ticker.QuoteVolume, 64)
+		if err != nil || quoteVolume < 0 {
+			continue
+		}
+		change, err := strconv.ParseFloat(ticker.PriceChangePercent, 64)
+		if err != nil {
+			change = 0
+		}
+		stats[ticker.Symbol] = streamDayStats{lastPrice: lastPrice, quoteVolume: quoteVolume, priceChangePercent: change}
+	}
+
+	u.mu.Lock()
+	u.stats = stats
+	u.mu.Unlock()
+	return nil
+}
+
+// topSymbols — USDT-символы с наибольшим 24h оборотом.
+func (u *StreamMarketData) topSymbols(top int) []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	symbols := make([]string, 0, len(u.stats))
+	for symbol := range u.stats {
+		symbols = append(symbols, symbol)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		a, b := u.stats[symbols[i]].quoteVolume, u.stats[symbols[j]].quoteVolume
+		if a != b {
+			return a > b
+		}
+		return symbols[i] < symbols[j]
+	})
+	if top > 0 && len(symbols) > top {
+		symbols = symbols[:top]
+	}
+	return symbols
+}
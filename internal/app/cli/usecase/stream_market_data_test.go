@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/adapter/webapi"
+	"github.com/drybin/palisade/internal/adapter/webapi/mexcsim"
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+func (m *memTrend) UpsertMinuteBars(_ context.Context, bars []repo.MarketMinuteBar) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, bar := range bars {
+		m.bars[bar.Symbol] = append(m.bars[bar.Symbol], bar)
+	}
+	return nil
+}
+
+func (m *memTrend) GetLastMinuteOpenTime(_ context.Context, symbol string) (*time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bars := m.bars[symbol]
+	if len(bars) == 0 {
+		return nil, nil
+	}
+	last := bars[len(bars)-1].OpenTime
+	return &last, nil
+}
+
+func (m *memTrend) countFrom(symbol string, from time.Time) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, bar := range m.bars[symbol] {
+		if !bar.OpenTime.Before(from) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *memState) lastSnapshot(symbol string) (repo.MarketSnapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.snapshots) - 1; i >= 0; i-- {
+		if s.snapshots[i].Symbol == symbol {
+			return s.snapshots[i], true
+		}
+	}
+	return repo.MarketSnapshot{}, false
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStreamMarketData_snapshotsBackfillAndReconnect(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Minute)
+	ex := newSimSignalExchange()
+	ex.SetTicker24h("AAAUSDT", 250000, 3.5)
+	history := mexc.Klines{}
+	for i := 9; i >= 1; i-- {
+		openTime := now.Add(-time.Duration(i) * time.Minute)
+		history = append(history, mexc.Kline{
+			OpenTime: openTime.UnixMilli(), Open: 1, High: 1.01, Low: 0.99, Close: 1,
+			CloseTime: openTime.Add(time.Minute).UnixMilli() - 1,
+		})
+	}
+	ex.SetKlines("AAAUSDT", history)
+
+	stream := mexcsim.NewStream()
+	streamServer := mexcsim.NewStreamServer(stream)
+	t.Cleanup(streamServer.Close)
+
+	state := newMemState()
+	trend := &memTrend{bars: map[string][]repo.MarketMinuteBar{
+		"AAAUSDT": {{Symbol: "AAAUSDT", OpenTime: now.Add(-10 * time.Minute), Open: 1, High: 1, Low: 1, Close: 1}},
+	}}
+	u := NewStreamMarketDataUsecase(
+		newSimWebapi(t, ex),
+		webapi.NewMexcMarketStream(config.MexcConfig{WsUrl: mexcsim.StreamURL(streamServer)}),
+		state,
+		trend,
+	)
+	opts := DefaultStreamMarketDataOptions()
+	opts.Symbols = []string{"AAAUSDT"}
+	opts.FlushInterval = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- u.Process(ctx, opts) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("process: %v", err)
+		}
+	}()
+
+	if !stream.WaitSubscriptions(1, 5*time.Second) {
+		t.Fatalf("stream was not subscribed")
+	}
+	stream.PublishBookTicker("AAAUSDT", 1.0, 50, 1.002, 40)
+	stream.PublishKline("AAAUSDT", mexc.Kline{
+		OpenTime: now.UnixMilli(), Open: 1, High: 1.003, Low: 1, Close: 1.001,
+		CloseTime: now.Add(time.Minute).UnixMilli() - 1,
+	})
+
+	waitFor(t, "first snapshot", func() bool {
+		snapshot, ok := state.lastSnapshot("AAAUSDT")
+		return ok && snapshot.BidPrice == 1.0
+	})
+	snapshot, _ := state.lastSnapshot("AAAUSDT")
+	if snapshot.AskPrice != 1.002 || snapshot.AskQty != 40 || snapshot.QuoteVolume24h != 250000 || snapshot.PriceChangePercent != 3.5 {
+		t.Fatalf("unexpected snapshot: %+v", snapshot)
+	}
+	waitFor(t, "gap backfill", func() bool {
+		return trend.countFrom("AAAUSDT", now.Add(-9*time.Minute)) >= 9
+	})
+
+	stream.DropConnections()
+	if !stream.WaitSubscriptions(2, 5*time.Second) {
+		t.Fatalf("stream was not resubscribed after drop")
+	}
+	stream.PublishBookTicker("AAAUSDT", 1.01, 10, 1.012, 20)
+	waitFor(t, "snapshot after reconnect", func() bool {
+		snapshot, ok := state.lastSnapshot("AAAUSDT")
+		return ok && snapshot.BidPrice == 1.01
+	})
+}
+
+func TestStreamMarketData_nextKlineClosesPreviousAndMarksGap(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	u := NewStreamMarketDataUsecase(nil, nil, nil, nil)
+	u.reset()
+	u.lastBar["AAAUSDT"] = start
+
+	kline := func(openTime time.Time, close float64) *mexc.Kline {
+		return &mexc.Kline{
+			OpenTime: openTime.UnixMilli(), Open: 1, High: 2, Low: 0.5, Close: close,
+			CloseTime: openTime.Add(time.Minute).UnixMilli() - 1,
+		}
+	}
+	u.handleEvent(mexc.StreamEvent{Symbol: "AAAUSDT", Kline: kline(start, 9)})
+	u.handleEvent(mexc.StreamEvent{Symbol: "AAAUSDT", Kline: kline(start.Add(3*time.Minute), 1.1)})
+	u.handleEvent(mexc.StreamEvent{Symbol: "AAAUSDT", Kline: kline(start.Add(3*time.Minute), 1.2)})
+	u.handleEvent(mexc.StreamEvent{Symbol: "AAAUSDT", Kline: kline(start.Add(4*time.Minute), 1.3)})
+
+	if len(u.pending) != 1 || !u.pending[0].OpenTime.Equal(start.Add(3*time.Minute)) || u.pending[0].Close != 1.2 {
+		t.Fatalf("expected only the 10:03 bar with last close, got %+v", u.pending)
+	}
+	gap, ok := u.gaps["AAAUSDT"]
+	if !ok || !gap.from.Equal(start.Add(time.Minute)) || !gap.to.Equal(start.Add(3*time.Minute)) {
+		t.Fatalf("unexpected gap %+v", gap)
+	}
+	if u.lastPrice["AAAUSDT"] != 1.3 {
+		t.Fatalf("unexpected last price %f", u.lastPrice["AAAUSDT"])
+	}
+}
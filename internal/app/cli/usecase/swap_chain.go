@@ -1,10 +1,10 @@
 package usecase
 
 import (
-	"math"
 	"strconv"
 	"strings"
 
+	"github.com/drybin/palisade/internal/domain/enum/order"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 )
 
@@ -38,10 +38,13 @@ func swapTakerFeeRate(sym *mexc.SymbolDetail) float64 {
 	return f
 }
 
-// swapBookQuote — лучшие bid/ask по символу (исполнимые стороны книги).
+// swapBookQuote — лучшие bid/ask по символу (исполнимые стороны книги) и
+// объёмы на них в базовом активе; 0 — объём неизвестен.
 type swapBookQuote struct {
-	Bid float64
-	Ask float64
+	Bid    float64
+	Ask    float64
+	BidQty float64
+	AskQty float64
 }
 
 // BuildSwapBookMap строит map symbol -> bid/ask из bookTicker (сторона 0 = нет данных).
@@ -61,6 +64,8 @@ func BuildSwapBookMap(rows *mexc.BookTickers) map[string]swapBookQuote {
 		if a, err := strconv.ParseFloat(r.AskPrice, 64); err == nil && a > 0 {
 			q.Ask = a
 		}
+		q.BidQty, _ = strconv.ParseFloat(r.BidQty, 64)
+		q.AskQty, _ = strconv.ParseFloat(r.AskQty, 64)
 		if q.Bid <= 0 && q.Ask <= 0 {
 			continue
 		}
@@ -198,57 +203,44 @@ func applySwapChainRealism(
 	if stepA <= 0 || stepAB <= 0 || stepB <= 0 || interBuffer <= 0 {
 		return swapChainResult{}, false
 	}
-
-	askA := res.priceAUSDT
-	bidB := res.priceBUSDT
-	priceAB := res.priceAB
-	direct := res.usedDirectAB
-
-	qty1 := swapRoundQtyDown(1.0/askA, stepA)
-	if qty1 <= 0 {
-		return swapChainResult{}, false
-	}
-	costUSDT := qty1 * askA
-	if costUSDT <= 0 {
-		return swapChainResult{}, false
-	}
-
-	qtyANet := qty1 * (1.0 - feeAUSDT)
-	if qtyANet <= 0 {
+	cycle, ok := applySwapCycleRealism(swapChainLegs(res, stepA, stepAB, stepB, feeAUSDT, feeAB, feeBUSDT), 1.0, interBuffer)
+	if !ok {
 		return swapChainResult{}, false
 	}
 
-	qtyAExpected := qtyANet * interBuffer
-	var qty2 float64
-	if direct {
-		qty2 = swapRoundQtyDown(math.Min(qtyANet, qtyAExpected), stepAB)
-	} else {
-		planB := qtyANet / priceAB
-		maxAffordB := qtyAExpected / priceAB
-		qty2 = swapRoundQtyDown(math.Min(planB, maxAffordB), stepAB)
-	}
-	if qty2 <= 0 {
-		return swapChainResult{}, false
-	}
+	out := res
+	out.amountA = cycle.Fills[0].Qty
+	out.amountB = cycle.Fills[2].Qty
+	out.amountUSDT = cycle.Fills[2].Out
+	out.profitPercent = cycle.ProfitPercent
+	return out, true
+}
 
-	var amountB float64
-	if direct {
-		amountB = qty2 * priceAB * (1.0 - feeAB)
-	} else {
-		amountB = qty2 * (1.0 - feeAB)
+// swapChainLegs — ноги цикла USDT -> A -> B -> USDT для маршрута res.
+func swapChainLegs(res swapChainResult, stepA, stepAB, stepB, feeAUSDT, feeAB, feeBUSDT float64) []swapLeg {
+	side2 := order.BUY
+	if res.usedDirectAB {
+		side2 = order.SELL
 	}
-	qty3 := swapRoundQtyDown(amountB, stepB)
-	if qty3 <= 0 {
-		return swapChainResult{}, false
+	return []swapLeg{
+		{Side: order.BUY, Price: res.priceAUSDT, Fee: feeAUSDT, Step: stepA},
+		{Symbol: res.symbolAB, Side: side2, Price: res.priceAB, Fee: feeAB, Step: stepAB},
+		{Side: order.SELL, Price: res.priceBUSDT, Fee: feeBUSDT, Step: stepB},
 	}
-	outUSDT := qty3 * bidB * (1.0 - feeBUSDT)
+}
 
-	out := res
-	out.amountA = qty1
-	out.amountB = qty3
-	out.amountUSDT = outUSDT
-	out.profitPercent = (outUSDT/costUSDT - 1.0) * 100.0
-	return out, true
+// calcSwapChainFromDepth пересчитывает выбранный маршрут res по стаканам трёх
+// пар на объём amountUSDT: каждый шаг исполняется по уровням (VWAP), а не по
+// лучшей цене. Возвращает прибыль в процентах; ok=false, если какому-то
+// стакану не хватает объёма.
+func calcSwapChainFromDepth(
+	res swapChainResult,
+	depthA, depthAB, depthB *mexc.OrderBook,
+	amountUSDT float64,
+	feeAUSDT, feeAB, feeBUSDT float64,
+) (float64, bool) {
+	legs := swapChainLegs(res, 0, 0, 0, feeAUSDT, feeAB, feeBUSDT)
+	return calcSwapCycleFromDepth(legs, []*mexc.OrderBook{depthA, depthAB, depthB}, amountUSDT)
 }
 
 // BuildSymbolDetailIndex индекс symbol -> детали пары из ответа exchangeInfo.
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"math"
 	"testing"
 
 	"github.com/drybin/palisade/internal/domain/model/mexc"
@@ -122,3 +123,25 @@ func TestCalcSwapChainFromBook_requireRealismRejectsInvalidLotStep(t *testing.T)
 		t.Fatal("soft fallback should still return continuous route")
 	}
 }
+
+func TestCalcSwapChainFromDepth_thinBookEatsProfit(t *testing.T) {
+	res := swapChainResult{usedDirectAB: true}
+	deep := func(bid, ask float64) *mexc.OrderBook {
+		return &mexc.OrderBook{Bids: []mexc.BookLevel{{Price: bid, Qty: 1000}}, Asks: []mexc.BookLevel{{Price: ask, Qty: 1000}}}
+	}
+	// USDT -> AAA по 1.0, AAA -> BBB по 1.0, BBB -> USDT по 1.03: +3% на лучших ценах.
+	profit, ok := calcSwapChainFromDepth(res, deep(0.99, 1.0), deep(1.0, 1.01), deep(1.03, 1.04), 10, 0, 0, 0)
+	if !ok || math.Abs(profit-3) > 1e-9 {
+		t.Fatalf("expected 3%% on a deep book, got %g %v", profit, ok)
+	}
+
+	thinB := &mexc.OrderBook{Bids: []mexc.BookLevel{{Price: 1.03, Qty: 2}, {Price: 0.98, Qty: 1000}}}
+	profit, ok = calcSwapChainFromDepth(res, deep(0.99, 1.0), deep(1.0, 1.01), thinB, 10, 0, 0, 0)
+	if !ok || profit >= 0 {
+		t.Fatalf("selling through the thin top level must turn the chain into a loss, got %g", profit)
+	}
+
+	if _, ok := calcSwapChainFromDepth(res, deep(0.99, 1.0), deep(1.0, 1.01), &mexc.OrderBook{Bids: []mexc.BookLevel{{Price: 1.03, Qty: 2}}}, 10, 0, 0, 0); ok {
+		t.Fatalf("book without enough volume must not be priced")
+	}
+}
@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+// swapNoProfitFloor — swapCycleSearch.MinProfitPercent, при котором
+// пересчитываются все найденные циклы.
+const swapNoProfitFloor = -100.0
+
+// ParseSwapAssets разбирает список активов через запятую: "usdt, btc" →
+// [USDT BTC].
+func ParseSwapAssets(raw string) []string {
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.ToUpper(strings.TrimSpace(p))
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// swapLeg — один обмен цикла: отдаём From, получаем To по паре Symbol.
+// SELL — From базовый актив пары, исполнение по bid; BUY — From котируемый,
+// исполнение по ask. Step — шаг лота базового актива пары, Qty — объём
+// базового актива на лучшей цене (0 — неизвестен).
+type swapLeg struct {
+	Symbol string
+	From   string
+	To     string
+	Side   order.Side
+	Price  float64
+	Fee    float64
+	Step   float64
+	Qty    float64
+}
+
+// logRate — логарифм того, сколько To даёт единица From по лучшей цене после
+// комиссии; сумма по циклу > 0 — цикл прибылен без учёта лотов.
+func (l swapLeg) logRate() float64 {
+	if l.Side == order.SELL {
+		return math.Log(l.Price * (1 - l.Fee))
+	}
+	return math.Log((1 - l.Fee) / l.Price)
+}
+
+// swapLegFill — исполнение ноги: Qty — количество ордера в базовом активе
+// пары, Spent — сколько From ушло, Out — сколько To пришло после комиссии.
+type swapLegFill struct {
+	Qty   float64
+	Spent float64
+	Out   float64
+}
+
+// fillSwapLeg исполняет ногу на in единиц From по её цене: SELL отдаёт
+// кратное шагу количество базового актива, BUY покупает кратное шагу
+// количество на in котируемого.
+func fillSwapLeg(leg swapLeg, in float64) swapLegFill {
+	if in <= 0 || leg.Price <= 0 || leg.Step <= 0 {
+		return swapLegFill{}
+	}
+	if leg.Side == order.SELL {
+		qty := swapRoundQtyDown(in, leg.Step)
+		return swapLegFill{Qty: qty, Spent: qty, Out: qty * leg.Price * (1 - leg.Fee)}
+	}
+	qty := swapRoundQtyDown(in/leg.Price, leg.Step)
+	return swapLegFill{Qty: qty, Spent: qty * leg.Price, Out: qty * (1 - leg.Fee)}
+}
+
+// swapCycle — замкнутая цепочка обменов из актива Legs[0].From в него же.
+// Fills и ProfitPercent — по лучшим ценам с лотами и комиссиями на Amount
+// стартового актива; DepthProfitPercent и DepthPrices (VWAP ног) — по
+// стаканам, если проверялся.
+type swapCycle struct {
+	Legs               []swapLeg
+	Amount             float64
+	Fills              []swapLegFill
+	ProfitPercent      float64
+	DepthProfitPercent float64
+	DepthPrices        []float64
+	DepthChecked       bool
+}
+
+func (c swapCycle) Start() string {
+	if len(c.Legs) == 0 {
+		return ""
+	}
+	return c.Legs[0].From
+}
+
+// Path — запись цикла вида USDT -> A -> B -> USDT.
+func (c swapCycle) Path() string {
+	if len(c.Legs) == 0 {
+		return ""
+	}
+	assets := []string{c.Legs[0].From}
+	for _, leg := range c.Legs {
+		assets = append(assets, leg.To)
+	}
+	return strings.Join(assets, " -> ")
+}
+
+// swapCycleCapacity — сколько стартового актива цикл пропускает по объёмам
+// лучших цен: объём каждой ноги в её активе From пересчитывается в стартовый
+// по курсам предыдущих ног, берётся наименьший. 0 — объём какой-то ноги
+// неизвестен.
+func swapCycleCapacity(legs []swapLeg) float64 {
+	capacity := math.Inf(1)
+	rate := 1.0 // единиц From текущей ноги за единицу стартового актива
+	for _, leg := range legs {
+		if leg.Qty <= 0 || leg.Price <= 0 {
+			return 0
+		}
+		limit := leg.Qty
+		if leg.Side == order.BUY {
+			limit = leg.Qty * leg.Price
+		}
+		capacity = math.Min(capacity, limit/rate)
+		rate *= math.Exp(leg.logRate())
+	}
+	if math.IsInf(capacity, 1) {
+		return 0
+	}
+	return capacity
+}
+
+// applySwapCycleRealism проводит amount стартового актива по ногам с шагами
+// лота и комиссиями. Как в applySwapChainRealism, на промежуточных ногах
+// расходуется только interBuffer полученного (запас под комиссию и
+// округление), последняя нога продаёт всё. Прибыль считается от того, что
+// реально ушло на первую ногу.
+func applySwapCycleRealism(legs []swapLeg, amount, interBuffer float64) (swapCycle, bool) {
+	if len(legs) == 0 || amount <= 0 || interBuffer <= 0 {
+		return swapCycle{}, false
+	}
+	fills := make([]swapLegFill, 0, len(legs))
+	in := amount
+	for i, leg := range legs {
+		available := in
+		if i > 0 && i < len(legs)-1 {
+			available = in * interBuffer
+		}
+		fill := fillSwapLeg(leg, available)
+		if fill.Qty <= 0 || fill.Out <= 0 {
+			return swapCycle{}, false
+		}
+		fills = append(fills, fill)
+		in = fill.Out
+	}
+	return swapCycle{
+		Legs:          legs,
+		Amount:        amount,
+		Fills:         fills,
+		ProfitPercent: (in/fills[0].Spent - 1.0) * 100.0,
+	}, true
+}
+
+// calcSwapCycleFromDepth проводит amount стартового актива по стаканам ног:
+// каждая нога исполняется по уровням (VWAP), а не по лучшей цене.
+// depths — стаканы пар в порядке ног; ok=false, если какому-то стакану не
+// хватает объёма.
+func calcSwapCycleFromDepth(legs []swapLeg, depths []*mexc.OrderBook, amount float64) (float64, bool) {
+	_, profit, ok := simulateSwapCycleDepth(legs, depths, amount)
+	return profit, ok
+}
+
+// simulateSwapCycleDepth — то же, что calcSwapCycleFromDepth, но возвращает
+// и средние цены исполнения каждой ноги: с ними журнал сравнивает ожидание
+// по стакану с фактическим исполнением.
+func simulateSwapCycleDepth(legs []swapLeg, depths []*mexc.OrderBook, amount float64) ([]float64, float64, bool) {
+	if len(legs) == 0 || len(depths) != len(legs) || amount <= 0 {
+		return nil, 0, false
+	}
+	prices := make([]float64, 0, len(legs))
+	in := amount
+	for i, leg := range legs {
+		depth := depths[i]
+		if depth == nil {
+			return nil, 0, false
+		}
+		if leg.Side == order.SELL {
+			filled, avg := depth.FillSell(in, 0)
+			if filled < in*(1-1e-9) {
+				return nil, 0, false
+			}
+			prices = append(prices, avg)
+			in = filled * avg * (1 - leg.Fee)
+			continue
+		}
+		avg, spent := depth.BuyVWAP(in)
+		if avg <= 0 || spent < in*(1-1e-9) {
+			return nil, 0, false
+		}
+		prices = append(prices, avg)
+		in = spent / avg * (1 - leg.Fee)
+	}
+	return prices, (in/amount - 1) * 100, true
+}
+
+// swapGraph — активы и обмены между ними: по паре с bid — SELL из базового
+// актива в котируемый, с ask — BUY из котируемого в базовый.
+type swapGraph struct {
+	edges map[string][]swapLeg
+}
+
+// buildSwapGraph строит граф из bookTicker и exchangeInfo. Пары без шага
+// лота пропускаются: цикл через них не пересчитать реалистично. allow, если
+// задан, отсекает пары, по которым нельзя торговать.
+func buildSwapGraph(book map[string]swapBookQuote, index map[string]*mexc.SymbolDetail, allow func(symbol string) bool) *swapGraph {
+	graph := &swapGraph{edges: make(map[string][]swapLeg)}
+	for symbol, quote := range book {
+		detail := index[symbol]
+		if detail == nil || detail.BaseAsset == "" || detail.QuoteAsset == "" {
+			continue
+		}
+		if allow != nil && !allow(symbol) {
+			continue
+		}
+		step, err := swapLotStep(detail)
+		if err != nil || step <= 0 {
+			continue
+		}
+		fee := swapTakerFeeRate(detail)
+		if quote.Bid > 0 {
+			graph.add(swapLeg{Symbol: symbol, From: detail.BaseAsset, To: detail.QuoteAsset, Side: order.SELL, Price: quote.Bid, Fee: fee, Step: step, Qty: quote.BidQty})
+		}
+		if quote.Ask > 0 {
+			graph.add(swapLeg{Symbol: symbol, From: detail.QuoteAsset, To: detail.BaseAsset, Side: order.BUY, Price: quote.Ask, Fee: fee, Step: step, Qty: quote.AskQty})
+		}
+	}
+	for asset := range graph.edges {
+		edges := graph.edges[asset]
+		sort.Slice(edges, func(i, j int) bool {
+			if edges[i].To != edges[j].To {
+				return edges[i].To < edges[j].To
+			}
+			return edges[i].Symbol < edges[j].Symbol
+		})
+	}
+	return graph
+}
+
+func (g *swapGraph) add(leg swapLeg) {
+	g.edges[leg.From] = append(g.edges[leg.From], leg)
+}
+
+// swapCycleSearch — параметры поиска циклов.
+type swapCycleSearch struct {
+	Starts  []string
+	MinLegs int
+	MaxLegs int
+	// Amount — объём стартового актива, на котором цикл пересчитывается с
+	// лотами; 0 — одна единица.
+	Amount      float64
+	InterBuffer float64
+	// MinProfitPercent — циклы с прибылью по лучшим ценам ниже порога
+	// отбрасываются до пересчёта с лотами; swapNoProfitFloor оставляет все.
+	MinProfitPercent float64
+	// Limit — сколько лучших циклов вернуть; 0 — все.
+	Limit int
+}
+
+// findSwapCycles ищет ограниченным DFS простые циклы из каждого актива
+// Starts длиной MinLegs…MaxLegs: актив не повторяется, пока цикл не вернётся
+// в старт. Прибыль по лучшим ценам — сумма логарифмов курсов ног; прошедшие
+// порог циклы пересчитываются applySwapCycleRealism. Результат отсортирован
+// по убыванию прибыли.
+func findSwapCycles(graph *swapGraph, search swapCycleSearch) []swapCycle {
+	amount := search.Amount
+	if amount <= 0 {
+		amount = 1
+	}
+	minLog := math.Log1p(search.MinProfitPercent / 100)
+	if search.MinProfitPercent <= swapNoProfitFloor {
+		minLog = math.Inf(-1)
+	}
+
+	var out []swapCycle
+	keep := func() {
+		if search.Limit <= 0 || len(out) < 4*search.Limit {
+			return
+		}
+		sortSwapCycles(out)
+		out = out[:search.Limit]
+	}
+
+	for _, start := range search.Starts {
+		path := make([]swapLeg, 0, search.MaxLegs)
+		visited := map[string]bool{start: true}
+		var walk func(asset string, logRate float64)
+		walk = func(asset string, logRate float64) {
+			for _, leg := range graph.edges[asset] {
+				next := logRate + leg.logRate()
+				legs := len(path) + 1
+				if leg.To == start {
+					if legs < search.MinLegs || next < minLog {
+						continue
+					}
+					cycleLegs := append(append([]swapLeg(nil), path...), leg)
+					cycle, ok := applySwapCycleRealism(cycleLegs, amount, search.InterBuffer)
+					if !ok {
+						continue
+					}
+					out = append(out, cycle)
+					keep()
+					continue
+				}
+				if legs >= search.MaxLegs || visited[leg.To] {
+					continue
+				}
+				visited[leg.To] = true
+				path = append(path, leg)
+				walk(leg.To, next)
+				path = path[:len(path)-1]
+				visited[leg.To] = false
+			}
+		}
+		walk(start, 0)
+	}
+
+	sortSwapCycles(out)
+	if search.Limit > 0 && len(out) > search.Limit {
+		out = out[:search.Limit]
+	}
+	return out
+}
+
+func sortSwapCycles(cycles []swapCycle) {
+	sort.SliceStable(cycles, func(i, j int) bool { return cycles[i].ProfitPercent > cycles[j].ProfitPercent })
+}
+
+// rankSwapCyclesByDepth ставит вперёд циклы, проверенные по стаканам, по
+// убыванию прибыли с учётом глубины; непроверенные остаются в конце.
+func rankSwapCyclesByDepth(cycles []swapCycle) {
+	sort.SliceStable(cycles, func(i, j int) bool {
+		if cycles[i].DepthChecked != cycles[j].DepthChecked {
+			return cycles[i].DepthChecked
+		}
+		return cycles[i].DepthProfitPercent > cycles[j].DepthProfitPercent
+	})
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"math"
+	"testing"
+
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+func testSwapPair(symbol, base, quote, stepSize, takerCommission string) *mexc.SymbolDetail {
+	detail := testSymbolWithLotAndTaker(symbol, stepSize, takerCommission)
+	detail.BaseAsset = base
+	detail.QuoteAsset = quote
+	return detail
+}
+
+func TestFindSwapCycles_matchesThreeLegChain(t *testing.T) {
+	book := map[string]swapBookQuote{
+		"ETHUSDT": {Bid: 2300, Ask: 2326},
+		"BTCUSDT": {Bid: 78000, Ask: 78100},
+		"ETHBTC":  {Bid: 0.0298, Ask: 0.03},
+	}
+	idx := map[string]*mexc.SymbolDetail{
+		"ETHUSDT": testSwapPair("ETHUSDT", "ETH", "USDT", "0.00001", "0.001"),
+		"BTCUSDT": testSwapPair("BTCUSDT", "BTC", "USDT", "0.000001", "0.001"),
+		"ETHBTC":  testSwapPair("ETHBTC", "ETH", "BTC", "0.00001", "0.001"),
+	}
+	coinA := &mexc.SymbolDetail{BaseAsset: "ETH", Symbol: "ETHUSDT", QuoteAsset: "USDT"}
+	coinB := &mexc.SymbolDetail{BaseAsset: "BTC", Symbol: "BTCUSDT", QuoteAsset: "USDT"}
+	chain, ok := calcSwapChainFromBook(book, coinA, coinB, &SwapChainMeta{Index: idx, InterBuffer: 0.999, RequireRealism: true})
+	if !ok {
+		t.Fatal("chain expected")
+	}
+
+	cycles := findSwapCycles(buildSwapGraph(book, idx, nil), swapCycleSearch{
+		Starts: []string{"USDT"}, MinLegs: 3, MaxLegs: 3, InterBuffer: 0.999, MinProfitPercent: swapNoProfitFloor,
+	})
+	// USDT -> BTC -> ETH -> USDT и USDT -> ETH -> BTC -> USDT.
+	if len(cycles) != 2 {
+		t.Fatalf("expected both directions of the triangle, got %d", len(cycles))
+	}
+	for _, cycle := range cycles {
+		if cycle.Path() != "USDT -> ETH -> BTC -> USDT" {
+			continue
+		}
+		if math.Abs(cycle.ProfitPercent-chain.profitPercent) > 1e-9 {
+			t.Fatalf("cycle profit %g must match chain profit %g", cycle.ProfitPercent, chain.profitPercent)
+		}
+		return
+	}
+	t.Fatalf("USDT -> ETH -> BTC -> USDT not found in %+v", cycles)
+}
+
+func TestFindSwapCycles_fourLegsFromAnyStart(t *testing.T) {
+	// AAA дорого продаётся за BTC: прибыльны и USDT -> AAA -> BTC -> USDT, и
+	// цикл из четырёх ног через ETH.
+	book := map[string]swapBookQuote{
+		"AAAUSDT": {Bid: 0.99, Ask: 1.0},
+		"AAABTC":  {Bid: 0.0000105, Ask: 0.0000106},
+		"ETHBTC":  {Bid: 0.0299, Ask: 0.03},
+		"ETHUSDT": {Bid: 3000, Ask: 3001},
+		"BTCUSDT": {Bid: 99990, Ask: 100000},
+	}
+	idx := map[string]*mexc.SymbolDetail{
+		"AAAUSDT": testSwapPair("AAAUSDT", "AAA", "USDT", "0.01", "0"),
+		"AAABTC":  testSwapPair("AAABTC", "AAA", "BTC", "0.01", "0"),
+		"ETHBTC":  testSwapPair("ETHBTC", "ETH", "BTC", "0.0001", "0"),
+		"ETHUSDT": testSwapPair("ETHUSDT", "ETH", "USDT", "0.0001", "0"),
+		"BTCUSDT": testSwapPair("BTCUSDT", "BTC", "USDT", "0.000001", "0"),
+	}
+	graph := buildSwapGraph(book, idx, nil)
+
+	three := findSwapCycles(graph, swapCycleSearch{Starts: []string{"USDT"}, MinLegs: 3, MaxLegs: 3, Amount: 100, InterBuffer: 1, MinProfitPercent: swapNoProfitFloor})
+	four := findSwapCycles(graph, swapCycleSearch{Starts: []string{"USDT"}, MinLegs: 3, MaxLegs: 4, Amount: 100, InterBuffer: 1, MinProfitPercent: swapNoProfitFloor})
+	if len(four) <= len(three) {
+		t.Fatalf("4-leg search must add cycles: 3 legs %d, 4 legs %d", len(three), len(four))
+	}
+	found := false
+	for i, cycle := range four {
+		if i > 0 && cycle.ProfitPercent > four[i-1].ProfitPercent {
+			t.Fatalf("cycles must be ranked by profit")
+		}
+		if cycle.Path() == "USDT -> AAA -> BTC -> ETH -> USDT" {
+			found = cycle.ProfitPercent > 0 && len(cycle.Fills) == 4
+		}
+	}
+	if !found {
+		t.Fatalf("expected profitable 4-leg cycle USDT -> AAA -> BTC -> ETH -> USDT in %d cycles", len(four))
+	}
+
+	fromBTC := findSwapCycles(graph, swapCycleSearch{Starts: []string{"BTC"}, MinLegs: 3, MaxLegs: 4, Amount: 0.001, InterBuffer: 1, MinProfitPercent: 0})
+	if len(fromBTC) == 0 || fromBTC[0].Start() != "BTC" || fromBTC[0].ProfitPercent <= 0 {
+		t.Fatalf("expected profitable cycle from BTC, got %+v", fromBTC)
+	}
+	for _, cycle := range fromBTC {
+		if cycle.ProfitPercent < 0 {
+			t.Fatalf("profit floor must drop losing cycles, got %s %.4f%%", cycle.Path(), cycle.ProfitPercent)
+		}
+	}
+}
+
+func TestRankSwapCyclesByDepth_prefersDeepBooks(t *testing.T) {
+	cycles := []swapCycle{
+		{ProfitPercent: 3, DepthProfitPercent: 0.5, DepthChecked: true},
+		{ProfitPercent: 5},
+		{ProfitPercent: 2, DepthProfitPercent: 1.5, DepthChecked: true},
+	}
+	rankSwapCyclesByDepth(cycles)
+	if cycles[0].ProfitPercent != 2 || cycles[1].ProfitPercent != 3 || cycles[2].DepthChecked {
+		t.Fatalf("unexpected depth ranking %+v", cycles)
+	}
+}
+
+func TestSwapCycleCapacity_smallestLegInStartAsset(t *testing.T) {
+	legs := []swapLeg{
+		{Symbol: "ETHUSDT", From: "USDT", To: "ETH", Side: order.BUY, Price: 2000, Qty: 0.01},
+		{Symbol: "ETHBTC", From: "ETH", To: "BTC", Side: order.SELL, Price: 0.021, Qty: 0.002},
+		{Symbol: "BTCUSDT", From: "BTC", To: "USDT", Side: order.SELL, Price: 100000, Qty: 1},
+	}
+	// Нога 2 берёт 0.002 ETH — это 4 USDT по ask первой ноги.
+	if got := swapCycleCapacity(legs); math.Abs(got-4) > 1e-9 {
+		t.Fatalf("expected capacity 4 USDT, got %g", got)
+	}
+	legs[2].Qty = 0
+	if got := swapCycleCapacity(legs); got != 0 {
+		t.Fatalf("unknown leg volume must give 0, got %g", got)
+	}
+}
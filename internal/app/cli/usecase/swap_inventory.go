@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/model"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const (
+	defaultSwapInventoryUSDT = 20.0
+	// swapInventoryTolerance — на какую долю цели запас может отклониться,
+	// прежде чем ребаланс докупит или продаст его до цели.
+	swapInventoryTolerance = 0.3
+)
+
+// defaultSwapInventoryAssets — узлы, через которые проходит большинство
+// циклов MEXC.
+var defaultSwapInventoryAssets = []string{"BTC", "ETH", "USDC"}
+
+// rebalanceSwapInventory держит запас каждого актива opts.Inventory около
+// InventoryUSDT: если стоимость по паре ASSETUSDT вышла за допуск,
+// докупает или продаёт актив по рынку за USDT. Докупка проходит проверку
+// риска как вход и записывается в swap_inventory. Продаётся только то, что
+// купил ребаланс: чужой баланс актива и стартовые активы opts.Starts не
+// продаются. Актив без пары с USDT, сделка меньше минимального ордера и
+// заблокированная риском докупка пропускаются с пояснением.
+func (u *SwapProcess) rebalanceSwapInventory(ctx context.Context, book map[string]swapBookQuote, index map[string]*mexc.SymbolDetail, opts SwapProcessOptions, runID int64) error {
+	acct, err := u.repo.GetBalance(ctx)
+	if err != nil {
+		return wrap.Errorf("запас: баланс: %w", err)
+	}
+	free := make(map[string]float64, len(acct.Balances))
+	for _, bal := range acct.Balances {
+		free[bal.Asset] = bal.Free
+	}
+	stock, err := u.stateRepo.ListSwapInventory(ctx)
+	if err != nil {
+		return wrap.Errorf("запас: %w", err)
+	}
+	bought := make(map[string]repo.SwapInventory, len(stock))
+	for _, item := range stock {
+		bought[item.Asset] = item
+	}
+	starts := make(map[string]bool, len(opts.Starts))
+	for _, start := range opts.Starts {
+		starts[start] = true
+	}
+
+	target := opts.InventoryUSDT
+	for _, asset := range opts.Inventory {
+		if asset == swapStartAsset {
+			continue
+		}
+		symbol := asset + swapStartAsset
+		detail := index[symbol]
+		quote := book[symbol]
+		if detail == nil || quote.Bid <= 0 || quote.Ask <= 0 {
+			fmt.Printf("[DEBUG] Запас %s: нет пары %s с котировками, пропуск\n", asset, symbol)
+			continue
+		}
+		step, err := swapLotStep(detail)
+		if err != nil {
+			return err
+		}
+		value := free[asset] * quote.Bid
+		minQuote := parsePositiveFloat(detail.QuoteAmountPrecision)
+		params := model.OrderParams{
+			Symbol:           symbol,
+			OrderType:        order.MARKET,
+			NewClientOrderId: fmt.Sprintf("swap_%d_inv_%s", runID, asset),
+		}
+		switch {
+		case value < target*(1-swapInventoryTolerance):
+			params.Side = order.BUY
+			params.Quantity = swapRoundQtyDown(min(target-value, free[swapStartAsset])/quote.Ask, step)
+			if cost := params.Quantity * quote.Ask; params.Quantity <= 0 || cost < minQuote {
+				fmt.Printf("[DEBUG] Запас %s: докупить на %.4f USDT меньше минимального ордера или нет USDT\n", asset, target-value)
+				continue
+			}
+			blocked, err := entryBlockedByRisk(ctx, u.risk, symbol, params.Quantity*quote.Ask)
+			if err != nil {
+				return err
+			}
+			if blocked {
+				continue
+			}
+			free[swapStartAsset] -= params.Quantity * quote.Ask
+			fmt.Printf("[DEBUG] Запас %s: %.4f USDT из %.4f, BUY %s %.8f\n", asset, value, target, symbol, params.Quantity)
+		case value > target*(1+swapInventoryTolerance):
+			if starts[asset] {
+				fmt.Printf("[DEBUG] Запас %s: стартовый актив, излишек %.4f USDT не продаётся\n", asset, value-target)
+				continue
+			}
+			params.Side = order.SELL
+			params.Quantity = swapRoundQtyDown(min((value-target)/quote.Bid, bought[asset].Quantity), step)
+			if params.Quantity <= 0 || params.Quantity*quote.Bid < minQuote {
+				fmt.Printf("[DEBUG] Запас %s: излишек %.4f USDT, купленного ребалансом %.8f — меньше минимального ордера\n", asset, value-target, bought[asset].Quantity)
+				continue
+			}
+			fmt.Printf("[DEBUG] Запас %s: %.4f USDT из %.4f, SELL %s %.8f\n", asset, value, target, symbol, params.Quantity)
+		default:
+			continue
+		}
+		placed, err := u.repo.NewOrder(ctx, params)
+		if err != nil {
+			return wrap.Errorf("запас %s: %s %s: %w", asset, params.Side.String(), symbol, err)
+		}
+		result, err := u.queryIOCOrder(ctx, symbol, placed.OrderID)
+		if err != nil {
+			return wrap.Errorf("запас %s: %w", asset, err)
+		}
+		item := swapInventoryAfterFill(bought[asset], params.Side, result, swapTakerFeeRate(detail))
+		item.Asset = asset
+		if err := u.stateRepo.SaveSwapInventory(ctx, item); err != nil {
+			return wrap.Errorf("запас %s: %w", asset, err)
+		}
+		bought[asset] = item
+	}
+	return nil
+}
+
+// swapInventoryAfterFill — купленный ребалансом запас после его ордера:
+// BUY добавляет полученное после комиссии и потраченные USDT, SELL
+// списывает проданное и соразмерную часть стоимости.
+func swapInventoryAfterFill(item repo.SwapInventory, side order.Side, result *mexc.QueryOrderResult, feeRate float64) repo.SwapInventory {
+	if result == nil {
+		return item
+	}
+	executed, _ := strconv.ParseFloat(result.ExecutedQty, 64)
+	quote, _ := strconv.ParseFloat(result.CummulativeQuoteQty, 64)
+	if side == order.BUY {
+		item.Quantity += executed * (1 - feeRate)
+		item.Cost += quote
+		return item
+	}
+	if item.Quantity > 0 {
+		item.Cost -= item.Cost * min(executed/item.Quantity, 1)
+	}
+	item.Quantity = max(item.Quantity-executed, 0)
+	return item
+}
+
+// filterSwapCyclesByInventory оставляет циклы, все ноги которых после первой
+// оплачиваются из свободного баланса: в режиме inventory ноги выставляются
+// одновременно и не ждут, пока предыдущая принесёт актив.
+func filterSwapCyclesByInventory(cycles []swapCycle, balances []mexc.Balance) []swapCycle {
+	free := make(map[string]float64, len(balances))
+	for _, bal := range balances {
+		free[bal.Asset] = bal.Free
+	}
+	out := cycles[:0:0]
+	for _, cycle := range cycles {
+		covered := true
+		for i := 1; i < len(cycle.Legs) && i < len(cycle.Fills); i++ {
+			if free[cycle.Legs[i].From] < cycle.Fills[i].Spent {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			out = append(out, cycle)
+		}
+	}
+	return out
+}
+
+// swapInventoryFill — ответ биржи по ноге, выставленной одновременно с
+// остальными.
+type swapInventoryFill struct {
+	orderID string
+	result  *mexc.QueryOrderResult
+	err     error
+}
+
+// runSwapCycleInventory выставляет все ноги цикла одновременно ордерами
+// IMMEDIATE_OR_CANCEL на объёмы плана: промежуточные активы берутся из
+// запаса, и первая нога не задерживает остальные. Неполное исполнение не
+// разбирается — расхождение остаётся в запасе до следующего ребаланса.
+// Ошибки ног возвращаются вместе после итога запуска.
+func (u *SwapProcess) runSwapCycleInventory(ctx context.Context, cycle swapCycle, index map[string]*mexc.SymbolDetail, opts SwapProcessOptions, runID int64, journal *swapJournal) error {
+	records := make([]repo.SwapLeg, len(cycle.Legs))
+	params := make([]model.OrderParams, len(cycle.Legs))
+	for i, leg := range cycle.Legs {
+		fill := cycle.Fills[i]
+		price := swapIOCPrice(leg, index[leg.Symbol], opts.SlippagePercent)
+		clientOrderID := fmt.Sprintf("swap_%d_%d_%s", runID, i+1, leg.Symbol)
+		records[i] = journal.newLeg(cycle, i, fill, clientOrderID)
+		journal.saveLeg(ctx, records[i])
+		params[i] = model.OrderParams{
+			Symbol:           leg.Symbol,
+			Side:             leg.Side,
+			OrderType:        order.IMMEDIATE_OR_CANCEL,
+			Quantity:         fill.Qty,
+			Price:            price,
+			NewClientOrderId: clientOrderID,
+		}
+		fmt.Printf("[DEBUG] Шаг %d: IOC %s %s | quantity=%.8f price=%.8f (лучшая %.8f)\n",
+			i+1, leg.Side.String(), leg.Symbol, fill.Qty, price, leg.Price)
+	}
+
+	fills := make([]swapInventoryFill, len(cycle.Legs))
+	var wg sync.WaitGroup
+	for i := range params {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			placed, err := u.repo.NewOrder(ctx, params[i])
+			if err != nil {
+				fills[i].err = wrap.Errorf("place IOC %d (%s %s): %w", i+1, params[i].Side.String(), params[i].Symbol, err)
+				return
+			}
+			fills[i].orderID = placed.OrderID
+			fills[i].result, fills[i].err = u.queryIOCOrder(ctx, params[i].Symbol, placed.OrderID)
+		}(i)
+	}
+	wg.Wait()
+
+	orderIDs := make([]string, 0, len(fills))
+	var legErrs []error
+	for i, fill := range fills {
+		record := records[i]
+		if fill.orderID == "" {
+			record.Status = swapLegPlaceFailed
+		} else {
+			orderIDs = append(orderIDs, fill.orderID)
+			record.OrderID = fill.orderID
+			recordSwapLegFill(&record, fill.result, cycle.Legs[i].Fee)
+		}
+		journal.saveLeg(ctx, record)
+		if fill.err != nil {
+			legErrs = append(legErrs, fill.err)
+		}
+		fmt.Printf("[DEBUG] Шаг %d: %s, исполнено %.8f из %.8f\n", i+1, record.Status, record.ExecutedQuantity, record.Quantity)
+	}
+	legErr := errors.Join(legErrs...)
+	if legErr != nil {
+		journal.note(legErr)
+	}
+	result := journal.finish(ctx, len(cycle.Legs), swapUnwindResult{}, nil)
+
+	fmt.Printf("\n=== Inventory-цикл: %s ===\n", result.Status)
+	fmt.Printf("Ордера: %s\n", strings.Join(orderIDs, ", "))
+	flows := swapNetFlows(journal.legs)
+	for _, asset := range sortedSwapAssets(flows) {
+		fmt.Printf("  %s: %+.8f\n", asset, flows[asset])
+	}
+	if result.FinalAmount > 0 {
+		fmt.Printf("Запуск #%d: ожидали %.4f%% (стакан %.4f%%), получили %.4f%%\n",
+			result.ID, result.ExpectedProfitPercent, result.ExpectedDepthProfitPercent, result.RealizedProfitPercent)
+	}
+	return legErr
+}
@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/helpers"
+	"github.com/drybin/palisade/internal/domain/model"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const (
+	// IOC исполняется сразу, но ответ на запрос ордера может на мгновение
+	// отставать: ордер переспрашивается, пока он активен.
+	swapIOCQueryAttempts = 5
+	swapIOCQueryInterval = 200 * time.Millisecond
+)
+
+// executeSwapCycleIOC отправляет ноги подряд лимитными IMMEDIATE_OR_CANCEL
+// не хуже лучшей цены на SlippagePercent, без ожидания: каждая следующая нога
+// берёт то, что фактически исполнила предыдущая. Неполное исполнение или
+// ошибка ноги останавливают цикл; остатки промежуточных активов потом
+// разбирает liquidateSwapResiduals.
+func (u *SwapProcess) executeSwapCycleIOC(
+	ctx context.Context,
+	cycle swapCycle,
+	index map[string]*mexc.SymbolDetail,
+	opts SwapProcessOptions,
+	runID int64,
+	journal *swapJournal,
+) ([]string, error) {
+	orderIDs := make([]string, 0, len(cycle.Legs))
+	in := cycle.Amount
+	for i, leg := range cycle.Legs {
+		step := strconv.Itoa(i + 1)
+		priced := leg
+		priced.Price = swapIOCPrice(leg, index[leg.Symbol], opts.SlippagePercent)
+		fill := fillSwapLeg(priced, in)
+		if fill.Qty <= 0 {
+			return orderIDs, wrap.Errorf("шаг %s: количество по паре %s обнулилось после округления (step=%g)", step, leg.Symbol, leg.Step)
+		}
+		fmt.Printf("[DEBUG] Шаг %s: IOC %s %s | quantity=%.8f price=%.8f (лучшая %.8f)\n",
+			step, leg.Side.String(), leg.Symbol, fill.Qty, priced.Price, leg.Price)
+
+		clientOrderID := fmt.Sprintf("swap_%d_%s_%s", runID, step, leg.Symbol)
+		record := journal.newLeg(cycle, i, fill, clientOrderID)
+		journal.saveLeg(ctx, record)
+		placed, err := u.repo.NewOrder(ctx, model.OrderParams{
+			Symbol:           leg.Symbol,
+			Side:             leg.Side,
+			OrderType:        order.IMMEDIATE_OR_CANCEL,
+			Quantity:         fill.Qty,
+			Price:            priced.Price,
+			NewClientOrderId: clientOrderID,
+		})
+		if err != nil {
+			record.Status = swapLegPlaceFailed
+			journal.saveLeg(ctx, record)
+			return orderIDs, wrap.Errorf("place IOC %s (%s %s): %w", step, leg.Side.String(), leg.Symbol, err)
+		}
+		orderIDs = append(orderIDs, placed.OrderID)
+		record.OrderID = placed.OrderID
+
+		result, err := u.queryIOCOrder(ctx, leg.Symbol, placed.OrderID)
+		recordSwapLegFill(&record, result, leg.Fee)
+		journal.saveLeg(ctx, record)
+		if err != nil {
+			return orderIDs, wrap.Errorf("step %s: %w", step, err)
+		}
+		fmt.Printf("[DEBUG] Шаг %s: %s, исполнено %.8f из %.8f\n", step, record.Status, record.ExecutedQuantity, fill.Qty)
+		in = swapLegReceived(record)
+		if in <= 0 {
+			return orderIDs, nil
+		}
+	}
+	return orderIDs, nil
+}
+
+// swapIOCPrice — лимит IOC: BUY не дороже ask + slippage, SELL не дешевле
+// bid − slippage, с округлением к шагу цены в пределах допуска.
+func swapIOCPrice(leg swapLeg, detail *mexc.SymbolDetail, slippagePercent float64) float64 {
+	if detail == nil {
+		return leg.Price
+	}
+	step := signalPriceStep(detail)
+	if leg.Side == order.BUY {
+		return roundPriceDown(leg.Price*(1+slippagePercent/100), step)
+	}
+	return roundPriceUp(leg.Price*(1-slippagePercent/100), step)
+}
+
+func (u *SwapProcess) queryIOCOrder(ctx context.Context, symbol, orderID string) (*mexc.QueryOrderResult, error) {
+	var last *mexc.QueryOrderResult
+	for attempt := 0; attempt < swapIOCQueryAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return last, ctx.Err()
+			case <-time.After(swapIOCQueryInterval):
+			}
+		}
+		q, err := u.repo.GetOrderQuery(ctx, symbol, orderID)
+		if err != nil {
+			return last, wrap.Errorf("query IOC order %s: %w", orderID, err)
+		}
+		last = q
+		if q != nil && q.Status != "NEW" && q.Status != "PARTIALLY_FILLED" {
+			return q, nil
+		}
+	}
+	return last, nil
+}
+
+// swapNetFlows — на сколько ноги цикла изменили баланс каждого актива:
+// получено минус потрачено; продажи остатков не считаются.
+func swapNetFlows(legs []repo.SwapLeg) map[string]float64 {
+	flows := map[string]float64{}
+	for _, leg := range legs {
+		if leg.Liquidation {
+			continue
+		}
+		flows[leg.ToAsset] += swapLegReceived(leg)
+		flows[leg.FromAsset] -= swapLegSpent(leg)
+	}
+	return flows
+}
+
+// swapResiduals — сколько каждого промежуточного актива осталось после ног:
+// получено ногой минус потрачено следующей. Стартовый актив и пыль от
+// округления ниже порога не считаются.
+func swapResiduals(start string, legs []repo.SwapLeg) map[string]float64 {
+	out := map[string]float64{}
+	for asset, qty := range swapNetFlows(legs) {
+		if asset != start && qty > 1e-12 {
+			out[asset] = qty
+		}
+	}
+	return out
+}
+
+// liquidateSwapResiduals обменивает остатки промежуточных активов на
+// стартовый по рынку и пишет обмены в журнал: остаток продаётся парой
+// ASSETSTART, а без неё стартовый актив покупается за остаток парой
+// STARTASSET (BTCUSDT для остатка USDT при старте из BTC). Остаток, который
+// меньше минимальной суммы ордера или для которого нет пары со стартовым
+// активом, остаётся на балансе и только печатается.
+func (u *SwapProcess) liquidateSwapResiduals(ctx context.Context, start string, index map[string]*mexc.SymbolDetail, runID int64, journal *swapJournal) error {
+	residuals := swapResiduals(start, journal.legs)
+	if len(residuals) == 0 {
+		return nil
+	}
+	bookRows, err := u.repo.GetAllBookTickers(ctx)
+	if err != nil {
+		return wrap.Errorf("ликвидация: book ticker: %w", err)
+	}
+	book := BuildSwapBookMap(bookRows)
+	acct, err := u.repo.GetBalance(ctx)
+	if err != nil {
+		return wrap.Errorf("ликвидация: баланс: %w", err)
+	}
+
+	legNo := len(journal.legs)
+	for _, asset := range sortedSwapAssets(residuals) {
+		symbol, side, quoteAsset := asset+start, order.SELL, start
+		detail := index[symbol]
+		if detail == nil {
+			symbol, side, quoteAsset = start+asset, order.BUY, asset
+			detail = index[symbol]
+		}
+		if detail == nil {
+			fmt.Printf("[DEBUG] Ликвидация: нет пары %s или %s, остаток %.8f %s остаётся на балансе\n", asset+start, start+asset, residuals[asset], asset)
+			continue
+		}
+		residual := residuals[asset]
+		if bal, err := helpers.FindAssetBalance(acct.Balances, asset); err == nil {
+			residual = math.Min(residual, bal.Free)
+		}
+		step, err := swapLotStep(detail)
+		if err != nil {
+			return err
+		}
+		// qty — в базовом активе пары: сам остаток для SELL, стартовый актив
+		// на остаток по ask для BUY; value — в котируемом активе.
+		var qty, price float64
+		if side == order.SELL {
+			price = book[symbol].Bid
+			qty = swapRoundQtyDown(residual, step)
+		} else if price = book[symbol].Ask; price > 0 {
+			qty = swapRoundQtyDown(residual/price, step)
+		}
+		value := qty * price
+		if qty <= 0 || value < parsePositiveFloat(detail.QuoteAmountPrecision) {
+			fmt.Printf("[DEBUG] Ликвидация: остаток %.8f %s (~%.8f %s) меньше минимального ордера\n", residuals[asset], asset, value, quoteAsset)
+			continue
+		}
+
+		legNo++
+		clientOrderID := fmt.Sprintf("swap_%d_liq_%s", runID, asset)
+		record := journal.newLiquidationLeg(legNo, symbol, side, asset, start, price, qty, clientOrderID)
+		journal.saveLeg(ctx, record)
+		fmt.Printf("[DEBUG] Ликвидация: %s %s по рынку | quantity=%.8f (цена %.8f)\n", side.String(), symbol, qty, price)
+		placed, err := u.repo.NewOrder(ctx, model.OrderParams{
+			Symbol:           symbol,
+			Side:             side,
+			OrderType:        order.MARKET,
+			Quantity:         qty,
+			NewClientOrderId: clientOrderID,
+		})
+		if err != nil {
+			record.Status = swapLegPlaceFailed
+			journal.saveLeg(ctx, record)
+			return wrap.Errorf("ликвидация: %s %s: %w", side.String(), symbol, err)
+		}
+		record.OrderID = placed.OrderID
+		result, err := u.queryIOCOrder(ctx, symbol, placed.OrderID)
+		recordSwapLegFill(&record, result, swapTakerFeeRate(detail))
+		journal.saveLeg(ctx, record)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[DEBUG] Ликвидация %s: %s, получено %.8f %s\n", asset, record.Status, swapLegReceived(record), start)
+	}
+	return nil
+}
+
+// runSwapCycleIOC исполняет цикл в режиме ioc, при необходимости
+// ликвидирует остатки и печатает итог запуска с убытком от ликвидации.
+func (u *SwapProcess) runSwapCycleIOC(ctx context.Context, cycle swapCycle, index map[string]*mexc.SymbolDetail, opts SwapProcessOptions, runID int64, journal *swapJournal) error {
+	orderIDs, legErr := u.executeSwapCycleIOC(ctx, cycle, index, opts, runID, journal)
+	if legErr != nil {
+		fmt.Printf("[DEBUG] Цикл остановлен: %v\n", legErr)
+		journal.note(legErr)
+	}
+	var liqErr error
+	if opts.Liquidate {
+		liqErr = u.liquidateSwapResiduals(ctx, cycle.Start(), index, runID, journal)
+	} else if residuals := swapResiduals(cycle.Start(), journal.legs); len(residuals) > 0 {
+		fmt.Printf("[DEBUG] Остатки без ликвидации: %v\n", residuals)
+	}
+	result := journal.finish(ctx, len(cycle.Legs), swapUnwindResult{}, liqErr)
+
+	fmt.Printf("\n=== IOC-цикл: %s ===\n", result.Status)
+	fmt.Printf("Ордера: %s\n", strings.Join(orderIDs, ", "))
+	if result.FinalAmount > 0 {
+		spent := swapLegSpent(journal.legs[0])
+		fmt.Printf("Потрачено %.8f %s, получено %.8f %s (из них ликвидация %.8f), P/L %.8f %s (%.4f%%)\n",
+			spent, result.StartAsset, result.FinalAmount, result.StartAsset, result.LiquidatedAmount,
+			result.FinalAmount-spent, result.StartAsset, result.RealizedProfitPercent)
+	}
+	return liqErr
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/drybin/palisade/internal/adapter/webapi/mexcsim"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+func newSimSwapPair(symbol, base, quote string, quotePrecision int, stepSize, minQuote string) mexc.SymbolDetail {
+	return mexc.SymbolDetail{
+		Symbol:               symbol,
+		Status:               "1",
+		BaseAsset:            base,
+		QuoteAsset:           quote,
+		QuotePrecision:       quotePrecision,
+		OrderTypes:           []string{"LIMIT", "MARKET", "IMMEDIATE_OR_CANCEL"},
+		IsSpotTradingAllowed: true,
+		QuoteAmountPrecision: minQuote,
+		MaxQuoteAmount:       "1000000",
+		MakerCommission:      "0",
+		TakerCommission:      "0",
+		Filters:              []mexc.SymbolFilter{{FilterType: "LOT_SIZE", StepSize: stepSize}},
+	}
+}
+
+func TestSwapProcess_simIOCLiquidatesResidual(t *testing.T) {
+	ex := mexcsim.NewExchange()
+	state := newMemState()
+	for _, pair := range []mexc.SymbolDetail{
+		newSimSwapPair("ETHUSDT", "ETH", "USDT", 2, "0.000001", "1"),
+		newSimSwapPair("ETHBTC", "ETH", "BTC", 6, "0.000001", "0.00001"),
+		newSimSwapPair("BTCUSDT", "BTC", "USDT", 2, "0.0000001", "1"),
+	} {
+		ex.AddSymbol(pair)
+		state.coins[pair.Symbol] = pair
+	}
+	ex.SetBalance("USDT", 100)
+	// USDT -> ETH -> BTC -> USDT даёт ~5% по лучшим ценам и по стаканам.
+	ex.SetBook("ETHUSDT", []mexcsim.Level{{Price: 1999, Qty: 1}}, []mexcsim.Level{{Price: 2000, Qty: 1}})
+	ex.SetBook("ETHBTC", []mexcsim.Level{{Price: 0.021, Qty: 1}}, []mexcsim.Level{{Price: 0.0211, Qty: 1}})
+	ex.SetBook("BTCUSDT", []mexcsim.Level{{Price: 100000, Qty: 1}}, []mexcsim.Level{{Price: 100010, Qty: 1}})
+	// Пока исполняется первая нога, bid ETHBTC почти уходит: вторая нога
+	// исполняется частично и ETH остаётся на балансе.
+	ex.SetOrderListener(func(o mexcsim.Order) {
+		if o.Symbol == "ETHUSDT" && o.Side == "BUY" {
+			ex.SetBook("ETHBTC", []mexcsim.Level{{Price: 0.021, Qty: 0.002}}, []mexcsim.Level{{Price: 0.0211, Qty: 1}})
+		}
+	})
+
+	u := NewSwapProcessUsecase(newSimWebapi(t, ex), state, nil)
+	opts := DefaultSwapProcessOptions()
+	opts.Mode = SwapModeIOC
+	if err := u.Process(context.Background(), opts); err != nil {
+		t.Fatalf("swap ioc: %v", err)
+	}
+
+	if len(state.swapRuns) != 1 {
+		t.Fatalf("expected one journaled run, got %d", len(state.swapRuns))
+	}
+	run := state.swapRuns[0]
+	if run.Mode != SwapModeIOC || run.Path != "USDT -> ETH -> BTC -> USDT" || run.Status != repo.SwapRunLiquidated {
+		t.Fatalf("expected liquidated ioc run, got %+v", run)
+	}
+	// Нога 2 продала только 0.002 ETH (0.000042 BTC → 4.2 USDT на ноге 3),
+	// остальной купленный ETH продан по bid 1999.
+	bought := state.swapLegs[[2]int{run.ID, 1}]
+	wantLiquidated := (bought.ExecutedQuantity - 0.002) * 1999
+	if wantLiquidated <= 0 || math.Abs(run.LiquidatedAmount-wantLiquidated) > 1e-6 || math.Abs(run.FinalAmount-(4.2+wantLiquidated)) > 1e-6 {
+		t.Fatalf("expected liquidated %.6f and final %.6f, got %+v", wantLiquidated, 4.2+wantLiquidated, run)
+	}
+	if want := ((4.2+wantLiquidated)/bought.CumulativeQuoteQty - 1) * 100; math.Abs(run.RealizedProfitPercent-want) > 1e-6 {
+		t.Fatalf("expected realized %.6f%%, got %.6f%%", want, run.RealizedProfitPercent)
+	}
+	liquidation := state.swapLegs[[2]int{run.ID, 4}]
+	if !liquidation.Liquidation || liquidation.Symbol != "ETHUSDT" || liquidation.Status != "FILLED" {
+		t.Fatalf("expected ETHUSDT liquidation leg, got %+v", liquidation)
+	}
+	if leg := state.swapLegs[[2]int{run.ID, 2}]; math.Abs(leg.ExecutedQuantity-0.002) > 1e-12 {
+		t.Fatalf("expected partial IOC on leg 2, got %+v", leg)
+	}
+	if eth, _ := ex.Balance("ETH"); eth > 1e-12 {
+		t.Fatalf("expected no ETH left after liquidation, got %.8f", eth)
+	}
+	if open := ex.OpenOrders(""); len(open) != 0 {
+		t.Fatalf("IOC must not leave resting orders, got %d", len(open))
+	}
+}
+
+func TestSwapProcess_simIOCLiquidatesResidualThroughStartPair(t *testing.T) {
+	ex, state := newSimSwapTriangle()
+	ex.SetBalance("BTC", 0.001)
+	// BTC -> USDT -> ETH -> BTC: пока продаётся BTC, ask ETHUSDT почти
+	// уходит, и USDT остаётся. Пары USDTBTC нет — BTC покупается за остаток
+	// по BTCUSDT.
+	ex.SetOrderListener(func(o mexcsim.Order) {
+		if o.Symbol == "BTCUSDT" && o.Side == "SELL" {
+			ex.SetBook("ETHUSDT", []mexcsim.Level{{Price: 1999, Qty: 1}}, []mexcsim.Level{{Price: 2000, Qty: 0.002}})
+		}
+	})
+
+	opts := DefaultSwapProcessOptions()
+	opts.Mode = SwapModeIOC
+	opts.Starts = []string{"BTC"}
+	if err := NewSwapProcessUsecase(newSimWebapi(t, ex), state, nil).Process(context.Background(), opts); err != nil {
+		t.Fatalf("swap ioc from BTC: %v", err)
+	}
+
+	if len(state.swapRuns) != 1 {
+		t.Fatalf("expected one journaled run, got %d", len(state.swapRuns))
+	}
+	run := state.swapRuns[0]
+	if run.Path != "BTC -> USDT -> ETH -> BTC" || run.Status != repo.SwapRunLiquidated || run.LiquidatedAmount <= 0 {
+		t.Fatalf("expected liquidated run from BTC, got %+v", run)
+	}
+	liquidation := state.swapLegs[[2]int{run.ID, 4}]
+	if !liquidation.Liquidation || liquidation.Symbol != "BTCUSDT" || liquidation.Side != "BUY" ||
+		liquidation.FromAsset != "USDT" || liquidation.ToAsset != "BTC" || liquidation.Status != "FILLED" {
+		t.Fatalf("expected BTCUSDT BUY liquidation leg, got %+v", liquidation)
+	}
+	// Остаток USDT меньше шага лота BTC по ask.
+	if usdt, _ := ex.Balance("USDT"); usdt > 100010*0.0000001 {
+		t.Fatalf("expected USDT residual bought back into BTC, got %.8f", usdt)
+	}
+}
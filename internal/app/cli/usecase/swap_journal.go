@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+// Исходы разворота шага 1 после таймаута; пусто — разворот не понадобился.
+const (
+	swapUnwindDone        = "UNWOUND"
+	swapUnwindNotBuy      = "NOT_BUY"
+	swapUnwindNoBid       = "NO_BID"
+	swapUnwindBidBelowBuy = "BID_BELOW_BUY"
+	swapUnwindNoBalance   = "NO_BALANCE"
+	swapUnwindFailed      = "UNWIND_FAILED"
+)
+
+// Статусы ноги до ответа биржи и когда ордер не нашёлся при опросе.
+const (
+	swapLegPlacing     = "PLACING"
+	swapLegPlaceFailed = "PLACE_FAILED"
+	swapLegNotFound    = "NOT_FOUND"
+)
+
+// swapUnwindResult — исход разворота и сколько стартового актива вернула
+// продажа после комиссии.
+type swapUnwindResult struct {
+	Outcome  string
+	Received float64
+}
+
+// swapJournal пишет запуск swap-process в swap_run/swap_leg. Ошибки БД не
+// прерывают цикл — на бирже уже может быть открыта позиция, — а только
+// печатаются; итог запуска считается и без записи в БД.
+type swapJournal struct {
+	stateRepo repo.IStateRepository
+	run       repo.SwapRun
+	saved     bool
+	legs      []repo.SwapLeg
+}
+
+func startSwapJournal(ctx context.Context, stateRepo repo.IStateRepository, cycle swapCycle, amount float64, mode string) *swapJournal {
+	journal := &swapJournal{stateRepo: stateRepo, run: repo.SwapRun{
+		Path:                       cycle.Path(),
+		StartAsset:                 cycle.Start(),
+		Mode:                       mode,
+		Amount:                     amount,
+		ExpectedProfitPercent:      cycle.ProfitPercent,
+		ExpectedDepthProfitPercent: cycle.DepthProfitPercent,
+		Status:                     repo.SwapRunRunning,
+		StartedAt:                  time.Now().UTC(),
+	}}
+	run, err := stateRepo.CreateSwapRun(ctx, journal.run)
+	if err != nil {
+		fmt.Printf("[WARN] swap journal: %v\n", err)
+		return journal
+	}
+	journal.run, journal.saved = *run, true
+	return journal
+}
+
+// newLeg создаёт запись ноги i перед выставлением ордера.
+func (j *swapJournal) newLeg(cycle swapCycle, i int, fill swapLegFill, clientOrderID string) repo.SwapLeg {
+	leg := cycle.Legs[i]
+	record := repo.SwapLeg{
+		RunID:         j.run.ID,
+		Leg:           i + 1,
+		Symbol:        leg.Symbol,
+		Side:          leg.Side.String(),
+		FromAsset:     leg.From,
+		ToAsset:       leg.To,
+		ExpectedPrice: leg.Price,
+		Quantity:      fill.Qty,
+		ClientOrderID: clientOrderID,
+		Status:        swapLegPlacing,
+		PlacedAt:      time.Now().UTC(),
+	}
+	if i < len(cycle.DepthPrices) {
+		record.ExpectedDepthPrice = cycle.DepthPrices[i]
+	}
+	return record
+}
+
+// newLiquidationLeg создаёт запись обмена остатка asset на стартовый актив
+// по рынку: SELL пары ASSETSTART или BUY пары STARTASSET. ExpectedPrice —
+// bid или ask на момент обмена.
+func (j *swapJournal) newLiquidationLeg(legNo int, symbol string, side order.Side, asset, start string, price, qty float64, clientOrderID string) repo.SwapLeg {
+	return repo.SwapLeg{
+		RunID:         j.run.ID,
+		Leg:           legNo,
+		Symbol:        symbol,
+		Side:          side.String(),
+		FromAsset:     asset,
+		ToAsset:       start,
+		ExpectedPrice: price,
+		Quantity:      qty,
+		ClientOrderID: clientOrderID,
+		Status:        swapLegPlacing,
+		PlacedAt:      time.Now().UTC(),
+		Liquidation:   true,
+	}
+}
+
+// saveLeg сохраняет ногу и запоминает её последнее состояние для итога
+// запуска.
+func (j *swapJournal) saveLeg(ctx context.Context, leg repo.SwapLeg) {
+	stored := false
+	for i := range j.legs {
+		if j.legs[i].Leg == leg.Leg {
+			j.legs[i], stored = leg, true
+			break
+		}
+	}
+	if !stored {
+		j.legs = append(j.legs, leg)
+	}
+	if !j.saved {
+		return
+	}
+	if err := j.stateRepo.SaveSwapLeg(ctx, leg); err != nil {
+		fmt.Printf("[WARN] swap journal: %v\n", err)
+	}
+}
+
+// note запоминает ошибку, которая остановила цикл, но не провалила запуск.
+func (j *swapJournal) note(err error) {
+	j.run.LastError = err.Error()
+}
+
+// finish закрывает запуск: статус, исход разворота и фактическая прибыль.
+func (j *swapJournal) finish(ctx context.Context, totalLegs int, unwind swapUnwindResult, runErr error) repo.SwapRun {
+	run := finalizeSwapRun(j.run, j.legs, totalLegs, unwind, runErr)
+	now := time.Now().UTC()
+	run.FinishedAt = &now
+	j.run = run
+	if j.saved {
+		if err := j.stateRepo.FinishSwapRun(ctx, run); err != nil {
+			fmt.Printf("[WARN] swap journal: %v\n", err)
+		}
+	}
+	return run
+}
+
+// finalizeSwapRun выставляет итог запуска по записанным ногам. Цикл
+// завершён, если исполнены все его ноги; иначе остатки либо ликвидированы,
+// либо остались на балансе (PARTIAL). Прибыль считается от потраченного на
+// первой ноге до полученного в стартовом активе после комиссии — последней
+// ногой, ликвидацией или разворотом. Если исполнение неизвестно, FinalAmount
+// и RealizedProfitPercent остаются 0.
+func finalizeSwapRun(run repo.SwapRun, legs []repo.SwapLeg, totalLegs int, unwind swapUnwindResult, runErr error) repo.SwapRun {
+	run.UnwindOutcome = unwind.Outcome
+	cycleLegs := make([]repo.SwapLeg, 0, len(legs))
+	run.LiquidatedAmount = 0
+	for _, leg := range legs {
+		if leg.Liquidation {
+			run.LiquidatedAmount += swapLegReceived(leg)
+			continue
+		}
+		cycleLegs = append(cycleLegs, leg)
+	}
+	switch {
+	case unwind.Outcome == swapUnwindDone:
+		run.Status = repo.SwapRunUnwound
+		run.FinalAmount = unwind.Received
+	case runErr != nil:
+		run.Status = repo.SwapRunFailed
+		run.LastError = runErr.Error()
+	case !swapAnyLegExecuted(cycleLegs):
+		run.Status = repo.SwapRunMissed
+	case swapCycleFilled(cycleLegs, totalLegs):
+		run.Status = repo.SwapRunCompleted
+		run.FinalAmount = swapLegReceived(cycleLegs[len(cycleLegs)-1])
+	case run.LiquidatedAmount > 0:
+		run.Status = repo.SwapRunLiquidated
+		run.FinalAmount = run.LiquidatedAmount
+		if last := cycleLegs[len(cycleLegs)-1]; last.ToAsset == run.StartAsset {
+			run.FinalAmount += swapLegReceived(last)
+		}
+	default:
+		run.Status = repo.SwapRunPartial
+	}
+	if len(cycleLegs) > 0 && run.FinalAmount > 0 {
+		if spent := swapLegSpent(cycleLegs[0]); spent > 0 {
+			run.RealizedProfitPercent = (run.FinalAmount/spent - 1) * 100
+		}
+	}
+	return run
+}
+
+// swapAnyLegExecuted — хоть одна нога цикла что-то исполнила; ордер, который
+// не нашёлся при опросе, считается исполненным.
+func swapAnyLegExecuted(legs []repo.SwapLeg) bool {
+	for _, leg := range legs {
+		if swapLegSpent(leg) > 0 || leg.Status == swapLegNotFound {
+			return true
+		}
+	}
+	return false
+}
+
+// swapCycleFilled — все ноги цикла выставлены и исполнены целиком; ордер,
+// который не нашёлся при опросе, считается исполненным.
+func swapCycleFilled(legs []repo.SwapLeg, totalLegs int) bool {
+	if totalLegs == 0 || len(legs) != totalLegs {
+		return false
+	}
+	for _, leg := range legs {
+		if leg.Status != "FILLED" && leg.Status != swapLegNotFound {
+			return false
+		}
+	}
+	return true
+}
+
+// recordSwapLegFill переносит в ногу исполнение из ответа биржи. Комиссию
+// MEXC в ответе не отдаёт — она оценивается по тейкерской ставке в активе,
+// который нога получает.
+func recordSwapLegFill(leg *repo.SwapLeg, q *mexc.QueryOrderResult, feeRate float64) {
+	now := time.Now().UTC()
+	leg.FinishedAt = &now
+	if q == nil {
+		leg.Status = swapLegNotFound
+		return
+	}
+	leg.Status = q.Status
+	// Нечитаемое количество оставляет исполнение неизвестным (0).
+	leg.ExecutedQuantity, _ = strconv.ParseFloat(q.ExecutedQty, 64)
+	leg.CumulativeQuoteQty, _ = strconv.ParseFloat(q.CummulativeQuoteQty, 64)
+	leg.RealizedPrice = 0
+	if leg.ExecutedQuantity > 0 {
+		leg.RealizedPrice = leg.CumulativeQuoteQty / leg.ExecutedQuantity
+	}
+	leg.Fee = swapLegGross(*leg) * feeRate
+}
+
+// swapLegGross — полученное ногой до комиссии: базовый актив для BUY,
+// котируемый для SELL.
+func swapLegGross(leg repo.SwapLeg) float64 {
+	if leg.Side == order.SELL.String() {
+		return leg.CumulativeQuoteQty
+	}
+	return leg.ExecutedQuantity
+}
+
+func swapLegReceived(leg repo.SwapLeg) float64 {
+	return swapLegGross(leg) - leg.Fee
+}
+
+// swapLegSpent — сколько актива FromAsset ушло на ногу.
+func swapLegSpent(leg repo.SwapLeg) float64 {
+	if leg.Side == order.SELL.String() {
+		return leg.ExecutedQuantity
+	}
+	return leg.CumulativeQuoteQty
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+func TestFinalizeSwapRun_realizedFromFirstAndLastLeg(t *testing.T) {
+	// USDT -> ETH -> BTC -> USDT: 10 USDT на первой ноге, 10.05 USDT брутто на последней.
+	legs := []repo.SwapLeg{
+		{Leg: 1, Side: "BUY"},
+		{Leg: 2, Side: "SELL"},
+		{Leg: 3, Side: "SELL"},
+	}
+	recordSwapLegFill(&legs[0], &mexc.QueryOrderResult{Status: "FILLED", ExecutedQty: "0.004", CummulativeQuoteQty: "10"}, 0.001)
+	recordSwapLegFill(&legs[1], &mexc.QueryOrderResult{Status: "FILLED", ExecutedQty: "0.004", CummulativeQuoteQty: "0.00012"}, 0.001)
+	recordSwapLegFill(&legs[2], &mexc.QueryOrderResult{Status: "FILLED", ExecutedQty: "0.00012", CummulativeQuoteQty: "10.05"}, 0.001)
+	if math.Abs(legs[0].RealizedPrice-2500) > 1e-9 || math.Abs(legs[0].Fee-0.000004) > 1e-12 {
+		t.Fatalf("unexpected first leg fill %+v", legs[0])
+	}
+
+	run := finalizeSwapRun(repo.SwapRun{ID: 1}, legs, 3, swapUnwindResult{}, nil)
+	wantFinal := 10.05 * (1 - 0.001)
+	if run.Status != repo.SwapRunCompleted || math.Abs(run.FinalAmount-wantFinal) > 1e-9 {
+		t.Fatalf("expected completed run with final %.8f, got %+v", wantFinal, run)
+	}
+	if want := (wantFinal/10 - 1) * 100; math.Abs(run.RealizedProfitPercent-want) > 1e-9 {
+		t.Fatalf("expected realized %.6f%%, got %.6f%%", want, run.RealizedProfitPercent)
+	}
+
+	failed := finalizeSwapRun(repo.SwapRun{ID: 2}, legs[:2], 3, swapUnwindResult{Outcome: swapUnwindBidBelowBuy}, errors.New("timeout"))
+	if failed.Status != repo.SwapRunFailed || failed.FinalAmount != 0 || failed.UnwindOutcome != swapUnwindBidBelowBuy || failed.LastError != "timeout" {
+		t.Fatalf("expected failed run without realized result, got %+v", failed)
+	}
+
+	unwound := finalizeSwapRun(repo.SwapRun{ID: 3}, legs[:2], 3, swapUnwindResult{Outcome: swapUnwindDone, Received: 9.9}, nil)
+	if unwound.Status != repo.SwapRunUnwound || math.Abs(unwound.RealizedProfitPercent+1) > 1e-9 {
+		t.Fatalf("expected unwound run at -1%%, got %+v", unwound)
+	}
+}
+
+func TestFinalizeSwapRun_iocPartialAndMissed(t *testing.T) {
+	legs := []repo.SwapLeg{
+		{Leg: 1, Side: "BUY", FromAsset: "USDT", ToAsset: "ETH"},
+		{Leg: 2, Side: "SELL", FromAsset: "ETH", ToAsset: "BTC"},
+	}
+	recordSwapLegFill(&legs[0], &mexc.QueryOrderResult{Status: "FILLED", ExecutedQty: "0.005", CummulativeQuoteQty: "10"}, 0)
+	recordSwapLegFill(&legs[1], &mexc.QueryOrderResult{Status: "PARTIALLY_CANCELED", ExecutedQty: "0.002", CummulativeQuoteQty: "0.000042"}, 0)
+
+	partial := finalizeSwapRun(repo.SwapRun{ID: 1, StartAsset: "USDT"}, legs, 3, swapUnwindResult{}, nil)
+	if partial.Status != repo.SwapRunPartial || partial.FinalAmount != 0 {
+		t.Fatalf("expected partial run without realized result, got %+v", partial)
+	}
+	residuals := swapResiduals("USDT", legs)
+	if len(residuals) != 2 || math.Abs(residuals["ETH"]-0.003) > 1e-12 || math.Abs(residuals["BTC"]-0.000042) > 1e-12 {
+		t.Fatalf("expected ETH and BTC residuals, got %v", residuals)
+	}
+
+	missed := []repo.SwapLeg{{Leg: 1, Side: "BUY"}}
+	recordSwapLegFill(&missed[0], &mexc.QueryOrderResult{Status: "CANCELED", ExecutedQty: "0", CummulativeQuoteQty: "0"}, 0)
+	if run := finalizeSwapRun(repo.SwapRun{ID: 2}, missed, 3, swapUnwindResult{}, nil); run.Status != repo.SwapRunMissed {
+		t.Fatalf("expected missed run, got %+v", run)
+	}
+}
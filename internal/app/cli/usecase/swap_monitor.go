@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const (
+	defaultSwapMonitorInterval       = 2 * time.Second
+	defaultSwapMonitorReportInterval = 5 * time.Minute
+	// swapMonitorHistogramWidth — длина самой длинной полосы гистограммы.
+	swapMonitorHistogramWidth = 40
+)
+
+// SwapMonitorOptions — Interval: как часто опрашивается bookTicker по всем
+// парам; ThresholdPercent: с какой прибыли цикл считается возможностью;
+// Amount: объём стартового актива, на котором прибыль считается с лотами;
+// Duration: сколько работать (0 — до сигнала).
+type SwapMonitorOptions struct {
+	Starts           []string
+	MaxLegs          int
+	Interval         time.Duration
+	ThresholdPercent float64
+	Amount           float64
+	Duration         time.Duration
+	ReportInterval   time.Duration
+}
+
+func DefaultSwapMonitorOptions() SwapMonitorOptions {
+	return SwapMonitorOptions{
+		Starts:           []string{swapStartAsset},
+		MaxLegs:          swapMaxCycleLegs,
+		Interval:         defaultSwapMonitorInterval,
+		ThresholdPercent: minProfitPercent,
+		Amount:           amountInUSDT,
+		ReportInterval:   defaultSwapMonitorReportInterval,
+	}
+}
+
+type ISwapMonitor interface {
+	Process(context.Context, SwapMonitorOptions) error
+}
+
+// SwapMonitor следит за арбитражными циклами без сделок: на каждом опросе
+// bookTicker пересчитывает все циклы по графу пар, разрешённых для
+// спот-торговли, и ведёт каждую возможность выше порога от появления до
+// исчезновения. Закрытые возможности пишутся в swap_opportunity; по ним
+// строится гистограмма времени жизни — держатся ли они дольше, чем
+// swap-process успевает исполнить цикл. Опрос, а не WebSocket: граф
+// покрывает все пары, а на соединение MEXC даёт 30 подписок.
+type SwapMonitor struct {
+	repo      repo.IMexcRepository
+	stateRepo repo.IStateRepository
+}
+
+func NewSwapMonitorUsecase(repo repo.IMexcRepository, stateRepo repo.IStateRepository) *SwapMonitor {
+	return &SwapMonitor{repo: repo, stateRepo: stateRepo}
+}
+
+func (u *SwapMonitor) Process(ctx context.Context, opts SwapMonitorOptions) error {
+	if len(opts.Starts) == 0 {
+		return wrap.Errorf("no start assets for cycle search")
+	}
+	if opts.MaxLegs < swapMinCycleLegs || opts.MaxLegs > swapMaxCycleLegs {
+		return wrap.Errorf("max legs must be between %d and %d, got %d", swapMinCycleLegs, swapMaxCycleLegs, opts.MaxLegs)
+	}
+	if opts.Interval <= 0 {
+		return wrap.Errorf("interval must be positive, got %s", opts.Interval)
+	}
+	if opts.Amount <= 0 {
+		return wrap.Errorf("amount must be positive, got %g", opts.Amount)
+	}
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	spotTradingAllowed := true
+	coinsAllowed, err := u.stateRepo.GetCoins(ctx, repo.GetCoinsParams{
+		IsSpotTradingAllowed: &spotTradingAllowed,
+		Limit:                10000,
+		Offset:               0,
+	})
+	if err != nil {
+		return wrap.Errorf("failed to get coins (spot allowed): %w", err)
+	}
+	symbolsAllowed := make(map[string]bool, len(coinsAllowed))
+	for _, c := range coinsAllowed {
+		symbolsAllowed[c.Symbol] = true
+	}
+	exchangeInfoAll, err := u.repo.GetExchangeInfoAll(ctx)
+	if err != nil {
+		return wrap.Errorf("exchange info all: %w", err)
+	}
+	symbolIndex := BuildSymbolDetailIndex(exchangeInfoAll)
+	search := swapCycleSearch{
+		Starts:           opts.Starts,
+		MinLegs:          swapMinCycleLegs,
+		MaxLegs:          opts.MaxLegs,
+		InterBuffer:      swapIntermediateBuffer,
+		MinProfitPercent: opts.ThresholdPercent,
+		Amount:           opts.Amount,
+	}
+	fmt.Printf("swap-monitor: старт %s, до %d ног, порог %.2f%%, опрос раз в %s, пар в БД %d\n",
+		strings.Join(opts.Starts, ","), opts.MaxLegs, opts.ThresholdPercent, opts.Interval, len(symbolsAllowed))
+
+	tracker := newSwapOpportunityTracker(opts.ThresholdPercent)
+	var session []repo.SwapOpportunity
+	save := func(closed []repo.SwapOpportunity) {
+		for _, opp := range closed {
+			fmt.Printf("[-] %s: %s, пик %.4f%%, объём на пике %.4f %s\n",
+				opp.Path, swapOpportunityLifetime(opp), opp.PeakProfitPercent, opp.PeakDepthAmount, opp.StartAsset)
+			if err := u.stateRepo.SaveSwapOpportunity(context.WithoutCancel(ctx), opp); err != nil {
+				fmt.Printf("[WARN] swap-monitor: %v\n", err)
+			}
+		}
+		session = append(session, closed...)
+	}
+
+	pollTicker := time.NewTicker(opts.Interval)
+	defer pollTicker.Stop()
+	// Промежуточная гистограмма печатается раз в ReportInterval; 0 — только
+	// итоговая.
+	var report <-chan time.Time
+	if opts.ReportInterval > 0 {
+		reportTicker := time.NewTicker(opts.ReportInterval)
+		defer reportTicker.Stop()
+		report = reportTicker.C
+	}
+	polls := 0
+	for {
+		bookRows, err := u.repo.GetAllBookTickers(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			fmt.Printf("swap-monitor: book ticker: %v\n", err)
+		} else {
+			polls++
+			graph := buildSwapGraph(BuildSwapBookMap(bookRows), symbolIndex, func(symbol string) bool { return symbolsAllowed[symbol] })
+			opened, closed := tracker.observe(time.Now().UTC(), findSwapCycles(graph, search))
+			for _, opp := range opened {
+				fmt.Printf("[+] %s: %.4f%%, объём %.4f %s\n", opp.Path, opp.FirstProfitPercent, opp.PeakDepthAmount, opp.StartAsset)
+			}
+			save(closed)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-report:
+			printSwapOpportunityHistogram(session)
+			continue
+		case <-pollTicker.C:
+			continue
+		}
+		break
+	}
+
+	save(tracker.closeAll(time.Now().UTC()))
+	fmt.Printf("\n=== swap-monitor: опросов %d ===\n", polls)
+	printSwapOpportunityHistogram(session)
+	return nil
+}
+
+// swapOpportunityTracker ведёт открытые возможности по записи цикла: цикл
+// выше порога открывает возможность или продлевает её, пропавший из
+// опроса — закрывает.
+type swapOpportunityTracker struct {
+	threshold float64
+	open      map[string]*repo.SwapOpportunity
+}
+
+func newSwapOpportunityTracker(threshold float64) *swapOpportunityTracker {
+	return &swapOpportunityTracker{threshold: threshold, open: map[string]*repo.SwapOpportunity{}}
+}
+
+// observe учитывает циклы одного опроса и возвращает новые и закрытые
+// возможности; закрытые отсортированы по записи цикла.
+func (t *swapOpportunityTracker) observe(now time.Time, cycles []swapCycle) (opened, closed []repo.SwapOpportunity) {
+	seen := make(map[string]bool, len(cycles))
+	for _, cycle := range cycles {
+		if cycle.ProfitPercent <= t.threshold {
+			continue
+		}
+		path := cycle.Path()
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		depth := swapCycleCapacity(cycle.Legs)
+		opp, ok := t.open[path]
+		if !ok {
+			opp = &repo.SwapOpportunity{
+				Path:               path,
+				StartAsset:         cycle.Start(),
+				Legs:               len(cycle.Legs),
+				ThresholdPercent:   t.threshold,
+				FirstSeenAt:        now,
+				FirstProfitPercent: cycle.ProfitPercent,
+				PeakProfitPercent:  cycle.ProfitPercent,
+				PeakAt:             now,
+				PeakDepthAmount:    depth,
+			}
+			t.open[path] = opp
+			opened = append(opened, *opp)
+		}
+		opp.LastSeenAt = now
+		opp.Observations++
+		if cycle.ProfitPercent > opp.PeakProfitPercent {
+			opp.PeakProfitPercent = cycle.ProfitPercent
+			opp.PeakAt = now
+			opp.PeakDepthAmount = depth
+		}
+		opp.MaxDepthAmount = max(opp.MaxDepthAmount, depth)
+	}
+	for path, opp := range t.open {
+		if seen[path] {
+			continue
+		}
+		opp.ClosedAt = now
+		closed = append(closed, *opp)
+		delete(t.open, path)
+	}
+	sort.Slice(closed, func(i, j int) bool { return closed[i].Path < closed[j].Path })
+	return opened, closed
+}
+
+// closeAll закрывает все открытые возможности при остановке монитора.
+func (t *swapOpportunityTracker) closeAll(now time.Time) []repo.SwapOpportunity {
+	_, closed := t.observe(now, nil)
+	return closed
+}
+
+// swapOpportunityLifetime — время между первым и последним опросом, где цикл
+// был выше порога: оценка снизу, 0 — цикл виден в одном опросе.
+func swapOpportunityLifetime(opp repo.SwapOpportunity) time.Duration {
+	return opp.LastSeenAt.Sub(opp.FirstSeenAt)
+}
+
+// swapLifetimeBucket — корзина гистограммы: возможности, прожившие меньше
+// UpTo (0 — последняя корзина без верхней границы).
+type swapLifetimeBucket struct {
+	Label string
+	UpTo  time.Duration
+	Count int
+}
+
+func swapLifetimeHistogram(opps []repo.SwapOpportunity) []swapLifetimeBucket {
+	buckets := []swapLifetimeBucket{
+		{Label: "один опрос"},
+		{Label: "< 5s", UpTo: 5 * time.Second},
+		{Label: "5-15s", UpTo: 15 * time.Second},
+		{Label: "15-60s", UpTo: time.Minute},
+		{Label: "1-5m", UpTo: 5 * time.Minute},
+		{Label: ">= 5m"},
+	}
+	for _, opp := range opps {
+		if opp.Observations <= 1 {
+			buckets[0].Count++
+			continue
+		}
+		lifetime := swapOpportunityLifetime(opp)
+		i := 1
+		for i < len(buckets)-1 && lifetime >= buckets[i].UpTo {
+			i++
+		}
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+func printSwapOpportunityHistogram(opps []repo.SwapOpportunity) {
+	fmt.Printf("Возможностей закрыто: %d\n", len(opps))
+	if len(opps) == 0 {
+		return
+	}
+	buckets := swapLifetimeHistogram(opps)
+	most := 0
+	for _, bucket := range buckets {
+		most = max(most, bucket.Count)
+	}
+	fmt.Println("Время жизни:")
+	for _, bucket := range buckets {
+		bar := strings.Repeat("#", (bucket.Count*swapMonitorHistogramWidth+most-1)/most)
+		fmt.Printf("  %-10s %5d %5.1f%% %s\n", bucket.Label, bucket.Count, float64(bucket.Count)/float64(len(opps))*100, bar)
+	}
+	var peak, depth float64
+	for _, opp := range opps {
+		peak += opp.PeakProfitPercent
+		depth += opp.PeakDepthAmount
+	}
+	fmt.Printf("Средний пик прибыли %.4f%%, средний объём на пике %.4f\n", peak/float64(len(opps)), depth/float64(len(opps)))
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/adapter/webapi/mexcsim"
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+func TestSwapOpportunityTracker_lifetimeAndPeak(t *testing.T) {
+	legs := []swapLeg{
+		{Symbol: "ETHUSDT", From: "USDT", To: "ETH", Side: order.BUY, Price: 2000, Qty: 1},
+		{Symbol: "ETHBTC", From: "ETH", To: "BTC", Side: order.SELL, Price: 0.021, Qty: 0.002},
+		{Symbol: "BTCUSDT", From: "BTC", To: "USDT", Side: order.SELL, Price: 100000, Qty: 1},
+	}
+	other := []swapLeg{
+		{Symbol: "AAAUSDT", From: "USDT", To: "AAA", Side: order.BUY, Price: 1, Qty: 100},
+		{Symbol: "AAABTC", From: "AAA", To: "BTC", Side: order.SELL, Price: 0.0000105, Qty: 100},
+		{Symbol: "BTCUSDT", From: "BTC", To: "USDT", Side: order.SELL, Price: 100000, Qty: 1},
+	}
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tracker := newSwapOpportunityTracker(1)
+
+	opened, closed := tracker.observe(t0, []swapCycle{
+		{Legs: legs, ProfitPercent: 2},
+		{Legs: other, ProfitPercent: 5},
+		{Legs: legs[:1], ProfitPercent: 0.5},
+	})
+	if len(opened) != 2 || len(closed) != 0 {
+		t.Fatalf("expected two opened opportunities, got opened %d closed %d", len(opened), len(closed))
+	}
+	_, single := tracker.observe(t0.Add(2*time.Second), []swapCycle{{Legs: legs, ProfitPercent: 3}})
+	if len(single) != 1 || single[0].Path != "USDT -> AAA -> BTC -> USDT" || single[0].Observations != 1 {
+		t.Fatalf("expected the AAA cycle to close after one poll, got %+v", single)
+	}
+	_, closed = tracker.observe(t0.Add(4*time.Second), nil)
+	if len(closed) != 1 {
+		t.Fatalf("expected the ETH cycle to close, got %+v", closed)
+	}
+	opp := closed[0]
+	if opp.Observations != 2 || swapOpportunityLifetime(opp) != 2*time.Second || !opp.ClosedAt.Equal(t0.Add(4*time.Second)) {
+		t.Fatalf("unexpected lifetime of %+v", opp)
+	}
+	if opp.FirstProfitPercent != 2 || opp.PeakProfitPercent != 3 || !opp.PeakAt.Equal(t0.Add(2*time.Second)) || math.Abs(opp.PeakDepthAmount-4) > 1e-9 {
+		t.Fatalf("unexpected peak of %+v", opp)
+	}
+
+	buckets := swapLifetimeHistogram(append(single, opp))
+	if buckets[0].Count != 1 || buckets[1].Count != 1 {
+		t.Fatalf("expected one single-poll and one under-5s opportunity, got %+v", buckets)
+	}
+}
+
+func TestSwapMonitor_simRecordsOpportunityOnStop(t *testing.T) {
+	ex := mexcsim.NewExchange()
+	state := newMemState()
+	for _, pair := range []mexc.SymbolDetail{
+		newSimSwapPair("ETHUSDT", "ETH", "USDT", 2, "0.000001", "1"),
+		newSimSwapPair("ETHBTC", "ETH", "BTC", 6, "0.000001", "0.00001"),
+		newSimSwapPair("BTCUSDT", "BTC", "USDT", 2, "0.0000001", "1"),
+	} {
+		ex.AddSymbol(pair)
+		state.coins[pair.Symbol] = pair
+	}
+	ex.SetBook("ETHUSDT", []mexcsim.Level{{Price: 1999, Qty: 1}}, []mexcsim.Level{{Price: 2000, Qty: 1}})
+	ex.SetBook("ETHBTC", []mexcsim.Level{{Price: 0.021, Qty: 0.002}}, []mexcsim.Level{{Price: 0.0211, Qty: 1}})
+	ex.SetBook("BTCUSDT", []mexcsim.Level{{Price: 100000, Qty: 1}}, []mexcsim.Level{{Price: 100010, Qty: 1}})
+
+	opts := DefaultSwapMonitorOptions()
+	opts.Interval = 20 * time.Millisecond
+	opts.Duration = 200 * time.Millisecond
+	opts.ReportInterval = 0
+	if err := NewSwapMonitorUsecase(newSimWebapi(t, ex), state).Process(context.Background(), opts); err != nil {
+		t.Fatalf("swap monitor: %v", err)
+	}
+
+	if len(state.swapOpps) != 1 {
+		t.Fatalf("expected one recorded opportunity, got %+v", state.swapOpps)
+	}
+	opp := state.swapOpps[0]
+	if opp.Path != "USDT -> ETH -> BTC -> USDT" || opp.Observations < 2 || opp.PeakProfitPercent <= 1 {
+		t.Fatalf("unexpected opportunity %+v", opp)
+	}
+	if math.Abs(opp.PeakDepthAmount-4) > 1e-9 || opp.ClosedAt.Before(opp.LastSeenAt) {
+		t.Fatalf("expected 4 USDT depth and closing after the last poll, got %+v", opp)
+	}
+}
@@ -4,17 +4,16 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/domain/enum/order"
 	"github.com/drybin/palisade/internal/domain/helpers"
 	"github.com/drybin/palisade/internal/domain/model"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/service"
 	"github.com/drybin/palisade/pkg/wrap"
 )
 
@@ -88,22 +87,93 @@ const (
 	orderFillPollInterval = 3 * time.Second
 	// swapIntermediateBuffer — запас под комиссию и округление между шагами цепочки.
 	swapIntermediateBuffer = 0.999
+	// swapDepthLimit — уровней стакана для проверки цикла на его объёме.
+	swapDepthLimit = 20
+	// swapStartAsset — стартовый актив по умолчанию и валюта, в которой
+	// считаются объёмы циклов и запаса.
+	swapStartAsset   = "USDT"
+	swapMinCycleLegs = 3
+	swapMaxCycleLegs = 4
+	// swapTopCycles — сколько лучших циклов по лучшим ценам держит поиск,
+	// swapDepthCandidates — сколько из них пересчитывается по стаканам.
+	swapTopCycles       = 50
+	swapDepthCandidates = 5
+	// defaultSwapIOCSlippagePercent — насколько IOC-нога может быть хуже
+	// лучшей цены.
+	defaultSwapIOCSlippagePercent = 0.1
 )
 
+// Режимы исполнения swap-process.
+const (
+	// SwapModeLimit — ноги по очереди лимитными ордерами с ожиданием
+	// исполнения и разворотом шага 1 по таймауту.
+	SwapModeLimit = "limit"
+	// SwapModeIOC — ноги подряд IMMEDIATE_OR_CANCEL без ожидания, остатки
+	// после неполного исполнения продаются в стартовый актив.
+	SwapModeIOC = "ioc"
+	// SwapModeInventory — все ноги одновременно IMMEDIATE_OR_CANCEL из
+	// заранее купленного запаса промежуточных активов.
+	SwapModeInventory = "inventory"
+)
+
+// SwapProcessOptions — параметры одного запуска. Starts — активы, из
+// которых ищутся циклы (SwapStartHeld — все активы на балансе); AmountUSDT
+// — объём цикла в USDT, для другого стартового актива пересчитывается по
+// его цене. SlippagePercent действует в режимах ioc и inventory, Liquidate —
+// только в ioc. Inventory и InventoryUSDT — активы запаса и целевой объём
+// каждого в USDT для режима inventory.
+type SwapProcessOptions struct {
+	Mode            string
+	Starts          []string
+	AmountUSDT      float64
+	SlippagePercent float64
+	Liquidate       bool
+	Inventory       []string
+	InventoryUSDT   float64
+}
+
+func DefaultSwapProcessOptions() SwapProcessOptions {
+	return SwapProcessOptions{
+		Mode:            SwapModeLimit,
+		Starts:          []string{swapStartAsset},
+		AmountUSDT:      amountInUSDT,
+		SlippagePercent: defaultSwapIOCSlippagePercent,
+		Liquidate:       true,
+		Inventory:       append([]string(nil), defaultSwapInventoryAssets...),
+		InventoryUSDT:   defaultSwapInventoryUSDT,
+	}
+}
+
 type ISwapProcess interface {
-	Process(ctx context.Context) error
+	Process(ctx context.Context, opts SwapProcessOptions) error
 }
 
 type SwapProcess struct {
-	repo      *webapi.MexcWebapi
+	repo      repo.IMexcRepository
 	stateRepo repo.IStateRepository
+	risk      *service.RiskManager
 }
 
-func NewSwapProcessUsecase(repo *webapi.MexcWebapi, stateRepo repo.IStateRepository) *SwapProcess {
-	return &SwapProcess{repo: repo, stateRepo: stateRepo}
+func NewSwapProcessUsecase(repo repo.IMexcRepository, stateRepo repo.IStateRepository, risk *service.RiskManager) *SwapProcess {
+	return &SwapProcess{repo: repo, stateRepo: stateRepo, risk: risk}
 }
 
-func (u *SwapProcess) Process(ctx context.Context) error {
+func (u *SwapProcess) Process(ctx context.Context, opts SwapProcessOptions) error {
+	if opts.Mode != SwapModeLimit && opts.Mode != SwapModeIOC && opts.Mode != SwapModeInventory {
+		return wrap.Errorf("unknown swap mode %q, want %s, %s or %s", opts.Mode, SwapModeLimit, SwapModeIOC, SwapModeInventory)
+	}
+	if opts.SlippagePercent < 0 || opts.SlippagePercent >= 100 {
+		return wrap.Errorf("slippage must be in [0, 100), got %g", opts.SlippagePercent)
+	}
+	if len(opts.Starts) == 0 {
+		return wrap.Errorf("no start assets for cycle search")
+	}
+	if opts.AmountUSDT <= 0 {
+		return wrap.Errorf("amount must be positive, got %g", opts.AmountUSDT)
+	}
+	if opts.Mode == SwapModeInventory && (len(opts.Inventory) == 0 || opts.InventoryUSDT <= 0) {
+		return wrap.Errorf("inventory mode needs inventory assets and a positive target, got %v and %g", opts.Inventory, opts.InventoryUSDT)
+	}
 	releaseLock, acquired, err := acquireTradingLock(ctx, u.stateRepo)
 	if err != nil {
 		return err
@@ -147,332 +217,234 @@ func (u *SwapProcess) Process(ctx context.Context) error {
 	symbolIndex := BuildSymbolDetailIndex(exchangeInfoAll)
 	fmt.Printf("[DEBUG] ExchangeInfo: символов в индексе: %d\n\n", len(symbolIndex))
 
-	// --- 2. Строим все цепочки USDT -> A -> B -> USDT (через BTC/ETH/USDC), только если все 3 пары в списке разрешённых ---
-	allowedHubs := map[string]bool{"BTC": true, "ETH": true, "USDC": true}
-	var baseAssets []string
-	for symbol := range book {
-		if strings.HasSuffix(symbol, "USDT") && len(symbol) > 4 {
-			base := symbol[:len(symbol)-4]
-			baseAssets = append(baseAssets, base)
-		}
-	}
-	sort.Strings(baseAssets)
-
-	type chainProfit struct {
-		baseA, baseB string
-		profit       float64
-	}
-	var allChains []chainProfit
-	for i := 0; i < len(baseAssets); i++ {
-		for j := 0; j < len(baseAssets); j++ {
-			if i == j {
-				continue
-			}
-			baseA, baseB := baseAssets[i], baseAssets[j]
-			if !allowedHubs[baseA] && !allowedHubs[baseB] {
-				continue
-			}
-			symbolAUSDT := baseA + "USDT"
-			symbolBUSDT := baseB + "USDT"
-			if !symbolsAllowed[symbolAUSDT] || !symbolsAllowed[symbolBUSDT] {
-				continue
-			}
-			coinA := mexc.SymbolDetail{BaseAsset: baseA, Symbol: symbolAUSDT, QuoteAsset: "USDT"}
-			coinB := mexc.SymbolDetail{BaseAsset: baseB, Symbol: symbolBUSDT, QuoteAsset: "USDT"}
-			res, ok := calcSwapChainFromBook(book, &coinA, &coinB, &SwapChainMeta{
-				Index:          symbolIndex,
-				InterBuffer:    swapIntermediateBuffer,
-				RequireRealism: true,
-			})
-			if !ok {
-				continue
-			}
-			if !symbolsAllowed[res.symbolAB] {
-				continue
-			}
-			allChains = append(allChains, chainProfit{baseA, baseB, res.profitPercent})
+	runID := time.Now().UnixMilli()
+	inventory := map[string]bool{}
+	if opts.Mode == SwapModeInventory {
+		for _, asset := range opts.Inventory {
+			inventory[asset] = true
 		}
-	}
-	sort.Slice(allChains, func(i, j int) bool { return allChains[i].profit > allChains[j].profit })
-
-	if len(allChains) == 0 {
-		fmt.Println("[DEBUG] Нет ни одной цепочки: все пары в тикерах или не все три символа (AUSDT, BUSDT, A-B) разрешены для спот-API в БД. Выход.")
-		return nil
-	}
-
-	best := allChains[0]
-	fmt.Printf("[DEBUG] Топ-5 связок по прибыли:\n")
-	for i := 0; i < 5 && i < len(allChains); i++ {
-		c := allChains[i]
-		marker := ""
-		if i == 0 {
-			marker = "  <-- выбрана"
+		if err := u.rebalanceSwapInventory(ctx, book, symbolIndex, opts, runID); err != nil {
+			return err
 		}
-		fmt.Printf("  %d. USDT -> %s -> %s -> USDT  |  %.4f%%%s\n", i+1, c.baseA, c.baseB, c.profit, marker)
-	}
-	fmt.Println()
-
-	if best.profit <= minProfitPercent {
-		fmt.Printf("[DEBUG] Лучшая связка %.4f%% не превышает порог %.1f%%. Выход без сделок.\n", best.profit, minProfitPercent)
-		return nil
 	}
 
-	coinA := mexc.SymbolDetail{BaseAsset: best.baseA, Symbol: best.baseA + "USDT", QuoteAsset: "USDT"}
-	coinB := mexc.SymbolDetail{BaseAsset: best.baseB, Symbol: best.baseB + "USDT", QuoteAsset: "USDT"}
-	chain, ok := calcSwapChainFromBook(book, &coinA, &coinB, &SwapChainMeta{
-		Index:          symbolIndex,
-		InterBuffer:    swapIntermediateBuffer,
-		RequireRealism: true,
-	})
-	if !ok {
-		return wrap.Errorf("chain %s -> %s recalc failed", best.baseA, best.baseB)
-	}
-
-	// Масштабируем под amountInUSDT
-	chain.amountA *= amountInUSDT
-	chain.amountB *= amountInUSDT
-	chain.amountUSDT *= amountInUSDT
-
-	fmt.Printf("--- Выполняем цепочку: USDT -> %s -> %s -> USDT (прибыль %.4f%%, объём %.1f USDT) ---\n\n",
-		best.baseA, best.baseB, best.profit, amountInUSDT)
-
-	// --- 3. Проверка баланса USDT ---
+	// --- 2. Стартовые активы и их объёмы по балансу ---
 	accountInfo, err := u.repo.GetBalance(ctx)
 	if err != nil {
 		return wrap.Errorf("failed to get balance: %w", err)
 	}
-	usdtBal, err := helpers.FindUSDTBalance(accountInfo.Balances)
-	if err != nil {
-		return wrap.Errorf("USDT balance not found: %w", err)
+	amounts := swapStartAmounts(opts.Starts, accountInfo.Balances, book, opts.AmountUSDT, inventory)
+	if len(amounts) == 0 {
+		fmt.Printf("[DEBUG] Нет стартового актива с балансом на %.1f USDT. Выход без сделок.\n", opts.AmountUSDT)
+		return nil
 	}
-	fmt.Printf("[DEBUG] Баланс USDT: Free=%.2f Locked=%.2f\n", usdtBal.Free, usdtBal.Locked)
-	if usdtBal.Free < amountInUSDT {
-		return wrap.Errorf("недостаточно USDT: нужно %.1f, свободно %.2f", amountInUSDT, usdtBal.Free)
+	for _, asset := range sortedSwapAssets(amounts) {
+		fmt.Printf("[DEBUG] Старт %s: %.8f\n", asset, amounts[asset])
 	}
+	fmt.Println()
 
-	// --- 4. Symbol info для пар (precision, min notional) ---
-	symbolA := best.baseA + "USDT"
-	symbolB := best.baseB + "USDT"
-	infoA, err := u.repo.GetSymbolInfo(ctx, symbolA)
-	if err != nil {
-		return wrap.Errorf("get symbol info %s: %w", symbolA, err)
-	}
-	infoB, err := u.repo.GetSymbolInfo(ctx, symbolB)
-	if err != nil {
-		return wrap.Errorf("get symbol info %s: %w", symbolB, err)
-	}
-	infoAB, err := u.repo.GetSymbolInfo(ctx, chain.symbolAB)
-	if err != nil {
-		return wrap.Errorf("get symbol info %s: %w", chain.symbolAB, err)
+	// --- 3. Ищем циклы из стартовых активов по графу всех разрешённых пар ---
+	graph := buildSwapGraph(book, symbolIndex, func(symbol string) bool { return symbolsAllowed[symbol] })
+	cycles := findSwapCyclesFromStarts(graph, amounts, swapCycleSearch{
+		MinLegs:          swapMinCycleLegs,
+		MaxLegs:          swapMaxCycleLegs,
+		InterBuffer:      swapIntermediateBuffer,
+		MinProfitPercent: swapNoProfitFloor,
+		Limit:            swapTopCycles,
+	})
+	if opts.Mode == SwapModeInventory {
+		cycles = filterSwapCyclesByInventory(cycles, accountInfo.Balances)
 	}
-
-	symA := u.findSymbolDetail(infoA, symbolA)
-	symB := u.findSymbolDetail(infoB, symbolB)
-	symAB := u.findSymbolDetail(infoAB, chain.symbolAB)
-	if symA == nil || symB == nil || symAB == nil {
-		return wrap.Errorf("symbol not found in exchange info")
+	if len(cycles) == 0 {
+		fmt.Println("[DEBUG] Нет ни одного цикла: пары без котировок, без шага лота, не разрешены для спот-API в БД или не покрыты запасом. Выход.")
+		return nil
 	}
 
-	stepA, err := swapLotStep(symA)
-	if err != nil {
-		return wrap.Errorf("%s: %w", symbolA, err)
-	}
-	stepB, err := swapLotStep(symB)
-	if err != nil {
-		return wrap.Errorf("%s: %w", symbolB, err)
-	}
-	stepAB, err := swapLotStep(symAB)
-	if err != nil {
-		return wrap.Errorf("%s: %w", chain.symbolAB, err)
+	fmt.Printf("[DEBUG] Топ-5 циклов по прибыли на лучших ценах:\n")
+	for i := 0; i < 5 && i < len(cycles); i++ {
+		fmt.Printf("  %d. %s  |  %.4f%%\n", i+1, cycles[i].Path(), cycles[i].ProfitPercent)
 	}
+	fmt.Println()
 
-	feeA := swapTakerFeeRate(symA)
-	feeAB := swapTakerFeeRate(symAB)
-
-	qty1 := swapRoundQtyDown(amountInUSDT/chain.priceAUSDT, stepA)
-	price1 := chain.priceAUSDT
-	if qty1 <= 0 {
-		return wrap.Errorf("шаг 1: количество после округления обнулилось (%s step=%g)", symbolA, stepA)
+	if cycles[0].ProfitPercent <= minProfitPercent {
+		fmt.Printf("[DEBUG] Лучший цикл %.4f%% не превышает порог %.1f%%. Выход без сделок.\n", cycles[0].ProfitPercent, minProfitPercent)
+		return nil
 	}
 
-	// Preflight шагов 2–3 по той же модели объёмов, что и в swap_chain.
-	qtyANet := qty1 * (1.0 - feeA)
-	qtyAExpected := qtyANet * swapIntermediateBuffer
-	var qty2pref float64
-	if chain.usedDirectAB {
-		qty2pref = swapRoundQtyDown(math.Min(qtyANet, qtyAExpected), stepAB)
-	} else {
-		planB := qtyANet / chain.priceAB
-		maxAffordB := qtyAExpected / chain.priceAB
-		qty2pref = swapRoundQtyDown(math.Min(planB, maxAffordB), stepAB)
-	}
-	if qty2pref <= 0 {
-		return wrap.Errorf("preflight шаг 2: по паре %s количество обнулилось (step=%g, ожидаемо %s≈%.8f)",
-			chain.symbolAB, stepAB, best.baseA, qtyAExpected)
+	// Прибыль по лучшим ценам может не выдержать объёма: лучшие циклы
+	// пересчитываются по стаканам всех ног и ранжируются заново.
+	candidates := make([]swapCycle, 0, swapDepthCandidates)
+	for _, cycle := range cycles {
+		if len(candidates) >= swapDepthCandidates || cycle.ProfitPercent <= minProfitPercent {
+			break
+		}
+		cycle, err := u.checkSwapCycleDepth(ctx, cycle)
+		if err != nil {
+			return err
+		}
+		if !cycle.DepthChecked {
+			fmt.Printf("[DEBUG] %s: стакана не хватает на %.8f %s\n", cycle.Path(), cycle.Amount, cycle.Start())
+		}
+		candidates = append(candidates, cycle)
 	}
-	var amountBpref float64
-	if chain.usedDirectAB {
-		amountBpref = qty2pref * chain.priceAB * (1.0 - feeAB)
-	} else {
-		amountBpref = qty2pref * (1.0 - feeAB)
+	rankSwapCyclesByDepth(candidates)
+	best := candidates[0]
+	if !best.DepthChecked {
+		fmt.Printf("[DEBUG] Стакана не хватает на %.1f USDT ни по одному циклу. Выход без сделок.\n", opts.AmountUSDT)
+		return nil
 	}
-	qty3pref := swapRoundQtyDown(amountBpref, stepB)
-	if qty3pref <= 0 {
-		return wrap.Errorf("preflight шаг 3: по %s количество обнулилось (step=%g)", symbolB, stepB)
+	start := best.Start()
+	fmt.Printf("[DEBUG] Прибыль по стаканам на %.8f %s: %s %.4f%%\n", best.Amount, start, best.Path(), best.DepthProfitPercent)
+	if best.DepthProfitPercent <= minProfitPercent {
+		fmt.Printf("[DEBUG] С учётом глубины %.4f%% не превышает порог %.1f%%. Выход без сделок.\n", best.DepthProfitPercent, minProfitPercent)
+		return nil
 	}
 
-	fmt.Printf("[DEBUG] Шаг 1: BUY %s @ ask | quantity=%.8f price=%.8f (quote≈%.2f USDT)\n", symbolA, qty1, price1, qty1*price1)
+	fmt.Printf("--- Выполняем цикл: %s (прибыль %.4f%%, объём %.8f %s, режим %s) ---\n\n", best.Path(), best.DepthProfitPercent, best.Amount, start, opts.Mode)
 
-	runID := time.Now().UnixMilli()
-	// --- Шаг 1: Лимитный ордер BUY A за USDT ---
-	clientOrderId1 := fmt.Sprintf("swap_%d_1_%s", runID, best.baseA)
-
-	order1, err := u.repo.NewOrder(model.OrderParams{
-		Symbol:           symbolA,
-		Side:             order.BUY,
-		OrderType:        order.LIMIT,
-		Quantity:         qty1,
-		Price:            price1,
-		NewClientOrderId: clientOrderId1,
-	})
-	if err != nil {
-		return wrap.Errorf("place order 1 (BUY %s): %w", symbolA, err)
-	}
-	fmt.Printf("[DEBUG] Ордер 1 размещён: orderId=%s\n", order1.OrderID)
-
-	if err := u.waitStepOrUnwindStep1(ctx, "1", symbolA, order1.OrderID, unwindStep1Params{
-		symbolUSDT:     symbolA,
-		baseAsset:      best.baseA,
-		buyPrice:       price1,
-		symA:           symA,
-		runID:          runID,
-		pendingSymbol:  symbolA,
-		pendingOrderID: order1.OrderID,
-	}); err != nil {
-		return err
-	}
-
-	acctAfter1, err := u.repo.GetBalance(ctx)
+	// --- 4. Проверка баланса стартового актива ---
+	startBal, err := helpers.FindAssetBalance(accountInfo.Balances, start)
 	if err != nil {
-		return wrap.Errorf("balance after step 1: %w", err)
+		return wrap.Errorf("%s balance not found: %w", start, err)
 	}
-	balA, err := helpers.FindAssetBalance(acctAfter1.Balances, best.baseA)
-	if err != nil {
-		return err
+	fmt.Printf("[DEBUG] Баланс %s: Free=%.8f Locked=%.8f\n", start, startBal.Free, startBal.Locked)
+	if startBal.Free < best.Amount {
+		return wrap.Errorf("недостаточно %s: нужно %.8f, свободно %.8f", start, best.Amount, startBal.Free)
 	}
-	qtyAActual := balA.Free * swapIntermediateBuffer
-	fmt.Printf("[DEBUG] Доступно %s для шага 2 (с запасом): %.8f (free=%.8f)\n", best.baseA, qtyAActual, balA.Free)
 
-	// --- Шаг 2: Лимитный ордер на паре A-B по фактическому балансу A (после комиссии шага 1) ---
-	// usedDirectAB: пара AB (base=A, quote=B) — продаём A → SELL
-	// иначе пара BA (base=B, quote=A) — покупаем B за A → BUY; стоимость в A = qty2*price
-	var qty2 float64
-	price2 := chain.priceAB
-	if chain.usedDirectAB {
-		qty2 = swapRoundQtyDown(math.Min(qty1, qtyAActual), stepAB)
-	} else {
-		planB := qty1 / chain.priceAB
-		maxAffordB := qtyAActual / chain.priceAB
-		qty2 = swapRoundQtyDown(math.Min(planB, maxAffordB), stepAB)
-	}
-	if qty2 <= 0 {
-		return wrap.Errorf("шаг 2: после учёта баланса %s количество по паре %s обнулилось", best.baseA, chain.symbolAB)
+	// Preflight: по той же модели объёмов ни одна нога не должна обнулиться.
+	planned, ok := applySwapCycleRealism(best.Legs, best.Amount, swapIntermediateBuffer)
+	if !ok {
+		return wrap.Errorf("preflight %s: количество одной из ног обнулилось после округления", best.Path())
 	}
 
-	clientOrderId2 := fmt.Sprintf("swap_%d_2_%s", runID, chain.symbolAB)
-
-	var side2 order.Side
-	if chain.usedDirectAB {
-		side2 = order.SELL
-	} else {
-		side2 = order.BUY
+	// Риск проверяется только перед шагом 1: следующие шаги закрывают уже
+	// открытую позицию и не блокируются. Лимиты риска — в USDT.
+	first := best.Legs[0]
+	startPrice, ok := swapUSDTPrice(start, book)
+	if !ok {
+		return wrap.Errorf("нет цены %s в USDT для проверки риска", start)
 	}
-	leg2BookSide := "ask"
-	if chain.usedDirectAB {
-		leg2BookSide = "bid"
+	if blocked, err := entryBlockedByRisk(ctx, u.risk, first.Symbol, planned.Fills[0].Spent*startPrice); err != nil || blocked {
+		return err
 	}
-	fmt.Printf("[DEBUG] Шаг 2: %s %s @ %s | quantity=%.8f price=%.8f\n", side2.String(), chain.symbolAB, leg2BookSide, qty2, price2)
 
-	order2, err := u.repo.NewOrder(model.OrderParams{
-		Symbol:           chain.symbolAB,
-		Side:             side2,
-		OrderType:        order.LIMIT,
-		Quantity:         qty2,
-		Price:            price2,
-		NewClientOrderId: clientOrderId2,
-	})
-	if err != nil {
-		return wrap.Errorf("place order 2 (%s %s): %w", side2.String(), chain.symbolAB, err)
-	}
-	fmt.Printf("[DEBUG] Ордер 2 размещён: orderId=%s\n", order2.OrderID)
-
-	if err := u.waitStepOrUnwindStep1(ctx, "2", chain.symbolAB, order2.OrderID, unwindStep1Params{
-		symbolUSDT:     symbolA,
-		baseAsset:      best.baseA,
-		buyPrice:       price1,
-		symA:           symA,
-		runID:          runID,
-		pendingSymbol:  chain.symbolAB,
-		pendingOrderID: order2.OrderID,
-	}); err != nil {
-		return err
+	unwind := unwindStep1Params{runID: runID}
+	if first.Side == order.BUY {
+		unwind.symbolUSDT = first.Symbol
+		unwind.baseAsset = first.To
+		unwind.buyPrice = first.Price
+		unwind.symA = symbolIndex[first.Symbol]
 	}
 
-	// --- Шаг 3: Лимитный ордер SELL B за USDT (количество B получено на шаге 2) ---
-	acctAfter2, err := u.repo.GetBalance(ctx)
-	if err != nil {
-		return wrap.Errorf("balance after step 2: %w", err)
+	journal := startSwapJournal(ctx, u.stateRepo, best, best.Amount, opts.Mode)
+	switch opts.Mode {
+	case SwapModeIOC:
+		return u.runSwapCycleIOC(ctx, best, symbolIndex, opts, runID, journal)
+	case SwapModeInventory:
+		return u.runSwapCycleInventory(ctx, best, symbolIndex, opts, runID, journal)
 	}
-	balB, err := helpers.FindAssetBalance(acctAfter2.Balances, best.baseB)
+	orderIDs, unwound, err := u.executeSwapCycle(ctx, best, unwind, journal)
+	journal.finish(ctx, len(best.Legs), unwound, err)
 	if err != nil {
 		return err
 	}
-	qtyBActual := balB.Free * swapIntermediateBuffer
-	qty3 := swapRoundQtyDown(qtyBActual, stepB)
-	if qty3 <= 0 {
-		return wrap.Errorf("шаг 3: после учёта баланса %s количество обнулилось", best.baseB)
-	}
-	price3 := chain.priceBUSDT
-	fmt.Printf("[DEBUG] Доступно %s для шага 3 (с запасом): %.8f (free=%.8f)\n", best.baseB, qtyBActual, balB.Free)
-	fmt.Printf("[DEBUG] Шаг 3: SELL %s @ bid | quantity=%.8f price=%.8f\n", symbolB, qty3, price3)
-
-	clientOrderId3 := fmt.Sprintf("swap_%d_3_%s", runID, best.baseB)
-
-	order3, err := u.repo.NewOrder(model.OrderParams{
-		Symbol:           symbolB,
-		Side:             order.SELL,
-		OrderType:        order.LIMIT,
-		Quantity:         qty3,
-		Price:            price3,
-		NewClientOrderId: clientOrderId3,
-	})
-	if err != nil {
-		return wrap.Errorf("place order 3 (SELL %s): %w", symbolB, err)
-	}
-	fmt.Printf("[DEBUG] Ордер 3 размещён: orderId=%s\n", order3.OrderID)
-
-	if err := u.waitStepOrUnwindStep1(ctx, "3", symbolB, order3.OrderID, unwindStep1Params{
-		symbolUSDT:     symbolA,
-		baseAsset:      best.baseA,
-		buyPrice:       price1,
-		symA:           symA,
-		runID:          runID,
-		pendingSymbol:  symbolB,
-		pendingOrderID: order3.OrderID,
-	}); err != nil {
-		return err
+	if unwound.Outcome == swapUnwindDone {
+		fmt.Printf("Ордера: %s\n", strings.Join(orderIDs, ", "))
+		return nil
 	}
 
 	// --- Итог ---
 	accountInfo2, _ := u.repo.GetBalance(ctx)
-	usdtBal2, _ := helpers.FindUSDTBalance(accountInfo2.Balances)
-	fmt.Printf("\n=== Цепочка выполнена ===\n")
-	fmt.Printf("[DEBUG] Баланс USDT после: Free=%.2f Locked=%.2f\n", usdtBal2.Free, usdtBal2.Locked)
-	fmt.Printf("Ордера: %s, %s, %s\n", order1.OrderID, order2.OrderID, order3.OrderID)
+	startBal2, _ := helpers.FindAssetBalance(accountInfo2.Balances, start)
+	fmt.Printf("\n=== Цикл выполнен ===\n")
+	if startBal2 != nil {
+		fmt.Printf("[DEBUG] Баланс %s после: Free=%.8f Locked=%.8f\n", start, startBal2.Free, startBal2.Locked)
+	}
+	fmt.Printf("Ордера: %s\n", strings.Join(orderIDs, ", "))
+	if run := journal.run; run.FinalAmount > 0 {
+		fmt.Printf("Запуск #%d: ожидали %.4f%% (стакан %.4f%%), получили %.4f%%\n",
+			run.ID, run.ExpectedProfitPercent, run.ExpectedDepthProfitPercent, run.RealizedProfitPercent)
+	}
 	return nil
 }
 
-// unwindStep1Params — разворот при таймауте: продать baseAsset за USDT по bid, если bid >= buyPrice шага 1.
+// executeSwapCycle исполняет ноги цикла по очереди и пишет каждую в журнал.
+// После разворота шага 1 следующие ноги не выставляются.
+func (u *SwapProcess) executeSwapCycle(ctx context.Context, cycle swapCycle, unwind unwindStep1Params, journal *swapJournal) ([]string, swapUnwindResult, error) {
+	orderIDs := make([]string, 0, len(cycle.Legs))
+	in := cycle.Amount
+	for i, leg := range cycle.Legs {
+		step := strconv.Itoa(i + 1)
+		if i > 0 {
+			// Следующая нога — по фактическому балансу (после комиссии
+			// предыдущей), но не больше полученного на предыдущей ноге.
+			acct, err := u.repo.GetBalance(ctx)
+			if err != nil {
+				return orderIDs, swapUnwindResult{}, wrap.Errorf("balance after step %d: %w", i, err)
+			}
+			bal, err := helpers.FindAssetBalance(acct.Balances, leg.From)
+			if err != nil {
+				return orderIDs, swapUnwindResult{}, err
+			}
+			in = math.Min(in, bal.Free*swapIntermediateBuffer)
+			fmt.Printf("[DEBUG] Доступно %s для шага %s (с запасом): %.8f (free=%.8f)\n", leg.From, step, in, bal.Free)
+		}
+		fill := fillSwapLeg(leg, in)
+		if fill.Qty <= 0 {
+			return orderIDs, swapUnwindResult{}, wrap.Errorf("шаг %s: количество по паре %s обнулилось после округления (step=%g)", step, leg.Symbol, leg.Step)
+		}
+		bookSide := "ask"
+		if leg.Side == order.SELL {
+			bookSide = "bid"
+		}
+		fmt.Printf("[DEBUG] Шаг %s: %s %s @ %s | quantity=%.8f price=%.8f\n", step, leg.Side.String(), leg.Symbol, bookSide, fill.Qty, leg.Price)
+
+		clientOrderID := fmt.Sprintf("swap_%d_%s_%s", unwind.runID, step, leg.Symbol)
+		record := journal.newLeg(cycle, i, fill, clientOrderID)
+		journal.saveLeg(ctx, record)
+		placed, err := u.repo.NewOrder(ctx, model.OrderParams{
+			Symbol:           leg.Symbol,
+			Side:             leg.Side,
+			OrderType:        order.LIMIT,
+			Quantity:         fill.Qty,
+			Price:            leg.Price,
+			NewClientOrderId: clientOrderID,
+		})
+		if err != nil {
+			record.Status = swapLegPlaceFailed
+			journal.saveLeg(ctx, record)
+			return orderIDs, swapUnwindResult{}, wrap.Errorf("place order %s (%s %s): %w", step, leg.Side.String(), leg.Symbol, err)
+		}
+		fmt.Printf("[DEBUG] Ордер %s размещён: orderId=%s\n", step, placed.OrderID)
+		orderIDs = append(orderIDs, placed.OrderID)
+		record.OrderID = placed.OrderID
+		record.Status = "NEW"
+		journal.saveLeg(ctx, record)
+
+		unwind.pendingSymbol = leg.Symbol
+		unwind.pendingOrderID = placed.OrderID
+		result, unwound, err := u.waitStepOrUnwindStep1(ctx, step, leg.Symbol, placed.OrderID, unwind)
+		recordSwapLegFill(&record, result, leg.Fee)
+		journal.saveLeg(ctx, record)
+		if err != nil || unwound.Outcome == swapUnwindDone {
+			return orderIDs, unwound, err
+		}
+		// Полученное до комиссии — верхняя граница для следующей ноги; если
+		// исполнение неизвестно, берётся расчётное.
+		in = swapLegGross(record)
+		if in <= 0 {
+			in = fill.Qty
+			if leg.Side == order.SELL {
+				in = fill.Qty * leg.Price
+			}
+		}
+	}
+	return orderIDs, swapUnwindResult{}, nil
+}
+
+// unwindStep1Params — разворот при таймауте: продать baseAsset обратно в стартовый актив по bid, если bid >= buyPrice шага 1.
+// Пустой symbolUSDT — шаг 1 не был покупкой, разворачивать нечего.
 type unwindStep1Params struct {
 	symbolUSDT     string
 	baseAsset      string
@@ -484,72 +456,88 @@ type unwindStep1Params struct {
 }
 
 // waitStepOrUnwindStep1 ждёт исполнения ордера; при таймауте отменяет его и при bid>=цене покупки шага 1 продаёт актив A.
-func (u *SwapProcess) waitStepOrUnwindStep1(ctx context.Context, stepLabel, pollSymbol, pollOrderID string, p unwindStep1Params) error {
-	timedOut, err := u.pollOrderUntilFilled(ctx, pollSymbol, pollOrderID, stepLabel)
-	if err != nil {
-		return err
-	}
-	if !timedOut {
-		return nil
+// Возвращает последний ответ биржи по ордеру и исход разворота, если до него дошло.
+func (u *SwapProcess) waitStepOrUnwindStep1(ctx context.Context, stepLabel, pollSymbol, pollOrderID string, p unwindStep1Params) (*mexc.QueryOrderResult, swapUnwindResult, error) {
+	last, timedOut, err := u.pollOrderUntilFilled(ctx, pollSymbol, pollOrderID, stepLabel)
+	if err != nil || !timedOut {
+		return last, swapUnwindResult{}, err
 	}
 	unwound, uerr := u.tryUnwindStep1AfterTimeout(ctx, p)
 	if uerr != nil {
-		return uerr
+		return last, swapUnwindResult{Outcome: swapUnwindFailed}, uerr
 	}
-	if unwound {
+	if unwound.Outcome == swapUnwindDone {
 		fmt.Printf("\n=== Таймаут шага %s: разворот — %s продан по bid >= цене покупки ===\n", stepLabel, p.baseAsset)
-		return nil
+		return last, unwound, nil
 	}
-	return wrap.Errorf("step %s: таймаут ожидания исполнения ордера %s", stepLabel, pollOrderID)
+	return last, unwound, wrap.Errorf("step %s: таймаут ожидания исполнения ордера %s", stepLabel, pollOrderID)
 }
 
-func (u *SwapProcess) tryUnwindStep1AfterTimeout(ctx context.Context, p unwindStep1Params) (unwound bool, err error) {
+// checkSwapCycleDepth пересчитывает цикл по стаканам ног на cycle.Amount.
+func (u *SwapProcess) checkSwapCycleDepth(ctx context.Context, cycle swapCycle) (swapCycle, error) {
+	depths := make([]*mexc.OrderBook, 0, len(cycle.Legs))
+	for _, leg := range cycle.Legs {
+		depth, err := u.repo.GetDepth(ctx, leg.Symbol, swapDepthLimit)
+		if err != nil {
+			return cycle, wrap.Errorf("depth %s: %w", leg.Symbol, err)
+		}
+		depths = append(depths, depth)
+	}
+	cycle.DepthPrices, cycle.DepthProfitPercent, cycle.DepthChecked = simulateSwapCycleDepth(cycle.Legs, depths, cycle.Amount)
+	return cycle, nil
+}
+
+func (u *SwapProcess) tryUnwindStep1AfterTimeout(ctx context.Context, p unwindStep1Params) (swapUnwindResult, error) {
 	if p.pendingOrderID != "" && p.pendingSymbol != "" {
-		_, cancelErr := u.repo.CancelOrder(p.pendingSymbol, p.pendingOrderID)
+		_, cancelErr := u.repo.CancelOrder(ctx, p.pendingSymbol, p.pendingOrderID)
 		if cancelErr != nil {
 			fmt.Printf("[DEBUG] Отмена ордера %s перед разворотом: %v (продолжаем)\n", p.pendingOrderID, cancelErr)
 		} else {
 			fmt.Printf("[DEBUG] Ордер %s отменён перед разворотом\n", p.pendingOrderID)
 		}
 	}
+	if p.symbolUSDT == "" {
+		fmt.Println("[DEBUG] Разворот невозможен: шаг 1 цикла не был покупкой")
+		return swapUnwindResult{Outcome: swapUnwindNotBuy}, nil
+	}
 
 	bookRows, err := u.repo.GetAllBookTickers(ctx)
 	if err != nil {
-		return false, wrap.Errorf("разворот: book ticker: %w", err)
+		return swapUnwindResult{}, wrap.Errorf("разворот: book ticker: %w", err)
 	}
 	book := BuildSwapBookMap(bookRows)
 	q := book[p.symbolUSDT]
 	if q.Bid <= 0 {
 		fmt.Printf("[DEBUG] Разворот: нет bid по %s\n", p.symbolUSDT)
-		return false, nil
+		return swapUnwindResult{Outcome: swapUnwindNoBid}, nil
 	}
 	if q.Bid < p.buyPrice {
 		fmt.Printf("[DEBUG] Разворот не выполняем: bid %.8f < цены покупки шага 1 %.8f\n", q.Bid, p.buyPrice)
-		return false, nil
+		return swapUnwindResult{Outcome: swapUnwindBidBelowBuy}, nil
 	}
 
 	stepA, err := swapLotStep(p.symA)
 	if err != nil {
-		return false, err
+		return swapUnwindResult{}, err
 	}
 	acct, err := u.repo.GetBalance(ctx)
 	if err != nil {
-		return false, wrap.Errorf("разворот: баланс: %w", err)
+		return swapUnwindResult{}, wrap.Errorf("разворот: баланс: %w", err)
 	}
 	balA, err := helpers.FindAssetBalance(acct.Balances, p.baseAsset)
 	if err != nil {
 		fmt.Printf("[DEBUG] Разворот: на балансе нет %s: %v\n", p.baseAsset, err)
-		return false, nil
+		return swapUnwindResult{Outcome: swapUnwindNoBalance}, nil
 	}
 	qty := swapRoundQtyDown(balA.Free*swapIntermediateBuffer, stepA)
 	if qty <= 0 {
 		fmt.Printf("[DEBUG] Разворот: нет свободного %s для продажи (free=%.8f)\n", p.baseAsset, balA.Free)
-		return false, nil
+		return swapUnwindResult{Outcome: swapUnwindNoBalance}, nil
 	}
 
 	sellPrice := q.Bid
 	fmt.Printf("[DEBUG] Разворот: SELL %s @ bid | quantity=%.8f price=%.8f\n", p.symbolUSDT, qty, sellPrice)
-	unwindOrder, err := u.repo.NewOrder(model.OrderParams{
+	unwindOrder, err := u.repo.NewOrder(ctx, model.OrderParams{
 		Symbol:           p.symbolUSDT,
 		Side:             order.SELL,
 		OrderType:        order.LIMIT,
@@ -558,60 +546,60 @@ func (u *SwapProcess) tryUnwindStep1AfterTimeout(ctx context.Context, p unwindSt
 		NewClientOrderId: fmt.Sprintf("swap_%d_unwind_%s", p.runID, p.baseAsset),
 	})
 	if err != nil {
-		return false, wrap.Errorf("разворот: SELL %s: %w", p.symbolUSDT, err)
+		return swapUnwindResult{}, wrap.Errorf("разворот: SELL %s: %w", p.symbolUSDT, err)
 	}
-	if err := u.waitOrderFilled(ctx, p.symbolUSDT, unwindOrder.OrderID, "unwind"); err != nil {
-		return false, err
+	filled, err := u.waitOrderFilled(ctx, p.symbolUSDT, unwindOrder.OrderID, "unwind")
+	if err != nil {
+		return swapUnwindResult{}, err
 	}
 	fmt.Printf("[DEBUG] Разворот исполнен: orderId=%s\n", unwindOrder.OrderID)
-	return true, nil
+	// Ордер мог исполниться до первого опроса — тогда выручка оценивается по лимиту.
+	received := qty * sellPrice
+	if filled != nil {
+		if quote, err := strconv.ParseFloat(filled.CummulativeQuoteQty, 64); err == nil && quote > 0 {
+			received = quote
+		}
+	}
+	return swapUnwindResult{Outcome: swapUnwindDone, Received: received * (1 - swapTakerFeeRate(p.symA))}, nil
 }
 
-// pollOrderUntilFilled возвращает timedOut=true, если истёк дедлайн без FILLED.
-func (u *SwapProcess) pollOrderUntilFilled(ctx context.Context, symbol, orderID, stepLabel string) (timedOut bool, err error) {
+// pollOrderUntilFilled возвращает последний ответ биржи по ордеру и timedOut=true, если истёк дедлайн без FILLED.
+func (u *SwapProcess) pollOrderUntilFilled(ctx context.Context, symbol, orderID, stepLabel string) (last *mexc.QueryOrderResult, timedOut bool, err error) {
 	deadline := time.Now().Add(orderFillWaitTimeout)
 	for time.Now().Before(deadline) {
 		select {
 		case <-ctx.Done():
-			return false, ctx.Err()
+			return last, false, ctx.Err()
 		default:
 		}
 		time.Sleep(orderFillPollInterval)
-		q, err := u.repo.GetOrderQuery(symbol, orderID)
+		q, err := u.repo.GetOrderQuery(ctx, symbol, orderID)
 		if err != nil {
-			return false, wrap.Errorf("step %s query order: %w", stepLabel, err)
+			return last, false, wrap.Errorf("step %s query order: %w", stepLabel, err)
 		}
 		if q == nil {
 			fmt.Printf("[DEBUG] Шаг %s: ордер %s не найден (возможно уже исполнен)\n", stepLabel, orderID)
-			return false, nil
+			return last, false, nil
 		}
+		last = q
 		fmt.Printf("[DEBUG] Шаг %s: статус=%s executedQty=%s\n", stepLabel, q.Status, q.ExecutedQty)
 		if q.Status == "FILLED" {
-			return false, nil
+			return last, false, nil
 		}
 		if q.Status == "CANCELED" || q.Status == "REJECTED" || q.Status == "EXPIRED" {
-			return false, wrap.Errorf("step %s: ордер в статусе %s", stepLabel, q.Status)
+			return last, false, wrap.Errorf("step %s: ордер в статусе %s", stepLabel, q.Status)
 		}
 	}
-	return true, nil
+	return last, true, nil
 }
 
-func (u *SwapProcess) waitOrderFilled(ctx context.Context, symbol, orderID, stepLabel string) error {
-	timedOut, err := u.pollOrderUntilFilled(ctx, symbol, orderID, stepLabel)
+func (u *SwapProcess) waitOrderFilled(ctx context.Context, symbol, orderID, stepLabel string) (*mexc.QueryOrderResult, error) {
+	last, timedOut, err := u.pollOrderUntilFilled(ctx, symbol, orderID, stepLabel)
 	if err != nil {
-		return err
+		return last, err
 	}
 	if timedOut {
-		return wrap.Errorf("step %s: таймаут ожидания исполнения ордера %s", stepLabel, orderID)
-	}
-	return nil
-}
-
-func (u *SwapProcess) findSymbolDetail(info *mexc.SymbolInfo, symbol string) *mexc.SymbolDetail {
-	for i := range info.Symbols {
-		if info.Symbols[i].Symbol == symbol {
-			return &info.Symbols[i]
-		}
+		return last, wrap.Errorf("step %s: таймаут ожидания исполнения ордера %s", stepLabel, orderID)
 	}
-	return nil
+	return last, nil
 }
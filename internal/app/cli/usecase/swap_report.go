@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/enum/order"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const (
+	defaultSwapReportDays = 7
+	defaultSwapReportLast = 10
+)
+
+type SwapReportOptions struct {
+	Days int
+	// Last — сколько последних запусков вывести построчно.
+	Last int
+}
+
+func DefaultSwapReportOptions() SwapReportOptions {
+	return SwapReportOptions{Days: defaultSwapReportDays, Last: defaultSwapReportLast}
+}
+
+type ISwapReport interface {
+	Process(context.Context, SwapReportOptions) error
+}
+
+// SwapReport сводит журнал swap-process: сколько прибыли обещали лучшие цены
+// и стаканы и сколько получилось на деле, — и время жизни возможностей,
+// которые записал swap-monitor.
+type SwapReport struct {
+	stateRepo repo.IStateRepository
+}
+
+func NewSwapReportUsecase(stateRepo repo.IStateRepository) *SwapReport {
+	return &SwapReport{stateRepo: stateRepo}
+}
+
+func (u *SwapReport) Process(ctx context.Context, opts SwapReportOptions) error {
+	if opts.Days <= 0 {
+		return wrap.Errorf("days must be positive, got %d", opts.Days)
+	}
+	since := time.Now().UTC().Add(-time.Duration(opts.Days) * 24 * time.Hour)
+	runs, err := u.stateRepo.ListSwapRunsSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	legs, err := u.stateRepo.ListSwapLegsSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	opps, err := u.stateRepo.ListSwapOpportunitiesSince(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("=== swap-report за %d дн. ===\n", opts.Days)
+	if len(runs) == 0 {
+		fmt.Println("Запусков нет")
+	} else {
+		printSwapRunsReport(runs, legs, opts.Last)
+	}
+	if len(opps) > 0 {
+		fmt.Println("\nВозможности swap-monitor:")
+		printSwapOpportunityHistogram(opps)
+	}
+	return nil
+}
+
+func printSwapRunsReport(runs []repo.SwapRun, legs []repo.SwapLeg, last int) {
+	summary := summarizeSwapRuns(runs, legs)
+
+	statuses := make([]string, 0, len(summary.ByStatus))
+	for status := range summary.ByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	fmt.Printf("Запусков: %d\n", summary.Runs)
+	for _, status := range statuses {
+		fmt.Printf("  %-10s %d\n", status, summary.ByStatus[status])
+	}
+
+	fmt.Printf("\nС известным результатом: %d, потратили без результата: %d\n",
+		summary.Realized.Runs, summary.Realized.Unrealized)
+	if summary.Realized.Runs+summary.Realized.Unrealized > 0 {
+		printSwapEdge("  ", summary.Realized)
+	}
+
+	fmt.Printf("\nНоги с исполнением: %d\n", summary.FilledLegs)
+	if summary.FilledLegs > 0 {
+		fmt.Printf("  проскальзывание к лучшей цене: %.4f%%\n", summary.AvgSlippagePercent)
+		fmt.Printf("  проскальзывание к VWAP стакана: %.4f%%\n", summary.AvgDepthSlippagePercent)
+		fmt.Printf("  время исполнения: %s\n", summary.AvgFillTime.Round(time.Second))
+	}
+
+	if len(summary.Paths) > 0 {
+		fmt.Println("\nПо циклам:")
+		for _, path := range summary.Paths {
+			fmt.Printf("  %s\n", path.Path)
+			printSwapEdge("    ", path.swapEdge)
+		}
+	}
+
+	if last > 0 {
+		fmt.Println("\nПоследние запуски:")
+		from := len(runs) - last
+		if from < 0 {
+			from = 0
+		}
+		for i := len(runs) - 1; i >= from; i-- {
+			run := runs[i]
+			fmt.Printf("  #%d %s %s %-9s ожидали %.4f%% (стакан %.4f%%) получили %.4f%%",
+				run.ID, run.StartedAt.Format(time.DateTime), run.Path, run.Status,
+				run.ExpectedProfitPercent, run.ExpectedDepthProfitPercent, run.RealizedProfitPercent)
+			if run.UnwindOutcome != "" {
+				fmt.Printf(" разворот=%s", run.UnwindOutcome)
+			}
+			if run.LastError != "" {
+				fmt.Printf(" ошибка=%s", run.LastError)
+			}
+			fmt.Println()
+		}
+	}
+}
+
+func printSwapEdge(indent string, edge swapEdge) {
+	fmt.Printf("%sзапусков %d: ожидали %.4f%%, по стаканам %.4f%%, получили %.4f%%", indent,
+		edge.Runs, edge.ExpectedPercent, edge.DepthPercent, edge.RealizedPercent)
+	if edge.ExpectedPercent > 0 {
+		fmt.Printf(" (%.0f%% ожидаемого)", edge.RealizedPercent/edge.ExpectedPercent*100)
+	}
+	if edge.Unrealized > 0 {
+		fmt.Printf("; ещё %d без результата, с ними как −100%%: %.4f%%", edge.Unrealized, edge.WorstRealizedPercent)
+	}
+	fmt.Println()
+}
+
+// swapEdge — средние ожидаемая и фактическая прибыль запусков, у которых
+// фактическая известна. Unrealized — запуски, которые потратили стартовый
+// актив на первой ноге, но не вернули его (PARTIAL, FAILED, застрявшие
+// остатки): в средние они не входят, а WorstRealizedPercent считает их
+// потерей всего потраченного, чтобы убытки не выпадали из сводки.
+type swapEdge struct {
+	Runs                 int
+	ExpectedPercent      float64
+	DepthPercent         float64
+	RealizedPercent      float64
+	Unrealized           int
+	WorstRealizedPercent float64
+}
+
+func (e *swapEdge) add(run repo.SwapRun) {
+	e.Runs++
+	e.ExpectedPercent += run.ExpectedProfitPercent
+	e.DepthPercent += run.ExpectedDepthProfitPercent
+	e.RealizedPercent += run.RealizedProfitPercent
+	e.WorstRealizedPercent += run.RealizedProfitPercent
+}
+
+func (e *swapEdge) addUnrealized() {
+	e.Unrealized++
+	e.WorstRealizedPercent -= 100
+}
+
+func (e *swapEdge) average() {
+	if total := e.Runs + e.Unrealized; total > 0 {
+		e.WorstRealizedPercent /= float64(total)
+	}
+	if e.Runs == 0 {
+		return
+	}
+	n := float64(e.Runs)
+	e.ExpectedPercent /= n
+	e.DepthPercent /= n
+	e.RealizedPercent /= n
+}
+
+type swapPathEdge struct {
+	Path string
+	swapEdge
+}
+
+// swapRunSummary — сводка журнала. Проскальзывание ноги положительно, когда
+// исполнение хуже ожидания: BUY дороже, SELL дешевле.
+type swapRunSummary struct {
+	Runs                    int
+	ByStatus                map[string]int
+	Realized                swapEdge
+	Paths                   []swapPathEdge
+	FilledLegs              int
+	AvgSlippagePercent      float64
+	AvgDepthSlippagePercent float64
+	AvgFillTime             time.Duration
+}
+
+func summarizeSwapRuns(runs []repo.SwapRun, legs []repo.SwapLeg) swapRunSummary {
+	summary := swapRunSummary{Runs: len(runs), ByStatus: map[string]int{}}
+	spent := map[int]bool{}
+	for _, leg := range legs {
+		if leg.Leg == 1 && !leg.Liquidation && leg.ExecutedQuantity > 0 {
+			spent[leg.RunID] = true
+		}
+	}
+	paths := map[string]*swapPathEdge{}
+	for _, run := range runs {
+		summary.ByStatus[run.Status]++
+		if run.FinalAmount <= 0 && !spent[run.ID] {
+			continue
+		}
+		path := paths[run.Path]
+		if path == nil {
+			path = &swapPathEdge{Path: run.Path}
+			paths[run.Path] = path
+		}
+		if run.FinalAmount <= 0 {
+			summary.Realized.addUnrealized()
+			path.addUnrealized()
+			continue
+		}
+		summary.Realized.add(run)
+		path.add(run)
+	}
+	summary.Realized.average()
+	for _, path := range paths {
+		path.average()
+		summary.Paths = append(summary.Paths, *path)
+	}
+	sort.Slice(summary.Paths, func(i, j int) bool {
+		ni := summary.Paths[i].Runs + summary.Paths[i].Unrealized
+		nj := summary.Paths[j].Runs + summary.Paths[j].Unrealized
+		if ni != nj {
+			return ni > nj
+		}
+		return summary.Paths[i].Path < summary.Paths[j].Path
+	})
+
+	depthLegs := 0
+	var fillTime time.Duration
+	for _, leg := range legs {
+		if leg.RealizedPrice <= 0 || leg.ExpectedPrice <= 0 {
+			continue
+		}
+		summary.FilledLegs++
+		summary.AvgSlippagePercent += swapSlippagePercent(leg.Side, leg.ExpectedPrice, leg.RealizedPrice)
+		if leg.ExpectedDepthPrice > 0 {
+			depthLegs++
+			summary.AvgDepthSlippagePercent += swapSlippagePercent(leg.Side, leg.ExpectedDepthPrice, leg.RealizedPrice)
+		}
+		if leg.FinishedAt != nil {
+			fillTime += leg.FinishedAt.Sub(leg.PlacedAt)
+		}
+	}
+	if summary.FilledLegs > 0 {
+		summary.AvgSlippagePercent /= float64(summary.FilledLegs)
+		summary.AvgFillTime = fillTime / time.Duration(summary.FilledLegs)
+	}
+	if depthLegs > 0 {
+		summary.AvgDepthSlippagePercent /= float64(depthLegs)
+	}
+	return summary
+}
+
+func swapSlippagePercent(side string, expected, realized float64) float64 {
+	if side == order.SELL.String() {
+		return (1 - realized/expected) * 100
+	}
+	return (realized/expected - 1) * 100
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+func TestSummarizeSwapRuns_edgeAndSlippage(t *testing.T) {
+	placed := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	finished := placed.Add(6 * time.Second)
+	runs := []repo.SwapRun{
+		{ID: 1, Path: "USDT -> A -> B -> USDT", Status: repo.SwapRunCompleted, ExpectedProfitPercent: 2, ExpectedDepthProfitPercent: 1.5, FinalAmount: 10.1, RealizedProfitPercent: 1},
+		{ID: 2, Path: "USDT -> A -> B -> USDT", Status: repo.SwapRunCompleted, ExpectedProfitPercent: 4, ExpectedDepthProfitPercent: 2.5, FinalAmount: 10.2, RealizedProfitPercent: 2},
+		{ID: 3, Path: "USDT -> C -> B -> USDT", Status: repo.SwapRunFailed, ExpectedProfitPercent: 3},
+	}
+	legs := []repo.SwapLeg{
+		{RunID: 1, Leg: 1, Side: "BUY", ExpectedPrice: 100, ExpectedDepthPrice: 100.5, RealizedPrice: 101, PlacedAt: placed, FinishedAt: &finished},
+		{RunID: 1, Leg: 2, Side: "SELL", ExpectedPrice: 50, RealizedPrice: 49.5, PlacedAt: placed, FinishedAt: &finished},
+		{RunID: 3, Leg: 1, Side: "BUY", ExpectedPrice: 10, Status: swapLegPlaceFailed},
+	}
+
+	summary := summarizeSwapRuns(runs, legs)
+	if summary.Runs != 3 || summary.ByStatus[repo.SwapRunCompleted] != 2 || summary.ByStatus[repo.SwapRunFailed] != 1 {
+		t.Fatalf("unexpected status counts %+v", summary.ByStatus)
+	}
+	if summary.Realized.Runs != 2 || summary.Realized.ExpectedPercent != 3 || summary.Realized.DepthPercent != 2 || summary.Realized.RealizedPercent != 1.5 {
+		t.Fatalf("failed run must not count toward realized edge, got %+v", summary.Realized)
+	}
+	if len(summary.Paths) != 1 || summary.Paths[0].Runs != 2 {
+		t.Fatalf("expected one path with realized runs, got %+v", summary.Paths)
+	}
+	// BUY на 1% дороже, SELL на 1% дешевле ожидания.
+	if summary.FilledLegs != 2 || math.Abs(summary.AvgSlippagePercent-1) > 1e-9 {
+		t.Fatalf("expected 1%% adverse slippage over 2 legs, got %d legs %.6f%%", summary.FilledLegs, summary.AvgSlippagePercent)
+	}
+	if want := (101/100.5 - 1) * 100; math.Abs(summary.AvgDepthSlippagePercent-want) > 1e-9 {
+		t.Fatalf("expected depth slippage %.6f%%, got %.6f%%", want, summary.AvgDepthSlippagePercent)
+	}
+	if summary.AvgFillTime != 6*time.Second {
+		t.Fatalf("expected 6s fill time, got %s", summary.AvgFillTime)
+	}
+}
+
+func TestSummarizeSwapRuns_countsSpentRunsWithoutResult(t *testing.T) {
+	runs := []repo.SwapRun{
+		{ID: 1, Path: "USDT -> A -> B -> USDT", Status: repo.SwapRunCompleted, ExpectedProfitPercent: 2, FinalAmount: 10.2, RealizedProfitPercent: 2},
+		{ID: 2, Path: "USDT -> A -> B -> USDT", Status: repo.SwapRunPartial, ExpectedProfitPercent: 2},
+		{ID: 3, Path: "USDT -> C -> B -> USDT", Status: repo.SwapRunMissed, ExpectedProfitPercent: 3},
+	}
+	legs := []repo.SwapLeg{
+		{RunID: 1, Leg: 1, Side: "BUY", ExecutedQuantity: 1},
+		{RunID: 2, Leg: 1, Side: "BUY", ExecutedQuantity: 1},
+		{RunID: 3, Leg: 1, Side: "BUY"},
+	}
+
+	summary := summarizeSwapRuns(runs, legs)
+	if summary.Realized.Runs != 1 || summary.Realized.RealizedPercent != 2 {
+		t.Fatalf("only the completed run must count toward realized edge, got %+v", summary.Realized)
+	}
+	if summary.Realized.Unrealized != 1 || summary.Realized.WorstRealizedPercent != -49 {
+		t.Fatalf("partial run must be reported as a full loss, got %+v", summary.Realized)
+	}
+	if len(summary.Paths) != 1 || summary.Paths[0].Unrealized != 1 {
+		t.Fatalf("missed run spent nothing and must be skipped, got %+v", summary.Paths)
+	}
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+// SwapStartHeld — значение Starts, при котором циклы ищутся из всех активов
+// со свободным балансом.
+const SwapStartHeld = "HELD"
+
+// swapUSDTPrice — цена актива в USDT по середине лучших bid/ask пары
+// ASSETUSDT или обратной USDTASSET; ok=false, если котировок нет.
+func swapUSDTPrice(asset string, book map[string]swapBookQuote) (float64, bool) {
+	if asset == swapStartAsset {
+		return 1, true
+	}
+	if mid := swapQuoteMid(book[asset+swapStartAsset]); mid > 0 {
+		return mid, true
+	}
+	if mid := swapQuoteMid(book[swapStartAsset+asset]); mid > 0 {
+		return 1 / mid, true
+	}
+	return 0, false
+}
+
+// swapQuoteMid — середина bid/ask или единственная известная сторона.
+func swapQuoteMid(q swapBookQuote) float64 {
+	switch {
+	case q.Bid > 0 && q.Ask > 0:
+		return (q.Bid + q.Ask) / 2
+	case q.Bid > 0:
+		return q.Bid
+	default:
+		return q.Ask
+	}
+}
+
+// swapStartAmounts — сколько каждого стартового актива ставится на цикл:
+// amountUSDT по цене актива в USDT. Starts из одного SwapStartHeld — все
+// активы со свободным балансом, кроме exclude. Актив без цены в USDT или со
+// свободным балансом меньше нужного пропускается с пояснением.
+func swapStartAmounts(starts []string, balances []mexc.Balance, book map[string]swapBookQuote, amountUSDT float64, exclude map[string]bool) map[string]float64 {
+	free := make(map[string]float64, len(balances))
+	for _, bal := range balances {
+		free[bal.Asset] = bal.Free
+	}
+	if len(starts) == 1 && starts[0] == SwapStartHeld {
+		starts = nil
+		for _, bal := range balances {
+			if bal.Free > 0 && !exclude[bal.Asset] {
+				starts = append(starts, bal.Asset)
+			}
+		}
+		sort.Strings(starts)
+	}
+
+	amounts := make(map[string]float64, len(starts))
+	for _, asset := range starts {
+		price, ok := swapUSDTPrice(asset, book)
+		if !ok {
+			fmt.Printf("[DEBUG] Старт %s пропущен: нет цены в USDT\n", asset)
+			continue
+		}
+		amount := amountUSDT / price
+		if free[asset] < amount {
+			fmt.Printf("[DEBUG] Старт %s пропущен: нужно %.8f, свободно %.8f\n", asset, amount, free[asset])
+			continue
+		}
+		amounts[asset] = amount
+	}
+	return amounts
+}
+
+// findSwapCyclesFromStarts ищет циклы из каждого стартового актива на его
+// объёме и сводит их в один список по убыванию прибыли.
+func findSwapCyclesFromStarts(graph *swapGraph, amounts map[string]float64, search swapCycleSearch) []swapCycle {
+	var out []swapCycle
+	for _, start := range sortedSwapAssets(amounts) {
+		search.Starts = []string{start}
+		search.Amount = amounts[start]
+		out = append(out, findSwapCycles(graph, search)...)
+	}
+	sortSwapCycles(out)
+	if search.Limit > 0 && len(out) > search.Limit {
+		out = out[:search.Limit]
+	}
+	return out
+}
+
+func sortedSwapAssets(amounts map[string]float64) []string {
+	assets := make([]string, 0, len(amounts))
+	for asset := range amounts {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	return assets
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/drybin/palisade/internal/adapter/webapi/mexcsim"
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/internal/domain/service"
+)
+
+// newSimSwapTriangle — ETH/BTC/USDT, где USDT -> ETH -> BTC -> USDT и
+// BTC -> USDT -> ETH -> BTC дают ~5%.
+func newSimSwapTriangle() (*mexcsim.Exchange, *memState) {
+	ex := mexcsim.NewExchange()
+	state := newMemState()
+	for _, pair := range []mexc.SymbolDetail{
+		newSimSwapPair("ETHUSDT", "ETH", "USDT", 2, "0.000001", "1"),
+		newSimSwapPair("ETHBTC", "ETH", "BTC", 6, "0.000001", "0.00001"),
+		newSimSwapPair("BTCUSDT", "BTC", "USDT", 2, "0.0000001", "1"),
+	} {
+		ex.AddSymbol(pair)
+		state.coins[pair.Symbol] = pair
+	}
+	ex.SetBook("ETHUSDT", []mexcsim.Level{{Price: 1999, Qty: 1}}, []mexcsim.Level{{Price: 2000, Qty: 1}})
+	ex.SetBook("ETHBTC", []mexcsim.Level{{Price: 0.021, Qty: 1}}, []mexcsim.Level{{Price: 0.0211, Qty: 1}})
+	ex.SetBook("BTCUSDT", []mexcsim.Level{{Price: 100000, Qty: 1}}, []mexcsim.Level{{Price: 100010, Qty: 1}})
+	return ex, state
+}
+
+func TestSwapStartAmounts_heldAssetsByUSDTPrice(t *testing.T) {
+	book := map[string]swapBookQuote{
+		"BTCUSDT":  {Bid: 99990, Ask: 100010},
+		"ETHUSDT":  {Bid: 1999, Ask: 2001},
+		"USDTEURT": {Bid: 0.8, Ask: 0.8},
+	}
+	balances := []mexc.Balance{
+		{Asset: "USDT", Free: 5},
+		{Asset: "BTC", Free: 0.001},
+		{Asset: "ETH", Free: 1},
+		{Asset: "EURT", Free: 100},
+		{Asset: "XYZ", Free: 1000},
+	}
+
+	held := swapStartAmounts([]string{SwapStartHeld}, balances, book, 10, map[string]bool{"ETH": true})
+	// USDT мало, ETH — запас, у XYZ нет цены; EURT стоит 1.25 USDT.
+	if len(held) != 2 || math.Abs(held["BTC"]-0.0001) > 1e-12 || math.Abs(held["EURT"]-8) > 1e-9 {
+		t.Fatalf("expected BTC and EURT starts, got %v", held)
+	}
+	explicit := swapStartAmounts([]string{"ETH", "USDT"}, balances, book, 10, nil)
+	if len(explicit) != 1 || math.Abs(explicit["ETH"]-0.005) > 1e-12 {
+		t.Fatalf("expected only ETH to cover 10 USDT, got %v", explicit)
+	}
+}
+
+func TestSwapProcess_simIOCFromHeldBTC(t *testing.T) {
+	ex, state := newSimSwapTriangle()
+	ex.SetBalance("BTC", 0.001)
+
+	opts := DefaultSwapProcessOptions()
+	opts.Mode = SwapModeIOC
+	opts.Starts = []string{SwapStartHeld}
+	if err := NewSwapProcessUsecase(newSimWebapi(t, ex), state, nil).Process(context.Background(), opts); err != nil {
+		t.Fatalf("swap from BTC: %v", err)
+	}
+
+	if len(state.swapRuns) != 1 {
+		t.Fatalf("expected one journaled run, got %d", len(state.swapRuns))
+	}
+	run := state.swapRuns[0]
+	if run.StartAsset != "BTC" || !strings.HasPrefix(run.Path, "BTC -> ") || run.Status != repo.SwapRunCompleted {
+		t.Fatalf("expected completed cycle from BTC, got %+v", run)
+	}
+	// 10 USDT по середине BTCUSDT, округлённые вниз к шагу лота.
+	if want := 10 / 100000.0; math.Abs(run.Amount-want) > 1e-6 {
+		t.Fatalf("expected %.8f BTC on the cycle, got %+v", want, run)
+	}
+	if run.RealizedProfitPercent <= 1 {
+		t.Fatalf("expected realized profit above 1%%, got %+v", run)
+	}
+	if btc, _ := ex.Balance("BTC"); btc <= 0.001 {
+		t.Fatalf("expected BTC to grow, got %.8f", btc)
+	}
+}
+
+func TestSwapProcess_simInventoryLegsFromStock(t *testing.T) {
+	ex, state := newSimSwapTriangle()
+	ex.SetBalance("USDT", 100)
+	ex.SetBalance("BTC", 0.0002)
+
+	opts := DefaultSwapProcessOptions()
+	opts.Mode = SwapModeInventory
+	opts.Inventory = []string{"ETH", "BTC"}
+	if err := NewSwapProcessUsecase(newSimWebapi(t, ex), state, nil).Process(context.Background(), opts); err != nil {
+		t.Fatalf("swap inventory: %v", err)
+	}
+
+	// ETH докуплен до 20 USDT, BTC на 20 USDT в допуске и не трогается.
+	var rebalances []mexcsim.Order
+	for _, o := range ex.Orders() {
+		if strings.Contains(o.ClientOrderID, "_inv_") {
+			rebalances = append(rebalances, o)
+		}
+	}
+	if len(rebalances) != 1 || rebalances[0].Symbol != "ETHUSDT" || rebalances[0].Side != "BUY" || math.Abs(rebalances[0].CummulativeQuoteQty-20) > 1e-9 {
+		t.Fatalf("expected one ETH top-up for 20 USDT, got %+v", rebalances)
+	}
+	if eth := state.swapStock["ETH"]; math.Abs(eth.Cost-20) > 1e-9 || math.Abs(eth.Quantity-rebalances[0].ExecutedQty) > 1e-12 {
+		t.Fatalf("expected ETH top-up recorded as bot stock, got %+v", eth)
+	}
+
+	if len(state.swapRuns) != 1 {
+		t.Fatalf("expected one journaled run, got %d", len(state.swapRuns))
+	}
+	run := state.swapRuns[0]
+	if run.Mode != SwapModeInventory || run.Path != "USDT -> ETH -> BTC -> USDT" || run.Status != repo.SwapRunCompleted || run.RealizedProfitPercent <= 1 {
+		t.Fatalf("expected completed inventory run, got %+v", run)
+	}
+	legs := 0
+	for key, leg := range state.swapLegs {
+		if key[0] != run.ID {
+			continue
+		}
+		legs++
+		if leg.Status != "FILLED" {
+			t.Fatalf("expected all legs filled, got %+v", leg)
+		}
+	}
+	if legs != 3 {
+		t.Fatalf("expected three journaled legs, got %d", legs)
+	}
+	// Нога 2 продаёт ETH из запаса столько же, сколько купила нога 1, с
+	// точностью до буфера между ногами.
+	if eth, _ := ex.Balance("ETH"); math.Abs(eth-0.01) > 0.00002 {
+		t.Fatalf("expected ETH stock near 0.01 after the cycle, got %.8f", eth)
+	}
+}
+
+func TestSwapProcess_simInventoryRebalanceChecksRisk(t *testing.T) {
+	ex, state := newSimSwapTriangle()
+	ex.SetBalance("USDT", 100)
+	// Докупка ETH на 20 USDT больше лимита экспозиции по символу.
+	risk := service.NewRiskManager(state, nil, service.RiskLimits{MaxSymbolExposureUSDT: 5})
+
+	opts := DefaultSwapProcessOptions()
+	opts.Mode = SwapModeInventory
+	opts.Inventory = []string{"ETH"}
+	if err := NewSwapProcessUsecase(newSimWebapi(t, ex), state, risk).Process(context.Background(), opts); err != nil {
+		t.Fatalf("blocked top-up must not fail the run: %v", err)
+	}
+	for _, o := range ex.Orders() {
+		if strings.Contains(o.ClientOrderID, "_inv_") {
+			t.Fatalf("expected no inventory top-up past the risk limit, got %+v", o)
+		}
+	}
+}
+
+func TestSwapProcess_simInventoryRebalanceSellsOnlyBotStock(t *testing.T) {
+	ex, state := newSimSwapTriangle()
+	ex.SetBalance("USDT", 100)
+	// 0.01 BTC (~1000 USDT) на счёте, из них ребаланс купил 0.0005 BTC; ETH
+	// тоже куплен ребалансом, но стартовый актив.
+	ex.SetBalance("BTC", 0.01)
+	ex.SetBalance("ETH", 0.05)
+	state.swapStock["BTC"] = repo.SwapInventory{Asset: "BTC", Quantity: 0.0005, Cost: 50}
+	state.swapStock["ETH"] = repo.SwapInventory{Asset: "ETH", Quantity: 0.05, Cost: 100}
+
+	opts := DefaultSwapProcessOptions()
+	opts.Mode = SwapModeInventory
+	opts.Inventory = []string{"BTC", "ETH"}
+	opts.Starts = []string{"USDT", "ETH"}
+	if err := NewSwapProcessUsecase(newSimWebapi(t, ex), state, nil).Process(context.Background(), opts); err != nil {
+		t.Fatalf("swap inventory: %v", err)
+	}
+
+	var rebalances []mexcsim.Order
+	for _, o := range ex.Orders() {
+		if strings.Contains(o.ClientOrderID, "_inv_") {
+			rebalances = append(rebalances, o)
+		}
+	}
+	if len(rebalances) != 1 || rebalances[0].Symbol != "BTCUSDT" || rebalances[0].Side != "SELL" || math.Abs(rebalances[0].ExecutedQty-0.0005) > 1e-12 {
+		t.Fatalf("expected a single BTC sell capped at the bot stock, got %+v", rebalances)
+	}
+	if btc := state.swapStock["BTC"]; btc.Quantity > 1e-12 || math.Abs(btc.Cost) > 1e-9 {
+		t.Fatalf("expected BTC stock written off, got %+v", btc)
+	}
+	if eth := state.swapStock["ETH"]; eth.Quantity != 0.05 || eth.Cost != 100 {
+		t.Fatalf("start asset stock must stay untouched, got %+v", eth)
+	}
+}
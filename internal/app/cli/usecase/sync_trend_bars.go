@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/domain/repo"
 	"github.com/drybin/palisade/pkg/wrap"
 )
@@ -15,11 +14,11 @@ type ISyncTrendBars interface {
 }
 
 type SyncTrendBars struct {
-	mexcAPI   *webapi.MexcWebapi
+	mexcAPI   repo.IMexcRepository
 	trendRepo repo.ITrendRepository
 }
 
-func NewSyncTrendBarsUsecase(mexcAPI *webapi.MexcWebapi, trendRepo repo.ITrendRepository) *SyncTrendBars {
+func NewSyncTrendBarsUsecase(mexcAPI repo.IMexcRepository, trendRepo repo.ITrendRepository) *SyncTrendBars {
 	return &SyncTrendBars{mexcAPI: mexcAPI, trendRepo: trendRepo}
 }
 
@@ -28,9 +27,6 @@ func (u *SyncTrendBars) Process(ctx context.Context, opts TrendOptions) error {
 		return wrap.Errorf("no symbols in watchlist")
 	}
 	sleep := time.Duration(opts.SleepMs) * time.Millisecond
-	if sleep <= 0 {
-		sleep = defaultTrendSleepMs * time.Millisecond
-	}
 
 	fmt.Printf("sync-trend-bars: %d symbols\n", len(opts.Symbols))
 	for i, symbol := range opts.Symbols {
@@ -46,7 +42,9 @@ func (u *SyncTrendBars) Process(ctx context.Context, opts TrendOptions) error {
 		} else {
 			fmt.Printf("[%d/%d] %s minute today: +%d bars\n", i+1, len(opts.Symbols), symbol, minN)
 		}
-		time.Sleep(sleep)
+		if sleep > 0 {
+			time.Sleep(sleep)
+		}
 	}
 	fmt.Println("sync done")
 	return nil
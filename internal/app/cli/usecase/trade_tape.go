@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/config"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const (
+	defaultTradeTapeTop       = 30
+	defaultTradeTapeRetention = 24 * time.Hour
+
+	// aggTrades отдаёт окно не больше часа: глубже лента не добирается.
+	tradeTapeMaxBackfill = time.Hour
+	tradeTapeLimit       = 1000
+	// За один проход по символу берётся не больше tradeTapeMaxPages страниц,
+	// остаток доберёт следующий запуск.
+	tradeTapeMaxPages = 10
+)
+
+type CollectTradeTapeOptions struct {
+	Symbols   []string
+	Top       int
+	Retention time.Duration
+	Debug     bool
+}
+
+func DefaultCollectTradeTapeOptions() CollectTradeTapeOptions {
+	return CollectTradeTapeOptions{Top: defaultTradeTapeTop, Retention: defaultTradeTapeRetention}
+}
+
+type ICollectTradeTape interface {
+	Process(context.Context, CollectTradeTapeOptions) error
+}
+
+// CollectTradeTape пополняет ленту сделок market_trade из /api/v3/aggTrades
+// и удаляет сделки старше Retention.
+type CollectTradeTape struct {
+	api       repo.IMexcRepository
+	stateRepo repo.IStateRepository
+}
+
+func NewCollectTradeTapeUsecase(api repo.IMexcRepository, stateRepo repo.IStateRepository) *CollectTradeTape {
+	return &CollectTradeTape{api: api, stateRepo: stateRepo}
+}
+
+// Process добирает ленту по Symbols, а без них — по Top символам с
+// наибольшим оборотом из market_snapshot. Ошибка по одному символу не
+// останавливает остальные.
+func (u *CollectTradeTape) Process(ctx context.Context, opts CollectTradeTapeOptions) error {
+	symbols := opts.Symbols
+	if len(symbols) == 0 {
+		snapshots, err := u.stateRepo.ListMarketSnapshots(ctx)
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range snapshots {
+			if opts.Top > 0 && len(symbols) >= opts.Top {
+				break
+			}
+			symbols = append(symbols, snapshot.Symbol)
+		}
+	}
+
+	now := time.Now().UTC()
+	saved, failed := 0, 0
+	for _, symbol := range symbols {
+		n, err := syncTradeTape(ctx, u.api, u.stateRepo, symbol, now.Add(-tradeTapeMaxBackfill), now)
+		if err != nil {
+			fmt.Printf("collect-trade-tape: %s: %v\n", symbol, err)
+			failed++
+			continue
+		}
+		saved += n
+	}
+
+	var deleted int64
+	if opts.Retention > 0 {
+		var err error
+		deleted, err = u.stateRepo.DeleteMarketTradesBefore(ctx, now.Add(-opts.Retention))
+		if err != nil {
+			return err
+		}
+	}
+	if opts.Debug {
+		fmt.Printf("collect-trade-tape: символов %d (ошибок %d), сделок получено %d, удалено старых %d\n", len(symbols), failed, saved, deleted)
+	}
+	return nil
+}
+
+// syncTradeTape догружает ленту символа от последней сохранённой сделки (но
+// не раньше since) до now. Граничная миллисекунда запрашивается повторно
+// целиком: одинаковые сделки в ней получают те же номера Seq, и дубликаты
+// отбрасывает первичный ключ market_trade.
+func syncTradeTape(ctx context.Context, api repo.IMexcRepository, stateRepo repo.IStateRepository, symbol string, since, now time.Time) (int, error) {
+	from := since
+	last, err := stateRepo.GetLastMarketTradeTime(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	if last != nil && last.After(from) {
+		from = *last
+	}
+	if floor := now.Add(-tradeTapeMaxBackfill); from.Before(floor) {
+		from = floor
+	}
+
+	received := 0
+	for page := 0; page < tradeTapeMaxPages; page++ {
+		trades, err := api.GetAggTrades(ctx, symbol, from.UnixMilli(), now.UnixMilli(), tradeTapeLimit)
+		if err != nil {
+			return received, err
+		}
+		rows := make([]repo.MarketTrade, 0, len(trades))
+		seen := make(map[repo.MarketTrade]int, len(trades))
+		for _, trade := range trades {
+			row := repo.MarketTrade{
+				Symbol:     symbol,
+				TradedAt:   trade.Time,
+				Price:      trade.Price,
+				Qty:        trade.Qty,
+				BuyerMaker: trade.BuyerMaker,
+			}
+			row.Seq = seen[row]
+			seen[row]++
+			rows = append(rows, row)
+		}
+		if err := stateRepo.SaveMarketTrades(ctx, rows); err != nil {
+			return received, wrap.Errorf("save trade tape %s: %w", symbol, err)
+		}
+		received += len(rows)
+		if len(trades) < tradeTapeLimit {
+			break
+		}
+		next := trades[len(trades)-1].Time
+		if !next.After(from) {
+			break
+		}
+		from = next
+	}
+	return received, nil
+}
+
+// tapeMetrics — сводка ленты за окно. Покупки и продажи — по стороне
+// тейкера, в USDT.
+type tapeMetrics struct {
+	trades          int
+	buyQuote        float64
+	sellQuote       float64
+	tradesPerMinute float64
+	// imbalance — (покупки − продажи) / (покупки + продажи), от -1 до 1.
+	imbalance float64
+	// largeBuys и largeSells — сделки крупнее largeMultiplier средних.
+	largeBuys  int
+	largeSells int
+}
+
+func computeTapeMetrics(trades []repo.MarketTrade, window time.Duration, largeMultiplier float64) tapeMetrics {
+	var m tapeMetrics
+	for _, trade := range trades {
+		if trade.BuyerMaker {
+			m.sellQuote += trade.Price * trade.Qty
+		} else {
+			m.buyQuote += trade.Price * trade.Qty
+		}
+	}
+	m.trades = len(trades)
+	if m.trades == 0 {
+		return m
+	}
+	if window > 0 {
+		m.tradesPerMinute = float64(m.trades) / window.Minutes()
+	}
+	total := m.buyQuote + m.sellQuote
+	if total > 0 {
+		m.imbalance = (m.buyQuote - m.sellQuote) / total
+	}
+	if largeMultiplier > 0 {
+		threshold := total / float64(m.trades) * largeMultiplier
+		for _, trade := range trades {
+			if trade.Price*trade.Qty < threshold {
+				continue
+			}
+			if trade.BuyerMaker {
+				m.largeSells++
+			} else {
+				m.largeBuys++
+			}
+		}
+	}
+	return m
+}
+
+// isTapeConfirmed — лента подтверждает отскок от поддержки: торги идут,
+// тейкеры покупают не меньше MinTapeBuyImbalance и крупных продаж не больше,
+// чем крупных покупок.
+func isTapeConfirmed(cfg config.SignalStrategyConfig, m tapeMetrics) bool {
+	return m.trades > 0 &&
+		m.tradesPerMinute >= cfg.MinTapeTradesPerMinute &&
+		m.imbalance >= cfg.MinTapeBuyImbalance &&
+		m.largeSells <= m.largeBuys
+}
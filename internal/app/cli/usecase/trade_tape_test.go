@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+func TestCollectTradeTape_appendsNewTradesAndDropsExpired(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	ex := newSimSignalExchange()
+	ex.SetTrades("AAAUSDT", []mexc.Trade{
+		{Price: 1.00, Qty: 10, Time: now.Add(-2 * time.Hour)},
+		{Price: 1.00, Qty: 10, Time: now.Add(-50 * time.Minute)},
+		{Price: 1.01, Qty: 5, Time: now.Add(-10 * time.Minute)},
+		{Price: 1.00, Qty: 3, Time: now.Add(-5 * time.Minute), BuyerMaker: true},
+	})
+	state := newMemState()
+	state.tape = []repo.MarketTrade{{Symbol: "BBBUSDT", TradedAt: now.Add(-48 * time.Hour), Price: 1, Qty: 1}}
+	u := NewCollectTradeTapeUsecase(newSimWebapi(t, ex), state)
+	opts := CollectTradeTapeOptions{Symbols: []string{"AAAUSDT"}, Retention: 24 * time.Hour}
+
+	if err := u.Process(context.Background(), opts); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if len(state.tape) != 3 {
+		t.Fatalf("expected 3 trades within the last hour and the expired one dropped, got %+v", state.tape)
+	}
+
+	if err := u.Process(context.Background(), opts); err != nil {
+		t.Fatalf("collect again: %v", err)
+	}
+	if len(state.tape) != 3 {
+		t.Fatalf("repeated run must not duplicate trades, got %d", len(state.tape))
+	}
+	last, _ := state.GetLastMarketTradeTime(context.Background(), "AAAUSDT")
+	if last == nil || !last.Equal(now.Add(-5*time.Minute)) {
+		t.Fatalf("unexpected last trade time %v", last)
+	}
+}
+
+func TestCollectTradeTape_keepsIdenticalTradesInSameMillisecond(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	ex := newSimSignalExchange()
+	ex.SetTrades("AAAUSDT", []mexc.Trade{
+		{Price: 1.00, Qty: 10, Time: now.Add(-time.Minute)},
+		{Price: 1.00, Qty: 10, Time: now.Add(-time.Minute)},
+		{Price: 1.00, Qty: 10, Time: now.Add(-time.Minute), BuyerMaker: true},
+	})
+	state := newMemState()
+	u := NewCollectTradeTapeUsecase(newSimWebapi(t, ex), state)
+	opts := CollectTradeTapeOptions{Symbols: []string{"AAAUSDT"}, Retention: 24 * time.Hour}
+
+	for run := 0; run < 2; run++ {
+		if err := u.Process(context.Background(), opts); err != nil {
+			t.Fatalf("collect: %v", err)
+		}
+		if len(state.tape) != 3 {
+			t.Fatalf("run %d: identical trades must be kept once each, got %+v", run, state.tape)
+		}
+	}
+}
+
+func TestComputeTapeMetrics_imbalanceAndLargePrints(t *testing.T) {
+	trades := []repo.MarketTrade{
+		{Price: 1, Qty: 10},
+		{Price: 1, Qty: 10},
+		{Price: 1, Qty: 10, BuyerMaker: true},
+		{Price: 1, Qty: 170},
+	}
+	m := computeTapeMetrics(trades, 2*time.Minute, 3)
+	if m.trades != 4 || m.tradesPerMinute != 2 {
+		t.Fatalf("unexpected trade count: %+v", m)
+	}
+	if math.Abs(m.buyQuote-190) > 1e-9 || math.Abs(m.sellQuote-10) > 1e-9 || math.Abs(m.imbalance-0.9) > 1e-9 {
+		t.Fatalf("unexpected volumes: %+v", m)
+	}
+	if m.largeBuys != 1 || m.largeSells != 0 {
+		t.Fatalf("expected one large buy print, got %+v", m)
+	}
+	if !isTapeConfirmed(testStrategy.Signals, m) {
+		t.Fatal("expected buying tape to confirm the rebound")
+	}
+
+	trades[3].BuyerMaker = true
+	m = computeTapeMetrics(trades, 2*time.Minute, 3)
+	if m.imbalance >= 0 || m.largeSells != 1 || isTapeConfirmed(testStrategy.Signals, m) {
+		t.Fatalf("large sell print must reject the rebound: %+v", m)
+	}
+	if isTapeConfirmed(testStrategy.Signals, computeTapeMetrics(nil, 2*time.Minute, 3)) {
+		t.Fatal("empty tape must not confirm the rebound")
+	}
+}
@@ -13,7 +13,7 @@ const (
 		"AVAX,UNI,ATOM,NEAR,APT,ARB,OP,POL,ICP,FIL,HBAR,ETC,INJ,RENDER," +
 		"TAO,SEI,STX,WLD,PEPE"
 	defaultTrendDays      = 100
-	defaultTrendSleepMs   = 200
+	defaultTrendSleepMs   = 0
 	defaultTrendSMAMin    = 10
 	defaultTrendSMAMax    = 100
 	defaultTrendSMAStep   = 10
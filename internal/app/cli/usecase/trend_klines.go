@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/domain/enum"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 	"github.com/drybin/palisade/internal/domain/repo"
@@ -49,7 +48,7 @@ func persistMinuteKlines(ctx context.Context, trendRepo repo.ITrendRepository, s
 
 func fetchAndPersistDaily(
 	ctx context.Context,
-	mexcAPI *webapi.MexcWebapi,
+	mexcAPI repo.IMexcRepository,
 	trendRepo repo.ITrendRepository,
 	symbol string,
 	limit int,
@@ -66,7 +65,7 @@ func fetchAndPersistDaily(
 
 func syncMinuteBarsToday(
 	ctx context.Context,
-	mexcAPI *webapi.MexcWebapi,
+	mexcAPI repo.IMexcRepository,
 	trendRepo repo.ITrendRepository,
 	symbol string,
 ) (int, error) {
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+const (
+	defaultOrderFillsPollInterval = 30 * time.Second
+	defaultBracketCheckInterval   = 5 * time.Second
+	orderFillsReconnectMin        = time.Second
+	orderFillsReconnectMax        = 30 * time.Second
+)
+
+type WatchOrderFillsOptions struct {
+	Live bool
+	// PollInterval — период опроса открытых сделок через REST, пока стрим
+	// недоступен или обновление не удалось применить.
+	PollInterval time.Duration
+	// BracketInterval — период сверки цены со стопами брекетов; 0 отключает
+	// стоп-ногу, и позиции защищает только следующий запуск
+	// execute-palisade-signals.
+	BracketInterval time.Duration
+}
+
+func DefaultWatchOrderFillsOptions() WatchOrderFillsOptions {
+	return WatchOrderFillsOptions{PollInterval: defaultOrderFillsPollInterval, BracketInterval: defaultBracketCheckInterval}
+}
+
+type IWatchOrderFills interface {
+	Process(context.Context, WatchOrderFillsOptions) error
+}
+
+type orderFillHandler interface {
+	HandleExecutionReport(context.Context, mexc.ExecutionReport, bool) (bool, error)
+	ReconcileOpenTrades(context.Context, bool) (bool, error)
+	CheckBrackets(context.Context, bool) (bool, error)
+}
+
+// WatchOrderFills применяет исполнения ордеров из user data stream сразу,
+// без ожидания следующего запуска execute-palisade-signals: исполненный BUY
+// тут же получает SELL. Пока стрим не подключён, открытые сделки опрашиваются
+// через GET /api/v3/order с интервалом PollInterval; после каждого
+// (пере)подключения — один раз сразу, чтобы догнать пропущенные обновления.
+// Раз в BracketInterval сверяется цена со стопами брекетов: MEXC не держит
+// стоп-ногу OCO сам, и её срабатывание эмулируется здесь.
+//
+// Обновления применяет одна горутина: у процесса одно соединение с Postgres,
+// и каждое обновление берёт торговую блокировку palisade:spot-trading.
+type WatchOrderFills struct {
+	executor orderFillHandler
+	stream   repo.IUserDataStream
+}
+
+func NewWatchOrderFillsUsecase(executor *ExecutePalisadeSignals, stream repo.IUserDataStream) *WatchOrderFills {
+	return &WatchOrderFills{executor: executor, stream: stream}
+}
+
+// orderFillsEvent — обновление ордера (report != nil) либо смена состояния
+// стрима.
+type orderFillsEvent struct {
+	report    *mexc.ExecutionReport
+	connected bool
+}
+
+func (u *WatchOrderFills) Process(ctx context.Context, opts WatchOrderFillsOptions) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultOrderFillsPollInterval
+	}
+	if !opts.Live {
+		fmt.Println("Режим просмотра: ордера не размещаются. Для торговли нужен флаг --live")
+	}
+
+	events := make(chan orderFillsEvent, 256)
+	send := func(event orderFillsEvent) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		u.runWorker(ctx, opts, events)
+	}()
+
+	backoff := orderFillsReconnectMin
+	for {
+		startedAt := time.Now()
+		err := u.stream.Run(
+			ctx,
+			func() { send(orderFillsEvent{connected: true}) },
+			func(report mexc.ExecutionReport) { send(orderFillsEvent{report: &report}) },
+		)
+		send(orderFillsEvent{connected: false})
+		if ctx.Err() != nil {
+			break
+		}
+		if time.Since(startedAt) > orderFillsReconnectMax {
+			backoff = orderFillsReconnectMin
+		}
+		fmt.Printf("watch-order-fills: стрим оборвался (%v), переподключение через %s\n", err, backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		backoff = min(backoff*2, orderFillsReconnectMax)
+	}
+	<-workerDone
+	fmt.Println("watch-order-fills: остановлен")
+	return nil
+}
+
+func (u *WatchOrderFills) runWorker(ctx context.Context, opts WatchOrderFillsOptions, events <-chan orderFillsEvent) {
+	// Начатое обновление доводится до конца и после отмены ctx: иначе BUY
+	// может остаться исполненным без выставленного SELL.
+	workCtx := context.WithoutCancel(ctx)
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	var brackets <-chan time.Time
+	if opts.BracketInterval > 0 {
+		bracketTicker := time.NewTicker(opts.BracketInterval)
+		defer bracketTicker.Stop()
+		brackets = bracketTicker.C
+	}
+
+	connected := false
+	stale := true
+	poll := func() {
+		acquired, err := u.executor.ReconcileOpenTrades(workCtx, opts.Live)
+		if err != nil {
+			fmt.Printf("watch-order-fills: опрос сделок: %v\n", err)
+		}
+		stale = err != nil || !acquired
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-events:
+			switch {
+			case event.report != nil:
+				acquired, err := u.executor.HandleExecutionReport(workCtx, *event.report, opts.Live)
+				if err != nil {
+					fmt.Printf("watch-order-fills: ордер %s: %v\n", event.report.OrderID, err)
+				}
+				if err != nil || !acquired {
+					stale = true
+				}
+			case event.connected:
+				connected = true
+				poll()
+			default:
+				connected = false
+			}
+		case <-ticker.C:
+			if !connected || stale {
+				poll()
+			}
+		case <-brackets:
+			if _, err := u.executor.CheckBrackets(workCtx, opts.Live); err != nil {
+				fmt.Printf("watch-order-fills: брекеты: %v\n", err)
+			}
+		}
+	}
+}
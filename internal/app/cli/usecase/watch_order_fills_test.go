@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/adapter/webapi"
+	"github.com/drybin/palisade/internal/adapter/webapi/mexcsim"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+func TestWatchOrderFills_simFillsArriveByPush(t *testing.T) {
+	ex := newSimSignalExchange()
+	stream := mexcsim.NewStream()
+	streamServer := mexcsim.NewStreamServer(stream)
+	t.Cleanup(streamServer.Close)
+	ex.SetOrderListener(stream.PublishOrder)
+
+	cfg := newSimConfig(t, ex)
+	cfg.WsUrl = mexcsim.StreamURL(streamServer)
+	spot := webapi.NewMexcSpotClient(cfg)
+	state := newMemState()
+	state.signals = []repo.PalisadeSignalState{newSimSignal()}
+	executor := NewExecutePalisadeSignalsUsecase(newSimWebapiFromConfig(cfg, spot), state, nil, nil, newFixedSignalSizer(), testStrategy)
+	if err := executor.Process(context.Background(), true); err != nil {
+		t.Fatalf("open signal: %v", err)
+	}
+	if state.trade(1).OrderId == "" {
+		t.Fatalf("expected BUY placed")
+	}
+
+	u := NewWatchOrderFillsUsecase(executor, webapi.NewMexcUserStream(cfg, spot))
+	opts := DefaultWatchOrderFillsOptions()
+	opts.Live = true
+	// Поллинг между подключениями отключён: исполнения должны прийти из стрима.
+	opts.PollInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- u.Process(ctx, opts) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("process: %v", err)
+		}
+	}()
+	if !stream.WaitSubscriptions(1, 5*time.Second) {
+		t.Fatalf("user stream was not subscribed")
+	}
+
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 0.999, Qty: 500}}, []mexcsim.Level{{Price: 1.0, Qty: 500}})
+	waitFor(t, "SELL after BUY fill", func() bool { return state.trade(1).OrderId_sell != "" })
+	if intent := state.intent(1); intent.Status != "FILLED" || intent.ExecutedQuantity != 10 {
+		t.Fatalf("BUY intent must record the fill, got %+v", intent)
+	}
+
+	ex.SetBook("AAAUSDT", []mexcsim.Level{{Price: 1.05, Qty: 500}}, []mexcsim.Level{{Price: 1.06, Qty: 500}})
+	waitFor(t, "trade closed", func() bool { return state.trade(1).CloseDate != nil })
+	if closed := state.trade(1); math.Abs(closed.SellPrice-1.05) > 1e-9 {
+		t.Fatalf("expected trade closed at 1.05, got %+v", closed)
+	}
+}
@@ -0,0 +1,136 @@
+package mexc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Коды ошибок MEXC, на которые опирается клиент.
+const (
+	ErrCodeInvalidSymbol        = -1121
+	ErrCodeOrderWouldMatch      = -2010
+	ErrCodeUnknownOrder         = -2011
+	ErrCodeOrderNotExist        = -2013
+	ErrCodeTooManyRequests      = 429
+	ErrCodeTooFrequent          = 510
+	ErrCodeMinNotional          = 30002
+	ErrCodeInsufficientPosition = 30004
+	ErrCodeOversold             = 30005
+	ErrCodeInsufficientBalance  = 10101
+	ErrCodeAPIKeyInvalid        = 10072
+	ErrCodeSignatureInvalid     = 700002
+	ErrCodeTimestampRecvWindow  = 700003
+	ErrCodeIPNotWhitelisted     = 700006
+	ErrCodeNoPermission         = 700007
+)
+
+// ErrorClass — что вызывающему делать с ошибкой.
+type ErrorClass string
+
+const (
+	// ErrorClassRetryable — запрос не исполнен из-за лимита или сбоя биржи,
+	// его можно повторить позже.
+	ErrorClassRetryable ErrorClass = "retryable"
+	// ErrorClassFatal — ключ, подпись или права: без вмешательства человека
+	// повтор не поможет.
+	ErrorClassFatal ErrorClass = "fatal"
+	// ErrorClassBusiness — биржа разобрала запрос и отклонила его по
+	// торговым правилам: баланс, символ, минимальный объём, нет ордера.
+	ErrorClassBusiness ErrorClass = "business"
+	// ErrorClassUnknown — не ответ API: сетевая ошибка, таймаут, разбор.
+	ErrorClassUnknown ErrorClass = "unknown"
+)
+
+// APIError — ошибка REST API MEXC: тело {"code": ..., "msg": ...}, HTTP статус
+// и эндпоинт, на котором она получена.
+type APIError struct {
+	HTTPStatus int
+	Code       int
+	Msg        string
+	Endpoint   string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("mexc %s: http %d, code %d: %s", e.Endpoint, e.HTTPStatus, e.Code, e.Msg)
+}
+
+// Class классифицирует ошибку по коду и HTTP статусу.
+func (e *APIError) Class() ErrorClass {
+	switch {
+	case e.rateLimited(), e.Code == ErrCodeTimestampRecvWindow, e.HTTPStatus >= http.StatusInternalServerError:
+		return ErrorClassRetryable
+	case e.Code == ErrCodeAPIKeyInvalid, e.Code == ErrCodeSignatureInvalid,
+		e.Code == ErrCodeIPNotWhitelisted, e.Code == ErrCodeNoPermission,
+		e.HTTPStatus == http.StatusUnauthorized, e.HTTPStatus == http.StatusForbidden:
+		return ErrorClassFatal
+	default:
+		return ErrorClassBusiness
+	}
+}
+
+func (e *APIError) rateLimited() bool {
+	return e.HTTPStatus == http.StatusTooManyRequests || e.Code == ErrCodeTooManyRequests || e.Code == ErrCodeTooFrequent
+}
+
+// AsAPIError достаёт *APIError из цепочки обёрток.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// ClassifyError — класс ошибки; не ответ API даёт ErrorClassUnknown.
+func ClassifyError(err error) ErrorClass {
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.Class()
+	}
+	return ErrorClassUnknown
+}
+
+// IsRejected — биржа точно не приняла запрос: ответ с кодом бизнес-ошибки
+// или фатальной ошибки доступа. Для ордера это значит, что его нет.
+func IsRejected(err error) bool {
+	class := ClassifyError(err)
+	return class == ErrorClassBusiness || class == ErrorClassFatal
+}
+
+func IsInsufficientBalance(err error) bool {
+	return hasCode(err, ErrCodeInsufficientBalance, ErrCodeInsufficientPosition, ErrCodeOversold)
+}
+
+func IsInvalidSymbol(err error) bool {
+	return hasCode(err, ErrCodeInvalidSymbol)
+}
+
+// IsOrderNotFound — биржа не знает ордер: запрос по несуществующему id или
+// отмена уже исполненного/отменённого ордера.
+func IsOrderNotFound(err error) bool {
+	return hasCode(err, ErrCodeOrderNotExist, ErrCodeUnknownOrder)
+}
+
+// IsPostOnlyRejected — LIMIT_MAKER отклонён, потому что исполнился бы
+// сразу как тейкер.
+func IsPostOnlyRejected(err error) bool {
+	return hasCode(err, ErrCodeOrderWouldMatch)
+}
+
+func IsRateLimited(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.rateLimited()
+}
+
+func hasCode(err error, codes ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
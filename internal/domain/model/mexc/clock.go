@@ -0,0 +1,25 @@
+package mexc
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrClockSkew — локальные часы расходятся с биржей больше допустимого;
+// подписанные ордера в таком состоянии не выставляются.
+var ErrClockSkew = errors.New("clock skew exceeds limit")
+
+// ClockStatus — результат последней синхронизации с /api/v3/time.
+type ClockStatus struct {
+	// Offset — смещение часов биржи относительно локальных (server - local).
+	Offset time.Duration
+	// RoundTrip — время запроса /api/v3/time; погрешность Offset не больше
+	// его половины.
+	RoundTrip time.Duration
+	SyncedAt  time.Time
+}
+
+// Skew — модуль смещения с учётом погрешности измерения.
+func (s ClockStatus) Skew() time.Duration {
+	return s.Offset.Abs() + s.RoundTrip/2
+}
@@ -0,0 +1,157 @@
+package mexc
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+// BookLevel — уровень стакана: цена и количество базового актива.
+type BookLevel struct {
+	Price float64
+	Qty   float64
+}
+
+// OrderBook — L2 стакан из /api/v3/depth: Bids по убыванию цены, Asks по
+// возрастанию.
+type OrderBook struct {
+	LastUpdateID int64
+	Bids         []BookLevel
+	Asks         []BookLevel
+}
+
+// ParseOrderBookFromJSON разбирает ответ depth, где уровни — пары строк
+// ["price", "qty"].
+func ParseOrderBookFromJSON(data []byte) (*OrderBook, error) {
+	var raw struct {
+		LastUpdateID int64      `json:"lastUpdateId"`
+		Bids         [][]string `json:"bids"`
+		Asks         [][]string `json:"asks"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, wrap.Errorf("failed to unmarshal depth: %w", err)
+	}
+	bids, err := parseBookLevels(raw.Bids)
+	if err != nil {
+		return nil, wrap.Errorf("depth bids: %w", err)
+	}
+	asks, err := parseBookLevels(raw.Asks)
+	if err != nil {
+		return nil, wrap.Errorf("depth asks: %w", err)
+	}
+	return &OrderBook{LastUpdateID: raw.LastUpdateID, Bids: bids, Asks: asks}, nil
+}
+
+func parseBookLevels(raw [][]string) ([]BookLevel, error) {
+	levels := make([]BookLevel, 0, len(raw))
+	for _, row := range raw {
+		if len(row) < 2 {
+			return nil, wrap.Errorf("bad level %v", row)
+		}
+		price, err := strconv.ParseFloat(row[0], 64)
+		if err != nil {
+			return nil, wrap.Errorf("bad price %q: %w", row[0], err)
+		}
+		qty, err := strconv.ParseFloat(row[1], 64)
+		if err != nil {
+			return nil, wrap.Errorf("bad qty %q: %w", row[1], err)
+		}
+		if price > 0 && qty > 0 {
+			levels = append(levels, BookLevel{Price: price, Qty: qty})
+		}
+	}
+	return levels, nil
+}
+
+func (b *OrderBook) BestBid() float64 {
+	if len(b.Bids) == 0 {
+		return 0
+	}
+	return b.Bids[0].Price
+}
+
+func (b *OrderBook) BestAsk() float64 {
+	if len(b.Asks) == 0 {
+		return 0
+	}
+	return b.Asks[0].Price
+}
+
+// FillBuy — покупка qty по ask не дороже limit (0 — без ограничения):
+// исполненное количество и средняя цена.
+func (b *OrderBook) FillBuy(qty, limit float64) (filled, avgPrice float64) {
+	return fillLevels(b.Asks, qty, func(price float64) bool { return limit <= 0 || price <= limit })
+}
+
+// FillSell — продажа qty по bid не дешевле limit (0 — без ограничения).
+func (b *OrderBook) FillSell(qty, limit float64) (filled, avgPrice float64) {
+	return fillLevels(b.Bids, qty, func(price float64) bool { return price >= limit })
+}
+
+func fillLevels(levels []BookLevel, qty float64, accept func(float64) bool) (filled, avgPrice float64) {
+	var quote float64
+	for _, level := range levels {
+		if filled >= qty || !accept(level.Price) {
+			break
+		}
+		take := math.Min(level.Qty, qty-filled)
+		filled += take
+		quote += take * level.Price
+	}
+	if filled <= 0 {
+		return 0, 0
+	}
+	return filled, quote / filled
+}
+
+// BuyVWAP — средняя цена покупки на quote USDT по ask и фактически
+// потраченная сумма; меньше quote, если стакана не хватило.
+func (b *OrderBook) BuyVWAP(quote float64) (avgPrice, spent float64) {
+	var qty float64
+	for _, level := range b.Asks {
+		if spent >= quote {
+			break
+		}
+		take := math.Min(level.Price*level.Qty, quote-spent)
+		spent += take
+		qty += take / level.Price
+	}
+	if qty <= 0 {
+		return 0, 0
+	}
+	return spent / qty, spent
+}
+
+// BuySlippage — насколько средняя цена покупки на quote USDT хуже лучшего
+// ask; ok=false, если стакана не хватает на весь объём.
+func (b *OrderBook) BuySlippage(quote float64) (slippage float64, ok bool) {
+	avg, spent := b.BuyVWAP(quote)
+	if avg <= 0 || spent < quote*(1-1e-9) {
+		return 0, false
+	}
+	return avg/b.BestAsk() - 1, true
+}
+
+// SellSlippage — насколько средняя цена продажи qty хуже лучшего bid.
+func (b *OrderBook) SellSlippage(qty float64) (slippage float64, ok bool) {
+	filled, avg := b.FillSell(qty, 0)
+	if avg <= 0 || filled < qty*(1-1e-9) {
+		return 0, false
+	}
+	return 1 - avg/b.BestBid(), true
+}
+
+// LiquidityInRange — объём заявок обеих сторон в USDT с ценой в [low, high].
+func (b *OrderBook) LiquidityInRange(low, high float64) float64 {
+	var quote float64
+	for _, side := range [][]BookLevel{b.Bids, b.Asks} {
+		for _, level := range side {
+			if level.Price >= low && level.Price <= high {
+				quote += level.Price * level.Qty
+			}
+		}
+	}
+	return quote
+}
@@ -0,0 +1,81 @@
+package mexc
+
+import "strconv"
+
+// StreamBookTicker — лучший bid/ask из канала spot@public.bookTicker.v3.api.
+type StreamBookTicker struct {
+	Symbol   string
+	BidPrice float64
+	BidQty   float64
+	AskPrice float64
+	AskQty   float64
+}
+
+// StreamEvent — одно сообщение маркет-стрима: либо обновление стакана, либо
+// текущее состояние минутной свечи (Kline.OpenTime/CloseTime в мс).
+type StreamEvent struct {
+	Symbol     string
+	EventTime  int64
+	BookTicker *StreamBookTicker
+	Kline      *Kline
+}
+
+// Коды статусов ордера в приватном канале spot@private.orders.v3.api.
+var streamOrderStatuses = map[int]string{
+	1: "NEW",
+	2: "FILLED",
+	3: "PARTIALLY_FILLED",
+	4: "CANCELED",
+	5: "PARTIALLY_CANCELED",
+}
+
+// StreamOrderStatus переводит код статуса из стрима в строку REST API.
+func StreamOrderStatus(code int) string {
+	if status, ok := streamOrderStatuses[code]; ok {
+		return status
+	}
+	return "UNKNOWN"
+}
+
+// StreamOrderStatusCode — обратное преобразование для StreamOrderStatus.
+func StreamOrderStatusCode(status string) int {
+	for code, name := range streamOrderStatuses {
+		if name == status {
+			return code
+		}
+	}
+	return 0
+}
+
+// ExecutionReport — обновление ордера аккаунта из user data stream.
+type ExecutionReport struct {
+	Symbol             string
+	OrderID            string
+	ClientOrderID      string
+	Side               string
+	Status             string
+	Price              float64
+	Quantity           float64
+	CumulativeQty      float64
+	CumulativeQuoteQty float64
+	CreateTime         int64
+	EventTime          int64
+}
+
+// QueryOrderResult представляет отчёт в виде ответа GET /api/v3/order, чтобы
+// push-обновления обрабатывались тем же кодом, что и поллинг.
+func (r ExecutionReport) QueryOrderResult() QueryOrderResult {
+	return QueryOrderResult{
+		Symbol:              r.Symbol,
+		OrderID:             r.OrderID,
+		ClientOrderID:       r.ClientOrderID,
+		Price:               strconv.FormatFloat(r.Price, 'f', -1, 64),
+		OrigQty:             strconv.FormatFloat(r.Quantity, 'f', -1, 64),
+		ExecutedQty:         strconv.FormatFloat(r.CumulativeQty, 'f', -1, 64),
+		CummulativeQuoteQty: strconv.FormatFloat(r.CumulativeQuoteQty, 'f', -1, 64),
+		Status:              r.Status,
+		Side:                r.Side,
+		Time:                r.CreateTime,
+		UpdateTime:          r.EventTime,
+	}
+}
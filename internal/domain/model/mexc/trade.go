@@ -0,0 +1,81 @@
+package mexc
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+// Trade — сделка ленты: одна сделка из /api/v3/trades или агрегат сделок
+// одного тейкера по одной цене из /api/v3/aggTrades.
+type Trade struct {
+	Price float64
+	Qty   float64
+	Time  time.Time
+	// BuyerMaker — покупатель стоял в стакане, то есть тейкер продавал.
+	BuyerMaker bool
+}
+
+// Quote — объём сделки в котируемом активе.
+func (t Trade) Quote() float64 {
+	return t.Price * t.Qty
+}
+
+// ParseTradesFromJSON разбирает ответ /api/v3/trades.
+func ParseTradesFromJSON(data []byte) ([]Trade, error) {
+	var raw []struct {
+		Price        string `json:"price"`
+		Qty          string `json:"qty"`
+		Time         int64  `json:"time"`
+		IsBuyerMaker bool   `json:"isBuyerMaker"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, wrap.Errorf("failed to unmarshal trades: %w", err)
+	}
+	trades := make([]Trade, 0, len(raw))
+	for _, row := range raw {
+		trade, err := newTrade(row.Price, row.Qty, row.Time, row.IsBuyerMaker)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, trade)
+	}
+	return trades, nil
+}
+
+// ParseAggTradesFromJSON разбирает ответ /api/v3/aggTrades. Идентификаторы
+// a/f/l MEXC отдаёт пустыми, поэтому они не разбираются.
+func ParseAggTradesFromJSON(data []byte) ([]Trade, error) {
+	var raw []struct {
+		Price        string `json:"p"`
+		Qty          string `json:"q"`
+		Time         int64  `json:"T"`
+		IsBuyerMaker bool   `json:"m"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, wrap.Errorf("failed to unmarshal agg trades: %w", err)
+	}
+	trades := make([]Trade, 0, len(raw))
+	for _, row := range raw {
+		trade, err := newTrade(row.Price, row.Qty, row.Time, row.IsBuyerMaker)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, trade)
+	}
+	return trades, nil
+}
+
+func newTrade(price, qty string, timeMs int64, buyerMaker bool) (Trade, error) {
+	p, err := strconv.ParseFloat(price, 64)
+	if err != nil {
+		return Trade{}, wrap.Errorf("bad trade price %q: %w", price, err)
+	}
+	q, err := strconv.ParseFloat(qty, 64)
+	if err != nil {
+		return Trade{}, wrap.Errorf("bad trade qty %q: %w", qty, err)
+	}
+	return Trade{Price: p, Qty: q, Time: time.UnixMilli(timeMs).UTC(), BuyerMaker: buyerMaker}, nil
+}
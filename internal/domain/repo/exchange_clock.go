@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+// IExchangeClock — синхронизация часов с биржей. SyncClock заново измеряет
+// смещение и возвращает его вместе с mexc.ErrClockSkew, если расхождение
+// больше допустимого.
+type IExchangeClock interface {
+	SyncClock(ctx context.Context) (mexc.ClockStatus, error)
+}
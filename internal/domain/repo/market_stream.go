@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+// IMarketStream — подписка на публичный маркет-стрим биржи (book ticker и
+// минутные свечи). Run открывает соединения, подписывается на символы и
+// вызывает handler на каждое сообщение, пока соединение живо; возвращает
+// ошибку при обрыве и nil после отмены ctx. Переподключение — забота
+// вызывающего. handler может вызываться из нескольких горутин.
+type IMarketStream interface {
+	Run(ctx context.Context, symbols []string, handler func(mexc.StreamEvent)) error
+}
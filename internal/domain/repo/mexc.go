@@ -3,9 +3,49 @@ package repo
 import (
 	"context"
 
+	"github.com/drybin/palisade/internal/domain/enum"
+	"github.com/drybin/palisade/internal/domain/model"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
 )
 
+// IMexcRepository — биржевой шлюз, от которого зависят usecase'ы и сервисы.
+// Реализуется webapi.MexcWebapi; в тестах подменяется фейком, а в будущем
+// может быть реализован для другой биржи.
 type IMexcRepository interface {
+	// Баланс и рыночные данные
 	GetBalance(ctx context.Context) (*mexc.AccountInfo, error)
+	GetAllTickerPrices(ctx context.Context) (*mexc.TickersWithPrice, error)
+	GetAllBookTickers(ctx context.Context) (*mexc.BookTickers, error)
+	GetAll24hTickers(ctx context.Context) (*mexc.Tickers24h, error)
+	GetAvgPrice(ctx context.Context, symbol string) (*mexc.AvgPrice, error)
+	// GetDepth — стакан по символу, limit уровней с каждой стороны.
+	GetDepth(ctx context.Context, symbol string, limit int) (*mexc.OrderBook, error)
+	// GetTrades — последние limit сделок символа.
+	GetTrades(ctx context.Context, symbol string, limit int) ([]mexc.Trade, error)
+	// GetAggTrades — агрегированные сделки за [startTimeMs, endTimeMs]; окно
+	// MEXC не больше часа, нулевые границы — последние сделки.
+	GetAggTrades(ctx context.Context, symbol string, startTimeMs, endTimeMs int64, limit int) ([]mexc.Trade, error)
+	GetKlines(pair model.PairWithLevels, interval enum.KlineInterval) (*mexc.Klines, error)
+	GetKlinesPublic(
+		ctx context.Context,
+		symbol string,
+		interval enum.KlineInterval,
+		limit int,
+		startTimeMs *int64,
+	) (*mexc.Klines, error)
+
+	// Информация о торговых парах
+	GetSymbolInfo(ctx context.Context, symbol string) (*mexc.SymbolInfo, error)
+	GetExchangeInfoAll(ctx context.Context) (*mexc.SymbolInfo, error)
+
+	// Ордера
+	// NewOrder при потерянном ответе ищет ордер по NewClientOrderId и
+	// возвращает найденный вместо ошибки.
+	NewOrder(ctx context.Context, orderParams model.OrderParams) (*mexc.PlaceOrderResult, error)
+	CancelOrder(ctx context.Context, symbol string, orderId string) (*mexc.CancelOrderResponse, error)
+	GetOpenOrders(ctx context.Context, orderParams model.OrderParams) (*mexc.OpenOrders, error)
+	// GetOrderQuery и GetOrderQueryByClientID возвращают nil, nil, если биржа
+	// не знает такого ордера.
+	GetOrderQuery(ctx context.Context, symbol string, orderId string) (*mexc.QueryOrderResult, error)
+	GetOrderQueryByClientID(ctx context.Context, symbol string, clientOrderID string) (*mexc.QueryOrderResult, error)
 }
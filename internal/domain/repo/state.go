@@ -27,6 +27,19 @@ type MarketSnapshot struct {
 	PriceChangePercent float64
 }
 
+// MarketTrade — сделка из ленты символа (таблица market_trade).
+type MarketTrade struct {
+	Symbol   string
+	TradedAt time.Time
+	Price    float64
+	Qty      float64
+	// BuyerMaker — тейкер продавал.
+	BuyerMaker bool
+	// Seq — номер сделки среди одинаковых по времени, цене, объёму и
+	// стороне: MEXC не отдаёт идентификаторы aggTrades.
+	Seq int
+}
+
 type PalisadeSignalState struct {
 	Symbol             string
 	SentAt             time.Time
@@ -41,6 +54,8 @@ type PalisadeSignalState struct {
 	InvalidationReason string
 	ValidUntil         time.Time
 	UpdatedAt          time.Time
+	// ConfigVersion — версия strategy_config, по которой построен сигнал.
+	ConfigVersion string
 }
 
 type OrderIntent struct {
@@ -60,11 +75,227 @@ type OrderIntent struct {
 	LastError          string
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
+	// OrderType — LIMIT или LIMIT_MAKER (post-only).
+	OrderType string
+	// MakerFeeSaving — разница ставок тейкера и мейкера на момент
+	// выставления; для LIMIT_MAKER экономия = CumulativeQuoteQty × MakerFeeSaving.
+	MakerFeeSaving float64
 }
 
-type PaperTrade struct {
+// MakerFeeSavings — сводка исполненных post-only ордеров.
+type MakerFeeSavings struct {
+	Orders       int
+	FeeSavedUSDT float64
+}
+
+// Статусы OrderBracket.
+const (
+	OrderBracketActive    = "ACTIVE"
+	OrderBracketTriggered = "TRIGGERED"
+	OrderBracketClosed    = "CLOSED"
+)
+
+// OrderBracket — OCO-выход из позиции сигнала: тейк-профит лимитным SELL по
+// TakeProfitPrice и стоп-лимит — при bid не выше StopPrice позиция
+// продаётся лимитным SELL по StopLimitPrice. Исполнение одной ноги снимает
+// другую. MEXC spot не принимает ни STOP_LIMIT, ни OCO, поэтому на бирже
+// стоит только тейк-профит, а стоп-ногу эмулирует watch-order-fills.
+type OrderBracket struct {
+	TradeID         int
+	Symbol          string
+	TakeProfitPrice float64
+	StopPrice       float64
+	StopLimitPrice  float64
+	// ExpiresAt — после него позиция закрывается по рынку (MaxPositionHold).
+	ExpiresAt *time.Time
+	Status    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// StopTriggered — bid дошёл до стоп-цены.
+func (b OrderBracket) StopTriggered(bid float64) bool {
+	return b.StopPrice > 0 && bid > 0 && bid <= b.StopPrice
+}
+
+// Expired — позиция удерживается дольше MaxPositionHold.
+func (b OrderBracket) Expired(now time.Time) bool {
+	return b.ExpiresAt != nil && now.After(*b.ExpiresAt)
+}
+
+// TradeExitState — состояние exit-политики живой сделки trade_log, общей с
+// бумажной торговлей: взведён ли трейлинг, взята ли быстрая прибыль и
+// экстремумы bid с момента входа. Fee и LotStep фиксируются при первом SELL.
+type TradeExitState struct {
+	TradeID         int
+	StrategyVersion int
+	// StrategyKey — ключ имя/vN стратегии, по которой открыта сделка: по
+	// нему выход ищется в реестре.
+	StrategyKey        string
+	Fee                float64
+	LotStep            float64
+	BreakEvenArmed     bool
+	PartialProfitTaken bool
+	// PartialOrderID — SELL доли быстрой прибыли, пока он не заменён
+	// тейк-профитом на остаток.
+	PartialOrderID string
+	MaxBidPrice    float64
+	MinBidPrice    float64
+	UpdatedAt      time.Time
+}
+
+// Статусы Grid.
+const (
+	GridActive  = "ACTIVE"
+	GridStopped = "STOPPED"
+)
+
+// Grid — сетка ордеров внутри диапазона палисады: Levels покупок от
+// поддержки до середины диапазона и парная продажа над каждой. Сетка живёт,
+// пока монета остаётся палисадой и цена не вышла из диапазона.
+type Grid struct {
+	ID              int
+	Symbol          string
+	SupportPrice    float64
+	ResistancePrice float64
+	Levels          int
+	QuotePerLevel   float64
+	Status          string
+	StopReason      string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Статусы GridLevel. *_PLACING — ордер с ClientOrderID отправляется на
+// биржу; если процесс упал, следующий запуск ищет его по ClientOrderID.
+const (
+	GridLevelWaiting     = "WAITING"
+	GridLevelBuyPlacing  = "BUY_PLACING"
+	GridLevelBuyOpen     = "BUY_OPEN"
+	GridLevelSellPlacing = "SELL_PLACING"
+	GridLevelSellOpen    = "SELL_OPEN"
+	GridLevelClosed      = "CLOSED"
+)
+
+// GridLevel — уровень сетки: BUY Quantity по BuyPrice, после исполнения
+// SELL купленного по SellPrice, затем снова BUY. HeldQuantity и HeldCost —
+// купленное уровнем и ещё не проданное, RealizedPnl — без учёта комиссий.
+type GridLevel struct {
+	GridID        int
+	Level         int
+	BuyPrice      float64
+	SellPrice     float64
+	Quantity      float64
+	Status        string
+	OrderID       string
+	ClientOrderID string
+	HeldQuantity  float64
+	HeldCost      float64
+	Cycles        int
+	RealizedPnl   float64
+	UpdatedAt     time.Time
+}
+
+// Статусы SwapRun. LIQUIDATED — цикл исполнился не целиком и остатки
+// промежуточных активов проданы в стартовый, PARTIAL — остатки оставлены на
+// балансе, MISSED — IOC первой ноги ничего не исполнил.
+const (
+	SwapRunRunning    = "RUNNING"
+	SwapRunCompleted  = "COMPLETED"
+	SwapRunUnwound    = "UNWOUND"
+	SwapRunFailed     = "FAILED"
+	SwapRunLiquidated = "LIQUIDATED"
+	SwapRunPartial    = "PARTIAL"
+	SwapRunMissed     = "MISSED"
+)
+
+// SwapRun — запуск swap-process: какой цикл исполнялся и какую прибыль
+// обещали лучшие цены (ExpectedProfitPercent) и стаканы
+// (ExpectedDepthProfitPercent). FinalAmount — сколько стартового актива
+// вернулось после комиссий (включая LiquidatedAmount от продажи остатков),
+// RealizedProfitPercent — от потраченного на первой ноге; оба 0, если
+// исполнение неизвестно. Mode — limit или ioc.
+type SwapRun struct {
+	ID                         int
+	Path                       string
+	StartAsset                 string
+	Mode                       string
+	Amount                     float64
+	ExpectedProfitPercent      float64
+	ExpectedDepthProfitPercent float64
+	Status                     string
+	UnwindOutcome              string
+	FinalAmount                float64
+	RealizedProfitPercent      float64
+	LiquidatedAmount           float64
+	LastError                  string
+	StartedAt                  time.Time
+	FinishedAt                 *time.Time
+}
+
+// SwapLeg — нога запуска: ExpectedPrice — лимит по лучшей цене,
+// ExpectedDepthPrice — VWAP по стакану перед запуском, RealizedPrice —
+// средняя цена исполнения. Fee — оценка комиссии в активе ToAsset по
+// тейкерской ставке пары. Liquidation — продажа остатка промежуточного
+// актива после неполного цикла, а не нога цикла.
+type SwapLeg struct {
+	RunID              int
+	Leg                int
+	Symbol             string
+	Side               string
+	FromAsset          string
+	ToAsset            string
+	ExpectedPrice      float64
+	ExpectedDepthPrice float64
+	Quantity           float64
+	ClientOrderID      string
+	OrderID            string
+	Status             string
+	ExecutedQuantity   float64
+	CumulativeQuoteQty float64
+	RealizedPrice      float64
+	Fee                float64
+	PlacedAt           time.Time
+	FinishedAt         *time.Time
+	Liquidation        bool
+}
+
+// SwapOpportunity — цикл, который swap-monitor видел прибыльнее порога
+// ThresholdPercent подряд в Observations опросах: с FirstSeenAt по
+// LastSeenAt, закрыт в ClosedAt, когда пропал или монитор остановился.
+// DepthAmount — сколько стартового актива цикл пропускает по объёмам лучших
+// bid/ask: PeakDepthAmount в момент пика прибыли, MaxDepthAmount — наибольший.
+type SwapOpportunity struct {
 	ID                 int
-	StrategyVersion    int
+	Path               string
+	StartAsset         string
+	Legs               int
+	ThresholdPercent   float64
+	FirstSeenAt        time.Time
+	LastSeenAt         time.Time
+	ClosedAt           time.Time
+	Observations       int
+	FirstProfitPercent float64
+	PeakProfitPercent  float64
+	PeakAt             time.Time
+	PeakDepthAmount    float64
+	MaxDepthAmount     float64
+}
+
+// SwapInventory — сколько актива запаса swap-process купил ребаланс
+// (Quantity) и за сколько USDT (Cost). Больше Quantity ребаланс не продаёт:
+// остальной баланс актива принадлежит владельцу счёта.
+type SwapInventory struct {
+	Asset    string
+	Quantity float64
+	Cost     float64
+}
+
+type PaperTrade struct {
+	ID              int
+	StrategyVersion int
+	// StrategyKey — ключ имя/vN стратегии, по которой открыта сделка.
+	StrategyKey        string
 	Symbol             string
 	SignalAt           time.Time
 	Status             string
@@ -91,6 +322,7 @@ type PaperTrade struct {
 	ExitReason         string
 	LastPrice          float64
 	UpdatedAt          time.Time
+	ConfigVersion      string
 }
 
 type PaperTradeStats struct {
@@ -103,6 +335,56 @@ type PaperTradeStats struct {
 	AveragePnL float64
 	Wins       int
 	Losses     int
+	// AverageWin и AverageLoss — средний P/L закрытых прибыльных и
+	// убыточных сделок; AverageLoss положителен.
+	AverageWin  float64
+	AverageLoss float64
+}
+
+type BacktestRun struct {
+	ID              int
+	CreatedAt       time.Time
+	StrategyVersion int
+	Symbols         string
+	PeriodFrom      time.Time
+	PeriodTo        time.Time
+	Spread          float64
+	Bars            int
+	Signals         int
+	Trades          int
+	Canceled        int
+	Wins            int
+	Losses          int
+	WinRate         float64
+	TotalPnL        float64
+	MaxDrawdown     float64
+	ExitReasons     string
+}
+
+// DaemonJobRun — результат одного запуска задачи daemon; пустой Error —
+// успешный запуск.
+type DaemonJobRun struct {
+	Name       string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Error      string
+}
+
+// ClosedTrade — закрытая сделка из trade_log или trade_log_manual (Source —
+// имя таблицы); из неё считается реализованный PnL для риск-лимитов.
+type ClosedTrade struct {
+	Source    string
+	ID        int
+	Symbol    string
+	BuyPrice  float64
+	SellPrice float64
+	Amount    float64
+	CloseDate time.Time
+}
+
+// PnL — реализованный результат сделки в USDT без учёта комиссий.
+func (t ClosedTrade) PnL() float64 {
+	return (t.SellPrice - t.BuyPrice) * t.Amount
 }
 
 type IStateRepository interface {
@@ -138,7 +420,12 @@ type IStateRepository interface {
 	UpdateSellOrderIdTradeLogManual(context.Context, int, string) error
 	UpdateSuccesTradeLogManual(context.Context, int, time.Time, float64, float64) error
 	UpsertMarketSnapshot(context.Context, MarketSnapshot) error
+	UpsertMarketSnapshots(context.Context, []MarketSnapshot) error
 	ListMarketSnapshots(context.Context) ([]MarketSnapshot, error)
+	SaveMarketTrades(context.Context, []MarketTrade) error
+	GetLastMarketTradeTime(context.Context, string) (*time.Time, error)
+	ListMarketTradesSince(context.Context, string, time.Time) ([]MarketTrade, error)
+	DeleteMarketTradesBefore(context.Context, time.Time) (int64, error)
 	GetLastPalisadeSignal(context.Context, string) (*time.Time, error)
 	SavePalisadeSignal(context.Context, string, time.Time, float64) error
 	SavePalisadeSignalState(context.Context, PalisadeSignalState) error
@@ -148,12 +435,39 @@ type IStateRepository interface {
 	UpdateOrderIntentTradeID(context.Context, int, int) error
 	ListRecoverableOrderIntents(context.Context) ([]OrderIntent, error)
 	ListOrderIntentsByTradeID(context.Context, int) ([]OrderIntent, error)
+	GetMakerFeeSavings(context.Context) (MakerFeeSavings, error)
+	SaveOrderBracket(context.Context, OrderBracket) error
+	ListActiveOrderBrackets(context.Context) ([]OrderBracket, error)
+	UpdateOrderBracketStatus(context.Context, int, string) error
+	GetTradeExitState(context.Context, int) (*TradeExitState, error)
+	SaveTradeExitState(context.Context, TradeExitState) error
+	CreateGrid(context.Context, Grid) (*Grid, error)
+	ListActiveGrids(context.Context) ([]Grid, error)
+	UpdateGridStatus(context.Context, int, string, string) error
+	SaveGridLevel(context.Context, GridLevel) error
+	ListGridLevels(context.Context, int) ([]GridLevel, error)
+	CreateSwapRun(context.Context, SwapRun) (*SwapRun, error)
+	FinishSwapRun(context.Context, SwapRun) error
+	SaveSwapLeg(context.Context, SwapLeg) error
+	ListSwapRunsSince(context.Context, time.Time) ([]SwapRun, error)
+	ListSwapLegsSince(context.Context, time.Time) ([]SwapLeg, error)
+	SaveSwapOpportunity(context.Context, SwapOpportunity) error
+	ListSwapOpportunitiesSince(context.Context, time.Time) ([]SwapOpportunity, error)
+	ListSwapInventory(context.Context) ([]SwapInventory, error)
+	SaveSwapInventory(context.Context, SwapInventory) error
 	GetOpenPaperTradeBySymbol(context.Context, string, int) (*PaperTrade, error)
 	GetPaperTradeBySignal(context.Context, string, time.Time, int) (*PaperTrade, error)
 	ListOpenPaperTrades(context.Context, int) ([]PaperTrade, error)
 	CreatePaperTrade(context.Context, PaperTrade) (*PaperTrade, error)
 	UpdatePaperTrade(context.Context, PaperTrade) error
 	GetPaperTradeStats(context.Context, int) (PaperTradeStats, error)
+	SaveBacktestRun(context.Context, BacktestRun) (*BacktestRun, error)
+	SaveDaemonJobRun(context.Context, DaemonJobRun) error
+	ListClosedTradesSince(context.Context, time.Time) ([]ClosedTrade, error)
+	ListRecentClosedTrades(context.Context, int) ([]ClosedTrade, error)
+	GetRiskAlertSentAt(context.Context, string) (*time.Time, error)
+	SaveRiskAlert(context.Context, string, time.Time) error
+	SaveStrategyConfig(context.Context, string, []byte, time.Time) error
 }
 
 type SaveTradeLogParams struct {
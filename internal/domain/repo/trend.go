@@ -37,6 +37,7 @@ type ITrendRepository interface {
 	ListDailyBars(ctx context.Context, symbol string) ([]MarketDailyBar, error)
 
 	UpsertMinuteBar(ctx context.Context, bar MarketMinuteBar) error
+	UpsertMinuteBars(ctx context.Context, bars []MarketMinuteBar) error
 	GetLastMinuteOpenTime(ctx context.Context, symbol string) (*time.Time, error)
 	ListMinuteBarsFrom(ctx context.Context, symbol string, from time.Time) ([]MarketMinuteBar, error)
 
@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+)
+
+// IUserDataStream — приватный стрим ордеров аккаунта. Run получает listenKey,
+// подписывается и вызывает handler на каждое обновление ордера, пока
+// соединение живо; ready вызывается после подписки, чтобы вызывающий мог
+// догнать то, что случилось до неё. Возвращает ошибку при обрыве и nil после
+// отмены ctx; переподключение — забота вызывающего.
+type IUserDataStream interface {
+	Run(ctx context.Context, ready func(), handler func(mexc.ExecutionReport)) error
+}
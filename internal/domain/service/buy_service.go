@@ -14,12 +14,12 @@ import (
 const amountInUSDT = 10.0
 
 type ByuService struct {
-	api       *webapi.MexcWebapi
+	api       repo.IMexcRepository
 	apiV2     *webapi.MexcV2Webapi
 	stateRepo repo.IStateRepository
 }
 
-func NewByuService(api *webapi.MexcWebapi, apiV2 *webapi.MexcV2Webapi, stateRepo repo.IStateRepository) *ByuService {
+func NewByuService(api repo.IMexcRepository, apiV2 *webapi.MexcV2Webapi, stateRepo repo.IStateRepository) *ByuService {
 	return &ByuService{api: api, apiV2: apiV2, stateRepo: stateRepo}
 }
 
@@ -38,6 +38,7 @@ func (s *ByuService) Buy(
 	fmt.Println("Generated client order id: " + *clientOrderId)
 
 	orderResult, err := s.api.NewOrder(
+		ctx,
 		model.OrderParams{
 			Symbol:           "DOLZUSDT",
 			Side:             order.BUY,
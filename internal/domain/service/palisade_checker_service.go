@@ -3,10 +3,8 @@ package service
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
-	"github.com/drybin/palisade/internal/adapter/webapi"
 	"github.com/drybin/palisade/internal/domain/enum"
 	"github.com/drybin/palisade/internal/domain/model"
 	"github.com/drybin/palisade/internal/domain/model/mexc"
@@ -15,12 +13,12 @@ import (
 )
 
 type PalisadeCheckerService struct {
-	mexcRepo  *webapi.MexcWebapi
+	mexcRepo  repo.IMexcRepository
 	stateRepo repo.IStateRepository
 }
 
 func NewPalisadeCheckerService(
-	mexcRepo *webapi.MexcWebapi,
+	mexcRepo repo.IMexcRepository,
 	stateRepo repo.IStateRepository,
 ) *PalisadeCheckerService {
 	return &PalisadeCheckerService{
@@ -83,8 +81,7 @@ func (s *PalisadeCheckerService) CheckAndUpdateCoin(ctx context.Context, params
 		enum.MINUTES_15,
 	)
 	if err != nil {
-		// Проверяем на Invalid symbol
-		if strings.Contains(err.Error(), "Invalid symbol.") {
+		if mexc.IsInvalidSymbol(err) {
 			result.Skipped = true
 			result.SkipReason = "Invalid symbol"
 			result.Error = err
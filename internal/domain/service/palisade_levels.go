@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -8,25 +9,27 @@ import (
 	"github.com/drybin/palisade/internal/domain/enum"
 	"github.com/drybin/palisade/internal/domain/helpers"
 	"github.com/drybin/palisade/internal/domain/model"
+	"github.com/drybin/palisade/internal/domain/repo"
 	"github.com/drybin/palisade/pkg/wrap"
 )
 
 type PalisadeLevels struct {
-	api   *webapi.MexcWebapi
+	api   repo.IMexcRepository
 	apiV2 *webapi.MexcV2Webapi
 }
 
-func NewPalisadeLevels(api *webapi.MexcWebapi, apiV2 *webapi.MexcV2Webapi) *PalisadeLevels {
+func NewPalisadeLevels(api repo.IMexcRepository, apiV2 *webapi.MexcV2Webapi) *PalisadeLevels {
 	return &PalisadeLevels{api: api, apiV2: apiV2}
 }
 
 func (s *PalisadeLevels) CheckLevels(
+	ctx context.Context,
 	pair model.PairWithLevels,
 	interval enum.KlineIntervalV2,
 ) (*model.ArrayStats, error) {
 	// fmt.Println("Check Levels")
 
-	res, err := s.apiV2.GetKLines(pair, interval.String(), pair.Pair.CoinFirst.SymbolId)
+	res, err := s.apiV2.GetKLines(ctx, pair, interval.String(), pair.Pair.CoinFirst.SymbolId)
 	if err != nil {
 		return nil, wrap.Errorf("failed to get klines: %w", err)
 	}
@@ -43,8 +46,8 @@ func (s *PalisadeLevels) CheckLevels(
 	return &levels, nil
 }
 
-func (s *PalisadeLevels) CheckPriceInLevels(pair model.PairWithLevels, levels *model.ArrayStats) (bool, error) {
-	currentPrice, err := s.apiV2.GetPrice(pair.Pair.CoinFirst.CoinId, pair.Pair.CoinFirst.SymbolId)
+func (s *PalisadeLevels) CheckPriceInLevels(ctx context.Context, pair model.PairWithLevels, levels *model.ArrayStats) (bool, error) {
+	currentPrice, err := s.apiV2.GetPrice(ctx, pair.Pair.CoinFirst.CoinId, pair.Pair.CoinFirst.SymbolId)
 	if err != nil {
 		return false, wrap.Errorf("failed to get price: %w", err)
 	}
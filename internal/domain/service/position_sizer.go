@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+// SizingMethod — способ расчёта объёма входа в USDT.
+type SizingMethod string
+
+const (
+	// SizingFixed — всегда QuoteUSDT.
+	SizingFixed SizingMethod = "fixed"
+	// SizingPercent — PercentOfFree процентов свободного USDT.
+	SizingPercent SizingMethod = "percent"
+	// SizingVolatility — QuoteUSDT * TargetVolatility / волатильность флета:
+	// чем шире ходит цена, тем меньше позиция.
+	SizingVolatility SizingMethod = "volatility"
+	// SizingKelly — дробный критерий Келли по закрытым paper_trade.
+	SizingKelly SizingMethod = "kelly"
+)
+
+// ErrPositionTooSmall — объём не проходит минимум биржи или стратегия не
+// даёт преимущества; вход пропускается.
+var ErrPositionTooSmall = errors.New("position too small")
+
+// SizingRule — настройки объёма входа одной стратегии.
+type SizingRule struct {
+	Method SizingMethod
+	// QuoteUSDT — объём для fixed и базовый для volatility; для kelly — пока
+	// в истории меньше KellyMinTrades сделок.
+	QuoteUSDT        float64
+	PercentOfFree    float64
+	TargetVolatility float64
+	KellyFraction    float64
+	KellyMinTrades   int
+	// MaxQuoteUSDT — верхняя граница для любого метода; 0 — без границы.
+	MaxQuoteUSDT float64
+}
+
+type SizingRequest struct {
+	Symbol mexc.SymbolDetail
+	Price  float64
+	// LotStep — шаг количества символа (LOT_SIZE / baseSizePrecision).
+	LotStep  float64
+	FreeUSDT float64
+	// Volatility — волатильность флета в процентах (FlatAnalysisResult.Volatility).
+	Volatility float64
+	// StrategyVersion — версия paper_trade, по которой считается kelly. 0 —
+	// вход вне реестра стратегий (process, process_multi): бумажной истории
+	// у него нет, и kelly откатывается к fixed.
+	StrategyVersion int
+}
+
+type PositionSize struct {
+	Quantity  float64
+	QuoteUSDT float64
+	// Method — фактически применённый метод: без данных volatility и kelly
+	// откатываются к fixed.
+	Method SizingMethod
+}
+
+// PositionSizer считает объём входа по SizingRule и приводит его к
+// ограничениям символа из exchangeInfo: шаг количества, минимальный
+// (quoteAmountPrecision, LOT_SIZE.minQty) и максимальный (maxQuoteAmount)
+// объём. Объём никогда не превышает свободный USDT.
+type PositionSizer struct {
+	stateRepo repo.IStateRepository
+	rule      SizingRule
+}
+
+func NewPositionSizer(stateRepo repo.IStateRepository, rule SizingRule) *PositionSizer {
+	return &PositionSizer{stateRepo: stateRepo, rule: rule}
+}
+
+func (s *PositionSizer) Size(ctx context.Context, req SizingRequest) (PositionSize, error) {
+	if req.Price <= 0 || req.LotStep <= 0 {
+		return PositionSize{}, wrap.Errorf("sizing %s: price %.12f and lot step %.12f must be positive", req.Symbol.Symbol, req.Price, req.LotStep)
+	}
+	quote, method, err := s.targetQuote(ctx, req)
+	if err != nil {
+		return PositionSize{}, err
+	}
+
+	upper := req.FreeUSDT
+	if s.rule.MaxQuoteUSDT > 0 {
+		upper = math.Min(upper, s.rule.MaxQuoteUSDT)
+	}
+	if maxQuote := parseSizingFloat(req.Symbol.MaxQuoteAmount); maxQuote > 0 {
+		upper = math.Min(upper, maxQuote)
+	}
+	quote = math.Min(quote, upper)
+
+	quantity := floorToStep(quote/req.Price, req.LotStep)
+	if minQuote := parseSizingFloat(req.Symbol.QuoteAmountPrecision); minQuote > 0 && quantity*req.Price < minQuote {
+		quantity = ceilToStep(minQuote/req.Price, req.LotStep)
+	}
+	if minQty := symbolMinQty(req.Symbol); minQty > 0 && quantity < minQty {
+		quantity = ceilToStep(minQty, req.LotStep)
+	}
+	if quantity <= 0 || quantity*req.Price > upper*(1+1e-9) {
+		return PositionSize{}, wrap.Errorf("%w: %s %s %.2f USDT, exchange minimum does not fit into %.2f USDT",
+			ErrPositionTooSmall, req.Symbol.Symbol, method, quote, upper)
+	}
+	return PositionSize{Quantity: quantity, QuoteUSDT: quantity * req.Price, Method: method}, nil
+}
+
+func (s *PositionSizer) targetQuote(ctx context.Context, req SizingRequest) (float64, SizingMethod, error) {
+	switch s.rule.Method {
+	case SizingPercent:
+		return req.FreeUSDT * s.rule.PercentOfFree / 100, SizingPercent, nil
+	case SizingVolatility:
+		if req.Volatility <= 0 || s.rule.TargetVolatility <= 0 {
+			return s.rule.QuoteUSDT, SizingFixed, nil
+		}
+		return s.rule.QuoteUSDT * s.rule.TargetVolatility / req.Volatility, SizingVolatility, nil
+	case SizingKelly:
+		if req.StrategyVersion <= 0 {
+			return s.rule.QuoteUSDT, SizingFixed, nil
+		}
+		stats, err := s.stateRepo.GetPaperTradeStats(ctx, req.StrategyVersion)
+		if err != nil {
+			return 0, "", wrap.Errorf("sizing kelly: %w", err)
+		}
+		if stats.Wins+stats.Losses < s.rule.KellyMinTrades || stats.AverageWin <= 0 || stats.AverageLoss <= 0 {
+			return s.rule.QuoteUSDT, SizingFixed, nil
+		}
+		fraction := kellyFraction(stats) * s.rule.KellyFraction
+		if fraction <= 0 {
+			return 0, "", wrap.Errorf("%w: kelly fraction %.4f, win rate %d/%d", ErrPositionTooSmall,
+				fraction, stats.Wins, stats.Wins+stats.Losses)
+		}
+		return req.FreeUSDT * fraction, SizingKelly, nil
+	case SizingFixed, "":
+		return s.rule.QuoteUSDT, SizingFixed, nil
+	}
+	return 0, "", wrap.Errorf("unknown sizing method %q", s.rule.Method)
+}
+
+// kellyFraction — f* = p - (1-p)/b, где p — доля прибыльных сделок, b —
+// отношение среднего выигрыша к среднему проигрышу.
+func kellyFraction(stats repo.PaperTradeStats) float64 {
+	p := float64(stats.Wins) / float64(stats.Wins+stats.Losses)
+	b := stats.AverageWin / stats.AverageLoss
+	return p - (1-p)/b
+}
+
+func symbolMinQty(symbol mexc.SymbolDetail) float64 {
+	for _, filter := range symbol.Filters {
+		if filter.FilterType == "LOT_SIZE" {
+			return parseSizingFloat(filter.MinQty)
+		}
+	}
+	return 0
+}
+
+func parseSizingFloat(value string) float64 {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || parsed <= 0 {
+		return 0
+	}
+	return parsed
+}
+
+func floorToStep(value, step float64) float64 {
+	return math.Floor(value/step+1e-9) * step
+}
+
+func ceilToStep(value, step float64) float64 {
+	return math.Ceil(value/step-1e-9) * step
+}
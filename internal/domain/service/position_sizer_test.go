@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+type sizingStateFake struct {
+	repo.IStateRepository
+	stats   repo.PaperTradeStats
+	version int
+}
+
+func (s *sizingStateFake) GetPaperTradeStats(_ context.Context, version int) (repo.PaperTradeStats, error) {
+	s.version = version
+	return s.stats, nil
+}
+
+func sizingSymbol() mexc.SymbolDetail {
+	return mexc.SymbolDetail{
+		Symbol:               "AAAUSDT",
+		QuoteAmountPrecision: "5",
+		MaxQuoteAmount:       "50",
+		Filters:              []mexc.SymbolFilter{{FilterType: "LOT_SIZE", StepSize: "0.1", MinQty: "1"}},
+	}
+}
+
+func TestPositionSizer_methods(t *testing.T) {
+	cases := []struct {
+		name       string
+		rule       SizingRule
+		free       float64
+		volatility float64
+		wantQty    float64
+		wantMethod SizingMethod
+	}{
+		{name: "fixed", rule: SizingRule{Method: SizingFixed, QuoteUSDT: 10}, free: 100, wantQty: 5, wantMethod: SizingFixed},
+		{name: "fixed clamped by free", rule: SizingRule{Method: SizingFixed, QuoteUSDT: 10}, free: 7.33, wantQty: 3.6, wantMethod: SizingFixed},
+		{name: "fixed clamped by maxQuoteAmount", rule: SizingRule{Method: SizingFixed, QuoteUSDT: 80}, free: 100, wantQty: 25, wantMethod: SizingFixed},
+		{name: "fixed clamped by rule max", rule: SizingRule{Method: SizingFixed, QuoteUSDT: 40, MaxQuoteUSDT: 20}, free: 100, wantQty: 10, wantMethod: SizingFixed},
+		{name: "raised to minNotional", rule: SizingRule{Method: SizingFixed, QuoteUSDT: 1}, free: 100, wantQty: 2.5, wantMethod: SizingFixed},
+		{name: "percent", rule: SizingRule{Method: SizingPercent, PercentOfFree: 10}, free: 200, wantQty: 10, wantMethod: SizingPercent},
+		{name: "volatility", rule: SizingRule{Method: SizingVolatility, QuoteUSDT: 10, TargetVolatility: 2}, free: 100, volatility: 4, wantQty: 2.5, wantMethod: SizingVolatility},
+		{name: "volatility without data", rule: SizingRule{Method: SizingVolatility, QuoteUSDT: 10, TargetVolatility: 2}, free: 100, wantQty: 5, wantMethod: SizingFixed},
+	}
+	for _, tc := range cases {
+		sizer := NewPositionSizer(nil, tc.rule)
+		size, err := sizer.Size(context.Background(), SizingRequest{
+			Symbol: sizingSymbol(), Price: 2, LotStep: 0.1, FreeUSDT: tc.free, Volatility: tc.volatility,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if math.Abs(size.Quantity-tc.wantQty) > 1e-9 || size.Method != tc.wantMethod {
+			t.Fatalf("%s: expected %.8f via %s, got %+v", tc.name, tc.wantQty, tc.wantMethod, size)
+		}
+	}
+}
+
+func TestPositionSizer_minimumDoesNotFit(t *testing.T) {
+	sizer := NewPositionSizer(nil, SizingRule{Method: SizingFixed, QuoteUSDT: 10})
+	_, err := sizer.Size(context.Background(), SizingRequest{Symbol: sizingSymbol(), Price: 2, LotStep: 0.1, FreeUSDT: 4})
+	if !errors.Is(err, ErrPositionTooSmall) {
+		t.Fatalf("expected ErrPositionTooSmall, got %v", err)
+	}
+}
+
+func TestPositionSizer_kelly(t *testing.T) {
+	// p = 0.6, b = 2/1: f* = 0.6 - 0.4/2 = 0.4, четверть Келли — 10% свободного USDT.
+	state := &sizingStateFake{stats: repo.PaperTradeStats{Wins: 30, Losses: 20, AverageWin: 2, AverageLoss: 1}}
+	sizer := NewPositionSizer(state, SizingRule{Method: SizingKelly, QuoteUSDT: 6, KellyFraction: 0.25, KellyMinTrades: 30})
+	req := SizingRequest{Symbol: sizingSymbol(), Price: 2, LotStep: 0.1, FreeUSDT: 200, StrategyVersion: 8}
+
+	size, err := sizer.Size(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(size.QuoteUSDT-20) > 1e-9 || size.Method != SizingKelly || state.version != 8 {
+		t.Fatalf("expected 20 USDT by kelly for version 8, got %+v (version %d)", size, state.version)
+	}
+
+	state.stats = repo.PaperTradeStats{Wins: 5, Losses: 5, AverageWin: 2, AverageLoss: 1}
+	size, err = sizer.Size(context.Background(), req)
+	if err != nil || size.Method != SizingFixed || math.Abs(size.QuoteUSDT-6) > 1e-9 {
+		t.Fatalf("short history must fall back to fixed, got %+v %v", size, err)
+	}
+
+	state.stats = repo.PaperTradeStats{Wins: 10, Losses: 30, AverageWin: 1, AverageLoss: 1}
+	if _, err := sizer.Size(context.Background(), req); !errors.Is(err, ErrPositionTooSmall) {
+		t.Fatalf("negative edge must skip the entry, got %v", err)
+	}
+
+	// Вход вне реестра стратегий не берёт чужую бумажную статистику.
+	state.version = -1
+	req.StrategyVersion = 0
+	size, err = sizer.Size(context.Background(), req)
+	if err != nil || size.Method != SizingFixed || state.version != -1 {
+		t.Fatalf("entry without strategy version must size as fixed, got %+v %v (version %d)", size, err, state.version)
+	}
+}
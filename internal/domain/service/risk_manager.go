@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drybin/palisade/internal/adapter/webapi"
+	"github.com/drybin/palisade/internal/domain/repo"
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+const riskAlertInterval = time.Hour
+
+// riskDayZone — граница торгового дня для дневного лимита убытка; совпадает
+// с часовым поясом сессии Postgres (GMT+7).
+var riskDayZone = time.FixedZone("GMT+7", 7*60*60)
+
+// ErrRiskLimit — вход в позицию запрещён риск-лимитом.
+var ErrRiskLimit = errors.New("risk limit")
+
+// RiskLimits — портфельные лимиты; нулевое значение отключает лимит.
+type RiskLimits struct {
+	MaxOpenPositions      int
+	MaxSymbolExposureUSDT float64
+	MaxTotalExposureUSDT  float64
+	MaxDailyLossUSDT      float64
+	MaxConsecutiveLosses  int
+	LossCooldown          time.Duration
+}
+
+// RiskManager проверяет новый вход по всем стратегиям сразу: открытые
+// позиции берутся из trade_log, trade_log_manual, активных сеток
+// palisade_grid и запаса swap_inventory, а дневной PnL и серия убытков —
+// только из trade_log и trade_log_manual: у сеток и свопов нет истории
+// закрытых сделок по дням. Проверка
+// вызывается только перед ордером, открывающим позицию; выходы (SELL,
+// аварийные продажи, шаги 2–3 свопа) не блокируются никогда.
+//
+// Блокировка сопровождается сообщением в Telegram — не чаще раза в час на
+// одну причину, отметка хранится в risk_alert, чтобы не спамить из cron.
+type RiskManager struct {
+	stateRepo repo.IStateRepository
+	telegram  *webapi.TelegramWebapi
+	limits    RiskLimits
+	now       func() time.Time
+}
+
+func NewRiskManager(stateRepo repo.IStateRepository, telegram *webapi.TelegramWebapi, limits RiskLimits) *RiskManager {
+	return &RiskManager{stateRepo: stateRepo, telegram: telegram, limits: limits, now: time.Now}
+}
+
+// riskBlock — сработавший лимит: key различает события для дедупликации
+// алертов, detail — текст для лога и Telegram.
+type riskBlock struct {
+	key    string
+	detail string
+}
+
+// CheckEntry возвращает ошибку с ErrRiskLimit, если вход в symbol на
+// quoteUSDT нарушает лимиты. nil-RiskManager ничего не ограничивает.
+func (m *RiskManager) CheckEntry(ctx context.Context, symbol string, quoteUSDT float64) error {
+	if m == nil {
+		return nil
+	}
+	block, err := m.evaluate(ctx, symbol, quoteUSDT)
+	if err != nil {
+		return wrap.Errorf("risk check %s: %w", symbol, err)
+	}
+	if block == nil {
+		return nil
+	}
+	m.alert(ctx, symbol, *block)
+	return wrap.Errorf("%w: %s", ErrRiskLimit, block.detail)
+}
+
+func (m *RiskManager) evaluate(ctx context.Context, symbol string, quoteUSDT float64) (*riskBlock, error) {
+	now := m.now()
+	if m.limits.MaxDailyLossUSDT > 0 {
+		local := now.In(riskDayZone)
+		dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, riskDayZone)
+		trades, err := m.stateRepo.ListClosedTradesSince(ctx, dayStart)
+		if err != nil {
+			return nil, err
+		}
+		pnl := 0.0
+		for _, trade := range trades {
+			pnl += trade.PnL()
+		}
+		if -pnl >= m.limits.MaxDailyLossUSDT {
+			return &riskBlock{
+				key:    "daily_loss:" + dayStart.Format(time.DateOnly),
+				detail: fmt.Sprintf("daily realized loss %.2f USDT reached limit %.2f USDT", -pnl, m.limits.MaxDailyLossUSDT),
+			}, nil
+		}
+	}
+
+	if m.limits.MaxConsecutiveLosses > 0 && m.limits.LossCooldown > 0 {
+		recent, err := m.stateRepo.ListRecentClosedTrades(ctx, m.limits.MaxConsecutiveLosses)
+		if err != nil {
+			return nil, err
+		}
+		if block := lossCooldownBlock(recent, m.limits, now); block != nil {
+			return block, nil
+		}
+	}
+
+	if m.limits.MaxOpenPositions <= 0 && m.limits.MaxSymbolExposureUSDT <= 0 && m.limits.MaxTotalExposureUSDT <= 0 {
+		return nil, nil
+	}
+	open, err := m.stateRepo.GetOpenOrders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manual, err := m.stateRepo.GetOpenOrdersManual(ctx)
+	if err != nil {
+		return nil, err
+	}
+	positions := tradePositions(append(open, manual...))
+	bot, err := m.botPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return exposureBlock(append(positions, bot...), m.limits, symbol, quoteUSDT), nil
+}
+
+// riskPosition — открытая позиция и её объём в USDT по цене покупки.
+type riskPosition struct {
+	symbol   string
+	exposure float64
+}
+
+func tradePositions(trades []repo.TradeLog) []riskPosition {
+	out := make([]riskPosition, 0, len(trades))
+	for _, trade := range trades {
+		out = append(out, riskPosition{symbol: trade.Symbol, exposure: trade.BuyPrice * trade.Amount})
+	}
+	return out
+}
+
+// botPositions — позиции, которых нет в trade_log: каждая активная сетка —
+// одна позиция на купленное уровнями и стоящие BUY, каждый актив запаса
+// swap-process — позиция на стоимость купленного ребалансом.
+func (m *RiskManager) botPositions(ctx context.Context) ([]riskPosition, error) {
+	grids, err := m.stateRepo.ListActiveGrids(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []riskPosition
+	for _, grid := range grids {
+		levels, err := m.stateRepo.ListGridLevels(ctx, grid.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exposure := gridExposure(levels); exposure > 0 {
+			out = append(out, riskPosition{symbol: grid.Symbol, exposure: exposure})
+		}
+	}
+	stock, err := m.stateRepo.ListSwapInventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range stock {
+		if item.Quantity > 0 && item.Cost > 0 {
+			out = append(out, riskPosition{symbol: item.Asset + "USDT", exposure: item.Cost})
+		}
+	}
+	return out, nil
+}
+
+// gridExposure — USDT сетки: стоимость купленного уровнями и ещё не
+// проданного плюс BUY, которые стоят или выставляются.
+func gridExposure(levels []repo.GridLevel) float64 {
+	exposure := 0.0
+	for _, level := range levels {
+		exposure += level.HeldCost
+		if level.Status == repo.GridLevelBuyOpen || level.Status == repo.GridLevelBuyPlacing {
+			exposure += level.Quantity * level.BuyPrice
+		}
+	}
+	return exposure
+}
+
+// lossCooldownBlock срабатывает, если последние MaxConsecutiveLosses сделок
+// убыточны и с последней из них не прошло LossCooldown. recent — по
+// убыванию даты закрытия.
+func lossCooldownBlock(recent []repo.ClosedTrade, limits RiskLimits, now time.Time) *riskBlock {
+	if len(recent) < limits.MaxConsecutiveLosses {
+		return nil
+	}
+	for _, trade := range recent[:limits.MaxConsecutiveLosses] {
+		if trade.PnL() >= 0 {
+			return nil
+		}
+	}
+	last := recent[0]
+	until := last.CloseDate.Add(limits.LossCooldown)
+	if !now.Before(until) {
+		return nil
+	}
+	return &riskBlock{
+		key: fmt.Sprintf("loss_cooldown:%s:%d", last.Source, last.ID),
+		detail: fmt.Sprintf("%d consecutive losing trades, cooldown until %s",
+			limits.MaxConsecutiveLosses, until.In(riskDayZone).Format("2006-01-02 15:04")),
+	}
+}
+
+// exposureBlock проверяет число открытых позиций и объём в USDT (по цене
+// покупки) с учётом нового входа.
+func exposureBlock(open []riskPosition, limits RiskLimits, symbol string, quoteUSDT float64) *riskBlock {
+	if limits.MaxOpenPositions > 0 && len(open) >= limits.MaxOpenPositions {
+		return &riskBlock{
+			key:    "max_positions",
+			detail: fmt.Sprintf("%d open positions reached limit %d", len(open), limits.MaxOpenPositions),
+		}
+	}
+	symbolExposure, totalExposure := 0.0, 0.0
+	for _, position := range open {
+		totalExposure += position.exposure
+		if position.symbol == symbol {
+			symbolExposure += position.exposure
+		}
+	}
+	if limits.MaxSymbolExposureUSDT > 0 && symbolExposure+quoteUSDT > limits.MaxSymbolExposureUSDT {
+		return &riskBlock{
+			key: "symbol_exposure:" + symbol,
+			detail: fmt.Sprintf("%s exposure %.2f + %.2f USDT exceeds limit %.2f USDT",
+				symbol, symbolExposure, quoteUSDT, limits.MaxSymbolExposureUSDT),
+		}
+	}
+	if limits.MaxTotalExposureUSDT > 0 && totalExposure+quoteUSDT > limits.MaxTotalExposureUSDT {
+		return &riskBlock{
+			key: "total_exposure",
+			detail: fmt.Sprintf("total exposure %.2f + %.2f USDT exceeds limit %.2f USDT",
+				totalExposure, quoteUSDT, limits.MaxTotalExposureUSDT),
+		}
+	}
+	return nil
+}
+
+func (m *RiskManager) alert(ctx context.Context, symbol string, block riskBlock) {
+	if m.telegram == nil || !m.telegram.Configured() {
+		return
+	}
+	now := m.now()
+	sentAt, err := m.stateRepo.GetRiskAlertSentAt(ctx, block.key)
+	if err != nil {
+		fmt.Printf("Риск-алерт: %v\n", err)
+		return
+	}
+	if sentAt != nil && now.Sub(*sentAt) < riskAlertInterval {
+		return
+	}
+	if _, err := m.telegram.Send(fmt.Sprintf("<b>🛑 Risk limit</b> %s · %s", symbol, block.detail)); err != nil {
+		fmt.Printf("Telegram: %v\n", err)
+		return
+	}
+	if err := m.stateRepo.SaveRiskAlert(ctx, block.key, now); err != nil {
+		fmt.Printf("Риск-алерт: %v\n", err)
+	}
+}
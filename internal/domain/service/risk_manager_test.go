@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+type riskStateFake struct {
+	repo.IStateRepository
+	open   []repo.TradeLog
+	manual []repo.TradeLog
+	closed []repo.ClosedTrade
+	since  time.Time
+	grids  []repo.Grid
+	levels map[int][]repo.GridLevel
+	stock  []repo.SwapInventory
+}
+
+func (s *riskStateFake) ListActiveGrids(context.Context) ([]repo.Grid, error) {
+	return s.grids, nil
+}
+
+func (s *riskStateFake) ListGridLevels(_ context.Context, gridID int) ([]repo.GridLevel, error) {
+	return s.levels[gridID], nil
+}
+
+func (s *riskStateFake) ListSwapInventory(context.Context) ([]repo.SwapInventory, error) {
+	return s.stock, nil
+}
+
+func (s *riskStateFake) GetOpenOrders(context.Context) ([]repo.TradeLog, error) {
+	return s.open, nil
+}
+
+func (s *riskStateFake) GetOpenOrdersManual(context.Context) ([]repo.TradeLog, error) {
+	return s.manual, nil
+}
+
+func (s *riskStateFake) ListClosedTradesSince(_ context.Context, since time.Time) ([]repo.ClosedTrade, error) {
+	s.since = since
+	var out []repo.ClosedTrade
+	for _, trade := range s.closed {
+		if !trade.CloseDate.Before(since) {
+			out = append(out, trade)
+		}
+	}
+	return out, nil
+}
+
+// closed хранится по убыванию даты закрытия, как отдаёт Postgres.
+func (s *riskStateFake) ListRecentClosedTrades(_ context.Context, limit int) ([]repo.ClosedTrade, error) {
+	return s.closed[:min(limit, len(s.closed))], nil
+}
+
+func newTestRiskManager(state *riskStateFake, limits RiskLimits, now time.Time) *RiskManager {
+	m := NewRiskManager(state, nil, limits)
+	m.now = func() time.Time { return now }
+	return m
+}
+
+func TestRiskManager_nilAllowsEverything(t *testing.T) {
+	var m *RiskManager
+	if err := m.CheckEntry(context.Background(), "AAAUSDT", 1e9); err != nil {
+		t.Fatalf("nil manager must not block: %v", err)
+	}
+}
+
+func TestRiskManager_dailyLoss(t *testing.T) {
+	now := time.Date(2026, 3, 10, 5, 0, 0, 0, riskDayZone)
+	state := &riskStateFake{closed: []repo.ClosedTrade{
+		{Source: "trade_log", ID: 3, BuyPrice: 1, SellPrice: 0.9, Amount: 30, CloseDate: now.Add(-time.Hour)},
+		{Source: "trade_log_manual", ID: 7, BuyPrice: 2, SellPrice: 1.9, Amount: 20, CloseDate: now.Add(-2 * time.Hour)},
+		// Вчерашний убыток в дневной лимит не входит.
+		{Source: "trade_log", ID: 1, BuyPrice: 1, SellPrice: 0.5, Amount: 100, CloseDate: now.Add(-6 * time.Hour)},
+	}}
+	m := newTestRiskManager(state, RiskLimits{MaxDailyLossUSDT: 5}, now)
+
+	err := m.CheckEntry(context.Background(), "AAAUSDT", 10)
+	if !errors.Is(err, ErrRiskLimit) || !strings.Contains(err.Error(), "daily realized loss 5.00") {
+		t.Fatalf("expected daily loss block, got %v", err)
+	}
+	if want := time.Date(2026, 3, 10, 0, 0, 0, 0, riskDayZone); !state.since.Equal(want) {
+		t.Fatalf("day must start at local midnight, got %s", state.since)
+	}
+
+	m.limits.MaxDailyLossUSDT = 5.01
+	if err := m.CheckEntry(context.Background(), "AAAUSDT", 10); err != nil {
+		t.Fatalf("loss below limit must pass: %v", err)
+	}
+}
+
+func TestLossCooldownBlock(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	limits := RiskLimits{MaxConsecutiveLosses: 2, LossCooldown: 2 * time.Hour}
+	loss := func(id int, closedAgo time.Duration) repo.ClosedTrade {
+		return repo.ClosedTrade{Source: "trade_log", ID: id, BuyPrice: 1, SellPrice: 0.99, Amount: 10, CloseDate: now.Add(-closedAgo)}
+	}
+	win := repo.ClosedTrade{Source: "trade_log", ID: 9, BuyPrice: 1, SellPrice: 1.01, Amount: 10, CloseDate: now.Add(-time.Hour)}
+
+	block := lossCooldownBlock([]repo.ClosedTrade{loss(5, time.Hour), loss(4, 3*time.Hour)}, limits, now)
+	if block == nil || block.key != "loss_cooldown:trade_log:5" {
+		t.Fatalf("expected cooldown after two losses, got %+v", block)
+	}
+	if block := lossCooldownBlock([]repo.ClosedTrade{loss(5, 3*time.Hour), loss(4, 4*time.Hour)}, limits, now); block != nil {
+		t.Fatalf("cooldown must expire, got %+v", block)
+	}
+	if block := lossCooldownBlock([]repo.ClosedTrade{win, loss(4, 3*time.Hour)}, limits, now); block != nil {
+		t.Fatalf("a win must reset the streak, got %+v", block)
+	}
+	if block := lossCooldownBlock([]repo.ClosedTrade{loss(5, time.Hour)}, limits, now); block != nil {
+		t.Fatalf("short history must not block, got %+v", block)
+	}
+}
+
+func TestExposureBlock(t *testing.T) {
+	open := []repo.TradeLog{
+		{Symbol: "AAAUSDT", BuyPrice: 1, Amount: 20},
+		{Symbol: "BBBUSDT", BuyPrice: 2, Amount: 15},
+	}
+	cases := []struct {
+		name    string
+		limits  RiskLimits
+		symbol  string
+		quote   float64
+		wantKey string
+	}{
+		{name: "positions", limits: RiskLimits{MaxOpenPositions: 2}, symbol: "CCCUSDT", quote: 1, wantKey: "max_positions"},
+		{name: "symbol", limits: RiskLimits{MaxSymbolExposureUSDT: 25}, symbol: "AAAUSDT", quote: 6, wantKey: "symbol_exposure:AAAUSDT"},
+		{name: "symbol other", limits: RiskLimits{MaxSymbolExposureUSDT: 25}, symbol: "CCCUSDT", quote: 6},
+		{name: "total", limits: RiskLimits{MaxTotalExposureUSDT: 60}, symbol: "CCCUSDT", quote: 11, wantKey: "total_exposure"},
+		{name: "total fits", limits: RiskLimits{MaxTotalExposureUSDT: 60}, symbol: "CCCUSDT", quote: 10},
+	}
+	for _, tc := range cases {
+		block := exposureBlock(tradePositions(open), tc.limits, tc.symbol, tc.quote)
+		gotKey := ""
+		if block != nil {
+			gotKey = block.key
+		}
+		if gotKey != tc.wantKey {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.wantKey, gotKey)
+		}
+	}
+}
+
+func TestRiskManager_countsManualPositions(t *testing.T) {
+	state := &riskStateFake{
+		open:   []repo.TradeLog{{Symbol: "AAAUSDT", BuyPrice: 1, Amount: 10}},
+		manual: []repo.TradeLog{{Symbol: "BBBUSDT", BuyPrice: 1, Amount: 10}},
+	}
+	m := newTestRiskManager(state, RiskLimits{MaxOpenPositions: 2}, time.Now())
+	if err := m.CheckEntry(context.Background(), "CCCUSDT", 5); !errors.Is(err, ErrRiskLimit) {
+		t.Fatalf("manual positions must count towards the limit, got %v", err)
+	}
+}
+
+func TestRiskManager_countsGridAndSwapInventory(t *testing.T) {
+	state := &riskStateFake{
+		grids: []repo.Grid{{ID: 1, Symbol: "AAAUSDT"}},
+		levels: map[int][]repo.GridLevel{1: {
+			{Level: 0, Status: repo.GridLevelSellOpen, HeldCost: 10},
+			{Level: 1, Status: repo.GridLevelBuyOpen, BuyPrice: 1, Quantity: 5},
+			{Level: 2, Status: repo.GridLevelWaiting, BuyPrice: 1.1, Quantity: 5},
+		}},
+		stock: []repo.SwapInventory{{Asset: "BTC", Quantity: 0.0002, Cost: 20}},
+	}
+	// Сетка держит 10 USDT и ставит BUY на 5, запас BTC — 20 USDT.
+	m := newTestRiskManager(state, RiskLimits{MaxTotalExposureUSDT: 40}, time.Now())
+	if err := m.CheckEntry(context.Background(), "CCCUSDT", 6); !errors.Is(err, ErrRiskLimit) {
+		t.Fatalf("grid and swap inventory must count towards total exposure, got %v", err)
+	}
+	if err := m.CheckEntry(context.Background(), "CCCUSDT", 5); err != nil {
+		t.Fatalf("entry within the limit must pass, got %v", err)
+	}
+
+	m = newTestRiskManager(state, RiskLimits{MaxSymbolExposureUSDT: 16}, time.Now())
+	if err := m.CheckEntry(context.Background(), "AAAUSDT", 2); !errors.Is(err, ErrRiskLimit) {
+		t.Fatalf("grid must count towards its symbol exposure, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/drybin/palisade/pkg/wrap"
+)
+
+// ErrUnknownStrategy — в реестре нет стратегии с таким ключом.
+var ErrUnknownStrategy = errors.New("unknown strategy")
+
+// Registry — реализации стратегий по ключу имя/vN.
+type Registry struct {
+	byKey map[string]Strategy
+}
+
+func NewRegistry() *Registry {
+	return &Registry{byKey: map[string]Strategy{}}
+}
+
+// Key — ключ стратегии в реестре и в конфиге, например palisade/v8.
+func Key(name string, version int) string {
+	return fmt.Sprintf("%s/v%d", name, version)
+}
+
+// ParseKey разбирает ключ имя/vN.
+func ParseKey(key string) (name string, version int, err error) {
+	name, raw, ok := strings.Cut(key, "/v")
+	if ok {
+		version, err = strconv.Atoi(raw)
+	}
+	if !ok || err != nil || name == "" || version < 1 {
+		return "", 0, wrap.Errorf("strategy key %q: want name/vN", key)
+	}
+	return name, version, nil
+}
+
+// Register добавляет стратегию; повтор ключа — ошибка.
+func (r *Registry) Register(s Strategy) error {
+	key := Key(s.Name(), s.Version())
+	if _, ok := r.byKey[key]; ok {
+		return wrap.Errorf("strategy %s is already registered", key)
+	}
+	r.byKey[key] = s
+	return nil
+}
+
+// Get возвращает стратегию по ключу имя/vN.
+func (r *Registry) Get(key string) (Strategy, error) {
+	name, version, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return r.Version(name, version)
+}
+
+// Version возвращает версию version стратегии name.
+func (r *Registry) Version(name string, version int) (Strategy, error) {
+	s, ok := r.byKey[Key(name, version)]
+	if !ok {
+		return nil, wrap.Errorf("%w %s", ErrUnknownStrategy, Key(name, version))
+	}
+	return s, nil
+}
+
+// Keys — ключи зарегистрированных стратегий по возрастанию.
+func (r *Registry) Keys() []string {
+	keys := make([]string, 0, len(r.byKey))
+	for key := range r.byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,55 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeStrategy struct {
+	name    string
+	version int
+}
+
+func (s fakeStrategy) Name() string { return s.name }
+func (s fakeStrategy) Version() int { return s.version }
+func (s fakeStrategy) EvaluateEntry(MarketContext) (Entry, bool) {
+	return Entry{}, false
+}
+func (s fakeStrategy) DecideExit(*ExitState, Position, time.Time, float64) ExitDecision {
+	return ExitDecision{}
+}
+
+func TestRegistry_getByKey(t *testing.T) {
+	r := NewRegistry()
+	for _, s := range []Strategy{fakeStrategy{"palisade", 7}, fakeStrategy{"palisade", 8}, fakeStrategy{"grid", 1}} {
+		if err := r.Register(s); err != nil {
+			t.Fatalf("register %s: %v", Key(s.Name(), s.Version()), err)
+		}
+	}
+	got, err := r.Get("palisade/v7")
+	if err != nil || got.Name() != "palisade" || got.Version() != 7 {
+		t.Fatalf("expected palisade/v7, got %v err=%v", got, err)
+	}
+	if keys := r.Keys(); len(keys) != 3 || keys[0] != "grid/v1" || keys[2] != "palisade/v8" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+}
+
+func TestRegistry_rejectsDuplicateAndUnknown(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(fakeStrategy{"palisade", 8}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := r.Register(fakeStrategy{"palisade", 8}); err == nil {
+		t.Fatalf("expected duplicate key error")
+	}
+	if _, err := r.Get("palisade/v9"); !errors.Is(err, ErrUnknownStrategy) {
+		t.Fatalf("expected unknown strategy, got %v", err)
+	}
+	for _, key := range []string{"palisade", "palisade/8", "/v8", "palisade/v0", "palisade/vX"} {
+		if _, err := r.Get(key); err == nil || errors.Is(err, ErrUnknownStrategy) {
+			t.Fatalf("expected malformed key error for %q, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package strategy
+
+import (
+	"time"
+
+	"github.com/drybin/palisade/internal/domain/model/mexc"
+	"github.com/drybin/palisade/internal/domain/repo"
+)
+
+// Причины выхода, которые продают по цене, а не по стопу.
+const (
+	ExitReasonTarget  = "TARGET_REACHED"
+	ExitReasonPartial = "PARTIAL_PROFIT"
+)
+
+// Strategy — правила входа и выхода одной версии стратегии. Одна и та же
+// реализация работает в отборе сигналов, бумажной торговле, живом
+// исполнении и бэктесте, поэтому их результаты сравнимы. Реализация не
+// ходит в сеть и БД: всё нужное передаётся в аргументах.
+type Strategy interface {
+	// Name и Version — ключ стратегии в Registry.
+	Name() string
+	Version() int
+	// EvaluateEntry ищет вход по рынку пары; ok=false — входа нет.
+	EvaluateEntry(market MarketContext) (entry Entry, ok bool)
+	// DecideExit продвигает state на bid и решает, что продавать из
+	// позиции; пустая Reason — держать.
+	DecideExit(state *ExitState, pos Position, now time.Time, bid float64) ExitDecision
+}
+
+// MarketContext — рынок пары на момент оценки: снимок bookTicker и 24h,
+// 15m свечи и, если есть, стакан (nil — глубина не проверяется).
+type MarketContext struct {
+	Snapshot repo.MarketSnapshot
+	Symbol   mexc.SymbolDetail
+	Klines   mexc.Klines
+	Depth    *mexc.OrderBook
+	Now      time.Time
+}
+
+// Entry — найденный вход: цена входа и цель по диапазону support…target.
+type Entry struct {
+	Symbol, BaseAsset                        string
+	Current, EntryPrice, Support, Target     float64
+	MinExitPrice, NetProfit, Spread, Volume  float64
+	TouchesSupport, TouchesResistance, Score int
+}
+
+// ExitState — состояние выхода, которое переносится между тиками: у
+// бумажной сделки оно хранится в paper_trade, у живой — в
+// trade_log_exit_state.
+type ExitState struct {
+	BreakEvenArmed     bool
+	PartialProfitTaken bool
+	MaxBidPrice        float64
+	MinBidPrice        float64
+}
+
+// Position — позиция на текущем тике.
+type Position struct {
+	BuyPrice       float64
+	FilledQuantity float64
+	SoldQuantity   float64
+	SupportPrice   float64
+	TargetPrice    float64
+	OpenedAt       time.Time
+	// PendingReason — причина начатого, но не завершённого выхода.
+	PendingReason string
+	// Fee — ставка комиссии пары, LotStep — шаг количества.
+	Fee     float64
+	LotStep float64
+}
+
+// ExitDecision — продать Quantity не дешевле Limit; пустая Reason —
+// держать позицию. PartialTarget — сколько всего продаётся долей быстрой
+// прибыли.
+type ExitDecision struct {
+	Reason        string
+	Quantity      float64
+	Limit         float64
+	PartialTarget float64
+}
+
+// IsStop — выход по стопу: остаток продаётся по bid со скидкой.
+func (d ExitDecision) IsStop() bool {
+	return d.Reason != "" && d.Reason != ExitReasonTarget && d.Reason != ExitReasonPartial
+}
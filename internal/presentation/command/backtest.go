@@ -0,0 +1,113 @@
+package command
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drybin/palisade/internal/app/cli/usecase"
+	"github.com/drybin/palisade/pkg/wrap"
+	"github.com/urfave/cli/v2"
+)
+
+func NewBacktestCommand(service usecase.IBacktest) *cli.Command {
+	return &cli.Command{
+		Name:  "backtest",
+		Usage: "replay minute bar history through palisade signals and the paper trading engine",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "symbols",
+				Usage:    "comma-separated base assets, e.g. BTC,ETH",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "period start, UTC (YYYY-MM-DD or RFC3339); default --days before --to",
+			},
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "period end, UTC (YYYY-MM-DD or RFC3339); default now",
+			},
+			&cli.IntFlag{
+				Name:  "days",
+				Usage: "period length when --from is not set",
+				Value: 7,
+			},
+			&cli.StringFlag{
+				Name:  "versions",
+				Usage: "comma-separated versions of the configured strategy to compare; default the configured version",
+			},
+			&cli.Float64Flag{
+				Name:  "spread",
+				Usage: "synthetic bid/ask spread around bar prices",
+				Value: 0.001,
+			},
+			&cli.StringFlag{
+				Name:  "source",
+				Usage: "minute bar source: db (market_minute_bar) or api",
+				Value: "db",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "print results without saving backtest_run",
+			},
+			&cli.BoolFlag{Name: "debug"},
+		},
+		Action: func(c *cli.Context) error {
+			opts, err := parseBacktestOpts(c)
+			if err != nil {
+				return err
+			}
+			return service.Process(context.Background(), opts)
+		},
+	}
+}
+
+func parseBacktestOpts(c *cli.Context) (usecase.BacktestOptions, error) {
+	opts := usecase.DefaultBacktestOptions()
+	opts.Symbols = usecase.ParseTrendSymbols(c.String("symbols"))
+	if s := strings.TrimSpace(c.String("to")); s != "" {
+		to, err := parseBacktestTime(s)
+		if err != nil {
+			return opts, err
+		}
+		opts.To = to
+	}
+	opts.From = opts.To.AddDate(0, 0, -c.Int("days"))
+	if s := strings.TrimSpace(c.String("from")); s != "" {
+		from, err := parseBacktestTime(s)
+		if err != nil {
+			return opts, err
+		}
+		opts.From = from
+	}
+	opts.Versions = opts.Versions[:0]
+	for _, raw := range strings.Split(c.String("versions"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		version, err := strconv.Atoi(raw)
+		if err != nil {
+			return opts, wrap.Errorf("invalid strategy version %q", raw)
+		}
+		opts.Versions = append(opts.Versions, version)
+	}
+	opts.Spread = c.Float64("spread")
+	opts.Source = c.String("source")
+	opts.DryRun = c.Bool("dry-run")
+	opts.Debug = c.Bool("debug")
+	return opts, nil
+}
+
+func parseBacktestTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, wrap.Errorf("invalid time %q: expected YYYY-MM-DD or RFC3339", value)
+	}
+	return t.UTC(), nil
+}
@@ -8,18 +8,32 @@ import (
 )
 
 func NewCheckSwapCommand(service usecase.ICheckSwap) *cli.Command {
+	defaults := usecase.DefaultCheckSwapOptions()
 	return &cli.Command{
 		Name:  "check_swap",
 		Usage: "check_swap command",
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "quiet",
-				Usage: "только связки с прибылью > 1%% в формате USDT -> A -> B -> USDT",
+				Usage: "только циклы с прибылью > 1%% в формате USDT -> A -> B -> USDT",
+			},
+			&cli.StringFlag{
+				Name:  "start",
+				Usage: "активы через запятую, из которых ищутся циклы, например USDT,USDC,BTC",
+				Value: "USDT",
+			},
+			&cli.IntFlag{
+				Name:  "max-legs",
+				Usage: "наибольшая длина цикла: 3 или 4 ноги",
+				Value: defaults.MaxLegs,
 			},
 		},
 		Action: func(c *cli.Context) error {
-			quiet := c.Bool("quiet")
-			return service.Process(context.Background(), quiet)
+			opts := defaults
+			opts.Quiet = c.Bool("quiet")
+			opts.Starts = usecase.ParseSwapAssets(c.String("start"))
+			opts.MaxLegs = c.Int("max-legs")
+			return service.Process(context.Background(), opts)
 		},
 	}
 }
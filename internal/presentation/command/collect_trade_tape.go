@@ -0,0 +1,43 @@
+package command
+
+import (
+	"context"
+
+	"github.com/drybin/palisade/internal/app/cli/usecase"
+	"github.com/urfave/cli/v2"
+)
+
+func NewCollectTradeTapeCommand(service usecase.ICollectTradeTape) *cli.Command {
+	defaults := usecase.DefaultCollectTradeTapeOptions()
+	return &cli.Command{
+		Name:  "collect-trade-tape",
+		Usage: "append recent aggregated trades to the trade tape and drop expired ones",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "symbols",
+				Usage: "comma-separated base assets, e.g. BTC,ETH; default top --top USDT symbols by 24h volume",
+			},
+			&cli.IntFlag{
+				Name:  "top",
+				Usage: "number of USDT symbols by 24h quote volume when --symbols is not set",
+				Value: defaults.Top,
+			},
+			&cli.DurationFlag{
+				Name:  "retention",
+				Usage: "delete trades older than this, 0 keeps everything",
+				Value: defaults.Retention,
+			},
+			&cli.BoolFlag{Name: "debug"},
+		},
+		Action: func(c *cli.Context) error {
+			opts := defaults
+			if raw := c.String("symbols"); raw != "" {
+				opts.Symbols = usecase.ParseTrendSymbols(raw)
+			}
+			opts.Top = c.Int("top")
+			opts.Retention = c.Duration("retention")
+			opts.Debug = c.Bool("debug")
+			return service.Process(context.Background(), opts)
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/drybin/palisade/internal/app/cli/usecase"
+	"github.com/drybin/palisade/pkg/wrap"
+	"github.com/urfave/cli/v2"
+)
+
+func NewDaemonCommand(service usecase.IDaemon) *cli.Command {
+	defaults := usecase.DefaultDaemonOptions()
+	return &cli.Command{
+		Name:  "daemon",
+		Usage: "run scheduled jobs in one long-running process instead of run-cron.sh",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "jobs",
+				Usage: "comma-separated job=interval list, e.g. process=1m,process-sell=30s",
+				Value: usecase.DefaultDaemonJobs,
+			},
+			&cli.DurationFlag{
+				Name:  "shutdown-timeout",
+				Usage: "how long to wait for an in-flight job after SIGTERM before canceling it",
+				Value: defaults.ShutdownTimeout,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			jobs, err := usecase.ParseDaemonJobs(c.String("jobs"))
+			if err != nil {
+				return wrap.Errorf("parse --jobs: %w", err)
+			}
+			opts := usecase.DaemonOptions{Jobs: jobs, ShutdownTimeout: c.Duration("shutdown-timeout")}
+
+			ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return service.Process(ctx, opts)
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package command
+
+import (
+	"context"
+
+	"github.com/drybin/palisade/internal/app/cli/usecase"
+	"github.com/urfave/cli/v2"
+)
+
+func NewGridTradeCommand(service usecase.IGridTrade) *cli.Command {
+	return &cli.Command{
+		Name:  "grid-trade",
+		Usage: "run order grids inside palisade ranges; requires --live to place orders",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "symbols",
+				Usage: "comma-separated base assets to start a grid on, e.g. BTC,ETH; without it only running grids are maintained",
+			},
+			&cli.BoolFlag{Name: "live"},
+		},
+		Action: func(c *cli.Context) error {
+			opts := usecase.GridTradeOptions{Live: c.Bool("live")}
+			if raw := c.String("symbols"); raw != "" {
+				opts.Symbols = usecase.ParseTrendSymbols(raw)
+			}
+			return service.Process(context.Background(), opts)
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/drybin/palisade/internal/app/cli/usecase"
+	"github.com/urfave/cli/v2"
+)
+
+func NewStreamMarketDataCommand(service usecase.IStreamMarketData) *cli.Command {
+	defaults := usecase.DefaultStreamMarketDataOptions()
+	return &cli.Command{
+		Name:  "stream-market-data",
+		Usage: "keep market snapshots and minute bars live from the MEXC WebSocket stream",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "symbols",
+				Usage: "comma-separated base assets, e.g. BTC,ETH; default top --top USDT symbols by 24h volume",
+			},
+			&cli.IntFlag{
+				Name:  "top",
+				Usage: "number of USDT symbols by 24h quote volume when --symbols is not set",
+				Value: defaults.Top,
+			},
+			&cli.DurationFlag{
+				Name:  "flush",
+				Usage: "batch write interval",
+				Value: defaults.FlushInterval,
+			},
+			&cli.DurationFlag{
+				Name:  "stats-interval",
+				Usage: "24h ticker refresh interval",
+				Value: defaults.StatsInterval,
+			},
+			&cli.BoolFlag{Name: "debug"},
+		},
+		Action: func(c *cli.Context) error {
+			opts := defaults
+			if raw := c.String("symbols"); raw != "" {
+				opts.Symbols = usecase.ParseTrendSymbols(raw)
+			}
+			opts.Top = c.Int("top")
+			opts.FlushInterval = c.Duration("flush")
+			opts.StatsInterval = c.Duration("stats-interval")
+			opts.Debug = c.Bool("debug")
+
+			ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return service.Process(ctx, opts)
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/drybin/palisade/internal/app/cli/usecase"
+	"github.com/urfave/cli/v2"
+)
+
+func NewSwapMonitorCommand(service usecase.ISwapMonitor) *cli.Command {
+	defaults := usecase.DefaultSwapMonitorOptions()
+	return &cli.Command{
+		Name:  "swap-monitor",
+		Usage: "watch arbitrage cycles without trading and record how long profitable ones persist",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "start",
+				Usage: "comma-separated start assets, e.g. USDT,USDC,BTC",
+				Value: "USDT",
+			},
+			&cli.IntFlag{
+				Name:  "max-legs",
+				Usage: "longest cycle: 3 or 4 legs",
+				Value: defaults.MaxLegs,
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "book ticker polling interval",
+				Value: defaults.Interval,
+			},
+			&cli.Float64Flag{
+				Name:  "threshold",
+				Usage: "profit percent above which a cycle counts as an opportunity",
+				Value: defaults.ThresholdPercent,
+			},
+			&cli.Float64Flag{
+				Name:  "amount",
+				Usage: "start asset amount used to apply lot steps to cycle profit",
+				Value: defaults.Amount,
+			},
+			&cli.DurationFlag{
+				Name:  "duration",
+				Usage: "stop after this long, 0 runs until interrupted",
+			},
+			&cli.DurationFlag{
+				Name:  "report-interval",
+				Usage: "print the lifetime histogram this often, 0 prints it only on exit",
+				Value: defaults.ReportInterval,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			opts := defaults
+			opts.Starts = usecase.ParseSwapAssets(c.String("start"))
+			opts.MaxLegs = c.Int("max-legs")
+			opts.Interval = c.Duration("interval")
+			opts.ThresholdPercent = c.Float64("threshold")
+			opts.Amount = c.Float64("amount")
+			opts.Duration = c.Duration("duration")
+			opts.ReportInterval = c.Duration("report-interval")
+
+			ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return service.Process(ctx, opts)
+		},
+	}
+}
@@ -8,12 +8,57 @@ import (
 )
 
 func NewSwapProcessCommand(service usecase.ISwapProcess) *cli.Command {
+	defaults := usecase.DefaultSwapProcessOptions()
 	return &cli.Command{
 		Name:  "swap-process",
-		Usage: "найти связку USDT->A->B->USDT с прибылью >1% и исполнить лимитными ордерами по очереди",
-		Flags: []cli.Flag{},
+		Usage: "найти цикл обменов из актива на балансе с прибылью >1% и исполнить его лимитными ордерами по очереди, IOC подряд или из запаса одновременно",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "mode",
+				Usage: "limit — ноги по очереди с ожиданием исполнения, ioc — IMMEDIATE_OR_CANCEL подряд без ожидания, inventory — все ноги одновременно из запаса",
+				Value: defaults.Mode,
+			},
+			&cli.StringFlag{
+				Name:  "start",
+				Usage: "активы через запятую, из которых ищутся циклы, например USDT,USDC,BTC; held — все активы на балансе",
+				Value: "USDT",
+			},
+			&cli.Float64Flag{
+				Name:  "amount",
+				Usage: "объём цикла в USDT; для другого стартового актива пересчитывается по его цене",
+				Value: defaults.AmountUSDT,
+			},
+			&cli.Float64Flag{
+				Name:  "slippage",
+				Usage: "ioc и inventory: насколько лимит ноги может быть хуже лучшей цены, %",
+				Value: defaults.SlippagePercent,
+			},
+			&cli.BoolFlag{
+				Name:  "liquidate",
+				Usage: "ioc: продать остатки промежуточных активов в стартовый после неполного цикла",
+				Value: defaults.Liquidate,
+			},
+			&cli.StringFlag{
+				Name:  "inventory",
+				Usage: "inventory: активы запаса через запятую",
+				Value: "BTC,ETH,USDC",
+			},
+			&cli.Float64Flag{
+				Name:  "inventory-usdt",
+				Usage: "inventory: целевой объём запаса каждого актива в USDT",
+				Value: defaults.InventoryUSDT,
+			},
+		},
 		Action: func(c *cli.Context) error {
-			return service.Process(context.Background())
+			opts := defaults
+			opts.Mode = c.String("mode")
+			opts.Starts = usecase.ParseSwapAssets(c.String("start"))
+			opts.AmountUSDT = c.Float64("amount")
+			opts.SlippagePercent = c.Float64("slippage")
+			opts.Liquidate = c.Bool("liquidate")
+			opts.Inventory = usecase.ParseSwapAssets(c.String("inventory"))
+			opts.InventoryUSDT = c.Float64("inventory-usdt")
+			return service.Process(context.Background(), opts)
 		},
 	}
 }
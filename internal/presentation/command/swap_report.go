@@ -0,0 +1,34 @@
+package command
+
+import (
+	"context"
+
+	"github.com/drybin/palisade/internal/app/cli/usecase"
+	"github.com/urfave/cli/v2"
+)
+
+func NewSwapReportCommand(service usecase.ISwapReport) *cli.Command {
+	defaults := usecase.DefaultSwapReportOptions()
+	return &cli.Command{
+		Name:  "swap-report",
+		Usage: "summarize swap-process runs: expected vs realized profit, slippage and unwinds",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "days",
+				Usage: "report runs started within this many days",
+				Value: defaults.Days,
+			},
+			&cli.IntFlag{
+				Name:  "last",
+				Usage: "number of most recent runs to list, 0 lists none",
+				Value: defaults.Last,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			opts := defaults
+			opts.Days = c.Int("days")
+			opts.Last = c.Int("last")
+			return service.Process(context.Background(), opts)
+		},
+	}
+}
@@ -21,8 +21,8 @@ func trendFlags() []cli.Flag {
 		},
 		&cli.IntFlag{
 			Name:  "sleep-ms",
-			Usage: "pause between symbols (API rate limit)",
-			Value: 200,
+			Usage: "extra pause between symbols; MEXC rate limits are handled by the API client",
+			Value: 0,
 		},
 		&cli.IntFlag{
 			Name:  "sma-min",
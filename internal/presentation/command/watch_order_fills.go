@@ -0,0 +1,41 @@
+package command
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/drybin/palisade/internal/app/cli/usecase"
+	"github.com/urfave/cli/v2"
+)
+
+func NewWatchOrderFillsCommand(service usecase.IWatchOrderFills) *cli.Command {
+	defaults := usecase.DefaultWatchOrderFillsOptions()
+	return &cli.Command{
+		Name:  "watch-order-fills",
+		Usage: "apply signal order fills from the MEXC user data stream in real time and emulate bracket stops; requires --live to place orders",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "live"},
+			&cli.DurationFlag{
+				Name:  "poll-interval",
+				Usage: "REST polling interval while the stream is unavailable",
+				Value: defaults.PollInterval,
+			},
+			&cli.DurationFlag{
+				Name:  "bracket-interval",
+				Usage: "how often bracket stops are checked against the bid; 0 disables them",
+				Value: defaults.BracketInterval,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			opts := defaults
+			opts.Live = c.Bool("live")
+			opts.PollInterval = c.Duration("poll-interval")
+			opts.BracketInterval = c.Duration("bracket-interval")
+
+			ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return service.Process(ctx, opts)
+		},
+	}
+}
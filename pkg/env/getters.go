@@ -48,3 +48,14 @@ func GetDuration(name string, defaultVal time.Duration) time.Duration { // nolin
 
 	return result
 }
+
+func GetFloat(name string, defaultVal float64) float64 {
+	envVal := os.Getenv(name)
+
+	result, err := strconv.ParseFloat(envVal, 64)
+	if err != nil {
+		return defaultVal
+	}
+
+	return result
+}
@@ -263,3 +263,67 @@ func Test_getEnvString(t *testing.T) {
 		_ = os.Unsetenv(tt.envArgs.name)
 	}
 }
+
+// nolint:paralleltest
+func Test_getEnvFloat(t *testing.T) {
+	type args struct {
+		name       string
+		defaultVal float64
+	}
+
+	type envArgs struct {
+		name  string
+		value string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		envArgs envArgs
+		want    float64
+	}{
+		{
+			name: "Параметр задан в env",
+			args: args{
+				name:       "param_name",
+				defaultVal: 1.5,
+			},
+			envArgs: envArgs{
+				name:  "param_name",
+				value: "2.25",
+			},
+			want: 2.25,
+		},
+		{
+			name: "Параметр не задан в env, берем default значение",
+			args: args{
+				name:       "param_name",
+				defaultVal: 1.5,
+			},
+			envArgs: envArgs{
+				name:  "unknown_param_name",
+				value: "2.25",
+			},
+			want: 1.5,
+		},
+		{
+			name: "Параметр задан, но не float, берем default значение",
+			args: args{
+				name:       "param_name",
+				defaultVal: 1.5,
+			},
+			envArgs: envArgs{
+				name:  "param_name",
+				value: "bla-bla",
+			},
+			want: 1.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Setenv(tt.envArgs.name, tt.envArgs.value)
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, GetFloat(tt.args.name, tt.args.defaultVal))
+		})
+		_ = os.Unsetenv(tt.envArgs.name)
+	}
+}
@@ -1,7 +0,0 @@
-module mexc-sdk/mexcsdk
-
-go 1.16
-
-require (
-	github.com/aws/jsii-runtime-go v1.44.1
-)
@@ -10,6 +10,26 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type BacktestRun struct {
+	ID              int
+	CreatedAt       time.Time
+	StrategyVersion int
+	Symbols         string
+	PeriodFrom      time.Time
+	PeriodTo        time.Time
+	Spread          float64
+	Bars            int
+	Signals         int
+	Trades          int
+	Canceled        int
+	Wins            int
+	Losses          int
+	WinRate         float64
+	TotalPnl        float64
+	MaxDrawdown     float64
+	ExitReasons     string
+}
+
 type Coin struct {
 	ID                         int
 	Date                       time.Time
@@ -47,6 +67,18 @@ type Coin struct {
 	Maxrise                    *float64
 }
 
+type DaemonJob struct {
+	Name           string
+	LastStartedAt  time.Time
+	LastFinishedAt time.Time
+	LastSuccessAt  *time.Time
+	LastError      *string
+	LastDurationMs int
+	Runs           int
+	Failures       int
+	UpdatedAt      time.Time
+}
+
 type Log struct {
 	ID             int
 	Date           time.Time
@@ -88,6 +120,56 @@ type MarketSnapshot struct {
 	PriceChangePercent float64
 }
 
+type MarketTrade struct {
+	Symbol     string
+	TradedAt   time.Time
+	Price      float64
+	Qty        float64
+	BuyerMaker bool
+	Seq        int
+}
+
+type PalisadeGrid struct {
+	ID              int
+	Symbol          string
+	SupportPrice    float64
+	ResistancePrice float64
+	Levels          int
+	QuotePerLevel   float64
+	Status          string
+	StopReason      string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type PalisadeGridLevel struct {
+	GridID        int
+	Level         int
+	BuyPrice      float64
+	SellPrice     float64
+	Quantity      float64
+	Status        string
+	OrderID       string
+	ClientOrderID string
+	HeldQuantity  float64
+	HeldCost      float64
+	Cycles        int
+	RealizedPnl   float64
+	UpdatedAt     time.Time
+}
+
+type PalisadeOrderBracket struct {
+	TradeID         int
+	Symbol          string
+	TakeProfitPrice float64
+	StopPrice       float64
+	StopLimitPrice  float64
+	ExpiresAt       *time.Time
+	Status          string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
 type PalisadeOrderIntent struct {
 	ID                 int
 	ClientOrderID      string
@@ -105,6 +187,8 @@ type PalisadeOrderIntent struct {
 	LastError          string
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
+	OrderType          string
+	MakerFeeSaving     float64
 }
 
 type PalisadeSignal struct {
@@ -121,6 +205,7 @@ type PalisadeSignal struct {
 	InvalidationReason string
 	ValidUntil         time.Time
 	UpdatedAt          time.Time
+	ConfigVersion      string
 }
 
 type PaperTrade struct {
@@ -152,6 +237,13 @@ type PaperTrade struct {
 	ExitReason         string
 	LastPrice          float64
 	UpdatedAt          time.Time
+	ConfigVersion      string
+	StrategyKey        string
+}
+
+type RiskAlert struct {
+	Reason string
+	SentAt time.Time
 }
 
 type State struct {
@@ -168,6 +260,76 @@ type State struct {
 	Downlevel      float64
 }
 
+type StrategyConfig struct {
+	Version   string
+	Config    []byte
+	CreatedAt time.Time
+}
+
+type SwapInventory struct {
+	Asset     string
+	Quantity  float64
+	Cost      float64
+	UpdatedAt time.Time
+}
+
+type SwapLeg struct {
+	RunID              int
+	Leg                int
+	Symbol             string
+	Side               string
+	FromAsset          string
+	ToAsset            string
+	ExpectedPrice      float64
+	ExpectedDepthPrice float64
+	Quantity           float64
+	ClientOrderID      string
+	OrderID            string
+	Status             string
+	ExecutedQuantity   float64
+	CumulativeQuoteQty float64
+	RealizedPrice      float64
+	Fee                float64
+	PlacedAt           time.Time
+	FinishedAt         *time.Time
+	Liquidation        bool
+}
+
+type SwapOpportunity struct {
+	ID                 int
+	Path               string
+	StartAsset         string
+	Legs               int
+	ThresholdPercent   float64
+	FirstSeenAt        time.Time
+	LastSeenAt         time.Time
+	ClosedAt           time.Time
+	Observations       int
+	FirstProfitPercent float64
+	PeakProfitPercent  float64
+	PeakAt             time.Time
+	PeakDepthAmount    float64
+	MaxDepthAmount     float64
+}
+
+type SwapRun struct {
+	ID                         int
+	Path                       string
+	StartAsset                 string
+	Amount                     float64
+	ExpectedProfitPercent      float64
+	ExpectedDepthProfitPercent float64
+	Status                     string
+	UnwindOutcome              string
+	FinalAmount                float64
+	RealizedProfitPercent      float64
+	LastError                  string
+	StartedAt                  time.Time
+	FinishedAt                 *time.Time
+	Mode                       string
+	LiquidatedAmount           float64
+}
+
 type TradeLog struct {
 	ID           int
 	OpenDate     time.Time
@@ -186,6 +348,20 @@ type TradeLog struct {
 	Downlevel    float64
 }
 
+type TradeLogExitState struct {
+	TradeID            int
+	StrategyVersion    int
+	Fee                float64
+	LotStep            float64
+	BreakEvenArmed     bool
+	PartialProfitTaken bool
+	PartialOrderID     string
+	MaxBidPrice        float64
+	MinBidPrice        float64
+	UpdatedAt          time.Time
+	StrategyKey        string
+}
+
 type TradeLogManual struct {
 	ID           int
 	OpenDate     time.Time
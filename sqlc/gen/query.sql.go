@@ -23,13 +23,125 @@ func (q *Queries) CountMarketDailyBars(ctx context.Context, symbol string) (int6
 	return column_1, err
 }
 
+const createBacktestRun = `-- name: CreateBacktestRun :one
+INSERT INTO backtest_run (
+    created_at, strategy_version, symbols, period_from, period_to, spread, bars, signals, trades,
+    canceled, wins, losses, win_rate, total_pnl, max_drawdown, exit_reasons
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+RETURNING id, created_at, strategy_version, symbols, period_from, period_to, spread, bars, signals, trades, canceled, wins, losses, win_rate, total_pnl, max_drawdown, exit_reasons
+`
+
+type CreateBacktestRunParams struct {
+	CreatedAt       time.Time
+	StrategyVersion int
+	Symbols         string
+	PeriodFrom      time.Time
+	PeriodTo        time.Time
+	Spread          float64
+	Bars            int
+	Signals         int
+	Trades          int
+	Canceled        int
+	Wins            int
+	Losses          int
+	WinRate         float64
+	TotalPnl        float64
+	MaxDrawdown     float64
+	ExitReasons     string
+}
+
+func (q *Queries) CreateBacktestRun(ctx context.Context, arg CreateBacktestRunParams) (BacktestRun, error) {
+	row := q.db.QueryRow(ctx, createBacktestRun,
+		arg.CreatedAt,
+		arg.StrategyVersion,
+		arg.Symbols,
+		arg.PeriodFrom,
+		arg.PeriodTo,
+		arg.Spread,
+		arg.Bars,
+		arg.Signals,
+		arg.Trades,
+		arg.Canceled,
+		arg.Wins,
+		arg.Losses,
+		arg.WinRate,
+		arg.TotalPnl,
+		arg.MaxDrawdown,
+		arg.ExitReasons,
+	)
+	var i BacktestRun
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StrategyVersion,
+		&i.Symbols,
+		&i.PeriodFrom,
+		&i.PeriodTo,
+		&i.Spread,
+		&i.Bars,
+		&i.Signals,
+		&i.Trades,
+		&i.Canceled,
+		&i.Wins,
+		&i.Losses,
+		&i.WinRate,
+		&i.TotalPnl,
+		&i.MaxDrawdown,
+		&i.ExitReasons,
+	)
+	return i, err
+}
+
+const createGrid = `-- name: CreateGrid :one
+INSERT INTO palisade_grid (
+    symbol, support_price, resistance_price, levels, quote_per_level, status, created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+RETURNING id, symbol, support_price, resistance_price, levels, quote_per_level, status, stop_reason, created_at, updated_at
+`
+
+type CreateGridParams struct {
+	Symbol          string
+	SupportPrice    float64
+	ResistancePrice float64
+	Levels          int
+	QuotePerLevel   float64
+	Status          string
+	CreatedAt       time.Time
+}
+
+func (q *Queries) CreateGrid(ctx context.Context, arg CreateGridParams) (PalisadeGrid, error) {
+	row := q.db.QueryRow(ctx, createGrid,
+		arg.Symbol,
+		arg.SupportPrice,
+		arg.ResistancePrice,
+		arg.Levels,
+		arg.QuotePerLevel,
+		arg.Status,
+		arg.CreatedAt,
+	)
+	var i PalisadeGrid
+	err := row.Scan(
+		&i.ID,
+		&i.Symbol,
+		&i.SupportPrice,
+		&i.ResistancePrice,
+		&i.Levels,
+		&i.QuotePerLevel,
+		&i.Status,
+		&i.StopReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const createOrderIntent = `-- name: CreateOrderIntent :one
 INSERT INTO palisade_order_intent (
     client_order_id, symbol, side, price, quantity, open_balance, target_price, status,
     exchange_order_id, trade_id, executed_quantity, cumulative_quote_qty, last_error,
-    created_at, updated_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-RETURNING id, client_order_id, symbol, side, price, quantity, open_balance, target_price, status, exchange_order_id, trade_id, executed_quantity, cumulative_quote_qty, last_error, created_at, updated_at
+    created_at, updated_at, order_type, maker_fee_saving
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+RETURNING id, client_order_id, symbol, side, price, quantity, open_balance, target_price, status, exchange_order_id, trade_id, executed_quantity, cumulative_quote_qty, last_error, created_at, updated_at, order_type, maker_fee_saving
 `
 
 type CreateOrderIntentParams struct {
@@ -48,6 +160,8 @@ type CreateOrderIntentParams struct {
 	LastError          string
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
+	OrderType          string
+	MakerFeeSaving     float64
 }
 
 func (q *Queries) CreateOrderIntent(ctx context.Context, arg CreateOrderIntentParams) (PalisadeOrderIntent, error) {
@@ -67,6 +181,8 @@ func (q *Queries) CreateOrderIntent(ctx context.Context, arg CreateOrderIntentPa
 		arg.LastError,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.OrderType,
+		arg.MakerFeeSaving,
 	)
 	var i PalisadeOrderIntent
 	err := row.Scan(
@@ -86,6 +202,8 @@ func (q *Queries) CreateOrderIntent(ctx context.Context, arg CreateOrderIntentPa
 		&i.LastError,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.OrderType,
+		&i.MakerFeeSaving,
 	)
 	return i, err
 }
@@ -94,9 +212,9 @@ const createPaperTrade = `-- name: CreatePaperTrade :one
 INSERT INTO paper_trade (
     strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity,
     filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at,
-    closed_at, exit_reason, last_price, updated_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
-RETURNING id, strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity, filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at, closed_at, exit_reason, last_price, updated_at
+    closed_at, exit_reason, last_price, updated_at, config_version, strategy_key
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29)
+RETURNING id, strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity, filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at, closed_at, exit_reason, last_price, updated_at, config_version, strategy_key
 `
 
 type CreatePaperTradeParams struct {
@@ -127,6 +245,8 @@ type CreatePaperTradeParams struct {
 	ExitReason         string
 	LastPrice          float64
 	UpdatedAt          time.Time
+	ConfigVersion      string
+	StrategyKey        string
 }
 
 func (q *Queries) CreatePaperTrade(ctx context.Context, arg CreatePaperTradeParams) (PaperTrade, error) {
@@ -158,6 +278,8 @@ func (q *Queries) CreatePaperTrade(ctx context.Context, arg CreatePaperTradePara
 		arg.ExitReason,
 		arg.LastPrice,
 		arg.UpdatedAt,
+		arg.ConfigVersion,
+		arg.StrategyKey,
 	)
 	var i PaperTrade
 	err := row.Scan(
@@ -189,10 +311,153 @@ func (q *Queries) CreatePaperTrade(ctx context.Context, arg CreatePaperTradePara
 		&i.ExitReason,
 		&i.LastPrice,
 		&i.UpdatedAt,
+		&i.ConfigVersion,
+		&i.StrategyKey,
 	)
 	return i, err
 }
 
+const createSwapOpportunity = `-- name: CreateSwapOpportunity :exec
+INSERT INTO swap_opportunity (
+    path, start_asset, legs, threshold_percent, first_seen_at, last_seen_at, closed_at, observations,
+    first_profit_percent, peak_profit_percent, peak_at, peak_depth_amount, max_depth_amount
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+`
+
+type CreateSwapOpportunityParams struct {
+	Path               string
+	StartAsset         string
+	Legs               int
+	ThresholdPercent   float64
+	FirstSeenAt        time.Time
+	LastSeenAt         time.Time
+	ClosedAt           time.Time
+	Observations       int
+	FirstProfitPercent float64
+	PeakProfitPercent  float64
+	PeakAt             time.Time
+	PeakDepthAmount    float64
+	MaxDepthAmount     float64
+}
+
+func (q *Queries) CreateSwapOpportunity(ctx context.Context, arg CreateSwapOpportunityParams) error {
+	_, err := q.db.Exec(ctx, createSwapOpportunity,
+		arg.Path,
+		arg.StartAsset,
+		arg.Legs,
+		arg.ThresholdPercent,
+		arg.FirstSeenAt,
+		arg.LastSeenAt,
+		arg.ClosedAt,
+		arg.Observations,
+		arg.FirstProfitPercent,
+		arg.PeakProfitPercent,
+		arg.PeakAt,
+		arg.PeakDepthAmount,
+		arg.MaxDepthAmount,
+	)
+	return err
+}
+
+const createSwapRun = `-- name: CreateSwapRun :one
+INSERT INTO swap_run (
+    path, start_asset, amount, expected_profit_percent, expected_depth_profit_percent, status, started_at, mode
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, path, start_asset, amount, expected_profit_percent, expected_depth_profit_percent, status, unwind_outcome, final_amount, realized_profit_percent, last_error, started_at, finished_at, mode, liquidated_amount
+`
+
+type CreateSwapRunParams struct {
+	Path                       string
+	StartAsset                 string
+	Amount                     float64
+	ExpectedProfitPercent      float64
+	ExpectedDepthProfitPercent float64
+	Status                     string
+	StartedAt                  time.Time
+	Mode                       string
+}
+
+func (q *Queries) CreateSwapRun(ctx context.Context, arg CreateSwapRunParams) (SwapRun, error) {
+	row := q.db.QueryRow(ctx, createSwapRun,
+		arg.Path,
+		arg.StartAsset,
+		arg.Amount,
+		arg.ExpectedProfitPercent,
+		arg.ExpectedDepthProfitPercent,
+		arg.Status,
+		arg.StartedAt,
+		arg.Mode,
+	)
+	var i SwapRun
+	err := row.Scan(
+		&i.ID,
+		&i.Path,
+		&i.StartAsset,
+		&i.Amount,
+		&i.ExpectedProfitPercent,
+		&i.ExpectedDepthProfitPercent,
+		&i.Status,
+		&i.UnwindOutcome,
+		&i.FinalAmount,
+		&i.RealizedProfitPercent,
+		&i.LastError,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Mode,
+		&i.LiquidatedAmount,
+	)
+	return i, err
+}
+
+const deleteMarketTradesBefore = `-- name: DeleteMarketTradesBefore :execrows
+DELETE FROM market_trade WHERE traded_at < $1
+`
+
+func (q *Queries) DeleteMarketTradesBefore(ctx context.Context, tradedAt time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMarketTradesBefore, tradedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const finishSwapRun = `-- name: FinishSwapRun :exec
+UPDATE swap_run SET
+    status = $2,
+    unwind_outcome = $3,
+    final_amount = $4,
+    realized_profit_percent = $5,
+    last_error = $6,
+    finished_at = $7,
+    liquidated_amount = $8
+WHERE id = $1
+`
+
+type FinishSwapRunParams struct {
+	ID                    int
+	Status                string
+	UnwindOutcome         string
+	FinalAmount           float64
+	RealizedProfitPercent float64
+	LastError             string
+	FinishedAt            *time.Time
+	LiquidatedAmount      float64
+}
+
+func (q *Queries) FinishSwapRun(ctx context.Context, arg FinishSwapRunParams) error {
+	_, err := q.db.Exec(ctx, finishSwapRun,
+		arg.ID,
+		arg.Status,
+		arg.UnwindOutcome,
+		arg.FinalAmount,
+		arg.RealizedProfitPercent,
+		arg.LastError,
+		arg.FinishedAt,
+		arg.LiquidatedAmount,
+	)
+	return err
+}
+
 const getCoinInfo = `-- name: GetCoinInfo :one
 SELECT id, date, symbol, status, baseasset, baseassetprecision, quoteasset, quoteprecision, quoteassetprecision, basecommissionprecision, quotecommissionprecision, ordertypes, isspottradingallowed, ismargintradingallowed, quoteamountprecision, basesizeprecision, permissions, maxquoteamount, makercommission, takercommission, quoteamountprecisionmarket, maxquoteamountmarket, fullname, tradesidetype, ispalisade, lastcheck, support, resistance, rangevalue, rangepercent, avgprice, volatility, maxdrawdown, maxrise FROM coins
 WHERE symbol = $1 LIMIT 1
@@ -511,6 +776,20 @@ func (q *Queries) GetCountLogsByCoin(ctx context.Context, arg GetCountLogsByCoin
 	return count, err
 }
 
+const getLastMarketTradeTime = `-- name: GetLastMarketTradeTime :one
+SELECT traded_at FROM market_trade
+WHERE symbol = $1
+ORDER BY traded_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastMarketTradeTime(ctx context.Context, symbol string) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getLastMarketTradeTime, symbol)
+	var traded_at time.Time
+	err := row.Scan(&traded_at)
+	return traded_at, err
+}
+
 const getLastMinuteBarOpenTime = `-- name: GetLastMinuteBarOpenTime :one
 SELECT open_time FROM market_minute_bar
 WHERE symbol = $1
@@ -558,6 +837,26 @@ func (q *Queries) GetLastTradeIdManual(ctx context.Context) (interface{}, error)
 	return coalesce, err
 }
 
+const getMakerFeeSavings = `-- name: GetMakerFeeSavings :one
+SELECT
+    COUNT(*)::int AS orders,
+    COALESCE(SUM(cumulative_quote_qty * maker_fee_saving), 0)::double precision AS fee_saved
+FROM palisade_order_intent
+WHERE order_type = 'LIMIT_MAKER' AND executed_quantity > 0
+`
+
+type GetMakerFeeSavingsRow struct {
+	Orders   int
+	FeeSaved float64
+}
+
+func (q *Queries) GetMakerFeeSavings(ctx context.Context) (GetMakerFeeSavingsRow, error) {
+	row := q.db.QueryRow(ctx, getMakerFeeSavings)
+	var i GetMakerFeeSavingsRow
+	err := row.Scan(&i.Orders, &i.FeeSaved)
+	return i, err
+}
+
 const getOpenOrders = `-- name: GetOpenOrders :many
 SELECT id, open_date, deal_date, close_date, cancel_date, open_balance, close_balance, symbol, buy_price, sell_price, amount, orderid, orderid_sell, uplevel, downlevel FROM trade_log
 WHERE 
@@ -645,7 +944,7 @@ func (q *Queries) GetOpenOrdersManual(ctx context.Context) ([]TradeLogManual, er
 }
 
 const getOpenPaperTradeBySymbol = `-- name: GetOpenPaperTradeBySymbol :one
-SELECT id, strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity, filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at, closed_at, exit_reason, last_price, updated_at FROM paper_trade
+SELECT id, strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity, filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at, closed_at, exit_reason, last_price, updated_at, config_version, strategy_key FROM paper_trade
 WHERE symbol = $1
   AND strategy_version = $2
   AND status IN ('BUY_PENDING', 'PULLBACK_SEEN', 'POSITION_OPEN', 'SELL_PENDING')
@@ -690,12 +989,14 @@ func (q *Queries) GetOpenPaperTradeBySymbol(ctx context.Context, arg GetOpenPape
 		&i.ExitReason,
 		&i.LastPrice,
 		&i.UpdatedAt,
+		&i.ConfigVersion,
+		&i.StrategyKey,
 	)
 	return i, err
 }
 
 const getPaperTradeBySignal = `-- name: GetPaperTradeBySignal :one
-SELECT id, strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity, filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at, closed_at, exit_reason, last_price, updated_at FROM paper_trade
+SELECT id, strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity, filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at, closed_at, exit_reason, last_price, updated_at, config_version, strategy_key FROM paper_trade
 WHERE symbol = $1
   AND signal_at = $2
   AND strategy_version = $3
@@ -741,6 +1042,8 @@ func (q *Queries) GetPaperTradeBySignal(ctx context.Context, arg GetPaperTradeBy
 		&i.ExitReason,
 		&i.LastPrice,
 		&i.UpdatedAt,
+		&i.ConfigVersion,
+		&i.StrategyKey,
 	)
 	return i, err
 }
@@ -755,21 +1058,25 @@ SELECT
     COALESCE(SUM(pnl) FILTER (WHERE status IN ('POSITION_OPEN', 'SELL_PENDING')), 0)::double precision AS open_pnl,
     COALESCE(AVG(pnl) FILTER (WHERE status = 'CLOSED'), 0)::double precision AS average_pnl,
     COUNT(*) FILTER (WHERE status = 'CLOSED' AND pnl > 0)::int AS wins,
-    COUNT(*) FILTER (WHERE status = 'CLOSED' AND pnl < 0)::int AS losses
+    COUNT(*) FILTER (WHERE status = 'CLOSED' AND pnl < 0)::int AS losses,
+    COALESCE(AVG(pnl) FILTER (WHERE status = 'CLOSED' AND pnl > 0), 0)::double precision AS average_win,
+    COALESCE(AVG(-pnl) FILTER (WHERE status = 'CLOSED' AND pnl < 0), 0)::double precision AS average_loss
 FROM paper_trade
 WHERE strategy_version = $1
 `
 
 type GetPaperTradeStatsRow struct {
-	Total      int
-	Closed     int
-	Canceled   int
-	Open       int
-	TotalPnl   float64
-	OpenPnl    float64
-	AveragePnl float64
-	Wins       int
-	Losses     int
+	Total       int
+	Closed      int
+	Canceled    int
+	Open        int
+	TotalPnl    float64
+	OpenPnl     float64
+	AveragePnl  float64
+	Wins        int
+	Losses      int
+	AverageWin  float64
+	AverageLoss float64
 }
 
 func (q *Queries) GetPaperTradeStats(ctx context.Context, strategyVersion int) (GetPaperTradeStatsRow, error) {
@@ -785,6 +1092,42 @@ func (q *Queries) GetPaperTradeStats(ctx context.Context, strategyVersion int) (
 		&i.AveragePnl,
 		&i.Wins,
 		&i.Losses,
+		&i.AverageWin,
+		&i.AverageLoss,
+	)
+	return i, err
+}
+
+const getRiskAlertSentAt = `-- name: GetRiskAlertSentAt :one
+SELECT sent_at FROM risk_alert WHERE reason = $1
+`
+
+func (q *Queries) GetRiskAlertSentAt(ctx context.Context, reason string) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getRiskAlertSentAt, reason)
+	var sent_at time.Time
+	err := row.Scan(&sent_at)
+	return sent_at, err
+}
+
+const getTradeExitState = `-- name: GetTradeExitState :one
+SELECT trade_id, strategy_version, fee, lot_step, break_even_armed, partial_profit_taken, partial_order_id, max_bid_price, min_bid_price, updated_at, strategy_key FROM trade_log_exit_state WHERE trade_id = $1
+`
+
+func (q *Queries) GetTradeExitState(ctx context.Context, tradeID int) (TradeLogExitState, error) {
+	row := q.db.QueryRow(ctx, getTradeExitState, tradeID)
+	var i TradeLogExitState
+	err := row.Scan(
+		&i.TradeID,
+		&i.StrategyVersion,
+		&i.Fee,
+		&i.LotStep,
+		&i.BreakEvenArmed,
+		&i.PartialProfitTaken,
+		&i.PartialOrderID,
+		&i.MaxBidPrice,
+		&i.MinBidPrice,
+		&i.UpdatedAt,
+		&i.StrategyKey,
 	)
 	return i, err
 }
@@ -841,6 +1184,33 @@ func (q *Queries) GetTrendRetestState(ctx context.Context, arg GetTrendRetestSta
 	return i, err
 }
 
+const insertMarketTrade = `-- name: InsertMarketTrade :exec
+INSERT INTO market_trade (symbol, traded_at, price, qty, buyer_maker, seq)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT DO NOTHING
+`
+
+type InsertMarketTradeParams struct {
+	Symbol     string
+	TradedAt   time.Time
+	Price      float64
+	Qty        float64
+	BuyerMaker bool
+	Seq        int
+}
+
+func (q *Queries) InsertMarketTrade(ctx context.Context, arg InsertMarketTradeParams) error {
+	_, err := q.db.Exec(ctx, insertMarketTrade,
+		arg.Symbol,
+		arg.TradedAt,
+		arg.Price,
+		arg.Qty,
+		arg.BuyerMaker,
+		arg.Seq,
+	)
+	return err
+}
+
 const insertTrendSignalSent = `-- name: InsertTrendSignalSent :exec
 INSERT INTO trend_signal_sent (symbol, sma_period, day_utc, signal_kind)
 VALUES ($1, $2, $3, $4)
@@ -863,6 +1233,79 @@ func (q *Queries) InsertTrendSignalSent(ctx context.Context, arg InsertTrendSign
 	return err
 }
 
+const listActiveGrids = `-- name: ListActiveGrids :many
+SELECT id, symbol, support_price, resistance_price, levels, quote_per_level, status, stop_reason, created_at, updated_at FROM palisade_grid
+WHERE status = 'ACTIVE'
+ORDER BY id
+`
+
+func (q *Queries) ListActiveGrids(ctx context.Context) ([]PalisadeGrid, error) {
+	rows, err := q.db.Query(ctx, listActiveGrids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PalisadeGrid
+	for rows.Next() {
+		var i PalisadeGrid
+		if err := rows.Scan(
+			&i.ID,
+			&i.Symbol,
+			&i.SupportPrice,
+			&i.ResistancePrice,
+			&i.Levels,
+			&i.QuotePerLevel,
+			&i.Status,
+			&i.StopReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveOrderBrackets = `-- name: ListActiveOrderBrackets :many
+SELECT trade_id, symbol, take_profit_price, stop_price, stop_limit_price, expires_at, status, created_at, updated_at FROM palisade_order_bracket
+WHERE status = 'ACTIVE'
+ORDER BY trade_id
+`
+
+func (q *Queries) ListActiveOrderBrackets(ctx context.Context) ([]PalisadeOrderBracket, error) {
+	rows, err := q.db.Query(ctx, listActiveOrderBrackets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PalisadeOrderBracket
+	for rows.Next() {
+		var i PalisadeOrderBracket
+		if err := rows.Scan(
+			&i.TradeID,
+			&i.Symbol,
+			&i.TakeProfitPrice,
+			&i.StopPrice,
+			&i.StopLimitPrice,
+			&i.ExpiresAt,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listActivePalisadeSignals = `-- name: ListActivePalisadeSignals :many
 SELECT symbol, sent_at, strategy_version, support_price, entry_price, target_price, min_exit_price, net_profit, score,
        status, invalidation_reason, valid_until, updated_at
@@ -923,6 +1366,95 @@ func (q *Queries) ListActivePalisadeSignals(ctx context.Context) ([]ListActivePa
 	return items, nil
 }
 
+const listClosedTradesSince = `-- name: ListClosedTradesSince :many
+SELECT 'trade_log'::text AS source, id, symbol, buy_price, sell_price, amount, close_date
+FROM trade_log
+WHERE close_date >= $1::timestamptz AND sell_price IS NOT NULL
+UNION ALL
+SELECT 'trade_log_manual'::text AS source, id, symbol, buy_price, sell_price, amount, close_date
+FROM trade_log_manual
+WHERE close_date >= $1::timestamptz AND sell_price IS NOT NULL
+ORDER BY close_date
+`
+
+type ListClosedTradesSinceRow struct {
+	Source    string
+	ID        int
+	Symbol    string
+	BuyPrice  float64
+	SellPrice *float64
+	Amount    float64
+	CloseDate *time.Time
+}
+
+func (q *Queries) ListClosedTradesSince(ctx context.Context, dollar_1 time.Time) ([]ListClosedTradesSinceRow, error) {
+	rows, err := q.db.Query(ctx, listClosedTradesSince, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListClosedTradesSinceRow
+	for rows.Next() {
+		var i ListClosedTradesSinceRow
+		if err := rows.Scan(
+			&i.Source,
+			&i.ID,
+			&i.Symbol,
+			&i.BuyPrice,
+			&i.SellPrice,
+			&i.Amount,
+			&i.CloseDate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGridLevels = `-- name: ListGridLevels :many
+SELECT grid_id, level, buy_price, sell_price, quantity, status, order_id, client_order_id, held_quantity, held_cost, cycles, realized_pnl, updated_at FROM palisade_grid_level
+WHERE grid_id = $1
+ORDER BY level
+`
+
+func (q *Queries) ListGridLevels(ctx context.Context, gridID int) ([]PalisadeGridLevel, error) {
+	rows, err := q.db.Query(ctx, listGridLevels, gridID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PalisadeGridLevel
+	for rows.Next() {
+		var i PalisadeGridLevel
+		if err := rows.Scan(
+			&i.GridID,
+			&i.Level,
+			&i.BuyPrice,
+			&i.SellPrice,
+			&i.Quantity,
+			&i.Status,
+			&i.OrderID,
+			&i.ClientOrderID,
+			&i.HeldQuantity,
+			&i.HeldCost,
+			&i.Cycles,
+			&i.RealizedPnl,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMarketDailyBars = `-- name: ListMarketDailyBars :many
 SELECT symbol, day_utc, close FROM market_daily_bar
 WHERE symbol = $1
@@ -1024,8 +1556,47 @@ func (q *Queries) ListMarketSnapshots(ctx context.Context) ([]MarketSnapshot, er
 	return items, nil
 }
 
+const listMarketTradesSince = `-- name: ListMarketTradesSince :many
+SELECT symbol, traded_at, price, qty, buyer_maker, seq FROM market_trade
+WHERE symbol = $1
+  AND traded_at >= $2
+ORDER BY traded_at
+`
+
+type ListMarketTradesSinceParams struct {
+	Symbol   string
+	TradedAt time.Time
+}
+
+func (q *Queries) ListMarketTradesSince(ctx context.Context, arg ListMarketTradesSinceParams) ([]MarketTrade, error) {
+	rows, err := q.db.Query(ctx, listMarketTradesSince, arg.Symbol, arg.TradedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MarketTrade
+	for rows.Next() {
+		var i MarketTrade
+		if err := rows.Scan(
+			&i.Symbol,
+			&i.TradedAt,
+			&i.Price,
+			&i.Qty,
+			&i.BuyerMaker,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listOpenPaperTrades = `-- name: ListOpenPaperTrades :many
-SELECT id, strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity, filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at, closed_at, exit_reason, last_price, updated_at FROM paper_trade
+SELECT id, strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity, filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at, closed_at, exit_reason, last_price, updated_at, config_version, strategy_key FROM paper_trade
 WHERE status IN ('BUY_PENDING', 'PULLBACK_SEEN', 'POSITION_OPEN', 'SELL_PENDING')
   AND strategy_version = $1
 ORDER BY id
@@ -1069,6 +1640,8 @@ func (q *Queries) ListOpenPaperTrades(ctx context.Context, strategyVersion int)
 			&i.ExitReason,
 			&i.LastPrice,
 			&i.UpdatedAt,
+			&i.ConfigVersion,
+			&i.StrategyKey,
 		); err != nil {
 			return nil, err
 		}
@@ -1081,7 +1654,7 @@ func (q *Queries) ListOpenPaperTrades(ctx context.Context, strategyVersion int)
 }
 
 const listOrderIntentsByTradeID = `-- name: ListOrderIntentsByTradeID :many
-SELECT id, client_order_id, symbol, side, price, quantity, open_balance, target_price, status, exchange_order_id, trade_id, executed_quantity, cumulative_quote_qty, last_error, created_at, updated_at FROM palisade_order_intent
+SELECT id, client_order_id, symbol, side, price, quantity, open_balance, target_price, status, exchange_order_id, trade_id, executed_quantity, cumulative_quote_qty, last_error, created_at, updated_at, order_type, maker_fee_saving FROM palisade_order_intent
 WHERE trade_id = $1
 ORDER BY id
 `
@@ -1112,6 +1685,58 @@ func (q *Queries) ListOrderIntentsByTradeID(ctx context.Context, tradeID pgtype.
 			&i.LastError,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrderType,
+			&i.MakerFeeSaving,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecentClosedTrades = `-- name: ListRecentClosedTrades :many
+SELECT 'trade_log'::text AS source, id, symbol, buy_price, sell_price, amount, close_date
+FROM trade_log
+WHERE close_date IS NOT NULL AND sell_price IS NOT NULL
+UNION ALL
+SELECT 'trade_log_manual'::text AS source, id, symbol, buy_price, sell_price, amount, close_date
+FROM trade_log_manual
+WHERE close_date IS NOT NULL AND sell_price IS NOT NULL
+ORDER BY close_date DESC
+LIMIT $1
+`
+
+type ListRecentClosedTradesRow struct {
+	Source    string
+	ID        int
+	Symbol    string
+	BuyPrice  float64
+	SellPrice *float64
+	Amount    float64
+	CloseDate *time.Time
+}
+
+func (q *Queries) ListRecentClosedTrades(ctx context.Context, limit int32) ([]ListRecentClosedTradesRow, error) {
+	rows, err := q.db.Query(ctx, listRecentClosedTrades, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRecentClosedTradesRow
+	for rows.Next() {
+		var i ListRecentClosedTradesRow
+		if err := rows.Scan(
+			&i.Source,
+			&i.ID,
+			&i.Symbol,
+			&i.BuyPrice,
+			&i.SellPrice,
+			&i.Amount,
+			&i.CloseDate,
 		); err != nil {
 			return nil, err
 		}
@@ -1124,7 +1749,7 @@ func (q *Queries) ListOrderIntentsByTradeID(ctx context.Context, tradeID pgtype.
 }
 
 const listRecoverableOrderIntents = `-- name: ListRecoverableOrderIntents :many
-SELECT id, client_order_id, symbol, side, price, quantity, open_balance, target_price, status, exchange_order_id, trade_id, executed_quantity, cumulative_quote_qty, last_error, created_at, updated_at FROM palisade_order_intent
+SELECT id, client_order_id, symbol, side, price, quantity, open_balance, target_price, status, exchange_order_id, trade_id, executed_quantity, cumulative_quote_qty, last_error, created_at, updated_at, order_type, maker_fee_saving FROM palisade_order_intent
 WHERE status IN ('PLACING', 'UNKNOWN', 'ACKNOWLEDGED', 'RECOVERY_REQUIRED')
 ORDER BY created_at
 `
@@ -1155,6 +1780,168 @@ func (q *Queries) ListRecoverableOrderIntents(ctx context.Context) ([]PalisadeOr
 			&i.LastError,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.OrderType,
+			&i.MakerFeeSaving,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSwapInventory = `-- name: ListSwapInventory :many
+SELECT asset, quantity, cost, updated_at FROM swap_inventory
+ORDER BY asset
+`
+
+func (q *Queries) ListSwapInventory(ctx context.Context) ([]SwapInventory, error) {
+	rows, err := q.db.Query(ctx, listSwapInventory)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SwapInventory
+	for rows.Next() {
+		var i SwapInventory
+		if err := rows.Scan(
+			&i.Asset,
+			&i.Quantity,
+			&i.Cost,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSwapLegsSince = `-- name: ListSwapLegsSince :many
+SELECT swap_leg.run_id, swap_leg.leg, swap_leg.symbol, swap_leg.side, swap_leg.from_asset, swap_leg.to_asset, swap_leg.expected_price, swap_leg.expected_depth_price, swap_leg.quantity, swap_leg.client_order_id, swap_leg.order_id, swap_leg.status, swap_leg.executed_quantity, swap_leg.cumulative_quote_qty, swap_leg.realized_price, swap_leg.fee, swap_leg.placed_at, swap_leg.finished_at, swap_leg.liquidation FROM swap_leg
+JOIN swap_run ON swap_run.id = swap_leg.run_id
+WHERE swap_run.started_at >= $1
+ORDER BY swap_leg.run_id, swap_leg.leg
+`
+
+func (q *Queries) ListSwapLegsSince(ctx context.Context, startedAt time.Time) ([]SwapLeg, error) {
+	rows, err := q.db.Query(ctx, listSwapLegsSince, startedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SwapLeg
+	for rows.Next() {
+		var i SwapLeg
+		if err := rows.Scan(
+			&i.RunID,
+			&i.Leg,
+			&i.Symbol,
+			&i.Side,
+			&i.FromAsset,
+			&i.ToAsset,
+			&i.ExpectedPrice,
+			&i.ExpectedDepthPrice,
+			&i.Quantity,
+			&i.ClientOrderID,
+			&i.OrderID,
+			&i.Status,
+			&i.ExecutedQuantity,
+			&i.CumulativeQuoteQty,
+			&i.RealizedPrice,
+			&i.Fee,
+			&i.PlacedAt,
+			&i.FinishedAt,
+			&i.Liquidation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSwapOpportunitiesSince = `-- name: ListSwapOpportunitiesSince :many
+SELECT id, path, start_asset, legs, threshold_percent, first_seen_at, last_seen_at, closed_at, observations, first_profit_percent, peak_profit_percent, peak_at, peak_depth_amount, max_depth_amount FROM swap_opportunity
+WHERE first_seen_at >= $1
+ORDER BY first_seen_at, id
+`
+
+func (q *Queries) ListSwapOpportunitiesSince(ctx context.Context, firstSeenAt time.Time) ([]SwapOpportunity, error) {
+	rows, err := q.db.Query(ctx, listSwapOpportunitiesSince, firstSeenAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SwapOpportunity
+	for rows.Next() {
+		var i SwapOpportunity
+		if err := rows.Scan(
+			&i.ID,
+			&i.Path,
+			&i.StartAsset,
+			&i.Legs,
+			&i.ThresholdPercent,
+			&i.FirstSeenAt,
+			&i.LastSeenAt,
+			&i.ClosedAt,
+			&i.Observations,
+			&i.FirstProfitPercent,
+			&i.PeakProfitPercent,
+			&i.PeakAt,
+			&i.PeakDepthAmount,
+			&i.MaxDepthAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSwapRunsSince = `-- name: ListSwapRunsSince :many
+SELECT id, path, start_asset, amount, expected_profit_percent, expected_depth_profit_percent, status, unwind_outcome, final_amount, realized_profit_percent, last_error, started_at, finished_at, mode, liquidated_amount FROM swap_run
+WHERE started_at >= $1
+ORDER BY started_at, id
+`
+
+func (q *Queries) ListSwapRunsSince(ctx context.Context, startedAt time.Time) ([]SwapRun, error) {
+	rows, err := q.db.Query(ctx, listSwapRunsSince, startedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SwapRun
+	for rows.Next() {
+		var i SwapRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.Path,
+			&i.StartAsset,
+			&i.Amount,
+			&i.ExpectedProfitPercent,
+			&i.ExpectedDepthProfitPercent,
+			&i.Status,
+			&i.UnwindOutcome,
+			&i.FinalAmount,
+			&i.RealizedProfitPercent,
+			&i.LastError,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Mode,
+			&i.LiquidatedAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -1317,6 +2104,45 @@ func (q *Queries) SaveCoin(ctx context.Context, arg SaveCoinParams) (Coin, error
 	return i, err
 }
 
+const saveDaemonJobRun = `-- name: SaveDaemonJobRun :exec
+INSERT INTO daemon_job (
+    name, last_started_at, last_finished_at, last_success_at, last_error, last_duration_ms,
+    runs, failures, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, 1, $7, now())
+ON CONFLICT (name) DO UPDATE SET
+    last_started_at = EXCLUDED.last_started_at,
+    last_finished_at = EXCLUDED.last_finished_at,
+    last_success_at = COALESCE(EXCLUDED.last_success_at, daemon_job.last_success_at),
+    last_error = EXCLUDED.last_error,
+    last_duration_ms = EXCLUDED.last_duration_ms,
+    runs = daemon_job.runs + 1,
+    failures = daemon_job.failures + EXCLUDED.failures,
+    updated_at = EXCLUDED.updated_at
+`
+
+type SaveDaemonJobRunParams struct {
+	Name           string
+	LastStartedAt  time.Time
+	LastFinishedAt time.Time
+	LastSuccessAt  *time.Time
+	LastError      *string
+	LastDurationMs int
+	Failures       int
+}
+
+func (q *Queries) SaveDaemonJobRun(ctx context.Context, arg SaveDaemonJobRunParams) error {
+	_, err := q.db.Exec(ctx, saveDaemonJobRun,
+		arg.Name,
+		arg.LastStartedAt,
+		arg.LastFinishedAt,
+		arg.LastSuccessAt,
+		arg.LastError,
+		arg.LastDurationMs,
+		arg.Failures,
+	)
+	return err
+}
+
 const savePalisadeSignal = `-- name: SavePalisadeSignal :exec
 INSERT INTO palisade_signal (symbol, sent_at, score)
 VALUES ($1, $2, $3)
@@ -1346,7 +2172,8 @@ SET strategy_version = $2,
     status = $9,
     invalidation_reason = $10,
     valid_until = $11,
-    updated_at = $12
+    updated_at = $12,
+    config_version = $13
 WHERE symbol = $1
 `
 
@@ -1363,6 +2190,7 @@ type SavePalisadeSignalStateParams struct {
 	InvalidationReason string
 	ValidUntil         time.Time
 	UpdatedAt          time.Time
+	ConfigVersion      string
 }
 
 func (q *Queries) SavePalisadeSignalState(ctx context.Context, arg SavePalisadeSignalStateParams) error {
@@ -1379,10 +2207,44 @@ func (q *Queries) SavePalisadeSignalState(ctx context.Context, arg SavePalisadeS
 		arg.InvalidationReason,
 		arg.ValidUntil,
 		arg.UpdatedAt,
+		arg.ConfigVersion,
 	)
 	return err
 }
 
+const saveRiskAlert = `-- name: SaveRiskAlert :exec
+INSERT INTO risk_alert (reason, sent_at)
+VALUES ($1, $2)
+ON CONFLICT (reason) DO UPDATE SET sent_at = EXCLUDED.sent_at
+`
+
+type SaveRiskAlertParams struct {
+	Reason string
+	SentAt time.Time
+}
+
+func (q *Queries) SaveRiskAlert(ctx context.Context, arg SaveRiskAlertParams) error {
+	_, err := q.db.Exec(ctx, saveRiskAlert, arg.Reason, arg.SentAt)
+	return err
+}
+
+const saveStrategyConfig = `-- name: SaveStrategyConfig :exec
+INSERT INTO strategy_config (version, config, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (version) DO NOTHING
+`
+
+type SaveStrategyConfigParams struct {
+	Version   string
+	Config    []byte
+	CreatedAt time.Time
+}
+
+func (q *Queries) SaveStrategyConfig(ctx context.Context, arg SaveStrategyConfigParams) error {
+	_, err := q.db.Exec(ctx, saveStrategyConfig, arg.Version, arg.Config, arg.CreatedAt)
+	return err
+}
+
 const saveTradeLog = `-- name: SaveTradeLog :one
 INSERT INTO trade_log (
    open_date,
@@ -1639,6 +2501,21 @@ func (q *Queries) UpdateDealDateTradeLogManual(ctx context.Context, arg UpdateDe
 	return err
 }
 
+const updateGridStatus = `-- name: UpdateGridStatus :exec
+UPDATE palisade_grid SET status = $2, stop_reason = $3, updated_at = now() WHERE id = $1
+`
+
+type UpdateGridStatusParams struct {
+	ID         int
+	Status     string
+	StopReason string
+}
+
+func (q *Queries) UpdateGridStatus(ctx context.Context, arg UpdateGridStatusParams) error {
+	_, err := q.db.Exec(ctx, updateGridStatus, arg.ID, arg.Status, arg.StopReason)
+	return err
+}
+
 const updateIsPalisade = `-- name: UpdateIsPalisade :exec
 UPDATE coins
 SET isPalisade = $1, lastCheck = $2
@@ -1656,6 +2533,20 @@ func (q *Queries) UpdateIsPalisade(ctx context.Context, arg UpdateIsPalisadePara
 	return err
 }
 
+const updateOrderBracketStatus = `-- name: UpdateOrderBracketStatus :exec
+UPDATE palisade_order_bracket SET status = $2, updated_at = now() WHERE trade_id = $1
+`
+
+type UpdateOrderBracketStatusParams struct {
+	TradeID int
+	Status  string
+}
+
+func (q *Queries) UpdateOrderBracketStatus(ctx context.Context, arg UpdateOrderBracketStatusParams) error {
+	_, err := q.db.Exec(ctx, updateOrderBracketStatus, arg.TradeID, arg.Status)
+	return err
+}
+
 const updateOrderIntent = `-- name: UpdateOrderIntent :exec
 UPDATE palisade_order_intent
 SET status = $2,
@@ -1917,6 +2808,57 @@ func (q *Queries) UpdateTradeLevels(ctx context.Context, arg UpdateTradeLevelsPa
 	return err
 }
 
+const upsertGridLevel = `-- name: UpsertGridLevel :exec
+INSERT INTO palisade_grid_level (
+    grid_id, level, buy_price, sell_price, quantity, status, order_id, client_order_id,
+    held_quantity, held_cost, cycles, realized_pnl, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (grid_id, level) DO UPDATE SET
+    status = EXCLUDED.status,
+    order_id = EXCLUDED.order_id,
+    client_order_id = EXCLUDED.client_order_id,
+    held_quantity = EXCLUDED.held_quantity,
+    held_cost = EXCLUDED.held_cost,
+    cycles = EXCLUDED.cycles,
+    realized_pnl = EXCLUDED.realized_pnl,
+    updated_at = EXCLUDED.updated_at
+`
+
+type UpsertGridLevelParams struct {
+	GridID        int
+	Level         int
+	BuyPrice      float64
+	SellPrice     float64
+	Quantity      float64
+	Status        string
+	OrderID       string
+	ClientOrderID string
+	HeldQuantity  float64
+	HeldCost      float64
+	Cycles        int
+	RealizedPnl   float64
+	UpdatedAt     time.Time
+}
+
+func (q *Queries) UpsertGridLevel(ctx context.Context, arg UpsertGridLevelParams) error {
+	_, err := q.db.Exec(ctx, upsertGridLevel,
+		arg.GridID,
+		arg.Level,
+		arg.BuyPrice,
+		arg.SellPrice,
+		arg.Quantity,
+		arg.Status,
+		arg.OrderID,
+		arg.ClientOrderID,
+		arg.HeldQuantity,
+		arg.HeldCost,
+		arg.Cycles,
+		arg.RealizedPnl,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const upsertMarketDailyBar = `-- name: UpsertMarketDailyBar :exec
 INSERT INTO market_daily_bar (symbol, day_utc, close)
 VALUES ($1, $2, $3)
@@ -2008,6 +2950,173 @@ func (q *Queries) UpsertMarketSnapshot(ctx context.Context, arg UpsertMarketSnap
 	return err
 }
 
+const upsertOrderBracket = `-- name: UpsertOrderBracket :exec
+INSERT INTO palisade_order_bracket (
+    trade_id, symbol, take_profit_price, stop_price, stop_limit_price, expires_at, status,
+    created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+ON CONFLICT (trade_id) DO UPDATE SET
+    take_profit_price = EXCLUDED.take_profit_price,
+    stop_price = EXCLUDED.stop_price,
+    stop_limit_price = EXCLUDED.stop_limit_price,
+    expires_at = EXCLUDED.expires_at,
+    status = EXCLUDED.status,
+    updated_at = EXCLUDED.updated_at
+`
+
+type UpsertOrderBracketParams struct {
+	TradeID         int
+	Symbol          string
+	TakeProfitPrice float64
+	StopPrice       float64
+	StopLimitPrice  float64
+	ExpiresAt       *time.Time
+	Status          string
+	CreatedAt       time.Time
+}
+
+func (q *Queries) UpsertOrderBracket(ctx context.Context, arg UpsertOrderBracketParams) error {
+	_, err := q.db.Exec(ctx, upsertOrderBracket,
+		arg.TradeID,
+		arg.Symbol,
+		arg.TakeProfitPrice,
+		arg.StopPrice,
+		arg.StopLimitPrice,
+		arg.ExpiresAt,
+		arg.Status,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const upsertSwapInventory = `-- name: UpsertSwapInventory :exec
+INSERT INTO swap_inventory (asset, quantity, cost)
+VALUES ($1, $2, $3)
+ON CONFLICT (asset) DO UPDATE SET
+    quantity = EXCLUDED.quantity,
+    cost = EXCLUDED.cost,
+    updated_at = now()
+`
+
+type UpsertSwapInventoryParams struct {
+	Asset    string
+	Quantity float64
+	Cost     float64
+}
+
+func (q *Queries) UpsertSwapInventory(ctx context.Context, arg UpsertSwapInventoryParams) error {
+	_, err := q.db.Exec(ctx, upsertSwapInventory, arg.Asset, arg.Quantity, arg.Cost)
+	return err
+}
+
+const upsertSwapLeg = `-- name: UpsertSwapLeg :exec
+INSERT INTO swap_leg (
+    run_id, leg, symbol, side, from_asset, to_asset, expected_price, expected_depth_price, quantity,
+    client_order_id, order_id, status, executed_quantity, cumulative_quote_qty, realized_price, fee,
+    placed_at, finished_at, liquidation
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+ON CONFLICT (run_id, leg) DO UPDATE SET
+    order_id = EXCLUDED.order_id,
+    status = EXCLUDED.status,
+    executed_quantity = EXCLUDED.executed_quantity,
+    cumulative_quote_qty = EXCLUDED.cumulative_quote_qty,
+    realized_price = EXCLUDED.realized_price,
+    fee = EXCLUDED.fee,
+    finished_at = EXCLUDED.finished_at
+`
+
+type UpsertSwapLegParams struct {
+	RunID              int
+	Leg                int
+	Symbol             string
+	Side               string
+	FromAsset          string
+	ToAsset            string
+	ExpectedPrice      float64
+	ExpectedDepthPrice float64
+	Quantity           float64
+	ClientOrderID      string
+	OrderID            string
+	Status             string
+	ExecutedQuantity   float64
+	CumulativeQuoteQty float64
+	RealizedPrice      float64
+	Fee                float64
+	PlacedAt           time.Time
+	FinishedAt         *time.Time
+	Liquidation        bool
+}
+
+func (q *Queries) UpsertSwapLeg(ctx context.Context, arg UpsertSwapLegParams) error {
+	_, err := q.db.Exec(ctx, upsertSwapLeg,
+		arg.RunID,
+		arg.Leg,
+		arg.Symbol,
+		arg.Side,
+		arg.FromAsset,
+		arg.ToAsset,
+		arg.ExpectedPrice,
+		arg.ExpectedDepthPrice,
+		arg.Quantity,
+		arg.ClientOrderID,
+		arg.OrderID,
+		arg.Status,
+		arg.ExecutedQuantity,
+		arg.CumulativeQuoteQty,
+		arg.RealizedPrice,
+		arg.Fee,
+		arg.PlacedAt,
+		arg.FinishedAt,
+		arg.Liquidation,
+	)
+	return err
+}
+
+const upsertTradeExitState = `-- name: UpsertTradeExitState :exec
+INSERT INTO trade_log_exit_state (
+    trade_id, strategy_version, fee, lot_step, break_even_armed, partial_profit_taken,
+    partial_order_id, max_bid_price, min_bid_price, updated_at, strategy_key
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (trade_id) DO UPDATE SET
+    break_even_armed = EXCLUDED.break_even_armed,
+    partial_profit_taken = EXCLUDED.partial_profit_taken,
+    partial_order_id = EXCLUDED.partial_order_id,
+    max_bid_price = EXCLUDED.max_bid_price,
+    min_bid_price = EXCLUDED.min_bid_price,
+    updated_at = EXCLUDED.updated_at
+`
+
+type UpsertTradeExitStateParams struct {
+	TradeID            int
+	StrategyVersion    int
+	Fee                float64
+	LotStep            float64
+	BreakEvenArmed     bool
+	PartialProfitTaken bool
+	PartialOrderID     string
+	MaxBidPrice        float64
+	MinBidPrice        float64
+	UpdatedAt          time.Time
+	StrategyKey        string
+}
+
+func (q *Queries) UpsertTradeExitState(ctx context.Context, arg UpsertTradeExitStateParams) error {
+	_, err := q.db.Exec(ctx, upsertTradeExitState,
+		arg.TradeID,
+		arg.StrategyVersion,
+		arg.Fee,
+		arg.LotStep,
+		arg.BreakEvenArmed,
+		arg.PartialProfitTaken,
+		arg.PartialOrderID,
+		arg.MaxBidPrice,
+		arg.MinBidPrice,
+		arg.UpdatedAt,
+		arg.StrategyKey,
+	)
+	return err
+}
+
 const upsertTrendRetestState = `-- name: UpsertTrendRetestState :exec
 INSERT INTO trend_retest_state (
     symbol, sma_period, day_utc, wait_retest, retest_until, last_processed_open_time
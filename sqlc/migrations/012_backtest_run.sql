@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS backtest_run (
+    id               SERIAL PRIMARY KEY,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    strategy_version INT NOT NULL,
+    symbols          TEXT NOT NULL,
+    period_from      TIMESTAMPTZ NOT NULL,
+    period_to        TIMESTAMPTZ NOT NULL,
+    spread           DOUBLE PRECISION NOT NULL DEFAULT 0,
+    bars             INT NOT NULL DEFAULT 0,
+    signals          INT NOT NULL DEFAULT 0,
+    trades           INT NOT NULL DEFAULT 0,
+    canceled         INT NOT NULL DEFAULT 0,
+    wins             INT NOT NULL DEFAULT 0,
+    losses           INT NOT NULL DEFAULT 0,
+    win_rate         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_pnl        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_drawdown     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    exit_reasons     TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS backtest_run_strategy_idx ON backtest_run (strategy_version, created_at);
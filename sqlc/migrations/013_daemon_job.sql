@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS daemon_job (
+    name             TEXT PRIMARY KEY,
+    last_started_at  TIMESTAMPTZ NOT NULL,
+    last_finished_at TIMESTAMPTZ NOT NULL,
+    last_success_at  TIMESTAMPTZ,
+    last_error       TEXT,
+    last_duration_ms INT NOT NULL DEFAULT 0,
+    runs             INT NOT NULL DEFAULT 0,
+    failures         INT NOT NULL DEFAULT 0,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
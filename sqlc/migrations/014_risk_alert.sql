@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS risk_alert (
+    reason  TEXT PRIMARY KEY,
+    sent_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS strategy_config (
+    version    TEXT PRIMARY KEY,
+    config     JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE palisade_signal ADD COLUMN IF NOT EXISTS config_version TEXT NOT NULL DEFAULT '';
+ALTER TABLE paper_trade ADD COLUMN IF NOT EXISTS config_version TEXT NOT NULL DEFAULT '';
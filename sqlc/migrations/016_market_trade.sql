@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS market_trade (
+    symbol      TEXT NOT NULL,
+    traded_at   TIMESTAMPTZ NOT NULL,
+    price       DOUBLE PRECISION NOT NULL,
+    qty         DOUBLE PRECISION NOT NULL,
+    buyer_maker BOOLEAN NOT NULL,
+    PRIMARY KEY (symbol, traded_at, price, qty, buyer_maker)
+);
+
+CREATE INDEX IF NOT EXISTS market_trade_traded_at_idx ON market_trade (traded_at);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS palisade_order_bracket (
+    trade_id          INT PRIMARY KEY,
+    symbol            TEXT NOT NULL,
+    take_profit_price DOUBLE PRECISION NOT NULL,
+    stop_price        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    stop_limit_price  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    expires_at        TIMESTAMPTZ,
+    status            TEXT NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS palisade_order_bracket_status_idx
+    ON palisade_order_bracket (status);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS trade_log_exit_state (
+    trade_id             INT PRIMARY KEY,
+    strategy_version     INT NOT NULL,
+    fee                  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    lot_step             DOUBLE PRECISION NOT NULL DEFAULT 0,
+    break_even_armed     BOOLEAN NOT NULL DEFAULT false,
+    partial_profit_taken BOOLEAN NOT NULL DEFAULT false,
+    partial_order_id     TEXT NOT NULL DEFAULT '',
+    max_bid_price        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    min_bid_price        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
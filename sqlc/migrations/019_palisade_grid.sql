@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS palisade_grid (
+    id               SERIAL PRIMARY KEY,
+    symbol           TEXT NOT NULL,
+    support_price    DOUBLE PRECISION NOT NULL,
+    resistance_price DOUBLE PRECISION NOT NULL,
+    levels           INT NOT NULL,
+    quote_per_level  DOUBLE PRECISION NOT NULL,
+    status           TEXT NOT NULL,
+    stop_reason      TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS palisade_grid_active_symbol_idx
+    ON palisade_grid (symbol) WHERE status = 'ACTIVE';
+
+CREATE TABLE IF NOT EXISTS palisade_grid_level (
+    grid_id         INT NOT NULL REFERENCES palisade_grid (id),
+    level           INT NOT NULL,
+    buy_price       DOUBLE PRECISION NOT NULL,
+    sell_price      DOUBLE PRECISION NOT NULL,
+    quantity        DOUBLE PRECISION NOT NULL,
+    status          TEXT NOT NULL,
+    order_id        TEXT NOT NULL DEFAULT '',
+    client_order_id TEXT NOT NULL DEFAULT '',
+    held_quantity   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    held_cost       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    cycles          INT NOT NULL DEFAULT 0,
+    realized_pnl    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (grid_id, level)
+);
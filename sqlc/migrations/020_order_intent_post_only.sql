@@ -0,0 +1,3 @@
+ALTER TABLE palisade_order_intent
+    ADD COLUMN IF NOT EXISTS order_type TEXT NOT NULL DEFAULT 'LIMIT',
+    ADD COLUMN IF NOT EXISTS maker_fee_saving DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS swap_run (
+    id                            SERIAL PRIMARY KEY,
+    path                          TEXT NOT NULL,
+    start_asset                   TEXT NOT NULL,
+    amount                        DOUBLE PRECISION NOT NULL,
+    expected_profit_percent       DOUBLE PRECISION NOT NULL,
+    expected_depth_profit_percent DOUBLE PRECISION NOT NULL,
+    status                        TEXT NOT NULL,
+    unwind_outcome                TEXT NOT NULL DEFAULT '',
+    final_amount                  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    realized_profit_percent       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_error                    TEXT NOT NULL DEFAULT '',
+    started_at                    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at                   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS swap_run_started_at_idx ON swap_run (started_at);
+
+CREATE TABLE IF NOT EXISTS swap_leg (
+    run_id               INT NOT NULL REFERENCES swap_run (id),
+    leg                  INT NOT NULL,
+    symbol               TEXT NOT NULL,
+    side                 TEXT NOT NULL,
+    from_asset           TEXT NOT NULL,
+    to_asset             TEXT NOT NULL,
+    expected_price       DOUBLE PRECISION NOT NULL,
+    expected_depth_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    quantity             DOUBLE PRECISION NOT NULL,
+    client_order_id      TEXT NOT NULL,
+    order_id             TEXT NOT NULL DEFAULT '',
+    status               TEXT NOT NULL,
+    executed_quantity    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    cumulative_quote_qty DOUBLE PRECISION NOT NULL DEFAULT 0,
+    realized_price       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    fee                  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    placed_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at          TIMESTAMPTZ,
+    PRIMARY KEY (run_id, leg)
+);
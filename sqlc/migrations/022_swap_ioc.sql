@@ -0,0 +1,6 @@
+ALTER TABLE swap_run
+    ADD COLUMN IF NOT EXISTS mode TEXT NOT NULL DEFAULT 'limit',
+    ADD COLUMN IF NOT EXISTS liquidated_amount DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+ALTER TABLE swap_leg
+    ADD COLUMN IF NOT EXISTS liquidation BOOLEAN NOT NULL DEFAULT false;
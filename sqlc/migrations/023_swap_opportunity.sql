@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS swap_opportunity (
+    id                   SERIAL PRIMARY KEY,
+    path                 TEXT NOT NULL,
+    start_asset          TEXT NOT NULL,
+    legs                 INT NOT NULL,
+    threshold_percent    DOUBLE PRECISION NOT NULL,
+    first_seen_at        TIMESTAMPTZ NOT NULL,
+    last_seen_at         TIMESTAMPTZ NOT NULL,
+    closed_at            TIMESTAMPTZ NOT NULL,
+    observations         INT NOT NULL,
+    first_profit_percent DOUBLE PRECISION NOT NULL,
+    peak_profit_percent  DOUBLE PRECISION NOT NULL,
+    peak_at              TIMESTAMPTZ NOT NULL,
+    peak_depth_amount    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_depth_amount     DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS swap_opportunity_first_seen_at_idx ON swap_opportunity (first_seen_at);
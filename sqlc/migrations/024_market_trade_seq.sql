@@ -0,0 +1,7 @@
+-- MEXC не отдаёт идентификаторы aggTrades, а одинаковые агрегаты в одну
+-- миллисекунду — разные сделки: seq — их порядковый номер в ответе.
+ALTER TABLE market_trade
+    ADD COLUMN IF NOT EXISTS seq INT NOT NULL DEFAULT 0;
+
+ALTER TABLE market_trade DROP CONSTRAINT IF EXISTS market_trade_pkey;
+ALTER TABLE market_trade ADD PRIMARY KEY (symbol, traded_at, price, qty, buyer_maker, seq);
@@ -0,0 +1,7 @@
+-- Запас swap-process, купленный ребалансом: продаётся не больше него.
+CREATE TABLE IF NOT EXISTS swap_inventory (
+    asset      TEXT PRIMARY KEY,
+    quantity   DOUBLE PRECISION NOT NULL,
+    cost       DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,16 @@
+-- Полный ключ стратегии (имя/vN), по которой открыта сделка: выход
+-- ищется в реестре по нему, а не по имени из текущего конфига. Все прежние
+-- сделки открыты палисадой.
+ALTER TABLE trade_log_exit_state
+    ADD COLUMN IF NOT EXISTS strategy_key TEXT NOT NULL DEFAULT '';
+
+UPDATE trade_log_exit_state
+SET strategy_key = 'palisade/v' || strategy_version
+WHERE strategy_key = '';
+
+ALTER TABLE paper_trade
+    ADD COLUMN IF NOT EXISTS strategy_key TEXT NOT NULL DEFAULT '';
+
+UPDATE paper_trade
+SET strategy_key = 'palisade/v' || strategy_version
+WHERE strategy_key = '';
@@ -315,6 +315,26 @@ SELECT symbol, collected_at, last_price, bid_price, bid_qty, ask_price, ask_qty,
 FROM market_snapshot
 ORDER BY quote_volume_24h DESC;
 
+-- name: InsertMarketTrade :exec
+INSERT INTO market_trade (symbol, traded_at, price, qty, buyer_maker, seq)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT DO NOTHING;
+
+-- name: GetLastMarketTradeTime :one
+SELECT traded_at FROM market_trade
+WHERE symbol = $1
+ORDER BY traded_at DESC
+LIMIT 1;
+
+-- name: ListMarketTradesSince :many
+SELECT symbol, traded_at, price, qty, buyer_maker, seq FROM market_trade
+WHERE symbol = $1
+  AND traded_at >= $2
+ORDER BY traded_at;
+
+-- name: DeleteMarketTradesBefore :execrows
+DELETE FROM market_trade WHERE traded_at < $1;
+
 -- name: GetLastPalisadeSignal :one
 SELECT sent_at FROM palisade_signal WHERE symbol = $1;
 
@@ -335,7 +355,8 @@ SET strategy_version = $2,
     status = $9,
     invalidation_reason = $10,
     valid_until = $11,
-    updated_at = $12
+    updated_at = $12,
+    config_version = $13
 WHERE symbol = $1;
 
 -- name: ListActivePalisadeSignals :many
@@ -351,8 +372,8 @@ ORDER BY score DESC, updated_at DESC;
 INSERT INTO palisade_order_intent (
     client_order_id, symbol, side, price, quantity, open_balance, target_price, status,
     exchange_order_id, trade_id, executed_quantity, cumulative_quote_qty, last_error,
-    created_at, updated_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+    created_at, updated_at, order_type, maker_fee_saving
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 RETURNING *;
 
 -- name: UpdateOrderIntent :exec
@@ -373,11 +394,89 @@ SELECT * FROM palisade_order_intent
 WHERE status IN ('PLACING', 'UNKNOWN', 'ACKNOWLEDGED', 'RECOVERY_REQUIRED')
 ORDER BY created_at;
 
+-- name: GetMakerFeeSavings :one
+SELECT
+    COUNT(*)::int AS orders,
+    COALESCE(SUM(cumulative_quote_qty * maker_fee_saving), 0)::double precision AS fee_saved
+FROM palisade_order_intent
+WHERE order_type = 'LIMIT_MAKER' AND executed_quantity > 0;
+
 -- name: ListOrderIntentsByTradeID :many
 SELECT * FROM palisade_order_intent
 WHERE trade_id = $1
 ORDER BY id;
 
+-- name: GetTradeExitState :one
+SELECT * FROM trade_log_exit_state WHERE trade_id = $1;
+
+-- name: UpsertTradeExitState :exec
+INSERT INTO trade_log_exit_state (
+    trade_id, strategy_version, fee, lot_step, break_even_armed, partial_profit_taken,
+    partial_order_id, max_bid_price, min_bid_price, updated_at, strategy_key
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (trade_id) DO UPDATE SET
+    break_even_armed = EXCLUDED.break_even_armed,
+    partial_profit_taken = EXCLUDED.partial_profit_taken,
+    partial_order_id = EXCLUDED.partial_order_id,
+    max_bid_price = EXCLUDED.max_bid_price,
+    min_bid_price = EXCLUDED.min_bid_price,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: UpsertOrderBracket :exec
+INSERT INTO palisade_order_bracket (
+    trade_id, symbol, take_profit_price, stop_price, stop_limit_price, expires_at, status,
+    created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+ON CONFLICT (trade_id) DO UPDATE SET
+    take_profit_price = EXCLUDED.take_profit_price,
+    stop_price = EXCLUDED.stop_price,
+    stop_limit_price = EXCLUDED.stop_limit_price,
+    expires_at = EXCLUDED.expires_at,
+    status = EXCLUDED.status,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: ListActiveOrderBrackets :many
+SELECT * FROM palisade_order_bracket
+WHERE status = 'ACTIVE'
+ORDER BY trade_id;
+
+-- name: UpdateOrderBracketStatus :exec
+UPDATE palisade_order_bracket SET status = $2, updated_at = now() WHERE trade_id = $1;
+
+-- name: CreateGrid :one
+INSERT INTO palisade_grid (
+    symbol, support_price, resistance_price, levels, quote_per_level, status, created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+RETURNING *;
+
+-- name: ListActiveGrids :many
+SELECT * FROM palisade_grid
+WHERE status = 'ACTIVE'
+ORDER BY id;
+
+-- name: UpdateGridStatus :exec
+UPDATE palisade_grid SET status = $2, stop_reason = $3, updated_at = now() WHERE id = $1;
+
+-- name: UpsertGridLevel :exec
+INSERT INTO palisade_grid_level (
+    grid_id, level, buy_price, sell_price, quantity, status, order_id, client_order_id,
+    held_quantity, held_cost, cycles, realized_pnl, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (grid_id, level) DO UPDATE SET
+    status = EXCLUDED.status,
+    order_id = EXCLUDED.order_id,
+    client_order_id = EXCLUDED.client_order_id,
+    held_quantity = EXCLUDED.held_quantity,
+    held_cost = EXCLUDED.held_cost,
+    cycles = EXCLUDED.cycles,
+    realized_pnl = EXCLUDED.realized_pnl,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: ListGridLevels :many
+SELECT * FROM palisade_grid_level
+WHERE grid_id = $1
+ORDER BY level;
+
 -- name: GetOpenPaperTradeBySymbol :one
 SELECT * FROM paper_trade
 WHERE symbol = $1
@@ -398,8 +497,8 @@ LIMIT 1;
 INSERT INTO paper_trade (
     strategy_version, symbol, signal_at, status, entry_mode, support_price, entry_price, target_price, min_exit_price, expected_net_profit, break_even_armed, max_bid_price, min_bid_price, entry_low_price, partial_profit_taken, quantity,
     filled_quantity, sold_quantity, buy_quote, sell_quote, fees, pnl, opened_at,
-    closed_at, exit_reason, last_price, updated_at
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
+    closed_at, exit_reason, last_price, updated_at, config_version, strategy_key
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29)
 RETURNING *;
 
 -- name: ListOpenPaperTrades :many
@@ -440,6 +539,130 @@ SELECT
     COALESCE(SUM(pnl) FILTER (WHERE status IN ('POSITION_OPEN', 'SELL_PENDING')), 0)::double precision AS open_pnl,
     COALESCE(AVG(pnl) FILTER (WHERE status = 'CLOSED'), 0)::double precision AS average_pnl,
     COUNT(*) FILTER (WHERE status = 'CLOSED' AND pnl > 0)::int AS wins,
-    COUNT(*) FILTER (WHERE status = 'CLOSED' AND pnl < 0)::int AS losses
+    COUNT(*) FILTER (WHERE status = 'CLOSED' AND pnl < 0)::int AS losses,
+    COALESCE(AVG(pnl) FILTER (WHERE status = 'CLOSED' AND pnl > 0), 0)::double precision AS average_win,
+    COALESCE(AVG(-pnl) FILTER (WHERE status = 'CLOSED' AND pnl < 0), 0)::double precision AS average_loss
 FROM paper_trade
 WHERE strategy_version = $1;
+
+-- name: CreateBacktestRun :one
+INSERT INTO backtest_run (
+    created_at, strategy_version, symbols, period_from, period_to, spread, bars, signals, trades,
+    canceled, wins, losses, win_rate, total_pnl, max_drawdown, exit_reasons
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+RETURNING *;
+
+-- name: SaveDaemonJobRun :exec
+INSERT INTO daemon_job (
+    name, last_started_at, last_finished_at, last_success_at, last_error, last_duration_ms,
+    runs, failures, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, 1, $7, now())
+ON CONFLICT (name) DO UPDATE SET
+    last_started_at = EXCLUDED.last_started_at,
+    last_finished_at = EXCLUDED.last_finished_at,
+    last_success_at = COALESCE(EXCLUDED.last_success_at, daemon_job.last_success_at),
+    last_error = EXCLUDED.last_error,
+    last_duration_ms = EXCLUDED.last_duration_ms,
+    runs = daemon_job.runs + 1,
+    failures = daemon_job.failures + EXCLUDED.failures,
+    updated_at = EXCLUDED.updated_at;
+
+-- name: ListClosedTradesSince :many
+SELECT 'trade_log'::text AS source, id, symbol, buy_price, sell_price, amount, close_date
+FROM trade_log
+WHERE close_date >= $1::timestamptz AND sell_price IS NOT NULL
+UNION ALL
+SELECT 'trade_log_manual'::text AS source, id, symbol, buy_price, sell_price, amount, close_date
+FROM trade_log_manual
+WHERE close_date >= $1::timestamptz AND sell_price IS NOT NULL
+ORDER BY close_date;
+
+-- name: ListRecentClosedTrades :many
+SELECT 'trade_log'::text AS source, id, symbol, buy_price, sell_price, amount, close_date
+FROM trade_log
+WHERE close_date IS NOT NULL AND sell_price IS NOT NULL
+UNION ALL
+SELECT 'trade_log_manual'::text AS source, id, symbol, buy_price, sell_price, amount, close_date
+FROM trade_log_manual
+WHERE close_date IS NOT NULL AND sell_price IS NOT NULL
+ORDER BY close_date DESC
+LIMIT $1;
+
+-- name: GetRiskAlertSentAt :one
+SELECT sent_at FROM risk_alert WHERE reason = $1;
+
+-- name: SaveRiskAlert :exec
+INSERT INTO risk_alert (reason, sent_at)
+VALUES ($1, $2)
+ON CONFLICT (reason) DO UPDATE SET sent_at = EXCLUDED.sent_at;
+
+-- name: SaveStrategyConfig :exec
+INSERT INTO strategy_config (version, config, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (version) DO NOTHING;
+
+-- name: CreateSwapRun :one
+INSERT INTO swap_run (
+    path, start_asset, amount, expected_profit_percent, expected_depth_profit_percent, status, started_at, mode
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: FinishSwapRun :exec
+UPDATE swap_run SET
+    status = $2,
+    unwind_outcome = $3,
+    final_amount = $4,
+    realized_profit_percent = $5,
+    last_error = $6,
+    finished_at = $7,
+    liquidated_amount = $8
+WHERE id = $1;
+
+-- name: UpsertSwapLeg :exec
+INSERT INTO swap_leg (
+    run_id, leg, symbol, side, from_asset, to_asset, expected_price, expected_depth_price, quantity,
+    client_order_id, order_id, status, executed_quantity, cumulative_quote_qty, realized_price, fee,
+    placed_at, finished_at, liquidation
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+ON CONFLICT (run_id, leg) DO UPDATE SET
+    order_id = EXCLUDED.order_id,
+    status = EXCLUDED.status,
+    executed_quantity = EXCLUDED.executed_quantity,
+    cumulative_quote_qty = EXCLUDED.cumulative_quote_qty,
+    realized_price = EXCLUDED.realized_price,
+    fee = EXCLUDED.fee,
+    finished_at = EXCLUDED.finished_at;
+
+-- name: ListSwapRunsSince :many
+SELECT * FROM swap_run
+WHERE started_at >= $1
+ORDER BY started_at, id;
+
+-- name: ListSwapLegsSince :many
+SELECT swap_leg.* FROM swap_leg
+JOIN swap_run ON swap_run.id = swap_leg.run_id
+WHERE swap_run.started_at >= $1
+ORDER BY swap_leg.run_id, swap_leg.leg;
+
+-- name: CreateSwapOpportunity :exec
+INSERT INTO swap_opportunity (
+    path, start_asset, legs, threshold_percent, first_seen_at, last_seen_at, closed_at, observations,
+    first_profit_percent, peak_profit_percent, peak_at, peak_depth_amount, max_depth_amount
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+
+-- name: ListSwapOpportunitiesSince :many
+SELECT * FROM swap_opportunity
+WHERE first_seen_at >= $1
+ORDER BY first_seen_at, id;
+
+-- name: UpsertSwapInventory :exec
+INSERT INTO swap_inventory (asset, quantity, cost)
+VALUES ($1, $2, $3)
+ON CONFLICT (asset) DO UPDATE SET
+    quantity = EXCLUDED.quantity,
+    cost = EXCLUDED.cost,
+    updated_at = now();
+
+-- name: ListSwapInventory :many
+SELECT * FROM swap_inventory
+ORDER BY asset;
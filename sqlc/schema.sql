@@ -131,6 +131,18 @@ CREATE TABLE market_snapshot (
     price_change_percent DOUBLE PRECISION NOT NULL
 );
 
+CREATE TABLE market_trade (
+    symbol      TEXT NOT NULL,
+    traded_at   TIMESTAMPTZ NOT NULL,
+    price       DOUBLE PRECISION NOT NULL,
+    qty         DOUBLE PRECISION NOT NULL,
+    buyer_maker BOOLEAN NOT NULL,
+    seq         INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (symbol, traded_at, price, qty, buyer_maker, seq)
+);
+
+CREATE INDEX market_trade_traded_at_idx ON market_trade (traded_at);
+
 CREATE TABLE palisade_signal (
 	symbol                TEXT PRIMARY KEY,
 	sent_at               TIMESTAMPTZ NOT NULL,
@@ -144,7 +156,8 @@ CREATE TABLE palisade_signal (
 	status                TEXT NOT NULL DEFAULT 'ACTIVE',
 	invalidation_reason   TEXT NOT NULL DEFAULT '',
 	valid_until           TIMESTAMPTZ NOT NULL DEFAULT now(),
-	updated_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+	updated_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+	config_version        TEXT NOT NULL DEFAULT ''
 );
 
 CREATE TABLE palisade_order_intent (
@@ -163,12 +176,145 @@ CREATE TABLE palisade_order_intent (
     cumulative_quote_qty DOUBLE PRECISION NOT NULL DEFAULT 0,
     last_error            TEXT NOT NULL DEFAULT '',
     created_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
-    updated_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    order_type            TEXT NOT NULL DEFAULT 'LIMIT',
+    maker_fee_saving      DOUBLE PRECISION NOT NULL DEFAULT 0
 );
 
 CREATE INDEX palisade_order_intent_recovery_idx
     ON palisade_order_intent (status, updated_at);
 
+CREATE TABLE trade_log_exit_state (
+    trade_id             INT PRIMARY KEY,
+    strategy_version     INT NOT NULL,
+    fee                  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    lot_step             DOUBLE PRECISION NOT NULL DEFAULT 0,
+    break_even_armed     BOOLEAN NOT NULL DEFAULT false,
+    partial_profit_taken BOOLEAN NOT NULL DEFAULT false,
+    partial_order_id     TEXT NOT NULL DEFAULT '',
+    max_bid_price        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    min_bid_price        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    strategy_key         TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE palisade_order_bracket (
+    trade_id          INT PRIMARY KEY,
+    symbol            TEXT NOT NULL,
+    take_profit_price DOUBLE PRECISION NOT NULL,
+    stop_price        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    stop_limit_price  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    expires_at        TIMESTAMPTZ,
+    status            TEXT NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX palisade_order_bracket_status_idx
+    ON palisade_order_bracket (status);
+
+CREATE TABLE palisade_grid (
+    id               SERIAL PRIMARY KEY,
+    symbol           TEXT NOT NULL,
+    support_price    DOUBLE PRECISION NOT NULL,
+    resistance_price DOUBLE PRECISION NOT NULL,
+    levels           INT NOT NULL,
+    quote_per_level  DOUBLE PRECISION NOT NULL,
+    status           TEXT NOT NULL,
+    stop_reason      TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX palisade_grid_active_symbol_idx
+    ON palisade_grid (symbol) WHERE status = 'ACTIVE';
+
+CREATE TABLE palisade_grid_level (
+    grid_id         INT NOT NULL REFERENCES palisade_grid (id),
+    level           INT NOT NULL,
+    buy_price       DOUBLE PRECISION NOT NULL,
+    sell_price      DOUBLE PRECISION NOT NULL,
+    quantity        DOUBLE PRECISION NOT NULL,
+    status          TEXT NOT NULL,
+    order_id        TEXT NOT NULL DEFAULT '',
+    client_order_id TEXT NOT NULL DEFAULT '',
+    held_quantity   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    held_cost       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    cycles          INT NOT NULL DEFAULT 0,
+    realized_pnl    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (grid_id, level)
+);
+
+CREATE TABLE swap_run (
+    id                            SERIAL PRIMARY KEY,
+    path                          TEXT NOT NULL,
+    start_asset                   TEXT NOT NULL,
+    amount                        DOUBLE PRECISION NOT NULL,
+    expected_profit_percent       DOUBLE PRECISION NOT NULL,
+    expected_depth_profit_percent DOUBLE PRECISION NOT NULL,
+    status                        TEXT NOT NULL,
+    unwind_outcome                TEXT NOT NULL DEFAULT '',
+    final_amount                  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    realized_profit_percent       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_error                    TEXT NOT NULL DEFAULT '',
+    started_at                    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at                   TIMESTAMPTZ,
+    mode                          TEXT NOT NULL DEFAULT 'limit',
+    liquidated_amount             DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+CREATE INDEX swap_run_started_at_idx ON swap_run (started_at);
+
+CREATE TABLE swap_leg (
+    run_id               INT NOT NULL REFERENCES swap_run (id),
+    leg                  INT NOT NULL,
+    symbol               TEXT NOT NULL,
+    side                 TEXT NOT NULL,
+    from_asset           TEXT NOT NULL,
+    to_asset             TEXT NOT NULL,
+    expected_price       DOUBLE PRECISION NOT NULL,
+    expected_depth_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    quantity             DOUBLE PRECISION NOT NULL,
+    client_order_id      TEXT NOT NULL,
+    order_id             TEXT NOT NULL DEFAULT '',
+    status               TEXT NOT NULL,
+    executed_quantity    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    cumulative_quote_qty DOUBLE PRECISION NOT NULL DEFAULT 0,
+    realized_price       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    fee                  DOUBLE PRECISION NOT NULL DEFAULT 0,
+    placed_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at          TIMESTAMPTZ,
+    liquidation          BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (run_id, leg)
+);
+
+CREATE TABLE swap_opportunity (
+    id                   SERIAL PRIMARY KEY,
+    path                 TEXT NOT NULL,
+    start_asset          TEXT NOT NULL,
+    legs                 INT NOT NULL,
+    threshold_percent    DOUBLE PRECISION NOT NULL,
+    first_seen_at        TIMESTAMPTZ NOT NULL,
+    last_seen_at         TIMESTAMPTZ NOT NULL,
+    closed_at            TIMESTAMPTZ NOT NULL,
+    observations         INT NOT NULL,
+    first_profit_percent DOUBLE PRECISION NOT NULL,
+    peak_profit_percent  DOUBLE PRECISION NOT NULL,
+    peak_at              TIMESTAMPTZ NOT NULL,
+    peak_depth_amount    DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_depth_amount     DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+CREATE INDEX swap_opportunity_first_seen_at_idx ON swap_opportunity (first_seen_at);
+
+CREATE TABLE swap_inventory (
+    asset      TEXT PRIMARY KEY,
+    quantity   DOUBLE PRECISION NOT NULL,
+    cost       DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
 CREATE TABLE paper_trade (
     id              SERIAL PRIMARY KEY,
     strategy_version INT NOT NULL DEFAULT 1,
@@ -197,13 +343,60 @@ CREATE TABLE paper_trade (
     closed_at       TIMESTAMPTZ,
     exit_reason     TEXT NOT NULL DEFAULT '',
     last_price      DOUBLE PRECISION NOT NULL DEFAULT 0,
-    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    config_version  TEXT NOT NULL DEFAULT '',
+    strategy_key    TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX paper_trade_symbol_status_idx ON paper_trade (symbol, status);
 CREATE INDEX paper_trade_strategy_status_idx ON paper_trade (strategy_version, status);
 CREATE INDEX paper_trade_signal_idx ON paper_trade (strategy_version, symbol, signal_at);
 
+CREATE TABLE backtest_run (
+    id               SERIAL PRIMARY KEY,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    strategy_version INT NOT NULL,
+    symbols          TEXT NOT NULL,
+    period_from      TIMESTAMPTZ NOT NULL,
+    period_to        TIMESTAMPTZ NOT NULL,
+    spread           DOUBLE PRECISION NOT NULL DEFAULT 0,
+    bars             INT NOT NULL DEFAULT 0,
+    signals          INT NOT NULL DEFAULT 0,
+    trades           INT NOT NULL DEFAULT 0,
+    canceled         INT NOT NULL DEFAULT 0,
+    wins             INT NOT NULL DEFAULT 0,
+    losses           INT NOT NULL DEFAULT 0,
+    win_rate         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    total_pnl        DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_drawdown     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    exit_reasons     TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX backtest_run_strategy_idx ON backtest_run (strategy_version, created_at);
+
+CREATE TABLE daemon_job (
+    name             TEXT PRIMARY KEY,
+    last_started_at  TIMESTAMPTZ NOT NULL,
+    last_finished_at TIMESTAMPTZ NOT NULL,
+    last_success_at  TIMESTAMPTZ,
+    last_error       TEXT,
+    last_duration_ms INT NOT NULL DEFAULT 0,
+    runs             INT NOT NULL DEFAULT 0,
+    failures         INT NOT NULL DEFAULT 0,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE risk_alert (
+    reason  TEXT PRIMARY KEY,
+    sent_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE strategy_config (
+    version    TEXT PRIMARY KEY,
+    config     JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
 CREATE TABLE trend_retest_state (
     symbol                  TEXT NOT NULL,
     sma_period              INT NOT NULL,